@@ -0,0 +1,185 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package colorspace
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// CalibFunc is a color pipeline to be checked by Calib: it is passed
+// the RGB [3, Y, X] Macbeth chart image tensor, and returns its output
+// as a [Channels, Y, X] tensor of channel maps (e.g., OpponentDoG.Out),
+// at any resolution that covers the image (including its border)
+// in proportion.
+type CalibFunc func(img *tensor.Float32) *tensor.Float32
+
+// CalibDrift records one patch and channel whose mean response
+// differs from the reference by more than the Calib tolerance.
+type CalibDrift struct {
+
+	// index of the patch, in MacbethNames order
+	Patch int
+
+	// output channel
+	Channel int
+
+	// reference mean response
+	Ref float32
+
+	// current mean response
+	Resp float32
+}
+
+func (cd *CalibDrift) String() string {
+	return fmt.Sprintf("%s ch %d: ref: %g  resp: %g", MacbethNames[cd.Patch], cd.Channel, cd.Ref, cd.Resp)
+}
+
+// Calib is a calibration check for a color pipeline, which runs it on
+// the Macbeth chart and records the mean response of each output
+// channel within each of the 24 color patches, comparing these to
+// stored reference values to detect drift in the configuration.
+// Typical usage: SetRef and SaveRef once with a known-good configuration,
+// then OpenRef and Check to verify subsequent configurations.
+type Calib struct {
+
+	// size of the Macbeth chart image, not including the border
+	Size image.Point
+
+	// border around the chart image -- must be at least the Geom.FiltRt of the pipeline filters
+	Border int
+
+	// proportion of each patch to exclude on each side, to avoid responses to the patch edges
+	Inset float32 `default:"0.25"`
+
+	// tolerance for drift: maximum difference from the reference, as a proportion of the maximum absolute reference value for that channel
+	Tol float32 `default:"0.05"`
+
+	// Macbeth chart image that was passed to the pipeline
+	Image tensor.Float32 `display:"no-inline"`
+
+	// reference mean responses: Patch name and Resp [Channels] columns
+	Ref *table.Table `display:"no-inline"`
+
+	// current mean responses from the last Check: Patch name and Resp [Channels] columns
+	Resp *table.Table `display:"no-inline"`
+}
+
+func (cb *Calib) Defaults() {
+	cb.Size = image.Point{512, 512}
+	cb.Inset = 0.25
+	cb.Tol = 0.05
+}
+
+// Measure runs given pipeline on the Macbeth chart and returns a table
+// with the mean response of each output channel within each patch.
+func (cb *Calib) Measure(fun CalibFunc) *table.Table {
+	MacbethImage(&cb.Image, cb.Size.X, cb.Size.Y, cb.Border)
+	out := fun(&cb.Image)
+	nch := out.DimSize(0)
+	oy := out.DimSize(1)
+	ox := out.DimSize(2)
+	iy := float32(cb.Image.DimSize(1))
+	ix := float32(cb.Image.DimSize(2))
+
+	dt := table.New("MacbethCalib")
+	nm := dt.AddStringColumn("Patch")
+	rs := dt.AddFloat32Column("Resp", nch)
+	np := len(MacbethNames)
+	dt.SetNumRows(np)
+	for pi, pr := range MacbethPatches(cb.Size.X, cb.Size.Y, cb.Border) {
+		nm.SetString1D(MacbethNames[pi], pi)
+		ins := image.Point{int(cb.Inset * float32(pr.Dx())), int(cb.Inset * float32(pr.Dy()))}
+		pr = image.Rectangle{Min: pr.Min.Add(ins), Max: pr.Max.Sub(ins)}
+		sy := int(float32(pr.Min.Y) * float32(oy) / iy)
+		sx := int(float32(pr.Min.X) * float32(ox) / ix)
+		ey := max(int(math32.Ceil(float32(pr.Max.Y)*float32(oy)/iy)), sy+1)
+		ex := max(int(math32.Ceil(float32(pr.Max.X)*float32(ox)/ix)), sx+1)
+		ey = min(ey, oy)
+		ex = min(ex, ox)
+		n := float32((ey - sy) * (ex - sx))
+		for ch := 0; ch < nch; ch++ {
+			var sum float32
+			for y := sy; y < ey; y++ {
+				for x := sx; x < ex; x++ {
+					sum += out.Value(ch, y, x)
+				}
+			}
+			rs.Set(sum/n, pi, ch)
+		}
+	}
+	return dt
+}
+
+// SetRef runs given pipeline and records the results as the reference.
+func (cb *Calib) SetRef(fun CalibFunc) {
+	cb.Ref = cb.Measure(fun)
+}
+
+// SaveRef saves the reference values to given file, as tab-separated values.
+func (cb *Calib) SaveRef(filename fsx.Filename) error {
+	return cb.Ref.SaveCSV(filename, tensor.Tab, table.Headers)
+}
+
+// OpenRef opens reference values previously saved with SaveRef.
+func (cb *Calib) OpenRef(filename fsx.Filename) error {
+	cb.Ref = table.New("MacbethCalib")
+	return cb.Ref.OpenCSV(filename, tensor.Detect)
+}
+
+// Check runs given pipeline, recording the results in Resp, and
+// returns the list of patch channels that differ from the Ref values
+// by more than the Tol tolerance, which is empty if there is no drift.
+// A difference in the number of channels is reported as an error,
+// as is a missing Ref, which must be set with SetRef or OpenRef first.
+func (cb *Calib) Check(fun CalibFunc) ([]CalibDrift, error) {
+	if cb.Ref == nil {
+		return nil, fmt.Errorf("colorspace.Calib: no reference values: call SetRef or OpenRef first")
+	}
+	ref := cb.Ref.Column("Resp")
+	if ref == nil {
+		return nil, fmt.Errorf("colorspace.Calib: reference values have no Resp column")
+	}
+	cb.Resp = cb.Measure(fun)
+	rs := cb.Resp.Column("Resp")
+	nch := rs.Tensor.DimSize(1)
+	if rch := ref.Tensor.DimSize(1); rch != nch {
+		return nil, fmt.Errorf("colorspace.Calib: number of channels: %d != reference: %d", nch, rch)
+	}
+	np := len(MacbethNames)
+	var drift []CalibDrift
+	for ch := 0; ch < nch; ch++ {
+		var mx float32
+		for pi := 0; pi < np; pi++ {
+			mx = math32.Max(mx, math32.Abs(float32(ref.Float(pi, ch))))
+		}
+		tol := cb.Tol * mx
+		for pi := 0; pi < np; pi++ {
+			rv := float32(ref.Float(pi, ch))
+			cv := float32(rs.Float(pi, ch))
+			if math32.Abs(cv-rv) > tol {
+				drift = append(drift, CalibDrift{Patch: pi, Channel: ch, Ref: rv, Resp: cv})
+			}
+		}
+	}
+	return drift, nil
+}
+
+// CalibFunc returns a CalibFunc for checking this OpponentDoG, which
+// converts the image with RGBTensorToLMSComps into given lms tensor
+// and returns the Out tensor after filtering.  The Calib Border must
+// be at least Geom.FiltRt.
+func (od *OpponentDoG) CalibFunc(lms *tensor.Float32) CalibFunc {
+	return func(img *tensor.Float32) *tensor.Float32 {
+		RGBTensorToLMSComps(lms, img)
+		od.Filter(lms)
+		return &od.Out
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package colorspace
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// DeconvRGB reconstructs a color image from the outputs of filtering
+// each of the opponent channel images (WhiteBlack = GREY,
+// RedGreen = LvMC, BlueYellow = SvLMC), e.g., with V1 simple gabor
+// filters, by reversing the filtering of each channel with
+// vfilter.DeconvNorm, and recombining the channels into RGB with
+// SRGBFromOpponents, to visualize the color information that the
+// filter outputs carry.  The filtering removes the mean (DC) of each
+// channel, which is restored from Means, and the reconstruction does
+// not preserve the overall gain of band-pass filters, so all of the
+// channels are scaled by the same factor to match the Contrast of
+// the grey channel, preserving their relative contrasts.
+type DeconvRGB struct {
+
+	// gain that each opponent channel was filtered with (e.g., the extra color gain for the color channels), which the reconstruction is divided by
+	Gains [OpponentsN]float32
+
+	// mean value of each opponent channel, added to the reconstruction -- defaults to those of a mid-grey image, and can be set from the original image with SetStats
+	Means [OpponentsN]float32
+
+	// RMS contrast (standard deviation) of the reconstructed grey (WhiteBlack) channel, which all channels are scaled to match -- can be set from the original image with SetStats -- if 0, no scaling is done
+	Contrast float32 `default:"0.2"`
+}
+
+func (dr *DeconvRGB) Defaults() {
+	for i := range dr.Gains {
+		dr.Gains[i] = 1
+	}
+	_, _, _, _, lvm, svlm, grey := SRGBToLMSComps(0.5, 0.5, 0.5)
+	dr.Means = [OpponentsN]float32{grey, lvm, svlm}
+	dr.Contrast = 0.2
+}
+
+// SetStats sets the Means and Contrast from the given LMS components
+// tensor of the original image, as computed by RGBTensorToLMSComps,
+// excluding padWidth of padding on all sides.
+func (dr *DeconvRGB) SetStats(lms *tensor.Float32, padWidth int) {
+	comps := [OpponentsN]LMSComponents{GREY, LvMC, SvLMC}
+	for i, c := range comps {
+		mean, std := meanStd(Component(lms, c), padWidth)
+		dr.Means[i] = mean
+		if c == GREY {
+			dr.Contrast = std
+		}
+	}
+}
+
+// meanStd returns the mean and standard deviation of given [Y, X]
+// image, excluding padWidth of padding on all sides.
+func meanStd(img *tensor.Float32, padWidth int) (mean, std float32) {
+	sy, sx := img.DimSize(0), img.DimSize(1)
+	var sum, ssq float32
+	n := 0
+	for y := padWidth; y < sy-padWidth; y++ {
+		for x := padWidth; x < sx-padWidth; x++ {
+			v := img.Values[y*sx+x]
+			sum += v
+			ssq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	mean = sum / float32(n)
+	std = math32.Sqrt(max(ssq/float32(n)-mean*mean, 0))
+	return
+}
+
+// Deconv reconstructs the opponent channel images from the filter
+// outputs for each channel (indexed by Opponents, as from vfilter.Conv
+// with given geom and filters), into opp: [Opponents, Y, X], with
+// the same padded size as the filtered images, and recombines them
+// into rgb: [3, Y, X].  The outputs for a channel can be nil (e.g.,
+// for the color channels of greyscale filtering), in which case
+// the channel is set to its mean.  geom must already be configured
+// for the filtered images, e.g., by vfilter.Conv.
+func (dr *DeconvRGB) Deconv(geom *vfilter.Geom, flt *tensor.Float32, outs [OpponentsN]*tensor.Float32, opp, rgb *tensor.Float32) {
+	opp.SetShapeSizes(int(OpponentsN), geom.In.Y, geom.In.X)
+	var img tensor.Float32
+	for i, out := range outs {
+		vfilter.ChannelView(opp, i, &img)
+		if out == nil {
+			img.SetZeros()
+			continue
+		}
+		vfilter.DeconvNorm(geom, flt, &img, out, 1)
+		// note: Deconv reconstructs the negative of the filtered image
+		gi := -1 / dr.Gains[i]
+		for j, v := range img.Values {
+			img.Values[j] = gi * v
+		}
+	}
+	scale := float32(1)
+	if dr.Contrast > 0 {
+		vfilter.ChannelView(opp, int(WhiteBlack), &img)
+		if _, std := meanStd(&img, geom.Border.Y); std > 0 {
+			scale = dr.Contrast / std
+		}
+	}
+	for i := range outs {
+		vfilter.ChannelView(opp, i, &img)
+		for j, v := range img.Values {
+			img.Values[j] = scale*v + dr.Means[i]
+		}
+	}
+	OpponentsTensorToRGB(rgb, opp)
+}
+
+// OpponentsTensorToRGB converts an opponents tensor: [Opponents, Y, X],
+// with the GREY, LvMC and SvLMC components of RGBTensorToLMSComps,
+// to an RGB tensor: [3, Y, X], using SRGBFromOpponents.
+func OpponentsTensorToRGB(rgb, opp *tensor.Float32) {
+	sy := opp.DimSize(1)
+	sx := opp.DimSize(2)
+	n := sy * sx
+	rgb.SetShapeSizes(3, sy, sx)
+	for i := 0; i < n; i++ {
+		r, g, b := SRGBFromOpponents(opp.Values[i], opp.Values[n+i], opp.Values[2*n+i])
+		rgb.Values[i] = r
+		rgb.Values[n+i] = g
+		rgb.Values[2*n+i] = b
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package colorspace
+
+import (
+	"image"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/dog"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// SingleOpponents are the single-opponent center-surround channels
+// computed by OpponentDoG, as the rows of its output.
+type SingleOpponents int32 //enums:enum
+
+const (
+	// Red center On, Green surround Off
+	RedOnGreenOff SingleOpponents = iota
+
+	// Green center On, Red surround Off
+	GreenOnRedOff
+
+	// Blue center On, Yellow surround Off
+	BlueOnYellowOff
+
+	// Yellow center On, Blue surround Off
+	YellowOnBlueOff
+
+	// luminance (grey) On center, Off surround
+	LumOn
+
+	// luminance (grey) Off center, On surround
+	LumOff
+)
+
+// OpponentDoG computes the full set of single-opponent center-surround
+// DoG channels (R+/G-, G+/R-, B+/Y-, Y+/B-) plus luminance On and Off
+// from an LMS components tensor, as computed by RGBTensorToLMSComps,
+// into one aggregated output tensor with SingleOpponents as the rows.
+// Each color channel is the positive part of the difference between
+// the center (DoG On) filter applied to the first color component
+// and the surround (DoG Off) filter applied to the second, using
+// vfilter.ConvDiff.
+type OpponentDoG struct {
+
+	// DoG filter parameters, including the Gain and OnGain used for all channels
+	DoG dog.Filter `display:"inline"`
+
+	// extra gain for the color channels relative to luminance -- lower contrast in general
+	ColorGain float32 `default:"1"`
+
+	// geometry of input, output
+	Geom vfilter.Geom `edit:"-"`
+
+	// DoG filter tensor -- has 3 filters (on, off, net)
+	DoGTsr tensor.Float32 `display:"no-inline"`
+
+	// output: [SingleOpponentsN, Y, X]
+	Out tensor.Float32 `display:"no-inline"`
+
+	// ConvDiff output, [2, Y, X]
+	diff tensor.Float32
+}
+
+func (od *OpponentDoG) Defaults() {
+	od.DoG.Defaults()
+	od.ColorGain = 1
+	od.Config()
+}
+
+// Config renders the DoG filter and configures the Geom -- must be
+// called after any changes to the DoG parameters.
+// The image border must be at least Geom.FiltRt.
+func (od *OpponentDoG) Config() {
+	od.DoG.ToTensor(&od.DoGTsr)
+	sz := od.DoG.Size
+	spc := od.DoG.Spacing
+	od.Geom.Set(image.Point{0, 0}, image.Point{spc, spc}, image.Point{sz, sz})
+}
+
+// Filter computes all of the single-opponent channels from given
+// LMS components tensor into Out.
+func (od *OpponentDoG) Filter(lms *tensor.Float32) {
+	ctr := od.DoG.FilterTensor(&od.DoGTsr, dog.On)
+	srd := od.DoG.FilterTensor(&od.DoGTsr, dog.Off)
+	cgain := od.ColorGain * od.DoG.Gain
+	pairs := [4][2]LMSComponents{{LC, MC}, {MC, LC}, {SC, LMC}, {LMC, SC}}
+	for i, pr := range pairs {
+		vfilter.ConvDiff(&od.Geom, ctr, srd, Component(lms, pr[0]), Component(lms, pr[1]), &od.diff, cgain, od.DoG.OnGain)
+		od.setRow(SingleOpponents(i), 0)
+	}
+	grey := Component(lms, GREY)
+	vfilter.ConvDiff(&od.Geom, ctr, srd, grey, grey, &od.diff, od.DoG.Gain, od.DoG.OnGain)
+	od.setRow(LumOn, 0)
+	od.setRow(LumOff, 1)
+}
+
+// setRow copies given polarity of the ConvDiff output to given
+// output row, sizing the output as needed.
+func (od *OpponentDoG) setRow(row SingleOpponents, pol int) {
+	ny := od.diff.DimSize(1)
+	nx := od.diff.DimSize(2)
+	od.Out.SetShapeSizes(int(SingleOpponentsN), ny, nx)
+	copy(vfilter.Channel(&od.Out, int(row)).Values, vfilter.Channel(&od.diff, pol).Values)
+}
+
+// Row returns a view of the output for given channel, as a [Y, X] tensor
+func (od *OpponentDoG) Row(row SingleOpponents) *tensor.Float32 {
+	return vfilter.Channel(&od.Out, int(row))
+}
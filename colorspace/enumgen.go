@@ -6,6 +6,49 @@ import (
 	"cogentcore.org/core/enums"
 )
 
+var _SingleOpponentsValues = []SingleOpponents{0, 1, 2, 3, 4, 5}
+
+// SingleOpponentsN is the highest valid value for type SingleOpponents, plus one.
+const SingleOpponentsN SingleOpponents = 6
+
+var _SingleOpponentsValueMap = map[string]SingleOpponents{`RedOnGreenOff`: 0, `GreenOnRedOff`: 1, `BlueOnYellowOff`: 2, `YellowOnBlueOff`: 3, `LumOn`: 4, `LumOff`: 5}
+
+var _SingleOpponentsDescMap = map[SingleOpponents]string{0: `Red center On, Green surround Off`, 1: `Green center On, Red surround Off`, 2: `Blue center On, Yellow surround Off`, 3: `Yellow center On, Blue surround Off`, 4: `luminance (grey) On center, Off surround`, 5: `luminance (grey) Off center, On surround`}
+
+var _SingleOpponentsMap = map[SingleOpponents]string{0: `RedOnGreenOff`, 1: `GreenOnRedOff`, 2: `BlueOnYellowOff`, 3: `YellowOnBlueOff`, 4: `LumOn`, 5: `LumOff`}
+
+// String returns the string representation of this SingleOpponents value.
+func (i SingleOpponents) String() string { return enums.String(i, _SingleOpponentsMap) }
+
+// SetString sets the SingleOpponents value from its string representation,
+// and returns an error if the string is invalid.
+func (i *SingleOpponents) SetString(s string) error {
+	return enums.SetString(i, s, _SingleOpponentsValueMap, "SingleOpponents")
+}
+
+// Int64 returns the SingleOpponents value as an int64.
+func (i SingleOpponents) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the SingleOpponents value from an int64.
+func (i *SingleOpponents) SetInt64(in int64) { *i = SingleOpponents(in) }
+
+// Desc returns the description of the SingleOpponents value.
+func (i SingleOpponents) Desc() string { return enums.Desc(i, _SingleOpponentsDescMap) }
+
+// SingleOpponentsValues returns all possible values for the type SingleOpponents.
+func SingleOpponentsValues() []SingleOpponents { return _SingleOpponentsValues }
+
+// Values returns all possible values for the type SingleOpponents.
+func (i SingleOpponents) Values() []enums.Enum { return enums.Values(_SingleOpponentsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i SingleOpponents) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *SingleOpponents) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "SingleOpponents")
+}
+
 var _LMSComponentsValues = []LMSComponents{0, 1, 2, 3, 4, 5, 6}
 
 // LMSComponentsN is the highest valid value for type LMSComponents, plus one.
@@ -18,9 +18,11 @@ import (
 // otherwise it is flipped with Y=0 at the bottom to be consistent
 // with the emergent / OpenGL standard coordinate system
 func RGBImgToLMSComps(img image.Image, tsr *tensor.Float32, padWidth int, topZero bool) {
-	rgbtsr := &tensor.Float32{}
+	sz := img.Bounds().Size()
+	rgbtsr := vfilter.Pool.Get(3, sz.Y+2*padWidth, sz.X+2*padWidth)
 	vfilter.RGBToTensor(img, rgbtsr, padWidth, topZero)
-	RGBTensorToLMSComps(rgbtsr, tsr)
+	RGBTensorToLMSComps(tsr, rgbtsr)
+	vfilter.Pool.Put(rgbtsr)
 }
 
 // RGBTensorToLMSComps converts an RGB Tensor to corresponding LMS components
@@ -46,3 +48,28 @@ func RGBTensorToLMSComps(tsr *tensor.Float32, rgb *tensor.Float32) {
 		}
 	}
 }
+
+// RGBTensorToGrey converts an RGB Tensor to just the GREY component
+// of RGBTensorToLMSComps, as a 2D [Y, X] tensor, and assumes rgb is
+// 3 dimensional with outer-most dimension as RGB.  This is much more
+// efficient in memory and time than computing all of the components
+// when only greyscale processing is being done.
+func RGBTensorToGrey(grey *tensor.Float32, rgb *tensor.Float32) {
+	sy := rgb.DimSize(1)
+	sx := rgb.DimSize(2)
+	n := sy * sx
+	grey.SetShapeSizes(sy, sx)
+	rs := rgb.Values[:n]
+	gs := rgb.Values[n : 2*n]
+	bs := rgb.Values[2*n : 3*n]
+	for i := range grey.Values {
+		grey.Values[i] = SRGBToGrey(rs[i], gs[i], bs[i])
+	}
+}
+
+// Component returns a view of given component of an LMS components
+// tensor, as computed by RGBTensorToLMSComps, as a [Y, X] tensor that
+// directly shares the values of the lms tensor, with no copying.
+func Component(lms *tensor.Float32, comp LMSComponents) *tensor.Float32 {
+	return vfilter.Channel(lms, int(comp))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package colorspace
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+func TestRGBImgToLMSComps(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.RGBA{uint8(60 * x), uint8(80 * y), 128, 255})
+		}
+	}
+	pad := 1
+	var tsr tensor.Float32
+	RGBImgToLMSComps(img, &tsr, pad, true)
+	sz := tsr.ShapeSizes()
+	if len(sz) != 3 || sz[0] != int(LMSComponentsN) || sz[1] != 3+2*pad || sz[2] != 4+2*pad {
+		t.Fatalf("wrong shape: %v", sz)
+	}
+	tol := float32(1.0e-5)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			r, g, b := float32(60*x)/255, float32(80*y)/255, float32(128)/255
+			_, _, _, _, lvm, _, grey := SRGBToLMSComps(r, g, b)
+			if v := Component(&tsr, GREY).Value(y+pad, x+pad); math32.Abs(v-grey) > tol {
+				t.Errorf("grey at %d,%d: %g != %g", x, y, v, grey)
+			}
+			if v := Component(&tsr, LvMC).Value(y+pad, x+pad); math32.Abs(v-lvm) > tol {
+				t.Errorf("lvm at %d,%d: %g != %g", x, y, v, lvm)
+			}
+		}
+	}
+}
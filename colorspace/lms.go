@@ -128,6 +128,16 @@ func SRGBToLMS_HPE(r, g, b float32) (l, m, s float32) {
 	return
 }
 
+// LMSToSRGBLin_HPE converts Long, Medium, Short cone-based responses
+// to sRGB linear, inverting the Hunt-Pointer-Estevez transform
+// of SRGBLinToLMS_HPE.
+func LMSToSRGBLin_HPE(l, m, s float32) (rl, gl, bl float32) {
+	rl = 5.6200051*l + -4.5709642*m + 0.15569186*s
+	gl = -1.1550365*l + 2.2575233*m + -0.15413241*s
+	bl = 0.030735669*l + -0.19029687*m + 1.0682459*s
+	return
+}
+
 /*
   func LMStoXYZ_HPE(float& X, float& Y, float& Z,
                                     L, M, S) {
@@ -182,3 +192,38 @@ func LMSToComps(l, m, s float32) (lc, mc, sc, lmc, lvm, svlm, grey float32) {
 	// note: last term should be: 0.725 * (1/5)^-0.2 = grey background assumption (Yb/Yw = 1/5) = 1
 	return
 }
+
+// LMSToGrey converts Long, Medium, Short cone-based responses
+// to just the grey (luminance) component of LMSToComps.
+func LMSToGrey(l, m, s float32) float32 {
+	lrc := ResponseCompression(l)
+	mrc := ResponseCompression(m)
+	src := ResponseCompression(s)
+	return (1.0 / 0.431787) * (2.0*lrc + mrc + .05*src - 0.305)
+}
+
+// CompsToLMS converts the grey, L - M (LvM) and S - L+M (SvLM)
+// opponent components of LMSToComps back to the Long, Medium, Short
+// cone-based responses, inverting the opponent combination and the
+// response compression, e.g., for reconstructing a color image from
+// the filtered opponent channels.  Components outside of the range
+// produced by LMSToComps are clamped to the nearest valid responses.
+func CompsToLMS(grey, lvm, svlm float32) (l, m, s float32) {
+	gv := grey + 0.305/0.431787
+	lrc := 0.053575671*lvm - 0.034212402*svlm + 0.14156951*gv
+	mrc := -0.10584462*lvm + 0.031004989*svlm + 0.14156951*gv
+	src := -0.026134474*lvm + 0.74839629*svlm + 0.14156951*gv
+	l = ResponseDecompression(lrc)
+	m = ResponseDecompression(mrc)
+	s = ResponseDecompression(src)
+	return
+}
+
+// ResponseDecompression is the inverse of ResponseCompression,
+// returning the 0-1 normalized LMS value for given compressed
+// response, which is clamped to the range of ResponseCompression.
+func ResponseDecompression(rc float32) float32 {
+	q := math32.Clamp(rc-0.1, 0, 3.99)
+	pval := 27.13 * q / (4 - q)
+	return math32.Pow(pval, 1/0.42)
+}
@@ -5,40 +5,29 @@
 package colorspace
 
 import (
+	"image"
+
 	"cogentcore.org/core/math32/vecint"
 	"cogentcore.org/core/tensor"
 )
 
+// MacbethNames are the names of the 24 Macbeth color chart patches,
+// in the order of MacbethSRGB, starting at the upper left of the chart.
+var MacbethNames = []string{"Dark Skin", "Light Skin", "Blue Sky", "Foliage", "Blue Flower", "Bluish Green",
+	"Orange", "Purple Red", "Moderate Red", "Purple", "Yellow Green", "Orange Yellow",
+	"Blue", "Green", "Red", "Yellow", "Magenta", "Cyan",
+	"White", "Neutral 8", "Neutral 65", "Neutral 5", "Neutral 35", "Black"}
+
+// MacbethSRGB are the sRGB 0-255 values of the 24 Macbeth color chart patches
+var MacbethSRGB = [][3]int{{115, 82, 68}, {194, 150, 130}, {98, 122, 157}, {87, 108, 67}, {133, 128, 177}, {103, 189, 170},
+	{214, 126, 44}, {80, 91, 166}, {193, 90, 99}, {94, 60, 108}, {157, 188, 64}, {224, 163, 46},
+	{56, 61, 150}, {70, 148, 73}, {175, 54, 60}, {231, 199, 31}, {187, 86, 149}, {8, 133, 161},
+	{255, 255, 255}, {200, 200, 200}, {160, 160, 160}, {122, 122, 121}, {85, 85, 85}, {52, 52, 52}}
+
 // MacbethImage sets the Macbeth standard color test image to given tensor
 // with given size and border width around edges.
 // if img == nil it is created, and size enforced.
 func MacbethImage(img *tensor.Float32, width, height, bord int) {
-	sRGBvals := []int{115, 82, 68, // 'Dark Skin';
-		194, 150, 130, // 'Light Skin';
-		98, 122, 157, // 'Blue Sky';
-		87, 108, 67, // 'Foliage';
-		133, 128, 177, // 'Blue Flower';
-		103, 189, 170, // 'Bluish Green';
-		214, 126, 44, // 'Orange';
-		80, 91, 166, // 'Purple Red';
-		193, 90, 99, // 'Moderate Red';
-		94, 60, 108, // 'Purple';
-		157, 188, 64, // 'Yellow Green';
-		224, 163, 46, // 'Orange Yellow';
-		56, 61, 150, // 'Blue';
-		70, 148, 73, // 'Green';
-		175, 54, 60, // 'Red';
-		231, 199, 31, // 'Yellow';
-		187, 86, 149, // 'Magenta';
-		8, 133, 161, // 'Cyan';
-		255, 255, 255, // 'White';
-		200, 200, 200, // 'Neutral 8';
-		160, 160, 160, // 'Neutral 65';
-		122, 122, 121, // 'Neutral 5';
-		85, 85, 85, // 'Neutral 35';
-		52, 52, 52,
-	}
-
 	nsq := vecint.Vector2i{6, 4}
 	numsq := nsq.X * nsq.Y
 	sz := vecint.Vector2i{width + bord*2 + 8, height + bord*2 + 8}
@@ -64,9 +53,9 @@ func MacbethImage(img *tensor.Float32, width, height, bord int) {
 			if ps.X > marg.X && ps.Y > marg.Y {
 				clri := (nsq.Y-1-sqc.Y)*nsq.X + sqc.X
 				if clri < numsq {
-					r := float32(sRGBvals[clri*3]) / 255
-					g := float32(sRGBvals[clri*3+1]) / 255
-					b := float32(sRGBvals[clri*3+2]) / 255
+					r := float32(MacbethSRGB[clri][0]) / 255
+					g := float32(MacbethSRGB[clri][1]) / 255
+					b := float32(MacbethSRGB[clri][2]) / 255
 
 					img.Set(r, 0, ic.Y, ic.X)
 					img.Set(g, 1, ic.Y, ic.X)
@@ -76,3 +65,22 @@ func MacbethImage(img *tensor.Float32, width, height, bord int) {
 		}
 	}
 }
+
+// MacbethPatches returns the bounds of each of the 24 color patches,
+// in the order of MacbethSRGB, within the tensor generated by
+// MacbethImage with the same parameters, in tensor Y, X coordinates.
+func MacbethPatches(width, height, bord int) []image.Rectangle {
+	nsq := image.Point{6, 4}
+	marg := 8
+	sqSz := image.Point{width / nsq.X, height / nsq.Y}
+	rects := make([]image.Rectangle, nsq.X*nsq.Y)
+	for sy := 0; sy < nsq.Y; sy++ {
+		for sx := 0; sx < nsq.X; sx++ {
+			clri := (nsq.Y-1-sy)*nsq.X + sx
+			st := image.Point{bord + sx*sqSz.X + marg + 1, bord + sy*sqSz.Y + marg + 1}
+			ed := image.Point{bord + (sx+1)*sqSz.X, bord + (sy+1)*sqSz.Y}
+			rects[clri] = image.Rectangle{Min: st, Max: ed}
+		}
+	}
+	return rects
+}
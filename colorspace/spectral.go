@@ -0,0 +1,153 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package colorspace
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Spectral projects N-band multispectral or hyperspectral image tensors,
+// with band as the outer-most dimension [Band, Y, X] as for RGB tensors,
+// into Long, Medium, Short cone-based responses, via a linear projection
+// matrix, so that the data can go through the same LMS components and
+// V1 filtering as RGB images.  Band values are assumed to be linear
+// (e.g., reflectance) in the 0-1 range.
+type Spectral struct {
+
+	// projection from the bands to the L, M, S cone responses: for each of L, M, S, the weight of each band
+	Proj [3][]float32
+}
+
+// NBands returns the number of bands of the projection
+func (sp *Spectral) NBands() int {
+	return len(sp.Proj[0])
+}
+
+// SetRGB sets the projection for 3 bands of linear R, G, B values,
+// using the same transform as for sRGB images (SRGBLinToLMS_HPE).
+func (sp *Spectral) SetRGB() {
+	for i := range sp.Proj {
+		sp.Proj[i] = make([]float32, 3)
+	}
+	for b := range 3 {
+		var rgb [3]float32
+		rgb[b] = 1
+		l, m, s := SRGBLinToLMS_HPE(rgb[0], rgb[1], rgb[2])
+		sp.Proj[0][b] = l
+		sp.Proj[1][b] = m
+		sp.Proj[2][b] = s
+	}
+}
+
+// SetWavelengths sets the projection for bands with given center
+// wavelengths in nanometers, using gaussian approximations of the cone
+// spectral sensitivities, with peaks at 565 (L), 540 (M), and 440 (S) nm,
+// normalized so the weights for each cone sum to 1, so that a uniform
+// spectrum produces equal responses for each cone.  Bands outside of
+// the visible range (e.g., near infrared) get little or no weight.
+func (sp *Spectral) SetWavelengths(wls ...float32) {
+	peaks := [3]float32{565, 540, 440}
+	sigs := [3]float32{50, 45, 30}
+	for c := range sp.Proj {
+		sp.Proj[c] = make([]float32, len(wls))
+		var sum float32
+		for b, wl := range wls {
+			d := (wl - peaks[c]) / sigs[c]
+			w := math32.Exp(-0.5 * d * d)
+			sp.Proj[c][b] = w
+			sum += w
+		}
+		if sum > 0 {
+			for b := range wls {
+				sp.Proj[c][b] /= sum
+			}
+		}
+	}
+}
+
+// SetProj sets the projection for given number of bands from given
+// weights, in L, M, S order, with the weights of each band for each.
+func (sp *Spectral) SetProj(nbands int, wts ...float32) error {
+	if len(wts) != 3*nbands {
+		return fmt.Errorf("colorspace.Spectral.SetProj: %d weights given, need 3 * %d bands", len(wts), nbands)
+	}
+	for c := range sp.Proj {
+		sp.Proj[c] = make([]float32, nbands)
+		copy(sp.Proj[c], wts[c*nbands:(c+1)*nbands])
+	}
+	return nil
+}
+
+// LMS returns the L, M, S responses for given band values
+func (sp *Spectral) LMS(bands []float32) (l, m, s float32) {
+	for b, v := range bands {
+		l += sp.Proj[0][b] * v
+		m += sp.Proj[1][b] * v
+		s += sp.Proj[2][b] * v
+	}
+	return
+}
+
+// check returns an error if the number of bands of given tensor
+// does not match the projection.
+func (sp *Spectral) check(bands *tensor.Float32) error {
+	if nb := bands.DimSize(0); nb != sp.NBands() {
+		return fmt.Errorf("colorspace.Spectral: tensor has %d bands, projection has %d", nb, sp.NBands())
+	}
+	return nil
+}
+
+// TensorToLMSComps converts a [Band, Y, X] tensor to the corresponding
+// LMS components including color opponents, with components as the
+// outer-most dimension, as in RGBTensorToLMSComps.
+func (sp *Spectral) TensorToLMSComps(tsr, bands *tensor.Float32) error {
+	if err := sp.check(bands); err != nil {
+		return err
+	}
+	nb := bands.DimSize(0)
+	sy := bands.DimSize(1)
+	sx := bands.DimSize(2)
+	n := sy * sx
+	tsr.SetShapeSizes(int(LMSComponentsN), sy, sx)
+	bv := make([]float32, nb)
+	for i := range n {
+		for b := range nb {
+			bv[b] = bands.Values[b*n+i]
+		}
+		lc, mc, sc, lmc, lvm, svlm, grey := LMSToComps(sp.LMS(bv))
+		tsr.Values[int(LC)*n+i] = lc
+		tsr.Values[int(MC)*n+i] = mc
+		tsr.Values[int(SC)*n+i] = sc
+		tsr.Values[int(LMC)*n+i] = lmc
+		tsr.Values[int(LvMC)*n+i] = lvm
+		tsr.Values[int(SvLMC)*n+i] = svlm
+		tsr.Values[int(GREY)*n+i] = grey
+	}
+	return nil
+}
+
+// TensorToGrey converts a [Band, Y, X] tensor to just the GREY component
+// of TensorToLMSComps, as a 2D [Y, X] tensor, as in RGBTensorToGrey.
+func (sp *Spectral) TensorToGrey(grey, bands *tensor.Float32) error {
+	if err := sp.check(bands); err != nil {
+		return err
+	}
+	nb := bands.DimSize(0)
+	sy := bands.DimSize(1)
+	sx := bands.DimSize(2)
+	n := sy * sx
+	grey.SetShapeSizes(sy, sx)
+	bv := make([]float32, nb)
+	for i := range n {
+		for b := range nb {
+			bv[b] = bands.Values[b*n+i]
+		}
+		grey.Values[i] = LMSToGrey(sp.LMS(bv))
+	}
+	return nil
+}
@@ -22,7 +22,7 @@ func SRGBFromLinearComp(lin float32) float32 {
 	if lin <= 0.0031308 {
 		return 12.92 * lin
 	}
-	return (1.055*math32.Pow(lin, 1/2.4) + 0.055)
+	return 1.055*math32.Pow(lin, 1/2.4) - 0.055
 }
 
 // SRGBToLinear converts set of sRGB components to linear values,
@@ -54,3 +54,24 @@ func SRGBToLMSComps(r, g, b float32) (lc, mc, sc, lmc, lvm, svlm, grey float32)
 	lc, mc, sc, lmc, lvm, svlm, grey = LMSToComps(l, m, s)
 	return
 }
+
+// SRGBToGrey converts sRGB to just the grey (luminance) component
+// of SRGBToLMSComps, for greyscale-only processing.
+func SRGBToGrey(r, g, b float32) float32 {
+	l, m, s := SRGBToLMS_HPE(r, g, b) // note: HPE
+	return LMSToGrey(l, m, s)
+}
+
+// SRGBFromOpponents converts the grey, L - M (LvM) and S - L+M (SvLM)
+// opponent components of SRGBToLMSComps back to sRGB, clamped to
+// the 0-1 range, e.g., for viewing a color image reconstructed from
+// the filtered opponent channels.
+func SRGBFromOpponents(grey, lvm, svlm float32) (r, g, b float32) {
+	l, m, s := CompsToLMS(grey, lvm, svlm)
+	rl, gl, bl := LMSToSRGBLin_HPE(l, m, s) // note: HPE
+	r, g, b = SRGBFromLinear(max(rl, 0), max(gl, 0), max(bl, 0))
+	r = math32.Clamp(r, 0, 1)
+	g = math32.Clamp(g, 0, 1)
+	b = math32.Clamp(b, 0, 1)
+	return
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
 )
 
 func init() {
@@ -65,3 +66,53 @@ func BenchmarkSRGBLookup(b *testing.B) {
 		TheSRGBToOp.Lookup(r, g, b)
 	}
 }
+
+func TestSRGBTableRow(t *testing.T) {
+	tol := float32(1.0e-3)
+	sy, sx := 8, 100
+	rgb := &tensor.Float32{}
+	rgb.SetShapeSizes(3, sy, sx)
+	for i := range rgb.Values {
+		rgb.Values[i] = rand.Float32()
+	}
+	rgb.Set(1, 0, 0, 0) // extremes
+	rgb.Set(1, 1, 0, 0)
+	rgb.Set(1, 2, 0, 0)
+	rgb.Set(0, 0, 0, 1)
+	lms := &tensor.Float32{}
+	TheSRGBToOp.LookupTensor(lms, rgb)
+	for y := 0; y < sy; y++ {
+		for x := 0; x < sx; x++ {
+			lc, mc, sc, lmc, lvm, svlm, grey := SRGBToLMSComps(rgb.Value(0, y, x), rgb.Value(1, y, x), rgb.Value(2, y, x))
+			for c, v := range []float32{lc, mc, sc, lmc, lvm, svlm, grey} {
+				if lv := lms.Value(c, y, x); math32.Abs(v-lv) > tol {
+					t.Errorf("%v err at %d,%d: comp: %g  lookup: %g", LMSComponents(c), y, x, v, lv)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkSRGBCalcTensor(b *testing.B) {
+	rgb := &tensor.Float32{}
+	rgb.SetShapeSizes(3, 128, 128)
+	for i := range rgb.Values {
+		rgb.Values[i] = rand.Float32()
+	}
+	lms := &tensor.Float32{}
+	for n := 0; n < b.N; n++ {
+		RGBTensorToLMSComps(lms, rgb)
+	}
+}
+
+func BenchmarkSRGBLookupTensor(b *testing.B) {
+	rgb := &tensor.Float32{}
+	rgb.SetShapeSizes(3, 128, 128)
+	for i := range rgb.Values {
+		rgb.Values[i] = rand.Float32()
+	}
+	lms := &tensor.Float32{}
+	for n := 0; n < b.N; n++ {
+		TheSRGBToOp.LookupTensor(lms, rgb)
+	}
+}
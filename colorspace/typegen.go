@@ -6,8 +6,22 @@ import (
 	"cogentcore.org/core/types"
 )
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.CalibFunc", IDName: "calib-func", Doc: "CalibFunc is a color pipeline to be checked by Calib: it is passed\nthe RGB [3, Y, X] Macbeth chart image tensor, and returns its output\nas a [Channels, Y, X] tensor of channel maps (e.g., OpponentDoG.Out),\nat any resolution that covers the image (including its border)\nin proportion."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.CalibDrift", IDName: "calib-drift", Doc: "CalibDrift records one patch and channel whose mean response\ndiffers from the reference by more than the Calib tolerance.", Fields: []types.Field{{Name: "Patch", Doc: "index of the patch, in MacbethNames order"}, {Name: "Channel", Doc: "output channel"}, {Name: "Ref", Doc: "reference mean response"}, {Name: "Resp", Doc: "current mean response"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.Calib", IDName: "calib", Doc: "Calib is a calibration check for a color pipeline, which runs it on\nthe Macbeth chart and records the mean response of each output\nchannel within each of the 24 color patches, comparing these to\nstored reference values to detect drift in the configuration.\nTypical usage: SetRef and SaveRef once with a known-good configuration,\nthen OpenRef and Check to verify subsequent configurations.", Fields: []types.Field{{Name: "Size", Doc: "size of the Macbeth chart image, not including the border"}, {Name: "Border", Doc: "border around the chart image -- must be at least the Geom.FiltRt of the pipeline filters"}, {Name: "Inset", Doc: "proportion of each patch to exclude on each side, to avoid responses to the patch edges"}, {Name: "Tol", Doc: "tolerance for drift: maximum difference from the reference, as a proportion of the maximum absolute reference value for that channel"}, {Name: "Image", Doc: "Macbeth chart image that was passed to the pipeline"}, {Name: "Ref", Doc: "reference mean responses: Patch name and Resp [Channels] columns"}, {Name: "Resp", Doc: "current mean responses from the last Check: Patch name and Resp [Channels] columns"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.DeconvRGB", IDName: "deconv-rgb", Doc: "DeconvRGB reconstructs a color image from the outputs of filtering\neach of the opponent channel images (WhiteBlack = GREY,\nRedGreen = LvMC, BlueYellow = SvLMC), e.g., with V1 simple gabor\nfilters, by reversing the filtering of each channel with\nvfilter.DeconvNorm, and recombining the channels into RGB with\nSRGBFromOpponents, to visualize the color information that the\nfilter outputs carry.  The filtering removes the mean (DC) of each\nchannel, which is restored from Means, and the reconstruction does\nnot preserve the overall gain of band-pass filters, so all of the\nchannels are scaled by the same factor to match the Contrast of\nthe grey channel, preserving their relative contrasts.", Fields: []types.Field{{Name: "Gains", Doc: "gain that each opponent channel was filtered with (e.g., the extra color gain for the color channels), which the reconstruction is divided by"}, {Name: "Means", Doc: "mean value of each opponent channel, added to the reconstruction -- defaults to those of a mid-grey image, and can be set from the original image with SetStats"}, {Name: "Contrast", Doc: "RMS contrast (standard deviation) of the reconstructed grey (WhiteBlack) channel, which all channels are scaled to match -- can be set from the original image with SetStats -- if 0, no scaling is done"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.SingleOpponents", IDName: "single-opponents", Doc: "SingleOpponents are the single-opponent center-surround channels\ncomputed by OpponentDoG, as the rows of its output."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.OpponentDoG", IDName: "opponent-do-g", Doc: "OpponentDoG computes the full set of single-opponent center-surround\nDoG channels (R+/G-, G+/R-, B+/Y-, Y+/B-) plus luminance On and Off\nfrom an LMS components tensor, as computed by RGBTensorToLMSComps,\ninto one aggregated output tensor with SingleOpponents as the rows.\nEach color channel is the positive part of the difference between\nthe center (DoG On) filter applied to the first color component\nand the surround (DoG Off) filter applied to the second, using\nvfilter.ConvDiff.", Fields: []types.Field{{Name: "DoG", Doc: "DoG filter parameters, including the Gain and OnGain used for all channels"}, {Name: "ColorGain", Doc: "extra gain for the color channels relative to luminance -- lower contrast in general"}, {Name: "Geom", Doc: "geometry of input, output"}, {Name: "DoGTsr", Doc: "DoG filter tensor -- has 3 filters (on, off, net)"}, {Name: "Out", Doc: "output: [SingleOpponentsN, Y, X]"}, {Name: "diff", Doc: "ConvDiff output, [2, Y, X]"}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.LMSComponents", IDName: "lms-components", Doc: "LMSComponents are different components of the LMS space\nincluding opponent contrasts and grey"})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.Opponents", IDName: "opponents", Doc: "Opponents enumerates the three primary opponency channels:\nWhiteBlack, RedGreen, BlueYellow\nusing colloquial \"everyday\" terms."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.SRGBToOp", IDName: "srgb-to-op", Doc: "SRGBToOp implements a lookup-table for the conversion of\nSRGB components to LMS color opponent values.\nAfter all this, it looks like the direct computation is faster\nthan the lookup table!  In any case, it is all here and reasonably\naccurate (mostly under 1.0e-4 according to testing)", Fields: []types.Field{{Name: "Levels", Doc: "number of levels in the lookup table -- linear interpolation used"}, {Name: "Table", Doc: "lookup table"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.Spectral", IDName: "spectral", Doc: "Spectral projects N-band multispectral or hyperspectral image tensors,\nwith band as the outer-most dimension [Band, Y, X] as for RGB tensors,\ninto Long, Medium, Short cone-based responses, via a linear projection\nmatrix, so that the data can go through the same LMS components and\nV1 filtering as RGB images.  Band values are assumed to be linear\n(e.g., reflectance) in the 0-1 range.", Fields: []types.Field{{Name: "Proj", Doc: "projection from the bands to the L, M, S cone responses: for each of L, M, S, the weight of each band"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/colorspace.SRGBToOp", IDName: "srgb-to-op", Doc: "SRGBToOp implements a lookup-table for the conversion of\nSRGB components to LMS color opponent values.\nThe per-pixel Lookup is slower than the direct computation,\nbut LookupRow and LookupTensor, which process whole rows of pixels\nat a time using a table with the components interleaved per cell,\nare faster.  Values are reasonably accurate (mostly under 1.0e-4\naccording to testing).", Fields: []types.Field{{Name: "Levels", Doc: "number of levels in the lookup table -- linear interpolation used"}, {Name: "Table", Doc: "lookup table"}, {Name: "cells", Doc: "lookup table with components as the inner-most dimension,\nand R as the fastest varying of the outer dimensions:\n[B][G][R][LMSComponentsN], for LookupRow"}, {Name: "once", Doc: "ensures that Init builds the tables only once"}}})
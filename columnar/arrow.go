@@ -0,0 +1,141 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"reflect"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// Arrow format constants, from the Arrow Schema.fbs and Message.fbs
+const (
+	arrowMagic     = "ARROW1"
+	arrowVersionV5 = 4
+
+	// MessageHeader union types
+	arrowSchema      = 1
+	arrowRecordBatch = 3
+
+	// Type union types
+	arrowInt           = 2
+	arrowFloatingPoint = 3
+	arrowUtf8          = 5
+
+	// FloatingPoint precisions
+	arrowSingle = 1
+	arrowDouble = 2
+)
+
+// SaveArrow saves given table to given file in the Arrow IPC file format.
+func SaveArrow(dt *table.Table, filename string) error {
+	fp, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = WriteArrow(dt, fp)
+	if cerr := fp.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteArrow writes given table to given writer in the Arrow IPC file
+// format, as one record batch, with no validity (null) bitmaps.
+func WriteArrow(dt *table.Table, w io.Writer) error {
+	cols := columns(dt)
+	nrows := dt.NumRows()
+	schema := arrowSchemaTable(cols)
+
+	var buf bytes.Buffer
+	buf.WriteString(arrowMagic + "\x00\x00")
+	arrowMessage(&buf, fbTable{fbInt16(arrowVersionV5), fbUint8(arrowSchema), fbObj(schema), fbInt64(0)}, nil)
+
+	var body bytes.Buffer
+	var nodes, bufs []byte
+	addBuf := func(data []byte) {
+		bufs = binary.LittleEndian.AppendUint64(bufs, uint64(body.Len()))
+		bufs = binary.LittleEndian.AppendUint64(bufs, uint64(len(data)))
+		body.Write(data)
+		for body.Len()%8 != 0 {
+			body.WriteByte(0)
+		}
+	}
+	for ci := range cols {
+		cl := &cols[ci]
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(nrows))
+		nodes = binary.LittleEndian.AppendUint64(nodes, 0)
+		addBuf(nil) // validity
+		if cl.kind != reflect.String {
+			addBuf(cl.values(nrows))
+			continue
+		}
+		offs := make([]byte, 4, 4*(nrows+1))
+		var data []byte
+		for _, s := range cl.strings(nrows) {
+			data = append(data, s...)
+			offs = binary.LittleEndian.AppendUint32(offs, uint32(len(data)))
+		}
+		addBuf(offs)
+		addBuf(data)
+	}
+	batch := fbTable{fbInt64(nrows), fbObj(fbStructs{len(cols), nodes}), fbObj(fbStructs{len(bufs) / 16, bufs})}
+	batchOff := buf.Len()
+	batchMeta := arrowMessage(&buf, fbTable{fbInt16(arrowVersionV5), fbUint8(arrowRecordBatch), fbObj(batch), fbInt64(body.Len())}, body.Bytes())
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}) // end of stream
+
+	// Block struct: offset, metaDataLength, padding, bodyLength
+	var block []byte
+	block = binary.LittleEndian.AppendUint64(block, uint64(batchOff))
+	block = binary.LittleEndian.AppendUint32(block, uint32(batchMeta))
+	block = binary.LittleEndian.AppendUint32(block, 0)
+	block = binary.LittleEndian.AppendUint64(block, uint64(body.Len()))
+	footer := fbFinish(fbTable{fbInt16(arrowVersionV5), fbObj(schema), fbObj(fbStructs{}), fbObj(fbStructs{1, block})})
+	buf.Write(footer)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	buf.WriteString(arrowMagic)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// arrowSchemaTable returns the Schema table for given columns
+func arrowSchemaTable(cols []column) fbTable {
+	fields := make(fbVector, len(cols))
+	for ci := range cols {
+		cl := &cols[ci]
+		var typ int
+		var tt fbTable
+		switch cl.kind {
+		case reflect.String:
+			typ, tt = arrowUtf8, fbTable{}
+		case reflect.Float32:
+			typ, tt = arrowFloatingPoint, fbTable{fbInt16(arrowSingle)}
+		case reflect.Float64:
+			typ, tt = arrowFloatingPoint, fbTable{fbInt16(arrowDouble)}
+		default:
+			typ, tt = arrowInt, fbTable{fbInt32(64), fbBool(true)}
+		}
+		// name, nullable, type_type, type, dictionary, children
+		fields[ci] = fbTable{fbObj(fbString(cl.name)), fbBool(false), fbUint8(typ), fbObj(tt), {}, fbObj(fbVector{})}
+	}
+	// endianness (little), fields
+	return fbTable{fbInt16(0), fbObj(fields)}
+}
+
+// arrowMessage writes an encapsulated message with given Message
+// table and body, returning the length of the message metadata,
+// including the continuation marker and length prefix.
+func arrowMessage(buf *bytes.Buffer, msg fbTable, body []byte) int {
+	meta := fbFinish(msg)
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(meta))))
+	buf.Write(meta)
+	buf.Write(body)
+	return 8 + len(meta)
+}
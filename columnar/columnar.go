@@ -0,0 +1,87 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// column is one flattened scalar column of a table
+type column struct {
+
+	// name of the column, with the cell index for multi-dimensional cells
+	name string
+
+	// kind of values written: String, Float32, Float64 or Int64
+	kind reflect.Kind
+
+	// source table column
+	src *tensor.Rows
+
+	// cell index within the source column
+	cell int
+}
+
+// columns returns the flattened scalar columns of given table
+func columns(dt *table.Table) []column {
+	var cols []column
+	for ci := range dt.NumColumns() {
+		src := dt.ColumnByIndex(ci)
+		var kind reflect.Kind
+		switch src.DataType() {
+		case reflect.String, reflect.Float32, reflect.Float64:
+			kind = src.DataType()
+		default:
+			kind = reflect.Int64
+		}
+		name := dt.ColumnName(ci)
+		_, ncell := src.RowCellSize()
+		if ncell == 1 {
+			cols = append(cols, column{name: name, kind: kind, src: src})
+			continue
+		}
+		for c := range ncell {
+			cols = append(cols, column{name: fmt.Sprintf("%s_%d", name, c), kind: kind, src: src, cell: c})
+		}
+	}
+	return cols
+}
+
+// values returns the little-endian fixed-size values of the column,
+// for all but String columns.
+func (cl *column) values(nrows int) []byte {
+	sz := 8
+	if cl.kind == reflect.Float32 {
+		sz = 4
+	}
+	b := make([]byte, nrows*sz)
+	for r := range nrows {
+		v := cl.src.FloatRow(r, cl.cell)
+		switch cl.kind {
+		case reflect.Float32:
+			binary.LittleEndian.PutUint32(b[r*4:], math.Float32bits(float32(v)))
+		case reflect.Float64:
+			binary.LittleEndian.PutUint64(b[r*8:], math.Float64bits(v))
+		default:
+			binary.LittleEndian.PutUint64(b[r*8:], uint64(int64(v)))
+		}
+	}
+	return b
+}
+
+// strings returns the values of a String column
+func (cl *column) strings(nrows int) []string {
+	s := make([]string, nrows)
+	for r := range nrows {
+		s[r] = cl.src.StringRow(r, cl.cell)
+	}
+	return s
+}
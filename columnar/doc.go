@@ -0,0 +1,23 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package columnar writes table.Table outputs, such as per-image
+features, tuning curves, and image statistics, to the Apache Arrow
+IPC file format (.arrow, also known as Feather v2) and Apache Parquet
+format, for analysis of large filtered datasets in DuckDB, pandas,
+polars, etc, without the size and parsing overhead of CSV.
+
+The writers are self-contained, with no external dependencies, and
+write all of the data uncompressed in a single record batch or row
+group.
+
+* Columns with multi-dimensional cells (e.g., a histogram per row)
+are flattened into one scalar column per cell value, named Name_0,
+Name_1, etc, in row-major order.
+
+* String columns are written as UTF-8 strings, float32 and float64
+columns as such, and all other column types (ints, bools) as int64.
+*/
+package columnar
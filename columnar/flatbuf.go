@@ -0,0 +1,174 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import "encoding/binary"
+
+// This is a minimal FlatBuffers serializer for the Arrow metadata.
+// Unlike the standard builders, which build back-to-front, objects
+// are written front-to-back, with each table preceded by its vtable,
+// and followed by the objects it refers to, so that all offsets to
+// referenced objects are forward, as required.
+
+// fbObject is an object that can be written into a flatbuffer
+type fbObject interface {
+
+	// write writes the object, returning its position
+	write(b *fbBuilder) int
+}
+
+// fbBuilder accumulates a flatbuffer
+type fbBuilder struct {
+	buf []byte
+}
+
+// fbFinish returns a flatbuffer with given root table,
+// padded to a multiple of 8 bytes.
+func fbFinish(root fbObject) []byte {
+	b := &fbBuilder{buf: make([]byte, 4)}
+	b.patch(0, root.write(b))
+	b.align(8)
+	return b.buf
+}
+
+// align pads the buffer to a multiple of n bytes
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+// patch sets the offset at given position to refer to position pos
+func (b *fbBuilder) patch(at, pos int) {
+	binary.LittleEndian.PutUint32(b.buf[at:], uint32(pos-at))
+}
+
+func (b *fbBuilder) uint16(v int) {
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(v))
+}
+
+func (b *fbBuilder) uint32(v int) {
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(v))
+}
+
+// fbField is a table field, which is either an inline scalar,
+// a referenced object, or absent if both are empty.
+type fbField struct {
+	scalar []byte
+	obj    fbObject
+}
+
+func fbBool(v bool) fbField {
+	if v {
+		return fbField{scalar: []byte{1}}
+	}
+	return fbField{scalar: []byte{0}}
+}
+
+func fbUint8(v int) fbField {
+	return fbField{scalar: []byte{byte(v)}}
+}
+
+func fbInt16(v int) fbField {
+	return fbField{scalar: binary.LittleEndian.AppendUint16(nil, uint16(v))}
+}
+
+func fbInt32(v int) fbField {
+	return fbField{scalar: binary.LittleEndian.AppendUint32(nil, uint32(v))}
+}
+
+func fbInt64(v int) fbField {
+	return fbField{scalar: binary.LittleEndian.AppendUint64(nil, uint64(v))}
+}
+
+func fbObj(obj fbObject) fbField {
+	return fbField{obj: obj}
+}
+
+// fbTable is a table, with the fields indexed by field id
+type fbTable []fbField
+
+func (t fbTable) write(b *fbBuilder) int {
+	offs := make([]int, len(t))
+	size := 4 // soffset to vtable
+	for i, f := range t {
+		sz := len(f.scalar)
+		if f.obj != nil {
+			sz = 4
+		}
+		if sz == 0 {
+			continue
+		}
+		size = (size + sz - 1) / sz * sz
+		offs[i] = size
+		size += sz
+	}
+	b.align(2)
+	vt := len(b.buf)
+	b.uint16(4 + 2*len(t))
+	b.uint16(size)
+	for _, off := range offs {
+		b.uint16(off)
+	}
+	b.align(8)
+	start := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[start:], uint32(start-vt))
+	for i, f := range t {
+		if f.scalar != nil {
+			copy(b.buf[start+offs[i]:], f.scalar)
+		}
+	}
+	for i, f := range t {
+		if f.obj != nil {
+			b.patch(start+offs[i], f.obj.write(b))
+		}
+	}
+	return start
+}
+
+// fbString is a string
+type fbString string
+
+func (s fbString) write(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.uint32(len(s))
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// fbStructs is a vector of n structs with 8-byte alignment,
+// with the given little-endian data.
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+func (v fbStructs) write(b *fbBuilder) int {
+	b.align(4)
+	if len(b.buf)%8 == 0 {
+		b.uint32(0)
+	}
+	pos := len(b.buf)
+	b.uint32(v.n)
+	b.buf = append(b.buf, v.data...)
+	return pos
+}
+
+// fbVector is a vector of objects
+type fbVector []fbObject
+
+func (v fbVector) write(b *fbBuilder) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.uint32(len(v))
+	b.buf = append(b.buf, make([]byte, 4*len(v))...)
+	for i, obj := range v {
+		b.patch(pos+4+4*i, obj.write(b))
+	}
+	return pos
+}
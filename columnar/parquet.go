@@ -0,0 +1,160 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"reflect"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Parquet format constants, from parquet.thrift
+const (
+	parquetMagic = "PAR1"
+
+	// physical types
+	parquetInt64     = 2
+	parquetFloat     = 4
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired  = 0
+	parquetUTF8      = 0 // converted type
+	parquetPlain     = 0 // encoding
+	parquetRLE       = 3 // encoding
+	parquetDataPage  = 0 // page type
+	parquetUncompr   = 0 // compression codec
+	parquetVersion   = 1
+	parquetCreatedBy = "github.com/emer/vision/v2 version "
+)
+
+// SaveParquet saves given table to given file in the Parquet format.
+func SaveParquet(dt *table.Table, filename string) error {
+	fp, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	err = WriteParquet(dt, fp)
+	if cerr := fp.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteParquet writes given table to given writer in the Parquet format,
+// as one row group, with one uncompressed, plain-encoded data page per
+// column, and all columns required (no nulls).
+func WriteParquet(dt *table.Table, w io.Writer) error {
+	cols := columns(dt)
+	nrows := dt.NumRows()
+	var buf bytes.Buffer
+	buf.WriteString(parquetMagic)
+	offs := make([]int, len(cols))
+	sizes := make([]int, len(cols))
+	for ci := range cols {
+		cl := &cols[ci]
+		var data []byte
+		if cl.kind == reflect.String {
+			for _, s := range cl.strings(nrows) {
+				data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+				data = append(data, s...)
+			}
+		} else {
+			data = cl.values(nrows)
+		}
+		var t thrift
+		t.beginElem() // PageHeader
+		t.i32(1, parquetDataPage)
+		t.i32(2, int32(len(data)))
+		t.i32(3, int32(len(data)))
+		t.begin(5) // DataPageHeader
+		t.i32(1, int32(nrows))
+		t.i32(2, parquetPlain)
+		t.i32(3, parquetRLE)
+		t.i32(4, parquetRLE)
+		t.end()
+		t.end()
+		offs[ci] = buf.Len()
+		buf.Write(t.buf)
+		buf.Write(data)
+		sizes[ci] = buf.Len() - offs[ci]
+	}
+
+	var t thrift
+	t.beginElem() // FileMetaData
+	t.i32(1, parquetVersion)
+	t.list(2, thriftStruct, len(cols)+1)
+	t.beginElem() // root SchemaElement
+	t.string(4, "schema")
+	t.i32(5, int32(len(cols)))
+	t.end()
+	for ci := range cols {
+		cl := &cols[ci]
+		t.beginElem()
+		t.i32(1, parquetType(cl.kind))
+		t.i32(3, parquetRequired)
+		t.string(4, cl.name)
+		if cl.kind == reflect.String {
+			t.i32(6, parquetUTF8)
+			t.begin(10) // LogicalType
+			t.begin(1)  // StringType
+			t.end()
+			t.end()
+		}
+		t.end()
+	}
+	t.i64(3, int64(nrows))
+	t.list(4, thriftStruct, 1)
+	t.beginElem() // RowGroup
+	t.list(1, thriftStruct, len(cols))
+	total := 0
+	for ci := range cols {
+		cl := &cols[ci]
+		total += sizes[ci]
+		t.beginElem() // ColumnChunk
+		t.i64(2, int64(offs[ci]))
+		t.begin(3) // ColumnMetaData
+		t.i32(1, parquetType(cl.kind))
+		t.list(2, thriftI32, 1)
+		t.elemI32(parquetPlain)
+		t.list(3, thriftBinary, 1)
+		t.elemString(cl.name)
+		t.i32(4, parquetUncompr)
+		t.i64(5, int64(nrows))
+		t.i64(6, int64(sizes[ci]))
+		t.i64(7, int64(sizes[ci]))
+		t.i64(9, int64(offs[ci]))
+		t.end()
+		t.end()
+	}
+	t.i64(2, int64(total))
+	t.i64(3, int64(nrows))
+	t.end()
+	t.string(6, parquetCreatedBy+vfilter.Version)
+	t.end()
+	buf.Write(t.buf)
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(t.buf))))
+	buf.WriteString(parquetMagic)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// parquetType returns the physical type for given column kind
+func parquetType(kind reflect.Kind) int32 {
+	switch kind {
+	case reflect.String:
+		return parquetByteArray
+	case reflect.Float32:
+		return parquetFloat
+	case reflect.Float64:
+		return parquetDouble
+	}
+	return parquetInt64
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import "encoding/binary"
+
+// Thrift compact protocol types, as used for the Parquet metadata
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thrift is a minimal Thrift compact protocol encoder
+type thrift struct {
+	buf []byte
+
+	// last field id in the current struct
+	last int
+
+	// last field ids of the enclosing structs
+	stack []int
+}
+
+func (t *thrift) varint(v uint64) {
+	t.buf = binary.AppendUvarint(t.buf, v)
+}
+
+// field writes a field header for given field id and type
+func (t *thrift) field(id int, typ byte) {
+	if d := id - t.last; d > 0 && d <= 15 {
+		t.buf = append(t.buf, byte(d<<4)|typ)
+	} else {
+		t.buf = append(t.buf, typ)
+		t.varint(uint64(int64(id)<<1 ^ int64(id)>>63))
+	}
+	t.last = id
+}
+
+func (t *thrift) i32(id int, v int32) {
+	t.field(id, thriftI32)
+	t.varint(uint64(uint32(v<<1 ^ v>>31)))
+}
+
+func (t *thrift) i64(id int, v int64) {
+	t.field(id, thriftI64)
+	t.varint(uint64(v<<1 ^ v>>63))
+}
+
+func (t *thrift) string(id int, s string) {
+	t.field(id, thriftBinary)
+	t.elemString(s)
+}
+
+// list writes the header of a list of n elements of given type,
+// which must then be written with the elem methods.
+func (t *thrift) list(id int, typ byte, n int) {
+	t.field(id, thriftList)
+	if n < 15 {
+		t.buf = append(t.buf, byte(n<<4)|typ)
+	} else {
+		t.buf = append(t.buf, 0xf0|typ)
+		t.varint(uint64(n))
+	}
+}
+
+func (t *thrift) elemI32(v int32) {
+	t.varint(uint64(uint32(v<<1 ^ v>>31)))
+}
+
+func (t *thrift) elemString(s string) {
+	t.varint(uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+// begin begins a struct field with given id, which must be ended with end
+func (t *thrift) begin(id int) {
+	t.field(id, thriftStruct)
+	t.beginElem()
+}
+
+// beginElem begins a struct that is a list element or the top-level
+// struct, which must be ended with end
+func (t *thrift) beginElem() {
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+// end ends the current struct
+func (t *thrift) end() {
+	t.buf = append(t.buf, 0)
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
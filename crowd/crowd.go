@@ -0,0 +1,161 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package crowd
+
+//go:generate core generate -add-types
+
+import (
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// Pool specifies pooling of V1 features over eccentricity-scaled
+// regions, which are ellipses centered on each location, with the
+// long axis along the radial direction from the fixation point.
+type Pool struct {
+
+	// is this stage active?
+	On bool
+
+	// fixation point, as a proportion of the X, Y size of the features (0.5, 0.5 = center)
+	Fix math32.Vector2
+
+	// Bouma factor: radius of the pooling region along the radial direction, as a proportion of eccentricity
+	Bouma float32 `default:"0.5"`
+
+	// ratio of the radial to the tangential radius of the pooling regions
+	Aspect float32 `default:"2"`
+
+	// minimum radius of the pooling regions, in units of positions -- at or below 0.5, the foveal region is not pooled at all
+	MinRadius float32 `default:"0.5"`
+
+	// random seed for Mongrel
+	Seed int64
+}
+
+func (cp *Pool) Defaults() {
+	cp.On = true
+	cp.Fix.Set(0.5, 0.5)
+	cp.Bouma = 0.5
+	cp.Aspect = 2
+	cp.MinRadius = 0.5
+}
+
+func (cp *Pool) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return cp.On
+	}
+}
+
+// Radius returns the radial and tangential radius of the pooling
+// region at given position, for features of given Y, X size,
+// along with the unit vector in the radial direction (uy, ux).
+func (cp *Pool) Radius(y, x, ny, nx int) (rad, tan, uy, ux float32) {
+	dy := float32(y) - cp.Fix.Y*float32(ny-1)
+	dx := float32(x) - cp.Fix.X*float32(nx-1)
+	ecc := math32.Hypot(dy, dx)
+	rad = math32.Max(cp.Bouma*ecc, cp.MinRadius)
+	tan = math32.Max(rad/cp.Aspect, cp.MinRadius)
+	if ecc > 0 {
+		uy, ux = dy/ecc, dx/ecc
+	} else {
+		uy, ux = 0, 1
+	}
+	return
+}
+
+// Region calls fun for each position (py, px) within the pooling
+// region of given position, for features of given Y, X size.
+func (cp *Pool) Region(y, x, ny, nx int, fun func(py, px int)) {
+	rad, tan, uy, ux := cp.Radius(y, x, ny, nx)
+	ir := int(rad)
+	irr := 1 / (rad * rad)
+	itr := 1 / (tan * tan)
+	for py := max(y-ir, 0); py <= min(y+ir, ny-1); py++ {
+		oy := float32(py - y)
+		for px := max(x-ir, 0); px <= min(x+ir, nx-1); px++ {
+			ox := float32(px - x)
+			r := oy*uy + ox*ux
+			t := ox*uy - oy*ux
+			if r*r*irr+t*t*itr <= 1 {
+				fun(py, px)
+			}
+		}
+	}
+}
+
+// Pool computes the average of the [Y, X, Rows, Angle] features in
+// given input tensor over the pooling region at each location, into out.
+func (cp *Pool) Pool(in, out *tensor.Float32) {
+	cp.run(in, out, nil)
+}
+
+// Mongrel sets the features at each location in out to those at a
+// random location within its pooling region in the [Y, X, Rows, Angle]
+// input tensor, using random numbers from Seed, so the same Seed
+// always produces the same scrambling.
+func (cp *Pool) Mongrel(in, out *tensor.Float32) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	rnd := rand.New(rand.NewSource(cp.Seed))
+	rvals := make([]float32, ny*nx)
+	for i := range rvals {
+		rvals[i] = rnd.Float32()
+	}
+	cp.run(in, out, rvals)
+}
+
+// run runs Pool (rvals == nil) or Mongrel, using given random values
+// per position, in parallel over rows.
+func (cp *Pool) run(in, out *tensor.Float32, rvals []float32) {
+	ny := in.DimSize(0)
+	out.SetShapeSizes(in.Shape().Sizes...)
+	nproc.Run("CrowdPool", ny, func(st, n int) {
+		cp.poolThr(st, n, in, out, rvals)
+	})
+}
+
+// poolThr is per-thread implementation
+func (cp *Pool) poolThr(yst, nyr int, in, out *tensor.Float32, rvals []float32) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	nf := in.DimSize(2) * in.DimSize(3)
+	for y := yst; y < yst+nyr; y++ {
+		for x := 0; x < nx; x++ {
+			ov := out.Values[(y*nx+x)*nf : (y*nx+x+1)*nf]
+			if rvals != nil {
+				n := 0
+				cp.Region(y, x, ny, nx, func(py, px int) { n++ })
+				si := min(int(rvals[y*nx+x]*float32(n)), n-1)
+				n = 0
+				cp.Region(y, x, ny, nx, func(py, px int) {
+					if n == si {
+						copy(ov, in.Values[(py*nx+px)*nf:(py*nx+px+1)*nf])
+					}
+					n++
+				})
+				continue
+			}
+			clear(ov)
+			n := 0
+			cp.Region(y, x, ny, nx, func(py, px int) {
+				for i, v := range in.Values[(py*nx+px)*nf : (py*nx+px+1)*nf] {
+					ov[i] += v
+				}
+				n++
+			})
+			norm := 1 / float32(n)
+			for i := range ov {
+				ov[i] *= norm
+			}
+		}
+	}
+}
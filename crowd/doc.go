@@ -0,0 +1,24 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package crowd models visual crowding in the periphery, by pooling V1
+features over regions whose size scales with eccentricity from the
+fixation point, according to Bouma's law: the radius of the pooling
+region is about half the eccentricity, and elongated radially.
+
+* Pool.Pool computes the average features within each region,
+which is the summary-statistic representation that causes crowding.
+
+* Pool.Mongrel replaces the features at each location with those
+from a random location within its pooling region, producing a
+"mongrel"-style scrambled representation that preserves the local
+feature statistics but not their arrangement, which is intact near
+fixation and increasingly jumbled in the periphery.
+
+Features are [Y, X, Rows, Angle] tensors, as in V1 simple and complex
+cell outputs (e.g., V1All), and eccentricity is measured in units of
+the Y, X positions.
+*/
+package crowd
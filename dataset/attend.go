@@ -0,0 +1,92 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"image"
+	"image/draw"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Attend crops and rescales attention windows around object bounding
+// boxes (e.g., from dataset annotations), with a context margin, and
+// runs a FilterFunc (e.g., the V1 pipeline) on each window, producing
+// per-object feature tensors, as is standard for object-centric
+// recognition models.
+type Attend struct {
+
+	// context margin around each box, as a proportion of the box size added on each side
+	Margin float32 `default:"0.2"`
+
+	// expand each window to a square, around the same center, so objects are not distorted by rescaling
+	Square bool `default:"true"`
+
+	// loader settings for converting windows to tensors: Size is the window size, along with PadWidth, Color and TopZero
+	Loader Loader
+}
+
+func (at *Attend) Defaults() {
+	at.Margin = 0.2
+	at.Square = true
+	at.Loader.Defaults()
+}
+
+// Window returns the attention window for given box, including
+// the Margin and Square settings, clipped to given image bounds.
+func (at *Attend) Window(box, bounds image.Rectangle) image.Rectangle {
+	sz := box.Size()
+	w := float32(sz.X) * (1 + 2*at.Margin)
+	h := float32(sz.Y) * (1 + 2*at.Margin)
+	if at.Square {
+		w = math32.Max(w, h)
+		h = w
+	}
+	cx := 0.5 * float32(box.Min.X+box.Max.X)
+	cy := 0.5 * float32(box.Min.Y+box.Max.Y)
+	win := image.Rect(int(math32.Round(cx-0.5*w)), int(math32.Round(cy-0.5*h)), int(math32.Round(cx+0.5*w)), int(math32.Round(cy+0.5*h)))
+	return win.Intersect(bounds)
+}
+
+// Crop returns the attention window image for given box, at its
+// original size, which the Loader ToTensor rescales to the Loader Size.
+// If the window is entirely outside of the image, a blank image
+// of the Loader Size is returned.
+func (at *Attend) Crop(img image.Image, box image.Rectangle) image.Image {
+	win := at.Window(box, img.Bounds())
+	if win.Empty() {
+		return image.NewRGBA(image.Rectangle{Max: at.Loader.Size})
+	}
+	crop := image.NewRGBA(image.Rectangle{Max: win.Size()})
+	draw.Draw(crop, crop.Bounds(), img, win.Min, draw.Src)
+	return crop
+}
+
+// Filter crops the attention window for each box, converts it to a
+// tensor, and runs given filter function on it, with the per-object
+// outputs collected in out, which has the box index as the outer-most
+// dimension, and the filter output shape as the inner dimensions.
+// The filter is run sequentially over boxes, so it can use shared state.
+// If filter is nil, the window image tensors are collected.
+func (at *Attend) Filter(img image.Image, boxes []image.Rectangle, filter FilterFunc, out *tensor.Float32) {
+	if len(boxes) == 0 {
+		out.SetShapeSizes(0)
+		return
+	}
+	var wimg, wout tensor.Float32
+	for i, box := range boxes {
+		at.Loader.ToTensor(at.Crop(img, box), &wimg)
+		res := &wimg
+		if filter != nil {
+			filter(&wimg, &wout)
+			res = &wout
+		}
+		if i == 0 {
+			out.SetShapeSizes(append([]int{len(boxes)}, res.Shape().Sizes...)...)
+		}
+		copy(out.SubSpace(i).(*tensor.Float32).Values, res.Values)
+	}
+}
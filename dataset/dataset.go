@@ -0,0 +1,324 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Standard split names, used by the loaders and Env
+const (
+	Train = "train"
+	Test  = "test"
+)
+
+// ImageExts are the file extensions (lower case) recognized as images
+// when scanning directories.
+var ImageExts = []string{".png", ".jpg", ".jpeg", ".gif", ".bmp", ".tif", ".tiff", ".webp"}
+
+// IsImage returns true if given file name has one of the ImageExts
+func IsImage(fname string) bool {
+	return slices.Contains(ImageExts, strings.ToLower(filepath.Ext(fname)))
+}
+
+// Sample is one labeled image in a Dataset
+type Sample struct {
+
+	// path to the image file
+	File string
+
+	// class label name
+	Label string
+
+	// index of the Label in the Dataset Classes
+	Class int
+
+	// name of the split this sample belongs to, e.g., train or test -- empty if not split
+	Split string
+
+	// object instance name within the class, for datasets with multiple views of each object (e.g., CU3D)
+	Object string
+
+	// any further metadata fields, e.g., from the CSV manifest or file name
+	Meta map[string]string
+}
+
+// Dataset is a list of labeled image Samples, with the sorted
+// list of Classes that the Sample Class indexes refer to.
+type Dataset struct {
+
+	// name of the dataset
+	Name string
+
+	// sorted list of class label names
+	Classes []string
+
+	// all of the samples
+	Samples []Sample
+}
+
+// NumClasses returns the number of classes
+func (ds *Dataset) NumClasses() int {
+	return len(ds.Classes)
+}
+
+// SetClasses sets the Classes from the sorted unique Labels of
+// the Samples, and sets the Class index for each Sample.
+func (ds *Dataset) SetClasses() {
+	ds.Classes = nil // new slice: Classes may be shared with a Split
+	for i := range ds.Samples {
+		ds.Classes = append(ds.Classes, ds.Samples[i].Label)
+	}
+	sort.Strings(ds.Classes)
+	ds.Classes = slices.Compact(ds.Classes)
+	for i := range ds.Samples {
+		sm := &ds.Samples[i]
+		sm.Class, _ = slices.BinarySearch(ds.Classes, sm.Label)
+	}
+}
+
+// Split returns a new Dataset with the Samples in given split,
+// with a copy of the same Classes as this one.
+func (ds *Dataset) Split(split string) *Dataset {
+	sd := &Dataset{Name: ds.Name + "_" + split, Classes: slices.Clone(ds.Classes)}
+	for _, sm := range ds.Samples {
+		if sm.Split == split {
+			sd.Samples = append(sd.Samples, sm)
+		}
+	}
+	return sd
+}
+
+// SplitRandom randomly assigns each Sample to the Test split
+// with probability testFrac, and to the Train split otherwise,
+// using given random source (nil = global source).
+func (ds *Dataset) SplitRandom(testFrac float64, rnd *rand.Rand) {
+	for i := range ds.Samples {
+		var p float64
+		if rnd != nil {
+			p = rnd.Float64()
+		} else {
+			p = rand.Float64()
+		}
+		if p < testFrac {
+			ds.Samples[i].Split = Test
+		} else {
+			ds.Samples[i].Split = Train
+		}
+	}
+}
+
+// SplitObjects assigns the last nTest Object instances (in sorted
+// order) of each class to the Test split, and the rest to the Train
+// split, which is the standard way of testing generalization to novel
+// objects in CU3D-style datasets.
+func (ds *Dataset) SplitObjects(nTest int) {
+	objs := make(map[string][]string)
+	for _, sm := range ds.Samples {
+		objs[sm.Label] = append(objs[sm.Label], sm.Object)
+	}
+	test := make(map[string]bool)
+	for lbl, ol := range objs {
+		sort.Strings(ol)
+		ol = slices.Compact(ol)
+		for _, o := range ol[max(len(ol)-nTest, 0):] {
+			test[lbl+"/"+o] = true
+		}
+	}
+	for i := range ds.Samples {
+		sm := &ds.Samples[i]
+		if test[sm.Label+"/"+sm.Object] {
+			sm.Split = Test
+		} else {
+			sm.Split = Train
+		}
+	}
+}
+
+// Files returns the list of Sample files
+func (ds *Dataset) Files() []string {
+	fl := make([]string, len(ds.Samples))
+	for i, sm := range ds.Samples {
+		fl[i] = sm.File
+	}
+	return fl
+}
+
+// FromDirs loads a directory-per-class dataset, where each
+// sub-directory of root is a class with the image files for that
+// class within it.  If root has train and / or test sub-directories,
+// then each of these is loaded as a directory-per-class set, with the
+// Split set accordingly.
+func FromDirs(root string) (*Dataset, error) {
+	ds := &Dataset{Name: filepath.Base(root)}
+	split := false
+	for _, sp := range []string{Train, Test} {
+		sd := filepath.Join(root, sp)
+		if st, err := os.Stat(sd); err == nil && st.IsDir() {
+			split = true
+			if err := ds.addClassDirs(sd, sp); err != nil {
+				return ds, err
+			}
+		}
+	}
+	if !split {
+		if err := ds.addClassDirs(root, ""); err != nil {
+			return ds, err
+		}
+	}
+	ds.SetClasses()
+	return ds, nil
+}
+
+// addClassDirs adds the images in each class sub-directory of dir
+func (ds *Dataset) addClassDirs(dir, split string) error {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		if !de.IsDir() {
+			continue
+		}
+		cdir := filepath.Join(dir, de.Name())
+		err := filepath.WalkDir(cdir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !IsImage(path) {
+				return nil
+			}
+			ds.Samples = append(ds.Samples, Sample{File: path, Label: de.Name(), Split: split})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromCSV loads a dataset from a CSV manifest file, with a header row
+// naming the columns, which must include File (or Path) and Label
+// (or Class), and can include Split and Object -- all other columns
+// are stored in the Sample Meta.  Relative file paths are relative
+// to the directory containing the manifest.
+func FromCSV(fname string) (*Dataset, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rd := csv.NewReader(f)
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	recs, err := rd.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("dataset.FromCSV: %s is empty", fname)
+	}
+	fcol, lcol := -1, -1
+	hdr := recs[0]
+	for i, h := range hdr {
+		switch strings.ToLower(h) {
+		case "file", "path":
+			fcol = i
+		case "label", "class":
+			lcol = i
+		}
+	}
+	if fcol < 0 || lcol < 0 {
+		return nil, fmt.Errorf("dataset.FromCSV: %s must have File and Label columns, has: %v", fname, hdr)
+	}
+	ds := &Dataset{Name: strings.TrimSuffix(filepath.Base(fname), filepath.Ext(fname))}
+	dir := filepath.Dir(fname)
+	for _, rec := range recs[1:] {
+		if len(rec) <= max(fcol, lcol) {
+			continue
+		}
+		sm := Sample{File: rec[fcol], Label: rec[lcol]}
+		if !filepath.IsAbs(sm.File) {
+			sm.File = filepath.Join(dir, sm.File)
+		}
+		for i, v := range rec {
+			if i == fcol || i == lcol || i >= len(hdr) {
+				continue
+			}
+			switch strings.ToLower(hdr[i]) {
+			case "split":
+				sm.Split = strings.ToLower(v)
+			case "object":
+				sm.Object = v
+			default:
+				if sm.Meta == nil {
+					sm.Meta = make(map[string]string)
+				}
+				sm.Meta[hdr[i]] = v
+			}
+		}
+		ds.Samples = append(ds.Samples, sm)
+	}
+	ds.SetClasses()
+	return ds, nil
+}
+
+// CU3DFields are the default names of the metadata fields encoded in
+// CU3D-style file names, after the class and object fields,
+// e.g., car_003_r045_e15_l2.png: rotation, elevation, lighting.
+var CU3DFields = []string{"Rot", "Elev", "Light"}
+
+// FromCU3D loads a CU3D-style dataset, where metadata is encoded in
+// the image file names (anywhere under root) as fields separated by _,
+// with the class label as the first field, the object instance as the
+// second field, and any further fields stored in the Sample Meta under
+// the given field names (CU3DFields if nil; Field<n> beyond those).
+// Use SplitObjects to split into train and test by object instance.
+func FromCU3D(root string, fields []string) (*Dataset, error) {
+	if fields == nil {
+		fields = CU3DFields
+	}
+	ds := &Dataset{Name: filepath.Base(root)}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !IsImage(path) {
+			return nil
+		}
+		base := strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		fl := strings.Split(base, "_")
+		if len(fl) < 2 {
+			return nil
+		}
+		sm := Sample{File: path, Label: fl[0], Object: fl[1]}
+		if len(fl) > 2 {
+			sm.Meta = make(map[string]string)
+			for i, v := range fl[2:] {
+				if i < len(fields) {
+					sm.Meta[fields[i]] = v
+				} else {
+					sm.Meta[fmt.Sprintf("Field%d", i+2)] = v
+				}
+			}
+		}
+		ds.Samples = append(ds.Samples, sm)
+		return nil
+	})
+	if err != nil {
+		return ds, err
+	}
+	ds.SetClasses()
+	return ds, nil
+}
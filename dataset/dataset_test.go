@@ -0,0 +1,37 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitSetClasses(t *testing.T) {
+	ds := &Dataset{Name: "test"}
+	for _, lb := range []string{"cat", "dog", "emu", "fox"} {
+		ds.Samples = append(ds.Samples, Sample{File: lb + ".png", Label: lb, Split: Train})
+	}
+	ds.Samples = append(ds.Samples, Sample{File: "emu2.png", Label: "emu", Split: Test})
+	ds.SetClasses()
+	classes := slices.Clone(ds.Classes)
+
+	sd := ds.Split(Test)
+	if !slices.Equal(sd.Classes, classes) {
+		t.Errorf("split classes: %v != %v", sd.Classes, classes)
+	}
+	sd.SetClasses()
+	if !slices.Equal(sd.Classes, []string{"emu"}) {
+		t.Errorf("split SetClasses: %v", sd.Classes)
+	}
+	if !slices.Equal(ds.Classes, classes) {
+		t.Errorf("parent classes changed by split SetClasses: %v != %v", ds.Classes, classes)
+	}
+	for i, sm := range ds.Samples {
+		if ds.Classes[sm.Class] != sm.Label {
+			t.Errorf("parent sample %d class: %d = %s != %s", i, sm.Class, ds.Classes[sm.Class], sm.Label)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package dataset provides image loading for dataset pipelines, which
+feed images into the vision filtering stages.
+
+* Loader decodes, resizes and converts upcoming images to tensors
+using a pool of worker goroutines and a bounded prefetch channel,
+hiding the I/O latency while the current image is being filtered.
+
+* Dataset is a list of labeled image Samples, with train / test splits,
+loaded from common benchmark formats: a directory per class (FromDirs),
+a CSV manifest (FromCSV), or CU3D-style file name metadata (FromCU3D).
+
+* Env is an env.Env that iterates over a Dataset split, computing V1All
+features from each image with a user-supplied FilterFunc, along with
+a localist class Label.
+
+* Attend crops and rescales attention windows around object bounding
+boxes, with a context margin, and runs a FilterFunc on each window,
+producing per-object feature tensors.
+
+* Patches is an env.Env that samples many random fixed-size patches
+from each image, optionally augmented by random vxform transforms,
+computing features for each patch with a FilterFunc, and streaming
+them into a table.Table with WriteTable, for sparse coding and
+self-supervised receptive field learning.
+*/
+package dataset
@@ -0,0 +1,183 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+)
+
+// FilterFunc is a function that computes the V1All output features
+// (or any other features) from an input image tensor, as produced by
+// the Loader.
+type FilterFunc func(img, out *tensor.Float32)
+
+// Env is an env.Env that iterates over the Samples of a Dataset
+// split, in sequential or permuted order, loading the images with a
+// prefetching Loader, and computing output features with a
+// FilterFunc, so that recognition experiments need no custom I/O code.
+// States are: Image = input image tensor, V1All = Filter output,
+// Label = localist (one-hot) class label over the Dataset Classes.
+type Env struct {
+
+	// name of this environment, usually Train vs. Test
+	Name string
+
+	// the dataset to iterate over -- if Split is set, only samples in that split are used
+	Data *Dataset `display:"-"`
+
+	// if set, only the samples in this split of Data are used, e.g., train or test
+	Split string
+
+	// present samples in sequential order -- otherwise permuted random order, re-permuted every epoch
+	Sequential bool
+
+	// function computing the V1All features from the image tensor -- if nil, V1All is the image tensor
+	Filter FilterFunc `display:"-"`
+
+	// image loader, with settings for image size, padding, color -- Files are set automatically
+	Loader Loader
+
+	// permuted order of samples for the current epoch
+	Order []int `display:"-"`
+
+	// current epoch
+	Epoch env.Counter `display:"inline"`
+
+	// current trial within the epoch
+	Trial env.Counter `display:"inline"`
+
+	// current sample
+	Cur Sample `edit:"-"`
+
+	// current input image as tensor
+	ImageTsr tensor.Float32 `display:"no-inline"`
+
+	// current filter output features
+	V1AllTsr tensor.Float32 `display:"no-inline"`
+
+	// current localist class label
+	LabelTsr tensor.Float32 `display:"no-inline"`
+
+	// the samples in use, from Data and Split
+	samples []Sample
+}
+
+func (ev *Env) Defaults() {
+	ev.Loader.Defaults()
+}
+
+// Config configures the environment to use given dataset, split,
+// and filter function
+func (ev *Env) Config(ds *Dataset, split string, filter FilterFunc) {
+	ev.Data = ds
+	ev.Split = split
+	ev.Filter = filter
+}
+
+func (ev *Env) Label() string { return ev.Name }
+
+func (ev *Env) String() string {
+	return fmt.Sprintf("%s_%s", ev.Cur.Label, ev.Cur.Object)
+}
+
+func (ev *Env) Init(run int) {
+	ev.samples = ev.Data.Samples
+	if ev.Split != "" {
+		ev.samples = ev.Data.Split(ev.Split).Samples
+	}
+	ev.Epoch.Init()
+	ev.Trial.Init()
+	ev.Trial.Max = len(ev.samples)
+	ev.Trial.Cur = -1
+	ev.Order = rand.Perm(len(ev.samples))
+	ev.startEpoch()
+}
+
+// startEpoch starts loading the samples for the current epoch
+func (ev *Env) startEpoch() {
+	fl := make([]string, len(ev.samples))
+	for i := range fl {
+		fl[i] = ev.samples[ev.Row(i)].File
+	}
+	ev.Loader.Files = fl
+	ev.Loader.Start()
+}
+
+// Row returns the sample index for given trial, based on
+// Sequential or permuted Order
+func (ev *Env) Row(trial int) int {
+	if ev.Sequential {
+		return trial
+	}
+	return ev.Order[trial]
+}
+
+func (ev *Env) Step() bool {
+	if len(ev.samples) == 0 {
+		return false
+	}
+	ev.Epoch.Same()
+	nerr := 0
+	for {
+		it, ok := ev.Loader.Next()
+		if !ok {
+			if nerr >= len(ev.samples) {
+				log.Println("dataset.Env.Step: no images could be loaded")
+				return false
+			}
+			ev.Epoch.Incr()
+			ev.Trial.Cur = -1
+			rand.Shuffle(len(ev.Order), func(i, j int) { ev.Order[i], ev.Order[j] = ev.Order[j], ev.Order[i] })
+			ev.startEpoch()
+			continue
+		}
+		ev.Trial.Set(it.Index)
+		if it.Err != nil {
+			log.Println(it.Err)
+			nerr++
+			continue
+		}
+		ev.Cur = ev.samples[ev.Row(it.Index)]
+		tensor.SetShapeFrom(&ev.ImageTsr, it.Tensor)
+		ev.ImageTsr.CopyFrom(it.Tensor)
+		ev.Loader.Release(it)
+		break
+	}
+	if ev.Filter != nil {
+		ev.Filter(&ev.ImageTsr, &ev.V1AllTsr)
+	} else {
+		tensor.SetShapeFrom(&ev.V1AllTsr, &ev.ImageTsr)
+		ev.V1AllTsr.CopyFrom(&ev.ImageTsr)
+	}
+	ev.LabelTsr.SetShapeSizes(ev.Data.NumClasses())
+	ev.LabelTsr.SetZeros()
+	ev.LabelTsr.Values[ev.Cur.Class] = 1
+	return true
+}
+
+func (ev *Env) State(element string) tensor.Values {
+	switch element {
+	case "Image":
+		return &ev.ImageTsr
+	case "V1All":
+		return &ev.V1AllTsr
+	case "Label":
+		return &ev.LabelTsr
+	}
+	log.Println("dataset.Env.State -- could not find element:", element)
+	return nil
+}
+
+func (ev *Env) Action(element string, input tensor.Values) {
+	// nop
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*Env)(nil)
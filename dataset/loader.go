@@ -0,0 +1,254 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"sync"
+
+	"cogentcore.org/core/base/iox/imagex"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Item is one loaded image, as delivered by the Loader
+type Item struct {
+
+	// index of the file in the Loader Files list
+	Index int
+
+	// file name of the image
+	File string
+
+	// the decoded image, resized to the Loader Size if set -- a resized image is opaque, with any transparency composited according to the Loader Alpha
+	Image image.Image
+
+	// the decoded image, at its original size
+	Orig image.Image
+
+	// the image converted to a padded tensor: grey [Y, X] or RGB [3, Y, X] -- obtained from vfilter.Pool, and can be returned with Loader.Release
+	Tensor *tensor.Float32
+
+	// any error that occurred in loading the image -- Image, Orig and Tensor are nil if so
+	Err error
+}
+
+// Loader is a parallel prefetching image loader, which decodes, resizes
+// and converts upcoming images to tensors using a pool of worker
+// goroutines, while the current image is being filtered, thereby hiding
+// the I/O latency in dataset pipelines.  Items are delivered in the
+// order of Files, and at most Prefetch items are loaded ahead.
+type Loader struct {
+
+	// list of image files to load, in order
+	Files []string
+
+	// target image size to use -- images will be rescaled to this size
+	Size image.Point
+
+	// interpolation used to rescale images to Size
+	Resize vfilter.ResizeModes
+
+	// amount of padding to add on all sides of the tensor -- padding is filled according to Pad
+	PadWidth int
+
+	// if true, convert to an RGB [3, Y, X] tensor, otherwise a grey [Y, X] tensor
+	Color bool
+
+	// retain the Y=0 value at the top of the tensor -- otherwise it is flipped with Y=0 at the bottom
+	TopZero bool
+
+	// how the alpha (transparency) channel of images is handled -- compositing over a background avoids spurious edges around transparent regions
+	Alpha vfilter.Alpha `display:"inline"`
+
+	// how to fill the padding -- Reflect avoids the spurious edges at the borders of natural images produced by the default Wrap
+	Pad vfilter.EdgeModes
+
+	// number of worker goroutines -- if 0, the number of CPUs is used
+	NWorkers int
+
+	// maximum number of items to load ahead of the current one
+	Prefetch int `default:"8"`
+
+	// channel of per-item result channels, in order
+	pending chan chan *Item
+
+	// closed to stop loading
+	done chan struct{}
+
+	// waits for all goroutines to exit
+	wg sync.WaitGroup
+}
+
+func (ld *Loader) Defaults() {
+	ld.Size = image.Point{128, 128}
+	ld.Prefetch = 8
+	ld.Alpha.Defaults()
+}
+
+// job is a single image to load
+type job struct {
+	idx int
+	res chan *Item
+}
+
+// Start starts loading the Files from the beginning, in the background.
+// Any previous loading is stopped first.
+func (ld *Loader) Start() {
+	ld.Stop()
+	nw := ld.NWorkers
+	if nw <= 0 {
+		nw = nproc.NumCPU()
+	}
+	pf := max(ld.Prefetch, 1)
+	ld.pending = make(chan chan *Item, pf)
+	ld.done = make(chan struct{})
+	jobs := make(chan job, pf)
+	for w := 0; w < nw; w++ {
+		ld.wg.Add(1)
+		go func() {
+			defer ld.wg.Done()
+			for jb := range jobs {
+				jb.res <- ld.Load(jb.idx)
+			}
+		}()
+	}
+	ld.wg.Add(1)
+	go func() {
+		defer ld.wg.Done()
+		defer close(jobs)
+		defer close(ld.pending)
+		for i := range ld.Files {
+			res := make(chan *Item, 1)
+			select {
+			case ld.pending <- res:
+			case <-ld.done:
+				return
+			}
+			select {
+			case jobs <- job{idx: i, res: res}:
+			case <-ld.done:
+				return
+			}
+		}
+	}()
+}
+
+// Next returns the next loaded item, waiting for it if it is not yet
+// loaded.  Returns false when there are no more items (or Start
+// has not been called).
+func (ld *Loader) Next() (*Item, bool) {
+	if ld.pending == nil {
+		return nil, false
+	}
+	res, ok := <-ld.pending
+	if !ok {
+		return nil, false
+	}
+	return <-res, true
+}
+
+// Stop stops any loading in progress and waits for all of the
+// worker goroutines to exit.
+func (ld *Loader) Stop() {
+	if ld.done == nil {
+		return
+	}
+	close(ld.done)
+	for range ld.pending { // drain until the feeder exits
+	}
+	ld.wg.Wait()
+	ld.done = nil
+	ld.pending = nil
+}
+
+// Load loads the image at given index in Files, synchronously.
+// This is called by the worker goroutines, and can also be used directly.
+func (ld *Loader) Load(idx int) *Item {
+	it := &Item{Index: idx, File: ld.Files[idx]}
+	img, _, err := imagex.Open(it.File)
+	if err != nil {
+		it.Err = err
+		return it
+	}
+	it.Orig = img
+	it.Image = ld.ResizeImage(img)
+	sz := it.Image.Bounds().Size()
+	sy, sx := sz.Y+2*ld.PadWidth, sz.X+2*ld.PadWidth
+	if ld.Color {
+		it.Tensor = vfilter.Pool.Get(3, sy, sx)
+	} else {
+		it.Tensor = vfilter.Pool.Get(sy, sx)
+	}
+	ld.ToTensor(it.Image, it.Tensor)
+	return it
+}
+
+// Release returns the Tensor of given item to the vfilter.Pool
+// for reuse by subsequent loads, so that steady-state loading does
+// not allocate tensors.  The item Tensor must not be used after this.
+func (ld *Loader) Release(it *Item) {
+	vfilter.Pool.Put(it.Tensor)
+	it.Tensor = nil
+}
+
+// tensorSize returns the unpadded size of the tensor for given image:
+// Size if set, otherwise the image size.
+func (ld *Loader) tensorSize(img image.Image) image.Point {
+	if ld.Size.X <= 0 || ld.Size.Y <= 0 {
+		return img.Bounds().Size()
+	}
+	return ld.Size
+}
+
+// ResizeImage returns given image resized to Size, if set and it is
+// not already that size, with the Resize interpolation on the tensor
+// values.  The resized image is opaque, with any transparency
+// composited according to Alpha.
+func (ld *Loader) ResizeImage(img image.Image) image.Image {
+	sz := img.Bounds().Size()
+	tsz := ld.tensorSize(img)
+	if tsz == sz {
+		return img
+	}
+	raw := vfilter.Pool.Get(3, sz.Y, sz.X)
+	rs := vfilter.Pool.Get(3, tsz.Y, tsz.X)
+	defer vfilter.Pool.Put(raw, rs)
+	vfilter.RGBToTensorAlpha(img, raw, 0, true, &ld.Alpha, nil)
+	vfilter.ResizeTensor(raw, rs, tsz, 0, ld.Resize) // shapes are always valid
+	for i, v := range rs.Values {
+		rs.Values[i] = math32.Clamp(v, 0, 1)
+	}
+	return vfilter.RGBTensorToImage(nil, rs, 0, true)
+}
+
+// ToTensor converts given image to a tensor according to the
+// Color, PadWidth, TopZero, Alpha, and Pad settings, resizing it
+// to Size (if set) with the Resize interpolation, directly on
+// the tensor values.
+func (ld *Loader) ToTensor(img image.Image, tsr *tensor.Float32) {
+	sz := img.Bounds().Size()
+	if tsz := ld.tensorSize(img); tsz != sz {
+		var raw *tensor.Float32
+		if ld.Color {
+			raw = vfilter.Pool.Get(3, sz.Y, sz.X)
+			vfilter.RGBToTensorAlpha(img, raw, 0, ld.TopZero, &ld.Alpha, nil)
+		} else {
+			raw = vfilter.Pool.Get(sz.Y, sz.X)
+			vfilter.RGBToGreyAlpha(img, raw, 0, ld.TopZero, &ld.Alpha, nil)
+		}
+		vfilter.ResizeTensor(raw, tsr, tsz, ld.PadWidth, ld.Resize) // shapes are always valid
+		vfilter.Pool.Put(raw)
+	} else if ld.Color {
+		vfilter.RGBToTensorAlpha(img, tsr, ld.PadWidth, ld.TopZero, &ld.Alpha, nil)
+	} else {
+		vfilter.RGBToGreyAlpha(img, tsr, ld.PadWidth, ld.TopZero, &ld.Alpha, nil)
+	}
+	vfilter.Pad(tsr, ld.PadWidth, ld.Pad)
+}
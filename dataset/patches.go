@@ -0,0 +1,317 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dataset
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"log"
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/vision/v2/vxform"
+)
+
+// Patches is an env.Env that samples many random fixed-size patches
+// from each image of a Dataset split, optionally augmented by random
+// vxform transforms, and computes output features for each patch with
+// a FilterFunc, which is the standard input regime for sparse coding
+// and self-supervised learning of V1-like receptive fields.
+// States are: Image = patch tensor, V1All = Filter output,
+// Label = localist (one-hot) class label of the source image.
+// Log and WriteTable stream the patches into a table.Table.
+type Patches struct {
+
+	// name of this environment, usually Train vs. Test
+	Name string
+
+	// the dataset to sample from -- if Split is set, only samples in that split are used
+	Data *Dataset `display:"-"`
+
+	// if set, only the samples in this split of Data are used, e.g., train or test
+	Split string
+
+	// present images in sequential order -- otherwise permuted random order, re-permuted every epoch
+	Sequential bool
+
+	// size of the patches to sample
+	Size image.Point
+
+	// number of patches to sample from each image, before moving on to the next image
+	NPerImage int `default:"50"`
+
+	// apply a random XForm transform to each patch
+	Augment bool
+
+	// size of the region around each patch that is transformed for Augment, as a multiple of the patch Size, so that rotations and reductions fill the patch with actual image content
+	Context float32 `default:"1.5"`
+
+	// random transforms for Augment -- translations are proportions of the context region half-size
+	XForm vxform.Rand `display:"inline"`
+
+	// function computing the V1All features from the patch tensor -- if nil, V1All is the patch tensor
+	Filter FilterFunc `display:"-"`
+
+	// image loader, with settings for image size, padding, color -- Files are set automatically.  Images are loaded at their native size if Size is 0, and the PadWidth, Color and TopZero settings are used for the patch tensors
+	Loader Loader
+
+	// permuted order of images for the current epoch
+	Order []int `display:"-"`
+
+	// current epoch
+	Epoch env.Counter `display:"inline"`
+
+	// current trial (patch) within the epoch: NPerImage trials per image
+	Trial env.Counter `display:"inline"`
+
+	// current sample
+	Cur Sample `edit:"-"`
+
+	// position of the upper-left corner of the current patch within the (resized) image, for the center of the context region if Augment
+	Pos image.Point `edit:"-"`
+
+	// current transform, if Augment
+	XF vxform.XForm `edit:"-"`
+
+	// current patch as tensor
+	ImageTsr tensor.Float32 `display:"no-inline"`
+
+	// current filter output features
+	V1AllTsr tensor.Float32 `display:"no-inline"`
+
+	// current localist class label
+	LabelTsr tensor.Float32 `display:"no-inline"`
+
+	// the samples in use, from Data and Split
+	samples []Sample
+
+	// current image that patches are sampled from
+	img image.Image
+
+	// index of the current image in the epoch
+	imgIndex int
+
+	// number of patches sampled from the current image
+	nPatch int
+}
+
+func (ev *Patches) Defaults() {
+	ev.Size = image.Point{16, 16}
+	ev.NPerImage = 50
+	ev.Context = 1.5
+	ev.XForm.Scale.Set(0.8, 1.25)
+	ev.XForm.LogScale = true
+	ev.XForm.Rot.Set(-15, 15)
+	ev.Loader.Defaults()
+	ev.Loader.Size = image.Point{}
+}
+
+func (ev *Patches) ShouldDisplay(field string) bool {
+	switch field {
+	case "Context", "XForm":
+		return ev.Augment
+	default:
+		return true
+	}
+}
+
+// Config configures the environment to use given dataset, split,
+// and filter function
+func (ev *Patches) Config(ds *Dataset, split string, filter FilterFunc) {
+	ev.Data = ds
+	ev.Split = split
+	ev.Filter = filter
+}
+
+func (ev *Patches) Label() string { return ev.Name }
+
+func (ev *Patches) String() string {
+	return fmt.Sprintf("%s_%s_%d_%d", ev.Cur.Label, ev.Cur.Object, ev.Pos.X, ev.Pos.Y)
+}
+
+func (ev *Patches) Init(run int) {
+	ev.samples = ev.Data.Samples
+	if ev.Split != "" {
+		ev.samples = ev.Data.Split(ev.Split).Samples
+	}
+	ev.NPerImage = max(ev.NPerImage, 1)
+	ev.Epoch.Init()
+	ev.Trial.Init()
+	ev.Trial.Max = len(ev.samples) * ev.NPerImage
+	ev.Trial.Cur = -1
+	ev.Order = rand.Perm(len(ev.samples))
+	ev.img = nil
+	ev.startEpoch()
+}
+
+// startEpoch starts loading the images for the current epoch
+func (ev *Patches) startEpoch() {
+	fl := make([]string, len(ev.samples))
+	for i := range fl {
+		fl[i] = ev.samples[ev.Row(i)].File
+	}
+	ev.Loader.Files = fl
+	ev.Loader.Start()
+}
+
+// Row returns the sample index for given image index in the epoch,
+// based on Sequential or permuted Order
+func (ev *Patches) Row(idx int) int {
+	if ev.Sequential {
+		return idx
+	}
+	return ev.Order[idx]
+}
+
+// nextImage gets the next loaded image, starting a new epoch
+// at the end of the current one.  Returns false if no images
+// could be loaded.
+func (ev *Patches) nextImage() bool {
+	nerr := 0
+	for {
+		it, ok := ev.Loader.Next()
+		if !ok {
+			if nerr >= len(ev.samples) {
+				log.Println("dataset.Patches.Step: no images could be loaded")
+				return false
+			}
+			ev.Epoch.Incr()
+			ev.Trial.Cur = -1
+			rand.Shuffle(len(ev.Order), func(i, j int) { ev.Order[i], ev.Order[j] = ev.Order[j], ev.Order[i] })
+			ev.startEpoch()
+			continue
+		}
+		if it.Err != nil {
+			log.Println(it.Err)
+			nerr++
+			continue
+		}
+		ev.Cur = ev.samples[ev.Row(it.Index)]
+		ev.img = it.Image
+		ev.imgIndex = it.Index
+		ev.nPatch = 0
+		ev.Loader.Release(it)
+		return true
+	}
+}
+
+func (ev *Patches) Step() bool {
+	if len(ev.samples) == 0 {
+		return false
+	}
+	ev.Epoch.Same()
+	if ev.img == nil || ev.nPatch >= ev.NPerImage {
+		if !ev.nextImage() {
+			return false
+		}
+	}
+	ev.Trial.Set(ev.imgIndex*ev.NPerImage + ev.nPatch)
+	ev.nPatch++
+	ev.Loader.ToTensor(ev.Sample(ev.img), &ev.ImageTsr)
+	if ev.Filter != nil {
+		ev.Filter(&ev.ImageTsr, &ev.V1AllTsr)
+	} else {
+		tensor.SetShapeFrom(&ev.V1AllTsr, &ev.ImageTsr)
+		ev.V1AllTsr.CopyFrom(&ev.ImageTsr)
+	}
+	ev.LabelTsr.SetShapeSizes(ev.Data.NumClasses())
+	ev.LabelTsr.SetZeros()
+	ev.LabelTsr.Values[ev.Cur.Class] = 1
+	return true
+}
+
+// Sample returns a random patch of Size from given image, at a
+// uniformly random position, setting Pos.  If Augment, the Context
+// region around the patch is transformed by a random XForm (set in XF)
+// and the patch is taken from its center.  Parts of the patch outside
+// of images smaller than the patch (or context region) are black.
+func (ev *Patches) Sample(img image.Image) image.Image {
+	bd := img.Bounds()
+	sz := bd.Size()
+	win := ev.Size
+	if ev.Augment {
+		win.X = int(math32.Round(ev.Context * float32(ev.Size.X)))
+		win.Y = int(math32.Round(ev.Context * float32(ev.Size.Y)))
+		win.X = max(min(win.X, sz.X), ev.Size.X)
+		win.Y = max(min(win.Y, sz.Y), ev.Size.Y)
+	}
+	pos := image.Point{rand.Intn(max(sz.X-win.X, 0) + 1), rand.Intn(max(sz.Y-win.Y, 0) + 1)}
+	var reg image.Image = img
+	off := bd.Min.Add(pos)
+	if ev.Augment {
+		rimg := image.NewRGBA(image.Rectangle{Max: win})
+		draw.Draw(rimg, rimg.Bounds(), img, off, draw.Src)
+		ev.XForm.Gen(&ev.XF)
+		reg = ev.XF.Image(rimg)
+		off = win.Sub(ev.Size).Div(2)
+		pos = pos.Add(off)
+	}
+	ev.Pos = pos
+	patch := image.NewRGBA(image.Rectangle{Max: ev.Size})
+	draw.Draw(patch, patch.Bounds(), reg, off, draw.Src)
+	return patch
+}
+
+func (ev *Patches) State(element string) tensor.Values {
+	switch element {
+	case "Image":
+		return &ev.ImageTsr
+	case "V1All":
+		return &ev.V1AllTsr
+	case "Label":
+		return &ev.LabelTsr
+	}
+	log.Println("dataset.Patches.State -- could not find element:", element)
+	return nil
+}
+
+func (ev *Patches) Action(element string, input tensor.Values) {
+	// nop
+}
+
+// Log adds a row to given table with the current patch, configuring
+// the columns if the table has none, with File, Label, Class, X, Y
+// (the patch Pos), and Image and V1All as tensor cells with the shapes
+// of the current patch and output tensors.
+func (ev *Patches) Log(dt *table.Table) {
+	if dt.NumColumns() == 0 {
+		dt.AddStringColumn("File")
+		dt.AddStringColumn("Label")
+		for _, c := range []string{"Class", "X", "Y"} {
+			dt.AddFloat32Column(c)
+		}
+		dt.AddFloat32Column("Image", ev.ImageTsr.ShapeSizes()...)
+		dt.AddFloat32Column("V1All", ev.V1AllTsr.ShapeSizes()...)
+	}
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	dt.Column("File").SetStringRow(ev.Cur.File, row, 0)
+	dt.Column("Label").SetStringRow(ev.Cur.Label, row, 0)
+	dt.Column("Class").SetFloatRow(float64(ev.Cur.Class), row, 0)
+	dt.Column("X").SetFloatRow(float64(ev.Pos.X), row, 0)
+	dt.Column("Y").SetFloatRow(float64(ev.Pos.Y), row, 0)
+	copy(dt.Column("Image").RowTensor(row).(*tensor.Float32).Values, ev.ImageTsr.Values)
+	copy(dt.Column("V1All").RowTensor(row).(*tensor.Float32).Values, ev.V1AllTsr.Values)
+}
+
+// WriteTable steps through n patches, adding a row for each to given
+// table with Log, and returns the number of rows added, which is less
+// than n if Step fails.  Init must have been called.
+func (ev *Patches) WriteTable(dt *table.Table, n int) int {
+	for i := range n {
+		if !ev.Step() {
+			return i
+		}
+		ev.Log(dt)
+	}
+	return n
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*Patches)(nil)
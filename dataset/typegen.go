@@ -0,0 +1,25 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package dataset
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Attend", IDName: "attend", Doc: "Attend crops and rescales attention windows around object bounding\nboxes (e.g., from dataset annotations), with a context margin, and\nruns a FilterFunc (e.g., the V1 pipeline) on each window, producing\nper-object feature tensors, as is standard for object-centric\nrecognition models.", Fields: []types.Field{{Name: "Margin", Doc: "context margin around each box, as a proportion of the box size added on each side"}, {Name: "Square", Doc: "expand each window to a square, around the same center, so objects are not distorted by rescaling"}, {Name: "Loader", Doc: "loader settings for converting windows to tensors: Size is the window size, along with PadWidth, Color and TopZero"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Sample", IDName: "sample", Doc: "Sample is one labeled image in a Dataset", Fields: []types.Field{{Name: "File", Doc: "path to the image file"}, {Name: "Label", Doc: "class label name"}, {Name: "Class", Doc: "index of the Label in the Dataset Classes"}, {Name: "Split", Doc: "name of the split this sample belongs to, e.g., train or test -- empty if not split"}, {Name: "Object", Doc: "object instance name within the class, for datasets with multiple views of each object (e.g., CU3D)"}, {Name: "Meta", Doc: "any further metadata fields, e.g., from the CSV manifest or file name"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Dataset", IDName: "dataset", Doc: "Dataset is a list of labeled image Samples, with the sorted\nlist of Classes that the Sample Class indexes refer to.", Fields: []types.Field{{Name: "Name", Doc: "name of the dataset"}, {Name: "Classes", Doc: "sorted list of class label names"}, {Name: "Samples", Doc: "all of the samples"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.FilterFunc", IDName: "filter-func", Doc: "FilterFunc is a function that computes the V1All output features\n(or any other features) from an input image tensor, as produced by\nthe Loader."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Env", IDName: "env", Doc: "Env is an env.Env that iterates over the Samples of a Dataset\nsplit, in sequential or permuted order, loading the images with a\nprefetching Loader, and computing output features with a\nFilterFunc, so that recognition experiments need no custom I/O code.\nStates are: Image = input image tensor, V1All = Filter output,\nLabel = localist (one-hot) class label over the Dataset Classes.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train vs. Test"}, {Name: "Data", Doc: "the dataset to iterate over -- if Split is set, only samples in that split are used"}, {Name: "Split", Doc: "if set, only the samples in this split of Data are used, e.g., train or test"}, {Name: "Sequential", Doc: "present samples in sequential order -- otherwise permuted random order, re-permuted every epoch"}, {Name: "Filter", Doc: "function computing the V1All features from the image tensor -- if nil, V1All is the image tensor"}, {Name: "Loader", Doc: "image loader, with settings for image size, padding, color -- Files are set automatically"}, {Name: "Order", Doc: "permuted order of samples for the current epoch"}, {Name: "Epoch", Doc: "current epoch"}, {Name: "Trial", Doc: "current trial within the epoch"}, {Name: "Cur", Doc: "current sample"}, {Name: "ImageTsr", Doc: "current input image as tensor"}, {Name: "V1AllTsr", Doc: "current filter output features"}, {Name: "LabelTsr", Doc: "current localist class label"}, {Name: "samples", Doc: "the samples in use, from Data and Split"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Item", IDName: "item", Doc: "Item is one loaded image, as delivered by the Loader", Fields: []types.Field{{Name: "Index", Doc: "index of the file in the Loader Files list"}, {Name: "File", Doc: "file name of the image"}, {Name: "Image", Doc: "the decoded image, resized to the Loader Size if set -- a resized image is opaque, with any transparency composited according to the Loader Alpha"}, {Name: "Orig", Doc: "the decoded image, at its original size"}, {Name: "Tensor", Doc: "the image converted to a padded tensor: grey [Y, X] or RGB [3, Y, X] -- obtained from vfilter.Pool, and can be returned with Loader.Release"}, {Name: "Err", Doc: "any error that occurred in loading the image -- Image, Orig and Tensor are nil if so"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Loader", IDName: "loader", Doc: "Loader is a parallel prefetching image loader, which decodes, resizes\nand converts upcoming images to tensors using a pool of worker\ngoroutines, while the current image is being filtered, thereby hiding\nthe I/O latency in dataset pipelines.  Items are delivered in the\norder of Files, and at most Prefetch items are loaded ahead.", Fields: []types.Field{{Name: "Files", Doc: "list of image files to load, in order"}, {Name: "Size", Doc: "target image size to use -- images will be rescaled to this size"}, {Name: "Resize", Doc: "interpolation used to rescale images to Size"}, {Name: "PadWidth", Doc: "amount of padding to add on all sides of the tensor -- padding is filled according to Pad"}, {Name: "Color", Doc: "if true, convert to an RGB [3, Y, X] tensor, otherwise a grey [Y, X] tensor"}, {Name: "TopZero", Doc: "retain the Y=0 value at the top of the tensor -- otherwise it is flipped with Y=0 at the bottom"}, {Name: "Alpha", Doc: "how the alpha (transparency) channel of images is handled -- compositing over a background avoids spurious edges around transparent regions"}, {Name: "Pad", Doc: "how to fill the padding -- Reflect avoids the spurious edges at the borders of natural images produced by the default Wrap"}, {Name: "NWorkers", Doc: "number of worker goroutines -- if 0, the number of CPUs is used"}, {Name: "Prefetch", Doc: "maximum number of items to load ahead of the current one"}, {Name: "pending", Doc: "channel of per-item result channels, in order"}, {Name: "done", Doc: "closed to stop loading"}, {Name: "wg", Doc: "waits for all goroutines to exit"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.job", IDName: "job", Doc: "job is a single image to load", Fields: []types.Field{{Name: "idx"}, {Name: "res"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/dataset.Patches", IDName: "patches", Doc: "Patches is an env.Env that samples many random fixed-size patches\nfrom each image of a Dataset split, optionally augmented by random\nvxform transforms, and computes output features for each patch with\na FilterFunc, which is the standard input regime for sparse coding\nand self-supervised learning of V1-like receptive fields.\nStates are: Image = patch tensor, V1All = Filter output,\nLabel = localist (one-hot) class label of the source image.\nLog and WriteTable stream the patches into a table.Table.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train vs. Test"}, {Name: "Data", Doc: "the dataset to sample from -- if Split is set, only samples in that split are used"}, {Name: "Split", Doc: "if set, only the samples in this split of Data are used, e.g., train or test"}, {Name: "Sequential", Doc: "present images in sequential order -- otherwise permuted random order, re-permuted every epoch"}, {Name: "Size", Doc: "size of the patches to sample"}, {Name: "NPerImage", Doc: "number of patches to sample from each image, before moving on to the next image"}, {Name: "Augment", Doc: "apply a random XForm transform to each patch"}, {Name: "Context", Doc: "size of the region around each patch that is transformed for Augment, as a multiple of the patch Size, so that rotations and reductions fill the patch with actual image content"}, {Name: "XForm", Doc: "random transforms for Augment -- translations are proportions of the context region half-size"}, {Name: "Filter", Doc: "function computing the V1All features from the patch tensor -- if nil, V1All is the patch tensor"}, {Name: "Loader", Doc: "image loader, with settings for image size, padding, color -- Files are set automatically.  Images are loaded at their native size if Size is 0, and the PadWidth, Color and TopZero settings are used for the patch tensors"}, {Name: "Order", Doc: "permuted order of images for the current epoch"}, {Name: "Epoch", Doc: "current epoch"}, {Name: "Trial", Doc: "current trial (patch) within the epoch: NPerImage trials per image"}, {Name: "Cur", Doc: "current sample"}, {Name: "Pos", Doc: "position of the upper-left corner of the current patch within the (resized) image, for the center of the context region if Augment"}, {Name: "XF", Doc: "current transform, if Augment"}, {Name: "ImageTsr", Doc: "current patch as tensor"}, {Name: "V1AllTsr", Doc: "current filter output features"}, {Name: "LabelTsr", Doc: "current localist class label"}, {Name: "samples", Doc: "the samples in use, from Data and Split"}, {Name: "img", Doc: "current image that patches are sampled from"}, {Name: "imgIndex", Doc: "index of the current image in the epoch"}, {Name: "nPatch", Doc: "number of patches sampled from the current image"}}})
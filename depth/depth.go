@@ -0,0 +1,181 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package depth provides a filtering path for a depth channel accompanying
+RGB input (e.g., from Kinect-style RGB-D sensors), see vfilter.RGBDToTensor.
+
+Depth is filtered by a DoG (center-surround) filter, which responds to
+local bumps and dents in depth, and by an oriented depth gradient,
+which responds to depth edges and slanted surfaces.  Both are computed
+at the same spacing, so they can be included as extra rows in a
+V1All-style [Y, X, Rows, Angle] output tensor.
+*/
+package depth
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/dog"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Filter specifies the filtering of a depth channel by a DoG filter
+// and an oriented depth gradient, computed on the same Geom.
+type Filter struct {
+
+	// is this filter active?
+	On bool
+
+	// range of raw depth values that is mapped into the normalized 0-1 range -- values outside the range are clipped
+	Range minmax.F32
+
+	// if true, nearer surfaces have higher normalized values (raw sensor values are typically larger for farther surfaces)
+	NearHigh bool `default:"true"`
+
+	// DoG filter parameters for depth center-surround
+	DoG dog.Filter `display:"inline"`
+
+	// overall gain multiplier on the depth gradient
+	GradGain float32 `default:"4"`
+
+	// distance in pixels over which the depth gradient is computed, on either side of the center point
+	GradDist int `default:"2"`
+
+	// number of angles of oriented depth gradient -- should match the gabor NAngles for inclusion in V1All
+	NAngles int `default:"4"`
+
+	// geometry of input, output, determined by the DoG filter size and spacing
+	Geom vfilter.Geom `edit:"-"`
+
+	// DoG filter tensor
+	DoGTsr tensor.Float32 `display:"no-inline"`
+
+	// DoG output, with outer dim for On, Off polarity: [2, Y, X]
+	DoGOut tensor.Float32 `display:"no-inline"`
+
+	// oriented gradient output: [Y, X, Polarity (2), Angle]
+	GradOut tensor.Float32 `display:"no-inline"`
+}
+
+// NRows is the number of rows added to a V1All tensor by V1AllRows:
+// gradient On, Off, then DoG On, Off.
+const NRows = 4
+
+func (df *Filter) Defaults() {
+	df.On = true
+	df.Range.Set(0, 1)
+	df.NearHigh = true
+	df.DoG.Defaults()
+	df.DoG.Gain = 8
+	df.GradGain = 4
+	df.GradDist = 2
+	df.NAngles = 4
+	df.Config()
+}
+
+func (df *Filter) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return df.On
+	}
+}
+
+// Config renders the DoG filter and configures the Geom -- must be
+// called after any changes to the DoG parameters.  The border is the
+// larger of GradDist and the right half of the DoG filter (FiltRt).
+func (df *Filter) Config() {
+	df.DoG.ToTensor(&df.DoGTsr)
+	sz := df.DoG.Size
+	spc := df.DoG.Spacing
+	brd := max(df.GradDist, sz-vfilter.LeftHalf(sz))
+	df.Geom.Set(image.Point{brd, brd}, image.Point{spc, spc}, image.Point{sz, sz})
+}
+
+// Normalize maps raw depth values in given tensor into the
+// normalized 0-1 range according to Range and NearHigh, in place.
+func (df *Filter) Normalize(dep *tensor.Float32) {
+	for i, v := range dep.Values {
+		nv := df.Range.ClipNormValue(v)
+		if df.NearHigh {
+			nv = 1 - nv
+		}
+		dep.Values[i] = nv
+	}
+}
+
+// Filter runs the DoG and gradient filters on given depth tensor,
+// which must be a 2D [Y, X] normalized depth image with appropriate
+// padding (at least Geom.FiltRt), e.g., the Depth component of an
+// RGBD tensor from vfilter.RGBDToTensor.
+func (df *Filter) Filter(dep *tensor.Float32) {
+	net := df.DoG.FilterTensor(&df.DoGTsr, dog.Net)
+	vfilter.Conv1(&df.Geom, net, dep, &df.DoGOut, df.DoG.Gain)
+	Grad(&df.Geom, df.GradDist, df.NAngles, dep, &df.GradOut, df.GradGain)
+}
+
+// FilterRGBD runs the filters on the depth component of given RGBD tensor
+func (df *Filter) FilterRGBD(rgbd *tensor.Float32) {
+	df.Filter(vfilter.Channel(rgbd, 3))
+}
+
+// V1AllRows adds the depth filter outputs as NRows rows into given
+// V1All-style [Y, X, Rows, Angle] output tensor, starting at rowStart:
+// gradient On, Off polarities, then DoG On, Off (replicated across angles).
+// The output must already be allocated with sufficient rows, and the
+// same Y, X size as the depth outputs, otherwise an error is returned.
+func (df *Filter) V1AllRows(out *tensor.Float32, rowStart int) error {
+	if err := vfilter.FeatAgg([]int{0, 1}, rowStart, &df.GradOut, out); err != nil {
+		return err
+	}
+	nang := out.DimSize(3)
+	for ang := 0; ang < nang; ang++ {
+		if err := vfilter.OuterAgg(ang, rowStart+2, &df.DoGOut, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Grad computes the oriented gradient of given 2D image at output
+// locations determined by geom, as the difference between values
+// dist pixels on either side of each location along the direction
+// orthogonal to each of nang angles, which are in the same order as
+// gabor filter angles (first angle is horizontal).
+// Output has shape [Y, X, Polarity (2), Angle], where the 2 polarities
+// are for positive and negative gradients, respectively.
+// img must have a border of at least dist pixels.
+func Grad(geom *vfilter.Geom, dist, nang int, img, out *tensor.Float32, gain float32) {
+	imgSz := image.Point{img.DimSize(1), img.DimSize(0)}
+	geom.SetSize(imgSz)
+	out.SetShapeSizes(geom.Out.Y, geom.Out.X, 2, nang)
+	angInc := math32.Pi / float32(nang)
+	norm := gain / float32(2*dist)
+	for y := 0; y < geom.Out.Y; y++ {
+		iy := geom.Border.Y + y*geom.Spacing.Y
+		for x := 0; x < geom.Out.X; x++ {
+			ix := geom.Border.X + x*geom.Spacing.X
+			dx := img.Value(iy, ix+dist) - img.Value(iy, ix-dist)
+			dy := img.Value(iy+dist, ix) - img.Value(iy-dist, ix)
+			for ang := 0; ang < nang; ang++ {
+				angf := -float32(ang) * angInc
+				gv := norm * (dx*math32.Sin(angf) + dy*math32.Cos(angf))
+				if gv > 0 {
+					out.Set(gv, y, x, 0, ang)
+					out.Set(0, y, x, 1, ang)
+				} else {
+					out.Set(0, y, x, 0, ang)
+					out.Set(-gv, y, x, 1, ang)
+				}
+			}
+		}
+	}
+}
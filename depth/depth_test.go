@@ -0,0 +1,32 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package depth
+
+import (
+	"image"
+	"testing"
+)
+
+func TestConfigGeom(t *testing.T) {
+	var df Filter
+	df.Defaults()
+	if err := df.Geom.Validate(); err != nil {
+		t.Errorf("default Config: %v", err)
+	}
+	df.DoG.Size = 7
+	df.GradDist = 8
+	df.Config()
+	if err := df.Geom.Validate(); err != nil {
+		t.Errorf("odd size Config: %v", err)
+	}
+	if df.Geom.Border != (image.Point{8, 8}) {
+		t.Errorf("Border: %v should be GradDist when larger than FiltRt", df.Geom.Border)
+	}
+	df.GradDist = 2
+	df.Config()
+	if err := df.Geom.Validate(); err != nil {
+		t.Errorf("odd size Config: %v", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tensor"
 	"cogentcore.org/core/tensor/table"
+	"github.com/emer/vision/v2/vfilter"
 )
 
 // dog.Filter specifies a DoG Difference of Gaussians filter function.
@@ -157,7 +158,7 @@ func (gf *Filter) ToTable(tab *table.Table) {
 // FilterTensor extracts the given filter subspace from set of 3 filters in input tensor
 // 0 = On, 1 = Off, 2 = Net
 func (gf *Filter) FilterTensor(tsr *tensor.Float32, filt Filters) *tensor.Float32 {
-	return tsr.SubSpace(int(filt)).(*tensor.Float32)
+	return vfilter.Channel(tsr, int(filt))
 }
 
 // Filters is the type of filter
@@ -0,0 +1,195 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edges
+
+import (
+	"math"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Canny specifies Canny edge detection: gaussian smoothing,
+// Sobel gradient, non-maximum suppression along the gradient
+// direction, and hysteresis thresholding, where edge pixels above
+// LoThr are kept only if connected to pixels above HiThr.
+type Canny struct {
+
+	// gaussian sigma for smoothing the image prior to the gradient, in pixels -- 0 = no smoothing
+	Sigma float32 `default:"1.4"`
+
+	// low threshold for hysteresis, as a proportion of the maximum gradient magnitude
+	LoThr float32 `default:"0.1"`
+
+	// high threshold for hysteresis, as a proportion of the maximum gradient magnitude
+	HiThr float32 `default:"0.2"`
+
+	// number of angles for the oriented output
+	NAngles int `default:"4"`
+
+	// gradient magnitude after smoothing: [Y, X]
+	Mag tensor.Float32 `display:"no-inline"`
+
+	// binary edge map: 1 for edge pixels, else 0: [Y, X]
+	Edges tensor.Float32 `display:"no-inline"`
+
+	// gradient x component: [Y, X]
+	gx tensor.Float32
+
+	// gradient y component: [Y, X]
+	gy tensor.Float32
+}
+
+func (cn *Canny) Defaults() {
+	cn.Sigma = 1.4
+	cn.LoThr = 0.1
+	cn.HiThr = 0.2
+	cn.NAngles = 4
+}
+
+// Filter does Canny edge detection on given grey-scale 2D [Y, X] image,
+// into the Mag and Edges tensors, and the oriented output out, with
+// shape [Y, X, Polarity (2), Angle], with the gradient magnitude of
+// each edge pixel at its nearest angle and polarity, and 0 elsewhere.
+func (cn *Canny) Filter(img, out *tensor.Float32) {
+	sy := img.DimSize(0)
+	sx := img.DimSize(1)
+	if cn.Sigma > 0 {
+		var blur tensor.Float32
+		GaussBlur(img, &blur, cn.Sigma)
+		SobelGrad(&blur, &cn.gx, &cn.gy)
+	} else {
+		SobelGrad(img, &cn.gx, &cn.gy)
+	}
+	cn.Mag.SetShapeSizes(sy, sx)
+	mx := float32(0)
+	for i := range cn.Mag.Values {
+		m := math32.Hypot(cn.gx.Values[i], cn.gy.Values[i])
+		cn.Mag.Values[i] = m
+		mx = max(mx, m)
+	}
+	nms := NonMaxSuppress(&cn.Mag, &cn.gx, &cn.gy)
+	cn.Edges.SetShapeSizes(sy, sx)
+	cn.Edges.SetZeros()
+	lo := cn.LoThr * mx
+	hi := cn.HiThr * mx
+	var stack []int
+	for i, m := range nms {
+		if m > 0 && m >= hi {
+			cn.Edges.Values[i] = 1
+			stack = append(stack, i)
+		}
+	}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		y, x := i/sx, i%sx
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				yy, xx := y+dy, x+dx
+				if yy < 0 || yy >= sy || xx < 0 || xx >= sx {
+					continue
+				}
+				ni := yy*sx + xx
+				if cn.Edges.Values[ni] == 0 && nms[ni] > 0 && nms[ni] >= lo {
+					cn.Edges.Values[ni] = 1
+					stack = append(stack, ni)
+				}
+			}
+		}
+	}
+	out.SetShapeSizes(sy, sx, 2, cn.NAngles)
+	out.SetZeros()
+	for i, e := range cn.Edges.Values {
+		if e == 0 {
+			continue
+		}
+		ang, pol := nearestAngle(cn.gx.Values[i], cn.gy.Values[i], cn.NAngles)
+		out.Values[(i*2+pol)*cn.NAngles+ang] = cn.Mag.Values[i]
+	}
+}
+
+// NonMaxSuppress returns the gradient magnitude values in mag [Y, X]
+// that are local maxima along the gradient direction given by gx, gy,
+// quantized to the nearest of 4 directions, with other values set to 0.
+func NonMaxSuppress(mag, gx, gy *tensor.Float32) []float32 {
+	sy := mag.DimSize(0)
+	sx := mag.DimSize(1)
+	nms := make([]float32, sy*sx)
+	mv := func(y, x int) float32 {
+		if y < 0 || y >= sy || x < 0 || x >= sx {
+			return 0
+		}
+		return mag.Values[y*sx+x]
+	}
+	for y := range sy {
+		for x := range sx {
+			i := y*sx + x
+			m := mag.Values[i]
+			if m == 0 {
+				continue
+			}
+			th := math32.Atan2(gy.Values[i], gx.Values[i])
+			// quantize direction to 0, 45, 90, 135 degrees
+			q := int(math32.Round(th/(0.25*math.Pi))+8) % 4
+			var dy, dx int
+			switch q {
+			case 0:
+				dx = 1
+			case 1:
+				dy, dx = 1, 1
+			case 2:
+				dy = 1
+			case 3:
+				dy, dx = 1, -1
+			}
+			if m >= mv(y+dy, x+dx) && m > mv(y-dy, x-dx) {
+				nms[i] = m
+			}
+		}
+	}
+	return nms
+}
+
+// GaussBlur applies separable gaussian smoothing with given sigma
+// (in pixels) to the grey-scale 2D [Y, X] image, into out, with image
+// values beyond the edges equal to the nearest edge value.
+func GaussBlur(img, out *tensor.Float32, sigma float32) {
+	sy := img.DimSize(0)
+	sx := img.DimSize(1)
+	rad := int(math32.Ceil(3 * sigma))
+	wts := make([]float32, 2*rad+1)
+	sum := float32(0)
+	for i := range wts {
+		d := float32(i - rad)
+		wts[i] = math32.Exp(-d * d / (2 * sigma * sigma))
+		sum += wts[i]
+	}
+	for i := range wts {
+		wts[i] /= sum
+	}
+	tmp := make([]float32, sy*sx)
+	for y := range sy {
+		for x := range sx {
+			v := float32(0)
+			for i, w := range wts {
+				xx := min(max(x+i-rad, 0), sx-1)
+				v += w * img.Values[y*sx+xx]
+			}
+			tmp[y*sx+x] = v
+		}
+	}
+	out.SetShapeSizes(sy, sx)
+	for y := range sy {
+		for x := range sx {
+			v := float32(0)
+			for i, w := range wts {
+				yy := min(max(y+i-rad, 0), sy-1)
+				v += w * tmp[yy*sx+x]
+			}
+			out.Values[y*sx+x] = v
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edges
+
+import (
+	"image"
+	"math"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Point is one point on a Contour
+type Point struct {
+
+	// position of the point
+	Pos image.Point
+
+	// index of the angle (orientation) at the point
+	Angle int
+
+	// response value at the point
+	Value float32
+}
+
+// Contour is a linked edge segment, as an ordered list of points
+// along the edge, where successive points are neighbors.
+type Contour struct {
+
+	// points along the contour, in order
+	Points []Point
+}
+
+// Len returns the number of points
+func (ct *Contour) Len() int {
+	return len(ct.Points)
+}
+
+// Length returns the length of the contour in pixels, summing the
+// distances between successive points.
+func (ct *Contour) Length() float32 {
+	ln := float32(0)
+	for i := 1; i < len(ct.Points); i++ {
+		d := ct.Points[i].Pos.Sub(ct.Points[i-1].Pos)
+		ln += math32.Hypot(float32(d.X), float32(d.Y))
+	}
+	return ln
+}
+
+// Linker links oriented edge responses, such as V1 simple cell outputs,
+// into explicit Contour segments: the responses are reduced to the best
+// angle at each position, thresholded, and thinned by non-maximum
+// suppression across the edge, and then neighboring points are traced
+// along the edge direction, linking those with similar angles.
+type Linker struct {
+
+	// threshold on responses, as a proportion of the maximum response
+	Thr float32 `default:"0.2"`
+
+	// maximum difference in angle index between linked points
+	MaxAngDiff int `default:"1"`
+
+	// minimum number of points in a contour -- shorter contours are discarded
+	MinLen int `default:"3"`
+
+	// contours from the last Link
+	Contours []Contour `display:"-"`
+
+	// contour labels for each position: index of the contour + 1, or 0 if none: [Y, X]
+	Labels tensor.Float32 `display:"no-inline"`
+
+	// responses at the contour points, at their angle, else 0: [Y, X, 1, Angle]
+	Linked tensor.Float32 `display:"no-inline"`
+}
+
+func (lk *Linker) Defaults() {
+	lk.Thr = 0.2
+	lk.MaxAngDiff = 1
+	lk.MinLen = 3
+}
+
+// Link links the oriented responses in given [Y, X, Polarity, Angle]
+// tensor into Contours, and the Labels and Linked tensors, taking the
+// max over polarities.  As in gabor, the angles are evenly spaced over
+// 180 degrees starting at horizontal.
+func (lk *Linker) Link(in *tensor.Float32) {
+	sy := in.DimSize(0)
+	sx := in.DimSize(1)
+	npol := in.DimSize(2)
+	nang := in.DimSize(3)
+	n := sy * sx
+	vals := make([]float32, n)
+	angs := make([]int, n)
+	mx := float32(0)
+	for i := range n {
+		for p := range npol {
+			for a := range nang {
+				v := in.Values[(i*npol+p)*nang+a]
+				if v > vals[i] {
+					vals[i] = v
+					angs[i] = a
+				}
+			}
+		}
+		mx = max(mx, vals[i])
+	}
+	// threshold and non-maximum suppression across the edge
+	thr := lk.Thr * mx
+	keep := make([]bool, n)
+	for y := range sy {
+		for x := range sx {
+			i := y*sx + x
+			v := vals[i]
+			if v == 0 || v < thr {
+				continue
+			}
+			ex, ey := edgeDir(angs[i], nang)
+			d := image.Point{int(math32.Round(-ey)), int(math32.Round(ex))} // perpendicular
+			fv := valueAt(vals, sy, sx, y+d.Y, x+d.X)
+			bv := valueAt(vals, sy, sx, y-d.Y, x-d.X)
+			keep[i] = v >= fv && v > bv
+		}
+	}
+	lk.Contours = lk.Contours[:0]
+	lk.Labels.SetShapeSizes(sy, sx)
+	lk.Labels.SetZeros()
+	lk.Linked.SetShapeSizes(sy, sx, 1, nang)
+	lk.Linked.SetZeros()
+	used := make([]bool, n)
+	for i := range n {
+		if !keep[i] || used[i] {
+			continue
+		}
+		used[i] = true
+		ex, ey := edgeDir(angs[i], nang)
+		fwd := lk.trace(i, ex, ey, vals, angs, keep, used, sy, sx, nang)
+		bwd := lk.trace(i, -ex, -ey, vals, angs, keep, used, sy, sx, nang)
+		pts := make([]Point, 0, len(fwd)+len(bwd)+1)
+		for k := len(bwd) - 1; k >= 0; k-- {
+			pts = append(pts, lk.point(bwd[k], vals, angs, sx))
+		}
+		pts = append(pts, lk.point(i, vals, angs, sx))
+		for _, pi := range fwd {
+			pts = append(pts, lk.point(pi, vals, angs, sx))
+		}
+		if len(pts) < lk.MinLen {
+			continue
+		}
+		lk.Contours = append(lk.Contours, Contour{Points: pts})
+		lbl := float32(len(lk.Contours))
+		for _, pt := range pts {
+			lk.Labels.Set(lbl, pt.Pos.Y, pt.Pos.X)
+			lk.Linked.Set(pt.Value, pt.Pos.Y, pt.Pos.X, 0, pt.Angle)
+		}
+	}
+}
+
+// trace follows the contour from index i in direction dx, dy, marking
+// points as used, and returns the indexes of the points in order.
+func (lk *Linker) trace(i int, dx, dy float32, vals []float32, angs []int, keep, used []bool, sy, sx, nang int) []int {
+	var pts []int
+	for {
+		y, x := i/sx, i%sx
+		best := -1
+		bestv := float32(0)
+		var bdx, bdy float32
+		for oy := -1; oy <= 1; oy++ {
+			for ox := -1; ox <= 1; ox++ {
+				yy, xx := y+oy, x+ox
+				if (oy == 0 && ox == 0) || yy < 0 || yy >= sy || xx < 0 || xx >= sx {
+					continue
+				}
+				ni := yy*sx + xx
+				if !keep[ni] || used[ni] || angDiff(angs[ni], angs[i], nang) > lk.MaxAngDiff {
+					continue
+				}
+				od := math32.Hypot(float32(ox), float32(oy))
+				if (float32(ox)*dx+float32(oy)*dy)/od < 0.38 { // within 67.5 degrees
+					continue
+				}
+				if vals[ni] > bestv {
+					best = ni
+					bestv = vals[ni]
+					bdx, bdy = float32(ox), float32(oy)
+				}
+			}
+		}
+		if best < 0 {
+			return pts
+		}
+		used[best] = true
+		pts = append(pts, best)
+		ex, ey := edgeDir(angs[best], nang)
+		if ex*bdx+ey*bdy < 0 {
+			ex, ey = -ex, -ey
+		}
+		i, dx, dy = best, ex, ey
+	}
+}
+
+// point returns the Point for given index
+func (lk *Linker) point(i int, vals []float32, angs []int, sx int) Point {
+	return Point{Pos: image.Point{i % sx, i / sx}, Angle: angs[i], Value: vals[i]}
+}
+
+// edgeDir returns the unit vector along the edge for given angle index,
+// in X, Y image coordinates.
+func edgeDir(ang, nang int) (ex, ey float32) {
+	th := float32(ang) * math.Pi / float32(nang)
+	return math32.Cos(th), math32.Sin(th)
+}
+
+// angDiff returns the circular difference between angle indexes
+func angDiff(a, b, nang int) int {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	return min(d, nang-d)
+}
+
+// valueAt returns the value at given position, or 0 if out of bounds
+func valueAt(vals []float32, sy, sx, y, x int) float32 {
+	if y < 0 || y >= sy || x < 0 || x >= sx {
+		return 0
+	}
+	return vals[y*sx+x]
+}
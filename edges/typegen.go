@@ -0,0 +1,15 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package edges
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/edges.Canny", IDName: "canny", Doc: "Canny specifies Canny edge detection: gaussian smoothing,\nSobel gradient, non-maximum suppression along the gradient\ndirection, and hysteresis thresholding, where edge pixels above\nLoThr are kept only if connected to pixels above HiThr.", Fields: []types.Field{{Name: "Sigma", Doc: "gaussian sigma for smoothing the image prior to the gradient, in pixels -- 0 = no smoothing"}, {Name: "LoThr", Doc: "low threshold for hysteresis, as a proportion of the maximum gradient magnitude"}, {Name: "HiThr", Doc: "high threshold for hysteresis, as a proportion of the maximum gradient magnitude"}, {Name: "NAngles", Doc: "number of angles for the oriented output"}, {Name: "Mag", Doc: "gradient magnitude after smoothing: [Y, X]"}, {Name: "Edges", Doc: "binary edge map: 1 for edge pixels, else 0: [Y, X]"}, {Name: "gx", Doc: "gradient x component: [Y, X]"}, {Name: "gy", Doc: "gradient y component: [Y, X]"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/edges.Point", IDName: "point", Doc: "Point is one point on a Contour", Fields: []types.Field{{Name: "Pos", Doc: "position of the point"}, {Name: "Angle", Doc: "index of the angle (orientation) at the point"}, {Name: "Value", Doc: "response value at the point"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/edges.Contour", IDName: "contour", Doc: "Contour is a linked edge segment, as an ordered list of points\nalong the edge, where successive points are neighbors.", Fields: []types.Field{{Name: "Points", Doc: "points along the contour, in order"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/edges.Linker", IDName: "linker", Doc: "Linker links oriented edge responses, such as V1 simple cell outputs,\ninto explicit Contour segments: the responses are reduced to the best\nangle at each position, thresholded, and thinned by non-maximum\nsuppression across the edge, and then neighboring points are traced\nalong the edge direction, linking those with similar angles.", Fields: []types.Field{{Name: "Thr", Doc: "threshold on responses, as a proportion of the maximum response"}, {Name: "MaxAngDiff", Doc: "maximum difference in angle index between linked points"}, {Name: "MinLen", Doc: "minimum number of points in a contour -- shorter contours are discarded"}, {Name: "Contours", Doc: "contours from the last Link"}, {Name: "Labels", Doc: "contour labels for each position: index of the contour + 1, or 0 if none: [Y, X]"}, {Name: "Linked", Doc: "responses at the contour points, at their angle, else 0: [Y, X, 1, Angle]"}}})
@@ -16,9 +16,7 @@ import (
 	"cogentcore.org/core/tensor/table"
 	"cogentcore.org/core/tensor/tensorcore"
 	"cogentcore.org/core/tree"
-	"github.com/anthonynsimon/bild/transform"
 	"github.com/emer/vision/v2/colorspace"
-	"github.com/emer/vision/v2/dog"
 	"github.com/emer/vision/v2/vfilter"
 )
 
@@ -36,8 +34,8 @@ type Vis struct { //types:add
 	// name of image file to operate on -- if macbeth or empty use the macbeth standard color test image
 	ImageFile core.Filename
 
-	// LGN DoG filter parameters
-	DoG dog.Filter
+	// LGN single-opponent DoG filters
+	DoG colorspace.OpponentDoG
 
 	// names of the dog gain sets -- for naming output data
 	DoGNames []string
@@ -48,14 +46,11 @@ type Vis struct { //types:add
 	// OnGain factors -- 1 = perfect balance, otherwise has relative imbalance for capturing main effects
 	DoGOnGains []float32
 
-	// geometry of input, output
-	Geom vfilter.Geom `edit:"-"`
-
 	// target image size to use -- images will be rescaled to this size
 	ImgSize image.Point
 
-	// DoG filter tensor -- has 3 filters (on, off, net)
-	DoGTsr tensor.Float32 `display:"no-inline"`
+	// interpolation used to rescale images to ImgSize
+	Resize vfilter.ResizeModes
 
 	// DoG filter table (view only)
 	DoGTab table.Table `display:"no-inline"`
@@ -69,7 +64,7 @@ type Vis struct { //types:add
 	// LMS components + opponents tensor version of image
 	ImgLMS tensor.Float32 `display:"no-inline"`
 
-	// output from 3 dogs with different tuning
+	// output from 3 dogs with different tuning: [Y, X, SingleOpponents, DoGNames]
 	OutAll tensor.Float32 `display:"no-inline"`
 
 	// DoG filter output tensors
@@ -84,23 +79,20 @@ func (vi *Vis) Defaults() {
 	sz := 16
 	spc := 16
 	vi.DoG.Defaults()
-	vi.DoG.SetSize(sz, spc)
-	vi.DoG.OnSig = .5 // no spatial component, just pure contrast
-	vi.DoG.OffSig = .5
-	vi.DoG.Gain = 8
-	vi.DoG.OnGain = 1
-
-	// note: first arg is border -- we are relying on Geom
-	// to set border to .5 * filter size
+	vi.DoG.DoG.SetSize(sz, spc)
+	vi.DoG.DoG.OnSig = .5 // no spatial component, just pure contrast
+	vi.DoG.DoG.OffSig = .5
+	vi.DoG.DoG.Gain = 8
+	vi.DoG.DoG.OnGain = 1
+	// note: Config relies on Geom to set border to .5 * filter size
 	// any further border sizes on same image need to add Geom.FiltRt!
-	vi.Geom.Set(image.Point{0, 0}, image.Point{spc, spc}, image.Point{sz, sz})
+	vi.DoG.Config()
 	vi.ImgSize = image.Point{512, 512}
 	// vi.ImgSize = image.Point{256, 256}
 	// vi.ImgSize = image.Point{128, 128}
 	// vi.ImgSize = image.Point{64, 64}
-	vi.DoG.ToTensor(&vi.DoGTsr)
 	vi.DoGTab.Init()
-	vi.DoG.ToTable(&vi.DoGTab) // note: view only, testing
+	vi.DoG.DoG.ToTable(&vi.DoGTab) // note: view only, testing
 	tensorcore.AddGridStylerTo(vi.DoGTab.Columns.Values[1], func(s *tensorcore.GridStyle) {
 		s.Size.Min = 16
 		s.Range.Set(-0.01, 0.01)
@@ -140,19 +132,24 @@ func (vi *Vis) OpenImage(filepath string) error { //types:add
 		log.Println(err)
 		return err
 	}
+	brd := vi.DoG.Geom.FiltRt.X
 	isz := vi.Img.Bounds().Size()
 	if isz != vi.ImgSize {
-		vi.Img = transform.Resize(vi.Img, vi.ImgSize.X, vi.ImgSize.Y, transform.Linear)
+		raw := vfilter.Pool.Get(3, isz.Y, isz.X)
+		vfilter.RGBToTensor(vi.Img, raw, 0, false)
+		vfilter.ResizeTensor(raw, &vi.ImgTsr, vi.ImgSize, brd, vi.Resize) // pad for filt
+		vfilter.Pool.Put(raw)
+	} else {
+		vfilter.RGBToTensor(vi.Img, &vi.ImgTsr, brd, false) // pad for filt, bot zero
 	}
-	vfilter.RGBToTensor(vi.Img, &vi.ImgTsr, vi.Geom.FiltRt.X, false) // pad for filt, bot zero
-	vfilter.WrapPadRGB(&vi.ImgTsr, vi.Geom.FiltRt.X)
+	vfilter.WrapPadRGB(&vi.ImgTsr, brd)
 	colorspace.RGBTensorToLMSComps(&vi.ImgLMS, &vi.ImgTsr)
 	return nil
 }
 
 // OpenMacbeth opens the macbeth test image
 func (vi *Vis) OpenMacbeth() error {
-	colorspace.MacbethImage(&vi.ImgTsr, vi.ImgSize.X, vi.ImgSize.Y, vi.Geom.FiltRt.X)
+	colorspace.MacbethImage(&vi.ImgTsr, vi.ImgSize.X, vi.ImgSize.Y, vi.DoG.Geom.FiltRt.X)
 	colorspace.RGBTensorToLMSComps(&vi.ImgLMS, &vi.ImgTsr)
 	img := &image.RGBA{}
 	img = vfilter.RGBTensorToImage(img, &vi.ImgTsr, 0, false)
@@ -169,8 +166,8 @@ func (vi *Vis) OpenMacbeth() error {
 // ColorDoG runs color contrast DoG filtering on input image
 // must have valid Img in place to start.
 func (vi *Vis) ColorDoG() {
-	rimg := vi.ImgLMS.SubSpace(int(colorspace.LC)).(*tensor.Float32)
-	gimg := vi.ImgLMS.SubSpace(int(colorspace.MC)).(*tensor.Float32)
+	rimg := colorspace.Component(&vi.ImgLMS, colorspace.LC)
+	gimg := colorspace.Component(&vi.ImgLMS, colorspace.MC)
 	tensorcore.AddGridStylerTo(rimg, func(s *tensorcore.GridStyle) {
 		s.GridFill = 1
 	})
@@ -180,8 +177,8 @@ func (vi *Vis) ColorDoG() {
 	vi.OutTsrs["Red"] = rimg
 	vi.OutTsrs["Green"] = gimg
 
-	bimg := vi.ImgLMS.SubSpace(int(colorspace.SC)).(*tensor.Float32)
-	yimg := vi.ImgLMS.SubSpace(int(colorspace.LMC)).(*tensor.Float32)
+	bimg := colorspace.Component(&vi.ImgLMS, colorspace.SC)
+	yimg := colorspace.Component(&vi.ImgLMS, colorspace.LMC)
 	tensorcore.AddGridStylerTo(bimg, func(s *tensorcore.GridStyle) {
 		s.GridFill = 1
 	})
@@ -192,8 +189,8 @@ func (vi *Vis) ColorDoG() {
 	vi.OutTsrs["Yellow"] = yimg
 
 	// for display purposes only:
-	byimg := vi.ImgLMS.SubSpace(int(colorspace.SvLMC)).(*tensor.Float32)
-	rgimg := vi.ImgLMS.SubSpace(int(colorspace.LvMC)).(*tensor.Float32)
+	byimg := colorspace.Component(&vi.ImgLMS, colorspace.SvLMC)
+	rgimg := colorspace.Component(&vi.ImgLMS, colorspace.LvMC)
 	tensorcore.AddGridStylerTo(byimg, func(s *tensorcore.GridStyle) {
 		s.GridFill = 1
 	})
@@ -210,34 +207,27 @@ func (vi *Vis) ColorDoG() {
 
 // DoGFilter runs filtering for given gain factors
 func (vi *Vis) DoGFilter(name string, gain, onGain float32) {
-	dogOn := vi.DoG.FilterTensor(&vi.DoGTsr, dog.On)
-	dogOff := vi.DoG.FilterTensor(&vi.DoGTsr, dog.Off)
-
-	rgtsr := vi.OutTsr("DoG_" + name + "_Red-Green")
-	rimg := vi.OutTsr("Red")
-	gimg := vi.OutTsr("Green")
-	vfilter.ConvDiff(&vi.Geom, dogOn, dogOff, rimg, gimg, rgtsr, gain, onGain)
-
-	bytsr := vi.OutTsr("DoG_" + name + "_Blue-Yellow")
-	bimg := vi.OutTsr("Blue")
-	yimg := vi.OutTsr("Yellow")
-	vfilter.ConvDiff(&vi.Geom, dogOn, dogOff, bimg, yimg, bytsr, gain, onGain)
+	vi.DoG.DoG.Gain = gain
+	vi.DoG.DoG.OnGain = onGain
+	vi.DoG.Filter(&vi.ImgLMS)
+	otsr := vi.OutTsr("DoG_" + name)
+	tensor.SetShapeFrom(otsr, &vi.DoG.Out)
+	otsr.CopyFrom(&vi.DoG.Out)
 }
 
-// AggAll aggregates the different DoG components into
+// AggAll aggregates the different DoG outputs into OutAll
 func (vi *Vis) AggAll() {
-	otsr := vi.OutTsr("DoG_" + vi.DoGNames[0] + "_Red-Green")
+	otsr := vi.OutTsr("DoG_" + vi.DoGNames[0])
 	ny := otsr.DimSize(1)
 	nx := otsr.DimSize(2)
-	vi.OutAll.SetShapeSizes(ny, nx, 2, 2*len(vi.DoGNames))
+	vi.OutAll.SetShapeSizes(ny, nx, int(colorspace.SingleOpponentsN), len(vi.DoGNames))
 	tensorcore.AddGridStylerTo(&vi.OutAll, func(s *tensorcore.GridStyle) {
 		s.GridFill = 1
 	})
 	for i, nm := range vi.DoGNames {
-		rgtsr := vi.OutTsr("DoG_" + nm + "_Red-Green")
-		bytsr := vi.OutTsr("DoG_" + nm + "_Blue-Yellow")
-		vfilter.OuterAgg(i*2, 0, rgtsr, &vi.OutAll)
-		vfilter.OuterAgg(i*2+1, 0, bytsr, &vi.OutAll)
+		if err := vfilter.OuterAgg(i, 0, vi.OutTsr("DoG_"+nm), &vi.OutAll); err != nil {
+			log.Println(err)
+		}
 	}
 }
 
@@ -18,7 +18,6 @@ import (
 	"cogentcore.org/core/tensor/tensorcore"
 	_ "cogentcore.org/core/tensor/tensorcore" // include to get gui views
 	"cogentcore.org/core/tree"
-	"github.com/anthonynsimon/bild/transform"
 	"github.com/emer/vision/v2/colorspace"
 	"github.com/emer/vision/v2/fffb"
 	"github.com/emer/vision/v2/gabor"
@@ -44,14 +43,23 @@ type V1Img struct { //types:add
 	// target image size to use -- images will be rescaled to this size
 	Size image.Point
 
+	// interpolation used to rescale images to Size
+	Resize vfilter.ResizeModes
+
 	// current input image
 	Img image.Image `display:"-"`
 
 	// input image as an RGB tensor
 	Tsr tensor.Float32 `display:"no-inline"`
 
-	// LMS components + opponents tensor version of image
+	// if true, compute the full LMS components -- else just the Grey component
+	Color bool
+
+	// LMS components + opponents tensor version of image, if Color
 	LMS tensor.Float32 `display:"no-inline"`
+
+	// greyscale (LMS GREY component) version of image, if not Color
+	Grey tensor.Float32 `display:"no-inline"`
 }
 
 func (vi *V1Img) Defaults() {
@@ -69,11 +77,19 @@ func (vi *V1Img) OpenImage(filepath string, filtsz int) error { //types:add
 	}
 	isz := vi.Img.Bounds().Size()
 	if isz != vi.Size {
-		vi.Img = transform.Resize(vi.Img, vi.Size.X, vi.Size.Y, transform.Linear)
+		raw := vfilter.Pool.Get(3, isz.Y, isz.X)
+		vfilter.RGBToTensor(vi.Img, raw, 0, false)
+		vfilter.ResizeTensor(raw, &vi.Tsr, vi.Size, filtsz, vi.Resize) // pad for filt
+		vfilter.Pool.Put(raw)
+	} else {
+		vfilter.RGBToTensor(vi.Img, &vi.Tsr, filtsz, false) // pad for filt, bot zero
 	}
-	vfilter.RGBToTensor(vi.Img, &vi.Tsr, filtsz, false) // pad for filt, bot zero
 	vfilter.WrapPadRGB(&vi.Tsr, filtsz)
-	colorspace.RGBTensorToLMSComps(&vi.LMS, &vi.Tsr)
+	if vi.Color {
+		colorspace.RGBTensorToLMSComps(&vi.LMS, &vi.Tsr)
+	} else {
+		colorspace.RGBTensorToGrey(&vi.Grey, &vi.Tsr)
+	}
 	tensorcore.AddGridStylerTo(&vi.Tsr, func(s *tensorcore.GridStyle) {
 		s.Image = true
 		s.Range.SetMin(0)
@@ -81,6 +97,14 @@ func (vi *V1Img) OpenImage(filepath string, filtsz int) error { //types:add
 	return nil
 }
 
+// GreyImage returns the greyscale image, from LMS if Color
+func (vi *V1Img) GreyImage() *tensor.Float32 {
+	if vi.Color {
+		return colorspace.Component(&vi.LMS, colorspace.GREY)
+	}
+	return &vi.Grey
+}
+
 // V1sOut contains output tensors for V1 Simple filtering, one per opponnent
 type V1sOut struct { //types:add
 
@@ -95,6 +119,9 @@ type V1sOut struct { //types:add
 
 	// V1 simple gabor filter output, max-pooled 2x2 of Kwta tensor
 	PoolTsr tensor.Float32 `display:"no-inline"`
+
+	// converged kwta inhibition: pool-level and layer-level Gi per location
+	GiTsr tensor.Float32 `display:"no-inline"`
 }
 
 // Vis encapsulates specific visual processing pipeline in
@@ -147,6 +174,15 @@ type Vis struct { //types:add
 	// input image reconstructed from V1s tensor
 	ImgFromV1sTsr tensor.Float32 `display:"no-inline"`
 
+	// reconstruction of the color image from the V1s gabor outputs of each opponent channel, if Color
+	V1sDeconvRGB colorspace.DeconvRGB
+
+	// opponent channel images reconstructed from V1s, if Color: [Opponents, Y, X]
+	ImgFromV1sOppTsr tensor.Float32 `display:"no-inline"`
+
+	// RGB color image reconstructed from V1s, if Color
+	ImgFromV1sRGBTsr tensor.Float32 `display:"no-inline"`
+
 	// V1 simple gabor filter output, angle-only features tensor
 	V1sAngOnlyTsr tensor.Float32 `display:"no-inline"`
 
@@ -183,6 +219,7 @@ func (vi *Vis) Defaults() {
 	vi.V1sGeom.Set(image.Point{0, 0}, image.Point{spc, spc}, image.Point{sz, sz})
 	vi.V1sNeighInhib.Defaults()
 	vi.V1sKWTA.Defaults()
+	vi.V1sDeconvRGB.Defaults()
 	// vi.ImgSize = image.Point{64, 64}
 	vi.V1sGabor.ToTensor(&vi.V1sGaborTsr)
 	vi.V1sGaborTab.Init()
@@ -194,6 +231,10 @@ func (vi *Vis) Defaults() {
 	tensorcore.AddGridStylerTo(&vi.ImgFromV1sTsr, func(s *tensorcore.GridStyle) {
 		s.Image = true
 	})
+	tensorcore.AddGridStylerTo(&vi.ImgFromV1sRGBTsr, func(s *tensorcore.GridStyle) {
+		s.Image = true
+		s.Range.SetMin(0)
+	})
 }
 
 // V1SimpleImg runs V1Simple Gabor filtering on input image
@@ -207,7 +248,7 @@ func (vi *Vis) V1SimpleImg(v1s *V1sOut, img *tensor.Float32, gain float32) {
 		v1s.ExtGiTsr.SetZeros()
 	}
 	if vi.V1sKWTA.On {
-		vi.V1sKWTA.KWTAPool(&v1s.Tsr, &v1s.KwtaTsr, &vi.V1sInhibs, &v1s.ExtGiTsr)
+		vi.V1sKWTA.KWTAPoolGi(&v1s.Tsr, &v1s.KwtaTsr, &vi.V1sInhibs, &v1s.ExtGiTsr, &v1s.GiTsr)
 	} else {
 		v1s.KwtaTsr.CopyFrom(&v1s.Tsr)
 	}
@@ -215,17 +256,17 @@ func (vi *Vis) V1SimpleImg(v1s *V1sOut, img *tensor.Float32, gain float32) {
 
 // V1Simple runs all V1Simple Gabor filtering, depending on Color
 func (vi *Vis) V1Simple() {
-	grey := vi.Img.LMS.SubSpace(int(colorspace.GREY)).(*tensor.Float32)
+	grey := vi.Img.GreyImage()
 	wbout := &vi.V1s[colorspace.WhiteBlack]
 	vi.V1SimpleImg(wbout, grey, 1)
 	tensor.SetShapeFrom(&vi.V1sMaxTsr, &wbout.KwtaTsr)
 	vi.V1sMaxTsr.CopyFrom(&wbout.KwtaTsr)
 	if vi.Color {
 		rgout := &vi.V1s[colorspace.RedGreen]
-		rgimg := vi.Img.LMS.SubSpace(int(colorspace.LvMC)).(*tensor.Float32)
+		rgimg := colorspace.Component(&vi.Img.LMS, colorspace.LvMC)
 		vi.V1SimpleImg(rgout, rgimg, vi.ColorGain)
 		byout := &vi.V1s[colorspace.BlueYellow]
-		byimg := vi.Img.LMS.SubSpace(int(colorspace.SvLMC)).(*tensor.Float32)
+		byimg := colorspace.Component(&vi.Img.LMS, colorspace.SvLMC)
 		vi.V1SimpleImg(byout, byimg, vi.ColorGain)
 		for i, vl := range vi.V1sMaxTsr.Values {
 			rg := rgout.KwtaTsr.Values[i]
@@ -248,8 +289,28 @@ func (vi *Vis) ImgFromV1Simple() {
 	vi.ImgFromV1sTsr.SetShapeSizes(vi.Img.Tsr.Shape().Sizes[1:]...)
 	vi.ImgFromV1sTsr.SetZeros()
 	vfilter.UnPool(image.Point{2, 2}, image.Point{2, 2}, &vi.V1sUnPoolTsr, &vi.V1sPoolTsr, true)
-	vfilter.Deconv(&vi.V1sGeom, &vi.V1sGaborTsr, &vi.ImgFromV1sTsr, &vi.V1sUnPoolTsr, vi.V1sGabor.Gain)
+	vfilter.DeconvNorm(&vi.V1sGeom, &vi.V1sGaborTsr, &vi.ImgFromV1sTsr, &vi.V1sUnPoolTsr, vi.V1sGabor.Gain)
 	stats.UnitNormOut(&vi.ImgFromV1sTsr, &vi.ImgFromV1sTsr)
+	if vi.Color {
+		vi.ImgFromV1SimpleRGB()
+	}
+}
+
+// ImgFromV1SimpleRGB reverses V1Simple Gabor filtering from the
+// (pre-kwta) V1s outputs of each opponent channel back to the opponent
+// images, and the RGB color image that they represent.
+func (vi *Vis) ImgFromV1SimpleRGB() {
+	dr := &vi.V1sDeconvRGB
+	var outs [colorspace.OpponentsN]*tensor.Float32
+	for i := range outs {
+		outs[i] = &vi.V1s[i].Tsr
+		dr.Gains[i] = vi.V1sGabor.Gain
+		if i != int(colorspace.WhiteBlack) {
+			dr.Gains[i] *= vi.ColorGain
+		}
+	}
+	dr.SetStats(&vi.Img.LMS, vi.V1sGeom.FiltRt.X)
+	dr.Deconv(&vi.V1sGeom, &vi.V1sGaborTsr, outs, &vi.ImgFromV1sOppTsr, &vi.ImgFromV1sRGBTsr)
 }
 
 // V1Complex runs V1 complex filters on top of V1Simple features.
@@ -274,25 +335,36 @@ func (vi *Vis) V1All() {
 	}
 	vi.V1AllTsr.SetShapeSizes(ny, nx, nrows, nang)
 	// 1 length-sum
-	vfilter.FeatAgg([]int{0}, 0, &vi.V1cLenSumTsr, &vi.V1AllTsr)
+	if err := vfilter.FeatAgg([]int{0}, 0, &vi.V1cLenSumTsr, &vi.V1AllTsr); err != nil {
+		log.Println(err)
+	}
 	// 2 end-stop
-	vfilter.FeatAgg([]int{0, 1}, 1, &vi.V1cEndStopTsr, &vi.V1AllTsr)
+	if err := vfilter.FeatAgg([]int{0, 1}, 1, &vi.V1cEndStopTsr, &vi.V1AllTsr); err != nil {
+		log.Println(err)
+	}
 	// 2 pooled simple cell
 	if vi.Color && vi.SepColor {
 		rgout := &vi.V1s[colorspace.RedGreen]
 		byout := &vi.V1s[colorspace.BlueYellow]
 		vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, &rgout.KwtaTsr, &rgout.PoolTsr)
 		vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, &byout.KwtaTsr, &byout.PoolTsr)
-		vfilter.FeatAgg([]int{0, 1}, 5, &rgout.PoolTsr, &vi.V1AllTsr)
-		vfilter.FeatAgg([]int{0, 1}, 7, &byout.PoolTsr, &vi.V1AllTsr)
+		if err := vfilter.FeatAgg([]int{0, 1}, 5, &rgout.PoolTsr, &vi.V1AllTsr); err != nil {
+			log.Println(err)
+		}
+		if err := vfilter.FeatAgg([]int{0, 1}, 7, &byout.PoolTsr, &vi.V1AllTsr); err != nil {
+			log.Println(err)
+		}
 	} else {
-		vfilter.FeatAgg([]int{0, 1}, 3, &vi.V1sPoolTsr, &vi.V1AllTsr)
+		if err := vfilter.FeatAgg([]int{0, 1}, 3, &vi.V1sPoolTsr, &vi.V1AllTsr); err != nil {
+			log.Println(err)
+		}
 	}
 }
 
 // Filter is overall method to run filters on current image file name
 // loads the image from ImageFile and then runs filters
 func (vi *Vis) Filter() error { //types:add
+	vi.Img.Color = vi.Color
 	err := vi.Img.OpenImage(string(vi.Img.File), vi.V1sGeom.FiltRt.X)
 	if err != nil {
 		log.Println(err)
@@ -20,7 +20,6 @@ import (
 	_ "cogentcore.org/core/tensor/tensorcore" // include to get gui views
 	"cogentcore.org/core/tensor/tmath"
 	"cogentcore.org/core/tree"
-	"github.com/anthonynsimon/bild/transform"
 	"github.com/emer/vision/v2/dog"
 	"github.com/emer/vision/v2/vfilter"
 )
@@ -48,6 +47,9 @@ type Vis struct { //types:add
 	// target image size to use -- images will be rescaled to this size
 	ImgSize image.Point
 
+	// interpolation used to rescale images to ImgSize
+	Resize vfilter.ResizeModes
+
 	// DoG filter tensor -- has 3 filters (on, off, net)
 	DoGTsr tensor.Float32 `display:"no-inline"`
 
@@ -98,12 +100,17 @@ func (vi *Vis) OpenImage(filepath string) error { //types:add
 		log.Println(err)
 		return err
 	}
+	brd := vi.Geom.FiltRt.X
 	isz := vi.Img.Bounds().Size()
 	if isz != vi.ImgSize {
-		vi.Img = transform.Resize(vi.Img, vi.ImgSize.X, vi.ImgSize.Y, transform.Linear)
+		raw := vfilter.Pool.Get(isz.Y, isz.X)
+		vfilter.RGBToGrey(vi.Img, raw, 0, false)
+		vfilter.ResizeTensor(raw, &vi.ImgTsr, vi.ImgSize, brd, vi.Resize) // pad for filt
+		vfilter.Pool.Put(raw)
+	} else {
+		vfilter.RGBToGrey(vi.Img, &vi.ImgTsr, brd, false) // pad for filt, bot zero
 	}
-	vfilter.RGBToGrey(vi.Img, &vi.ImgTsr, vi.Geom.FiltRt.X, false) // pad for filt, bot zero
-	vfilter.WrapPad(&vi.ImgTsr, vi.Geom.FiltRt.X)
+	vfilter.WrapPad(&vi.ImgTsr, brd)
 	return nil
 }
 
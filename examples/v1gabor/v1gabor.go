@@ -18,7 +18,6 @@ import (
 	"cogentcore.org/core/tensor/tensorcore"
 	_ "cogentcore.org/core/tensor/tensorcore" // include to get gui views
 	"cogentcore.org/core/tree"
-	"github.com/anthonynsimon/bild/transform"
 	"github.com/emer/vision/v2/fffb"
 	"github.com/emer/vision/v2/gabor"
 	"github.com/emer/vision/v2/kwta"
@@ -55,6 +54,9 @@ type Vis struct { //types:add
 	// target image size to use -- images will be rescaled to this size
 	ImgSize image.Point
 
+	// interpolation used to rescale images to ImgSize
+	Resize vfilter.ResizeModes
+
 	// V1 simple gabor filter tensor
 	V1sGaborTsr tensor.Float32 `display:"no-inline"`
 
@@ -144,12 +146,17 @@ func (vi *Vis) OpenImage(filepath string) error { //types:add
 		log.Println(err)
 		return err
 	}
+	brd := vi.V1sGeom.FiltRt.X
 	isz := vi.Img.Bounds().Size()
 	if isz != vi.ImgSize {
-		vi.Img = transform.Resize(vi.Img, vi.ImgSize.X, vi.ImgSize.Y, transform.Linear)
+		raw := vfilter.Pool.Get(isz.Y, isz.X)
+		vfilter.RGBToGrey(vi.Img, raw, 0, false)
+		vfilter.ResizeTensor(raw, &vi.ImgTsr, vi.ImgSize, brd, vi.Resize) // pad for filt
+		vfilter.Pool.Put(raw)
+	} else {
+		vfilter.RGBToGrey(vi.Img, &vi.ImgTsr, brd, false) // pad for filt, bot zero
 	}
-	vfilter.RGBToGrey(vi.Img, &vi.ImgTsr, vi.V1sGeom.FiltRt.X, false) // pad for filt, bot zero
-	vfilter.WrapPad(&vi.ImgTsr, vi.V1sGeom.FiltRt.X)
+	vfilter.WrapPad(&vi.ImgTsr, brd)
 	return nil
 }
 
@@ -177,7 +184,7 @@ func (vi *Vis) ImgFromV1Simple() {
 	tensor.SetShapeFrom(&vi.ImgFromV1sTsr, &vi.ImgTsr)
 	vi.ImgFromV1sTsr.SetZeros()
 	vfilter.UnPool(image.Point{2, 2}, image.Point{2, 2}, &vi.V1sUnPoolTsr, &vi.V1sPoolTsr, true)
-	vfilter.Deconv(&vi.V1sGeom, &vi.V1sGaborTsr, &vi.ImgFromV1sTsr, &vi.V1sUnPoolTsr, vi.V1sGabor.Gain)
+	vfilter.DeconvNorm(&vi.V1sGeom, &vi.V1sGaborTsr, &vi.ImgFromV1sTsr, &vi.V1sUnPoolTsr, vi.V1sGabor.Gain)
 	stats.UnitNormOut(&vi.ImgFromV1sTsr, &vi.ImgFromV1sTsr)
 }
 
@@ -200,11 +207,17 @@ func (vi *Vis) V1All() {
 	nrows := 5
 	vi.V1AllTsr.SetShapeSizes(ny, nx, nrows, nang)
 	// 1 length-sum
-	vfilter.FeatAgg([]int{0}, 0, &vi.V1cLenSumTsr, &vi.V1AllTsr)
+	if err := vfilter.FeatAgg([]int{0}, 0, &vi.V1cLenSumTsr, &vi.V1AllTsr); err != nil {
+		log.Println(err)
+	}
 	// 2 end-stop
-	vfilter.FeatAgg([]int{0, 1}, 1, &vi.V1cEndStopTsr, &vi.V1AllTsr)
+	if err := vfilter.FeatAgg([]int{0, 1}, 1, &vi.V1cEndStopTsr, &vi.V1AllTsr); err != nil {
+		log.Println(err)
+	}
 	// 2 pooled simple cell
-	vfilter.FeatAgg([]int{0, 1}, 3, &vi.V1sPoolTsr, &vi.V1AllTsr)
+	if err := vfilter.FeatAgg([]int{0, 1}, 3, &vi.V1sPoolTsr, &vi.V1AllTsr); err != nil {
+		log.Println(err)
+	}
 }
 
 // Filter is overall method to run filters on current image file name
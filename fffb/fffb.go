@@ -9,6 +9,10 @@ based on average (or maximum) excitatory netinput (FF) and activation (FB).
 This produces a robust, graded k-Winners-Take-All dynamic of sparse
 distributed representations having approximately k out of N neurons
 active at any time, where k is typically 10-20 percent of N.
+
+SelfInhib adds optional unit-level self-inhibition based on each unit's
+own recent activation, to reduce the dominance of persistently strong
+units across frames.
 */
 package fffb
 
@@ -37,12 +41,16 @@ type Params struct {
 	// feedforward zero point for average netinput -- below this level, no FF inhibition is computed based on avg netinput, and this value is subtraced from the ff inhib contribution above this value -- the 0.1 default should be good for most cases (and helps FF_FB produce k-winner-take-all dynamics), but if average netinputs are lower than typical, you may need to lower it
 	FF0 float32 `default:"0.1"`
 
+	// unit-level self-inhibition, proportional to each unit's own recent activation
+	Self SelfInhib `display:"inline"`
+
 	// rate = 1 / tau
 	FBDt float32 `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 func (fb *Params) Update() {
 	fb.FBDt = 1 / fb.FBTau
+	fb.Self.Update()
 }
 
 func (fb *Params) Defaults() {
@@ -52,6 +60,7 @@ func (fb *Params) Defaults() {
 	fb.FBTau = 1.4
 	fb.MaxVsAvg = 0
 	fb.FF0 = 0.1
+	fb.Self.Defaults()
 	fb.Update()
 }
 
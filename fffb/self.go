@@ -0,0 +1,59 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fffb
+
+// SelfInhib parameterizes unit-level self-inhibition, where each unit
+// receives additional inhibition proportional to its own recent
+// activation, integrated across calls (e.g., successive frames),
+// which regularizes activity by reducing the dominance of units that
+// are persistently strongly active.  The recent activation state is
+// maintained per unit by the caller.
+type SelfInhib struct {
+
+	// enable self-inhibition
+	On bool
+
+	// gain on the recent activation of each unit for its self-inhibition
+	Gi float32 `min:"0" default:"0.5"`
+
+	// time constant in calls (e.g., frames) for integrating the recent activation of each unit
+	Tau float32 `min:"1" default:"10"`
+
+	// rate = 1 / tau
+	Dt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+}
+
+func (si *SelfInhib) Update() {
+	si.Dt = 1 / si.Tau
+}
+
+func (si *SelfInhib) Defaults() {
+	si.On = false
+	si.Gi = 0.5
+	si.Tau = 10
+	si.Update()
+}
+
+func (si *SelfInhib) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return si.On
+	}
+}
+
+// Inhib returns the self-inhibition for given recent activation of a unit
+func (si *SelfInhib) Inhib(recAct float32) float32 {
+	return si.Gi * recAct
+}
+
+// UpdateRecent integrates given current activations into the
+// recent activations of each unit, which must be the same length.
+func (si *SelfInhib) UpdateRecent(recAct, act []float32) {
+	for i, a := range act {
+		recAct[i] += si.Dt * (a - recAct[i])
+	}
+}
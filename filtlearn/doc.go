@@ -0,0 +1,20 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package filtlearn learns filters from image patches, as an alternative
+to the parametric gabor filters, with the learned filters rendered into
+the same [N, Y, X] filter tensor format used by vfilter.Conv, so they
+can be swapped in directly.
+
+* SamplePatches samples random patches from a set of image tensors.
+
+* PCA computes the principal components of the patches, along with
+the whitening transform used by ICA.
+
+* ICA computes independent components using the FastICA algorithm
+(Hyvarinen & Oja, 2000) on the PCA-whitened patches, which produces
+localized, oriented, gabor-like filters for natural images.
+*/
+package filtlearn
@@ -0,0 +1,50 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package filtlearn
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _InferencesValues = []Inferences{0, 1}
+
+// InferencesN is the highest valid value for type Inferences, plus one.
+const InferencesN Inferences = 2
+
+var _InferencesValueMap = map[string]Inferences{`ISTA`: 0, `MatchingPursuit`: 1}
+
+var _InferencesDescMap = map[Inferences]string{0: `ISTA is the iterative shrinkage-thresholding algorithm, which minimizes the squared reconstruction error plus Lambda times the L1 norm of the code.`, 1: `MatchingPursuit greedily selects up to NActive bases that best match the remaining residual.`}
+
+var _InferencesMap = map[Inferences]string{0: `ISTA`, 1: `MatchingPursuit`}
+
+// String returns the string representation of this Inferences value.
+func (i Inferences) String() string { return enums.String(i, _InferencesMap) }
+
+// SetString sets the Inferences value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Inferences) SetString(s string) error {
+	return enums.SetString(i, s, _InferencesValueMap, "Inferences")
+}
+
+// Int64 returns the Inferences value as an int64.
+func (i Inferences) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Inferences value from an int64.
+func (i *Inferences) SetInt64(in int64) { *i = Inferences(in) }
+
+// Desc returns the description of the Inferences value.
+func (i Inferences) Desc() string { return enums.Desc(i, _InferencesDescMap) }
+
+// InferencesValues returns all possible values for the type Inferences.
+func InferencesValues() []Inferences { return _InferencesValues }
+
+// Values returns all possible values for the type Inferences.
+func (i Inferences) Values() []enums.Enum { return enums.Values(_InferencesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Inferences) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Inferences) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "Inferences")
+}
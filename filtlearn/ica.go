@@ -0,0 +1,178 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filtlearn
+
+import (
+	"math"
+	"math/rand"
+
+	"cogentcore.org/core/tensor"
+)
+
+// ICA computes independent components of a set of patches using
+// the symmetric FastICA algorithm with the tanh (log-cosh) contrast
+// function, on the PCA-whitened patches.  For natural image patches,
+// the resulting Filters and Bases are localized, oriented, and
+// band-pass, similar to gabor filters.
+type ICA struct {
+
+	// number of independent components to compute -- must be <= number of pixels per patch
+	NComps int `default:"16"`
+
+	// maximum number of FastICA iterations
+	MaxIter int `default:"200"`
+
+	// convergence tolerance on the change in the unmixing vectors
+	Tol float64 `default:"0.0001"`
+
+	// random seed for the initial unmixing matrix
+	Seed int64 `default:"1"`
+
+	// renormalize the Filters so the positive and negative parts each sum to 1, as for gabor filters
+	Renorm bool `default:"true"`
+
+	// the PCA used for whitening
+	PCA PCA `display:"-"`
+
+	// number of iterations used in the last Fit
+	NIter int `edit:"-"`
+
+	// unmixing matrix in the whitened space: [NComps][NComps]
+	W []float64 `display:"-"`
+
+	// learned filters in pixel space, for use with vfilter.Conv: [NComps, Y, X]
+	Filters tensor.Float32 `display:"no-inline"`
+
+	// learned basis functions in pixel space, which generate the patches: [NComps, Y, X]
+	Bases tensor.Float32 `display:"no-inline"`
+}
+
+func (ic *ICA) Defaults() {
+	ic.NComps = 16
+	ic.MaxIter = 200
+	ic.Tol = 0.0001
+	ic.Seed = 1
+	ic.Renorm = true
+}
+
+// Fit computes the independent components of given patches,
+// with shape [N, Y, X] (e.g., from Patches.Sample), into
+// Filters and Bases.
+func (ic *ICA) Fit(patches *tensor.Float32) {
+	ic.PCA.Fit(patches)
+	nc := min(ic.NComps, ic.PCA.NPixels())
+	n := patches.DimSize(0)
+	z := ic.PCA.Whiten(nc, patches)
+	rnd := rand.New(rand.NewSource(ic.Seed))
+	w := make([]float64, nc*nc)
+	for i := range w {
+		w[i] = rnd.NormFloat64()
+	}
+	SymDecorrelate(w, nc, nc)
+	wn := make([]float64, nc*nc)
+	for ic.NIter = 0; ic.NIter < ic.MaxIter; ic.NIter++ {
+		for c := 0; c < nc; c++ {
+			wc := w[c*nc : (c+1)*nc]
+			nw := wn[c*nc : (c+1)*nc]
+			clear(nw)
+			var dg float64
+			for i := 0; i < n; i++ {
+				zi := z[i*nc : (i+1)*nc]
+				var u float64
+				for j, v := range wc {
+					u += v * zi[j]
+				}
+				g := math.Tanh(u)
+				dg += 1 - g*g
+				for j, v := range zi {
+					nw[j] += g * v
+				}
+			}
+			for j := range nw {
+				nw[j] = (nw[j] - dg*wc[j]) / float64(n)
+			}
+		}
+		SymDecorrelate(wn, nc, nc)
+		var lim float64
+		for c := 0; c < nc; c++ {
+			var dp float64
+			for j := 0; j < nc; j++ {
+				dp += wn[c*nc+j] * w[c*nc+j]
+			}
+			lim = math.Max(lim, math.Abs(math.Abs(dp)-1))
+		}
+		w, wn = wn, w
+		if lim < ic.Tol {
+			break
+		}
+	}
+	ic.W = w
+	ic.toTensors(nc)
+}
+
+// toTensors computes the Filters and Bases in pixel space
+func (ic *ICA) toTensors(nc int) {
+	pc := &ic.PCA
+	d := pc.NPixels()
+	ic.Filters.SetShapeSizes(nc, pc.Size[0], pc.Size[1])
+	ic.Bases.SetShapeSizes(nc, pc.Size[0], pc.Size[1])
+	for c := 0; c < nc; c++ {
+		fv := ic.Filters.Values[c*d : (c+1)*d]
+		bv := ic.Bases.Values[c*d : (c+1)*d]
+		for p := 0; p < d; p++ {
+			var f, b float64
+			for e := 0; e < nc; e++ {
+				ev := pc.Vecs[e*d+p]
+				sd := math.Sqrt(math.Max(pc.Vals[e], 1e-12))
+				f += ic.W[c*nc+e] * ev / sd
+				b += ic.W[c*nc+e] * ev * sd
+			}
+			fv[p] = float32(f)
+			bv[p] = float32(b)
+		}
+		// sign is arbitrary: make the largest magnitude value positive
+		var mx float32
+		for _, v := range fv {
+			if math.Abs(float64(v)) > math.Abs(float64(mx)) {
+				mx = v
+			}
+		}
+		if mx < 0 {
+			for p := range fv {
+				fv[p] = -fv[p]
+				bv[p] = -bv[p]
+			}
+		}
+	}
+	if ic.Renorm {
+		RenormPosNeg(&ic.Filters)
+	}
+}
+
+// RenormPosNeg renormalizes each filter in an [N, Y, X] filter tensor
+// so that its positive and negative values each sum to 1, as is
+// done for the gabor filters.
+func RenormPosNeg(tsr *tensor.Float32) {
+	nf := tsr.DimSize(0)
+	d := tsr.Len() / max(nf, 1)
+	for f := 0; f < nf; f++ {
+		fv := tsr.Values[f*d : (f+1)*d]
+		var pos, neg float32
+		for _, v := range fv {
+			if v > 0 {
+				pos += v
+			} else {
+				neg -= v
+			}
+		}
+		for i, v := range fv {
+			if v > 0 && pos > 0 {
+				fv[i] = v / pos
+			} else if v < 0 && neg > 0 {
+				fv[i] = v / neg
+			}
+		}
+	}
+}
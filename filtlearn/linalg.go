@@ -0,0 +1,121 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filtlearn
+
+import (
+	"math"
+	"sort"
+)
+
+// SymEigen computes the eigenvalues and eigenvectors of a symmetric
+// n x n matrix (row-major, which is not modified), using the cyclic
+// Jacobi method, returning the eigenvalues in decreasing order,
+// and the corresponding eigenvectors as the rows of vecs (n x n).
+func SymEigen(mat []float64, n int) (vals, vecs []float64) {
+	a := make([]float64, n*n)
+	copy(a, mat)
+	v := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		v[i*n+i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += a[p*n+q] * a[p*n+q]
+			}
+		}
+		if off < 1e-22 {
+			break
+		}
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a[p*n+q]
+				if math.Abs(apq) < 1e-300 {
+					continue
+				}
+				theta := (a[q*n+q] - a[p*n+p]) / (2 * apq)
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp := a[k*n+p]
+					akq := a[k*n+q]
+					a[k*n+p] = c*akp - s*akq
+					a[k*n+q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk := a[p*n+k]
+					aqk := a[q*n+k]
+					a[p*n+k] = c*apk - s*aqk
+					a[q*n+k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp := v[k*n+p]
+					vkq := v[k*n+q]
+					v[k*n+p] = c*vkp - s*vkq
+					v[k*n+q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return a[idx[i]*n+idx[i]] > a[idx[j]*n+idx[j]] })
+	vals = make([]float64, n)
+	vecs = make([]float64, n*n)
+	for i, c := range idx {
+		vals[i] = a[c*n+c]
+		for k := 0; k < n; k++ {
+			vecs[i*n+k] = v[k*n+c]
+		}
+	}
+	return
+}
+
+// SymDecorrelate performs symmetric decorrelation of the rows of
+// the m x n matrix w (m <= n): w = (w w^T)^(-1/2) w, so that the
+// rows are orthonormal, while treating all rows equally.
+func SymDecorrelate(w []float64, m, n int) {
+	wwt := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := i; j < m; j++ {
+			var s float64
+			for k := 0; k < n; k++ {
+				s += w[i*n+k] * w[j*n+k]
+			}
+			wwt[i*m+j] = s
+			wwt[j*m+i] = s
+		}
+	}
+	vals, vecs := SymEigen(wwt, m)
+	// isq = E diag(1/sqrt(d)) E^T
+	isq := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			var s float64
+			for e := 0; e < m; e++ {
+				s += vecs[e*m+i] * vecs[e*m+j] / math.Sqrt(math.Max(vals[e], 1e-12))
+			}
+			isq[i*m+j] = s
+		}
+	}
+	res := make([]float64, m*n)
+	for i := 0; i < m; i++ {
+		for k := 0; k < n; k++ {
+			var s float64
+			for j := 0; j < m; j++ {
+				s += isq[i*m+j] * w[j*n+k]
+			}
+			res[i*n+k] = s
+		}
+	}
+	copy(w, res)
+}
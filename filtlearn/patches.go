@@ -0,0 +1,80 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filtlearn
+
+//go:generate core generate -add-types
+
+import (
+	"math/rand"
+
+	"cogentcore.org/core/tensor"
+)
+
+// Patches has parameters for sampling patches from images
+type Patches struct {
+
+	// size of the square patches, in pixels
+	Size int `default:"12"`
+
+	// number of patches to sample in total, evenly divided across the images
+	N int `default:"10000"`
+
+	// border in pixels to exclude from sampling on all sides of each image, e.g., the padding added to image tensors
+	Border int
+
+	// subtract the mean value from each patch, removing the DC component
+	SubMean bool `default:"true"`
+
+	// random seed for sampling
+	Seed int64 `default:"1"`
+}
+
+func (pt *Patches) Defaults() {
+	pt.Size = 12
+	pt.N = 10000
+	pt.SubMean = true
+	pt.Seed = 1
+}
+
+// Sample samples patches at random positions from the given 2D
+// [Y, X] grey image tensors, into out with shape [N, Size, Size].
+// Images that are too small for a patch are skipped.
+func (pt *Patches) Sample(imgs []*tensor.Float32, out *tensor.Float32) {
+	sz := pt.Size
+	out.SetShapeSizes(pt.N, sz, sz)
+	rnd := rand.New(rand.NewSource(pt.Seed))
+	var ok []*tensor.Float32
+	for _, img := range imgs {
+		if img.DimSize(0)-2*pt.Border >= sz && img.DimSize(1)-2*pt.Border >= sz {
+			ok = append(ok, img)
+		}
+	}
+	if len(ok) == 0 {
+		out.SetShapeSizes(0, sz, sz)
+		return
+	}
+	np := sz * sz
+	for i := 0; i < pt.N; i++ {
+		img := ok[i%len(ok)]
+		sx := img.DimSize(1)
+		y0 := pt.Border + rnd.Intn(img.DimSize(0)-2*pt.Border-sz+1)
+		x0 := pt.Border + rnd.Intn(sx-2*pt.Border-sz+1)
+		pv := out.Values[i*np : (i+1)*np]
+		var sum float32
+		for y := 0; y < sz; y++ {
+			copy(pv[y*sz:(y+1)*sz], img.Values[(y0+y)*sx+x0:])
+		}
+		if !pt.SubMean {
+			continue
+		}
+		for _, v := range pv {
+			sum += v
+		}
+		mn := sum / float32(np)
+		for j := range pv {
+			pv[j] -= mn
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filtlearn
+
+import (
+	"math"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// PCA computes the principal components of a set of patches
+type PCA struct {
+
+	// patch size: Y, X
+	Size [2]int `edit:"-"`
+
+	// mean over patches of each pixel
+	Mean []float64 `display:"-"`
+
+	// eigenvalues (variances) of the components, in decreasing order
+	Vals []float64 `display:"-"`
+
+	// eigenvectors of the components as rows [Components][Pixels], in order of decreasing eigenvalue
+	Vecs []float64 `display:"-"`
+}
+
+// Fit computes the principal components of given patches,
+// with shape [N, Y, X].
+func (pc *PCA) Fit(patches *tensor.Float32) {
+	n := patches.DimSize(0)
+	pc.Size = [2]int{patches.DimSize(1), patches.DimSize(2)}
+	d := pc.Size[0] * pc.Size[1]
+	pc.Mean = make([]float64, d)
+	for i := 0; i < n; i++ {
+		for j, v := range patches.Values[i*d : (i+1)*d] {
+			pc.Mean[j] += float64(v)
+		}
+	}
+	for j := range pc.Mean {
+		pc.Mean[j] /= float64(max(n, 1))
+	}
+	cov := make([]float64, d*d)
+	nproc.Run("PCAFit", d, func(st, n int) {
+		pc.covThr(st, n, patches, cov)
+	})
+	for r := 0; r < d; r++ { // fill in lower triangle
+		for c := 0; c < r; c++ {
+			cov[r*d+c] = cov[c*d+r]
+		}
+	}
+	pc.Vals, pc.Vecs = SymEigen(cov, d)
+}
+
+// covThr is per-thread implementation, computing upper triangle rows
+func (pc *PCA) covThr(rst, nr int, patches *tensor.Float32, cov []float64) {
+	n := patches.DimSize(0)
+	d := len(pc.Mean)
+	for r := rst; r < rst+nr; r++ {
+		for i := 0; i < n; i++ {
+			pv := patches.Values[i*d : (i+1)*d]
+			rv := float64(pv[r]) - pc.Mean[r]
+			for c := r; c < d; c++ {
+				cov[r*d+c] += rv * (float64(pv[c]) - pc.Mean[c])
+			}
+		}
+		for c := r; c < d; c++ {
+			cov[r*d+c] /= float64(max(n-1, 1))
+		}
+	}
+}
+
+// NPixels returns the number of pixels per patch
+func (pc *PCA) NPixels() int {
+	return pc.Size[0] * pc.Size[1]
+}
+
+// ToTensor renders the first nc components into given filter
+// tensor, with shape [nc, Y, X].
+func (pc *PCA) ToTensor(nc int, tsr *tensor.Float32) {
+	d := pc.NPixels()
+	tsr.SetShapeSizes(nc, pc.Size[0], pc.Size[1])
+	for i, v := range pc.Vecs[:nc*d] {
+		tsr.Values[i] = float32(v)
+	}
+}
+
+// Whiten returns the whitened, dimension-reduced projections of the
+// patches onto the first nc components, each scaled to unit variance:
+// [N][nc] row-major.
+func (pc *PCA) Whiten(nc int, patches *tensor.Float32) []float64 {
+	n := patches.DimSize(0)
+	d := pc.NPixels()
+	z := make([]float64, n*nc)
+	xc := make([]float64, d)
+	for i := 0; i < n; i++ {
+		for j, v := range patches.Values[i*d : (i+1)*d] {
+			xc[j] = float64(v) - pc.Mean[j]
+		}
+		for e := 0; e < nc; e++ {
+			var s float64
+			for j, v := range pc.Vecs[e*d : (e+1)*d] {
+				s += v * xc[j]
+			}
+			z[i*nc+e] = s / math.Sqrt(math.Max(pc.Vals[e], 1e-12))
+		}
+	}
+	return z
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package filtlearn
+
+import (
+	"image"
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Inferences are the sparse code inference algorithms
+type Inferences int32 //enums:enum
+
+const (
+	// ISTA is the iterative shrinkage-thresholding algorithm, which
+	// minimizes the squared reconstruction error plus Lambda times the
+	// L1 norm of the code.
+	ISTA Inferences = iota
+
+	// MatchingPursuit greedily selects up to NActive bases that best
+	// match the remaining residual.
+	MatchingPursuit
+)
+
+// Sparse is an Olshausen & Field (1996) style sparse coding learner,
+// which learns a dictionary of bases such that each patch is
+// reconstructed as a sparse linear combination of the bases, by
+// alternating sparse code inference with gradient updates of the
+// bases to reduce the reconstruction error.  For natural image
+// patches, the learned bases are localized, oriented, and band-pass.
+type Sparse struct {
+
+	// number of bases in the dictionary
+	NBases int `default:"64"`
+
+	// sparse code inference algorithm
+	Inference Inferences
+
+	// sparsity penalty on the L1 norm of the code, for ISTA
+	Lambda float32 `default:"0.1"`
+
+	// number of ISTA iterations per inference
+	NIter int `default:"100"`
+
+	// maximum number of active bases per patch, for MatchingPursuit
+	NActive int `default:"8"`
+
+	// learning rate for dictionary updates
+	LRate float32 `default:"0.1"`
+
+	// number of patches per dictionary update
+	BatchSize int `default:"100"`
+
+	// random seed for the initial dictionary and batch sampling
+	Seed int64 `default:"1"`
+
+	// patch size: Y, X
+	Size [2]int `edit:"-"`
+
+	// the dictionary of bases, each normalized to unit length: [NBases, Y, X] -- also usable as a filter bank for vfilter.Conv
+	Bases tensor.Float32 `display:"no-inline"`
+
+	// ISTA step size, 1 / the largest eigenvalue of the bases gram matrix
+	step float32
+
+	// random number generator
+	rnd *rand.Rand
+}
+
+func (sc *Sparse) Defaults() {
+	sc.NBases = 64
+	sc.Inference = ISTA
+	sc.Lambda = 0.1
+	sc.NIter = 100
+	sc.NActive = 8
+	sc.LRate = 0.1
+	sc.BatchSize = 100
+	sc.Seed = 1
+}
+
+// Init initializes the dictionary to random unit-length bases
+// of given patch size.
+func (sc *Sparse) Init(sy, sx int) {
+	sc.Size = [2]int{sy, sx}
+	sc.rnd = rand.New(rand.NewSource(sc.Seed))
+	sc.Bases.SetShapeSizes(sc.NBases, sy, sx)
+	for i := range sc.Bases.Values {
+		sc.Bases.Values[i] = float32(sc.rnd.NormFloat64())
+	}
+	sc.normBases()
+}
+
+// NPixels returns the number of pixels per patch
+func (sc *Sparse) NPixels() int {
+	return sc.Size[0] * sc.Size[1]
+}
+
+// normBases normalizes the bases to unit length, and updates
+// the ISTA step size
+func (sc *Sparse) normBases() {
+	d := sc.NPixels()
+	for b := 0; b < sc.NBases; b++ {
+		bv := sc.Bases.Values[b*d : (b+1)*d]
+		var ss float32
+		for _, v := range bv {
+			ss += v * v
+		}
+		if ss == 0 {
+			continue
+		}
+		nrm := 1 / math32.Sqrt(ss)
+		for i := range bv {
+			bv[i] *= nrm
+		}
+	}
+	sc.step = 1 / sc.maxEigen()
+}
+
+// maxEigen estimates the largest eigenvalue of the gram matrix of the
+// bases (B B^T), using power iteration
+func (sc *Sparse) maxEigen() float32 {
+	d := sc.NPixels()
+	nb := sc.NBases
+	v := make([]float32, nb)
+	u := make([]float32, d)
+	for i := range v {
+		v[i] = 1
+	}
+	var ev float32 = 1
+	for it := 0; it < 30; it++ {
+		sc.reconstruct(v, u)
+		var nrm float32
+		for b := 0; b < nb; b++ {
+			v[b] = dot(sc.Bases.Values[b*d:(b+1)*d], u)
+			nrm += v[b] * v[b]
+		}
+		nrm = math32.Sqrt(nrm)
+		if nrm == 0 {
+			break
+		}
+		ev = nrm
+		for b := range v {
+			v[b] /= nrm
+		}
+	}
+	return ev
+}
+
+// Learn learns the dictionary from given patches, with shape
+// [N, Y, X] (e.g., from Patches.Sample), for given number of
+// dictionary updates, each on a random batch of BatchSize patches.
+// The dictionary is initialized if the patch size does not match.
+func (sc *Sparse) Learn(patches *tensor.Float32, nUpdates int) {
+	sy := patches.DimSize(1)
+	sx := patches.DimSize(2)
+	if sc.Size != [2]int{sy, sx} || sc.Bases.DimSize(0) != sc.NBases {
+		sc.Init(sy, sx)
+	}
+	if sc.rnd == nil {
+		sc.rnd = rand.New(rand.NewSource(sc.Seed))
+	}
+	n := patches.DimSize(0)
+	d := sc.NPixels()
+	nb := sc.NBases
+	bs := min(sc.BatchSize, n)
+	var batch, codes tensor.Float32
+	batch.SetShapeSizes(bs, sy, sx)
+	grad := make([]float32, nb*d)
+	res := make([]float32, d)
+	for up := 0; up < nUpdates; up++ {
+		for i := 0; i < bs; i++ {
+			pi := sc.rnd.Intn(n)
+			copy(batch.Values[i*d:(i+1)*d], patches.Values[pi*d:(pi+1)*d])
+		}
+		sc.Encode(&batch, &codes)
+		clear(grad)
+		for i := 0; i < bs; i++ {
+			cv := codes.Values[i*nb : (i+1)*nb]
+			sc.reconstruct(cv, res)
+			for j, v := range batch.Values[i*d : (i+1)*d] {
+				res[j] = v - res[j]
+			}
+			for b, a := range cv {
+				if a == 0 {
+					continue
+				}
+				gv := grad[b*d : (b+1)*d]
+				for j, r := range res {
+					gv[j] += a * r
+				}
+			}
+		}
+		lr := sc.LRate / float32(bs)
+		for i, g := range grad {
+			sc.Bases.Values[i] += lr * g
+		}
+		sc.normBases()
+	}
+}
+
+// Encode infers the sparse codes for given patches, with shape
+// [N, Y, X] matching the Bases size, into codes with shape [N, NBases].
+func (sc *Sparse) Encode(patches, codes *tensor.Float32) {
+	npat := patches.DimSize(0)
+	codes.SetShapeSizes(npat, sc.NBases)
+	if sc.step == 0 {
+		sc.normBases()
+	}
+	nproc.Run("SparseEncode", npat, func(st, n int) {
+		sc.encodeThr(st, n, patches, codes)
+	})
+}
+
+// encodeThr is per-thread implementation
+func (sc *Sparse) encodeThr(st, n int, patches, codes *tensor.Float32) {
+	d := sc.NPixels()
+	nb := sc.NBases
+	res := make([]float32, d)
+	for i := st; i < st+n; i++ {
+		sc.EncodeOne(patches.Values[i*d:(i+1)*d], codes.Values[i*nb:(i+1)*nb], res)
+	}
+}
+
+// EncodeOne infers the sparse code for one patch of NPixels values,
+// into code of NBases values, using res as a NPixels residual buffer.
+func (sc *Sparse) EncodeOne(patch, code, res []float32) {
+	d := sc.NPixels()
+	clear(code)
+	switch sc.Inference {
+	case ISTA:
+		thr := sc.Lambda * sc.step
+		for it := 0; it < sc.NIter; it++ {
+			sc.reconstruct(code, res)
+			for j, v := range patch {
+				res[j] = v - res[j]
+			}
+			for b := range code {
+				v := code[b] + sc.step*dot(sc.Bases.Values[b*d:(b+1)*d], res)
+				switch {
+				case v > thr:
+					code[b] = v - thr
+				case v < -thr:
+					code[b] = v + thr
+				default:
+					code[b] = 0
+				}
+			}
+		}
+	case MatchingPursuit:
+		copy(res, patch)
+		for it := 0; it < sc.NActive; it++ {
+			mb := -1
+			var mv float32
+			for b := range code {
+				v := dot(sc.Bases.Values[b*d:(b+1)*d], res)
+				if math32.Abs(v) > math32.Abs(mv) {
+					mb, mv = b, v
+				}
+			}
+			if mb < 0 {
+				break
+			}
+			code[mb] += mv
+			for j, bv := range sc.Bases.Values[mb*d : (mb+1)*d] {
+				res[j] -= mv * bv
+			}
+		}
+	}
+}
+
+// EncodeImage infers the sparse codes for patches of given 2D [Y, X]
+// grey image tensor at the positions given by the geometry, as for
+// vfilter.Conv, with the geometry filter size set to the Bases size.
+// Output has shape [Y, X, 2, NBases], with the positive part of the
+// codes in the first row and the negative part in the second,
+// corresponding to the on / off polarities of Conv output.
+func (sc *Sparse) EncodeImage(geom *vfilter.Geom, img, out *tensor.Float32) {
+	geom.FiltSz = image.Point{sc.Size[1], sc.Size[0]}
+	geom.UpdtFilt()
+	geom.SetSize(image.Point{img.DimSize(1), img.DimSize(0)})
+	ny := geom.Out.Y
+	nx := geom.Out.X
+	d := sc.NPixels()
+	patches := vfilter.Pool.Get(ny*nx, sc.Size[0], sc.Size[1])
+	codes := vfilter.Pool.Get(ny*nx, sc.NBases)
+	isx := img.DimSize(1)
+	ist := geom.Border.Sub(geom.FiltLt)
+	for y := 0; y < ny; y++ {
+		iy := ist.Y + y*geom.Spacing.Y
+		for x := 0; x < nx; x++ {
+			ix := ist.X + x*geom.Spacing.X
+			pv := patches.Values[(y*nx+x)*d:]
+			for fy := 0; fy < sc.Size[0]; fy++ {
+				copy(pv[fy*sc.Size[1]:(fy+1)*sc.Size[1]], img.Values[(iy+fy)*isx+ix:])
+			}
+		}
+	}
+	sc.Encode(patches, codes)
+	nb := sc.NBases
+	out.SetShapeSizes(ny, nx, 2, nb)
+	for i := 0; i < ny*nx; i++ {
+		cv := codes.Values[i*nb : (i+1)*nb]
+		ov := out.Values[i*2*nb : (i+1)*2*nb]
+		for b, v := range cv {
+			ov[b] = math32.Max(v, 0)
+			ov[nb+b] = math32.Max(-v, 0)
+		}
+	}
+	vfilter.Pool.Put(patches, codes)
+}
+
+// reconstruct computes the reconstruction from given code into out
+func (sc *Sparse) reconstruct(code, out []float32) {
+	d := sc.NPixels()
+	clear(out)
+	for b, a := range code {
+		if a == 0 {
+			continue
+		}
+		for j, v := range sc.Bases.Values[b*d : (b+1)*d] {
+			out[j] += a * v
+		}
+	}
+}
+
+// dot returns the dot product of two slices, of the length of a
+func dot(a, b []float32) float32 {
+	var s float32
+	for i, v := range a {
+		s += v * b[i]
+	}
+	return s
+}
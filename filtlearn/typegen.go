@@ -0,0 +1,17 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package filtlearn
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/filtlearn.ICA", IDName: "ica", Doc: "ICA computes independent components of a set of patches using\nthe symmetric FastICA algorithm with the tanh (log-cosh) contrast\nfunction, on the PCA-whitened patches.  For natural image patches,\nthe resulting Filters and Bases are localized, oriented, and\nband-pass, similar to gabor filters.", Fields: []types.Field{{Name: "NComps", Doc: "number of independent components to compute -- must be <= number of pixels per patch"}, {Name: "MaxIter", Doc: "maximum number of FastICA iterations"}, {Name: "Tol", Doc: "convergence tolerance on the change in the unmixing vectors"}, {Name: "Seed", Doc: "random seed for the initial unmixing matrix"}, {Name: "Renorm", Doc: "renormalize the Filters so the positive and negative parts each sum to 1, as for gabor filters"}, {Name: "PCA", Doc: "the PCA used for whitening"}, {Name: "NIter", Doc: "number of iterations used in the last Fit"}, {Name: "W", Doc: "unmixing matrix in the whitened space: [NComps][NComps]"}, {Name: "Filters", Doc: "learned filters in pixel space, for use with vfilter.Conv: [NComps, Y, X]"}, {Name: "Bases", Doc: "learned basis functions in pixel space, which generate the patches: [NComps, Y, X]"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/filtlearn.Patches", IDName: "patches", Doc: "Patches has parameters for sampling patches from images", Fields: []types.Field{{Name: "Size", Doc: "size of the square patches, in pixels"}, {Name: "N", Doc: "number of patches to sample in total, evenly divided across the images"}, {Name: "Border", Doc: "border in pixels to exclude from sampling on all sides of each image, e.g., the padding added to image tensors"}, {Name: "SubMean", Doc: "subtract the mean value from each patch, removing the DC component"}, {Name: "Seed", Doc: "random seed for sampling"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/filtlearn.PCA", IDName: "pca", Doc: "PCA computes the principal components of a set of patches", Fields: []types.Field{{Name: "Size", Doc: "patch size: Y, X"}, {Name: "Mean", Doc: "mean over patches of each pixel"}, {Name: "Vals", Doc: "eigenvalues (variances) of the components, in decreasing order"}, {Name: "Vecs", Doc: "eigenvectors of the components as rows [Components][Pixels], in order of decreasing eigenvalue"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/filtlearn.Inferences", IDName: "inferences", Doc: "Inferences are the sparse code inference algorithms"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/filtlearn.Sparse", IDName: "sparse", Doc: "Sparse is an Olshausen & Field (1996) style sparse coding learner,\nwhich learns a dictionary of bases such that each patch is\nreconstructed as a sparse linear combination of the bases, by\nalternating sparse code inference with gradient updates of the\nbases to reduce the reconstruction error.  For natural image\npatches, the learned bases are localized, oriented, and band-pass.", Fields: []types.Field{{Name: "NBases", Doc: "number of bases in the dictionary"}, {Name: "Inference", Doc: "sparse code inference algorithm"}, {Name: "Lambda", Doc: "sparsity penalty on the L1 norm of the code, for ISTA"}, {Name: "NIter", Doc: "number of ISTA iterations per inference"}, {Name: "NActive", Doc: "maximum number of active bases per patch, for MatchingPursuit"}, {Name: "LRate", Doc: "learning rate for dictionary updates"}, {Name: "BatchSize", Doc: "number of patches per dictionary update"}, {Name: "Seed", Doc: "random seed for the initial dictionary and batch sampling"}, {Name: "Size", Doc: "patch size: Y, X"}, {Name: "Bases", Doc: "the dictionary of bases, each normalized to unit length: [NBases, Y, X] -- also usable as a filter bank for vfilter.Conv"}, {Name: "step", Doc: "ISTA step size, 1 / the largest eigenvalue of the bases gram matrix"}, {Name: "rnd", Doc: "random number generator"}}})
@@ -0,0 +1,310 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gabor
+
+import (
+	"math"
+	"sort"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// FitParams are the gabor parameters estimated by Fit for a 2D filter,
+// using the same conventions as Filter: Angle 0 is horizontal
+// (the sine wave varies along Y), and Phase 0 is asymmetric (sine).
+// Unlike Filter, the sigmas are in pixels, not proportions of size.
+type FitParams struct {
+
+	// orientation angle in degrees, in [0, 180)
+	Angle float32
+
+	// wavelength of the sine wave, in pixels
+	WvLen float32
+
+	// spatial frequency, in cycles per pixel: 1 / WvLen
+	Freq float32
+
+	// phase offset of the sine wave, in degrees, in [0, 360)
+	Phase float32
+
+	// gaussian sigma along the length dimension (elongated axis perpendicular to the sine waves), in pixels
+	SigLen float32
+
+	// gaussian sigma along the width dimension (in the direction of the sine waves), in pixels
+	SigWd float32
+
+	// X offset of the gaussian center from the center of the filter, in pixels
+	CtrX float32
+
+	// Y offset of the gaussian center from the center of the filter, in pixels
+	CtrY float32
+
+	// amplitude multiplier
+	Amp float32
+
+	// proportion of variance in the filter explained by the fitted gabor
+	R2 float32
+}
+
+// Fit returns the best-fitting gabor parameters for given 2D [Y, X]
+// filter, e.g., learned weights from a network layer, by minimizing the
+// squared error, starting from the best matches over a grid of angles
+// and wavelengths, refined with the Nelder-Mead simplex method.
+// The parameters optimized are angle, wavelength, phase, sigmas and
+// center, with the amplitude computed analytically.
+func Fit(flt *tensor.Float32) FitParams {
+	sy := flt.DimSize(0)
+	sx := flt.DimSize(1)
+	f := make([]float64, sy*sx)
+	var mean float64
+	for i, v := range flt.Values[:sy*sx] {
+		f[i] = float64(v)
+		mean += f[i]
+	}
+	mean /= float64(len(f))
+	var sst float64
+	for _, v := range f {
+		sst += (v - mean) * (v - mean)
+	}
+	g := make([]float64, len(f))
+	sse := func(p []float64) float64 {
+		gaborModel(p, sy, sx, g)
+		_, e := fitAmp(f, g)
+		return e
+	}
+
+	// grid search for starting points
+	type start struct {
+		p []float64
+		e float64
+	}
+	var starts []start
+	sz := float64(max(sy, sx))
+	for ai := 0; ai < 12; ai++ {
+		ang := math.Pi * float64(ai) / 12
+		for wl := 2.0; wl <= 2*sz; wl *= 1.3 {
+			for pi := 0; pi < 4; pi++ {
+				phs := 0.5 * math.Pi * float64(pi)
+				sig := math.Min(0.4*wl, 0.3*sz)
+				p := []float64{ang, wl, phs, sig, sig, 0, 0}
+				starts = append(starts, start{p, sse(p)})
+			}
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].e < starts[j].e })
+	best := starts[0].p
+	bestE := starts[0].e
+	for _, st := range starts[:min(4, len(starts))] {
+		p, e := nelderMead(sse, st.p, 2000)
+		if e < bestE {
+			best, bestE = p, e
+		}
+	}
+	gaborModel(best, sy, sx, g)
+	amp, _ := fitAmp(f, g)
+	return normFitParams(best, amp, bestE, sst)
+}
+
+// normFitParams returns the FitParams for given parameter vector,
+// normalizing to positive amplitude, wavelength and sigmas,
+// angle in [0, 180) and phase in [0, 360) degrees.
+func normFitParams(p []float64, amp, sse, sst float64) FitParams {
+	ang, wl, phs := p[0], math.Abs(p[1]), p[2]
+	if p[1] < 0 { // sin(-k y + phs) = sin(k y - phs + pi)
+		phs = math.Pi - phs
+	}
+	if amp < 0 {
+		amp = -amp
+		phs += math.Pi
+	}
+	ang = math.Mod(ang, 2*math.Pi)
+	if ang < 0 {
+		ang += 2 * math.Pi
+	}
+	if ang >= math.Pi { // rotation by pi flips the sine axis
+		ang -= math.Pi
+		phs = math.Pi - phs
+	}
+	phs = math.Mod(phs, 2*math.Pi)
+	if phs < 0 {
+		phs += 2 * math.Pi
+	}
+	fp := FitParams{}
+	fp.Angle = float32(ang * 180 / math.Pi)
+	fp.WvLen = float32(wl)
+	if wl > 0 {
+		fp.Freq = float32(1 / wl)
+	}
+	fp.Phase = float32(phs * 180 / math.Pi)
+	if fp.Phase >= 360 { // float32 rounding
+		fp.Phase = 0
+	}
+	if fp.Angle >= 180 {
+		fp.Angle = 0
+	}
+	fp.SigLen = float32(math.Abs(p[3]))
+	fp.SigWd = float32(math.Abs(p[4]))
+	fp.CtrX = float32(p[5])
+	fp.CtrY = float32(p[6])
+	fp.Amp = float32(amp)
+	if sst > 0 {
+		fp.R2 = float32(1 - sse/sst)
+	}
+	return fp
+}
+
+// ToTensor renders the fitted gabor into given 2D tensor of given size
+func (fp *FitParams) ToTensor(sy, sx int, tsr *tensor.Float32) {
+	tsr.SetShapeSizes(sy, sx)
+	p := []float64{float64(fp.Angle) * math.Pi / 180, float64(fp.WvLen), float64(fp.Phase) * math.Pi / 180, float64(fp.SigLen), float64(fp.SigWd), float64(fp.CtrX), float64(fp.CtrY)}
+	g := make([]float64, sy*sx)
+	gaborModel(p, sy, sx, g)
+	for i, v := range g {
+		tsr.Values[i] = float32(v) * fp.Amp
+	}
+}
+
+// FitTable fits gabor parameters to each filter in given [N, Y, X]
+// tensor of filters (e.g., learned receptive fields), and records the
+// resulting parameters in given table, with one row per filter, for
+// population analyses.
+func FitTable(flts *tensor.Float32, tab *table.Table) {
+	nf := flts.DimSize(0)
+	cols := []string{"Angle", "WvLen", "Freq", "Phase", "SigLen", "SigWd", "CtrX", "CtrY", "Amp", "R2"}
+	tab.AddIntColumn("Filter")
+	for _, c := range cols {
+		tab.AddFloat32Column(c)
+	}
+	tab.SetNumRows(nf)
+	for i := 0; i < nf; i++ {
+		fp := Fit(flts.SubSpace(i).(*tensor.Float32))
+		vals := []float32{fp.Angle, fp.WvLen, fp.Freq, fp.Phase, fp.SigLen, fp.SigWd, fp.CtrX, fp.CtrY, fp.Amp, fp.R2}
+		tab.ColumnByIndex(0).SetFloat1D(float64(i), i)
+		for c, v := range vals {
+			tab.ColumnByIndex(c+1).SetFloat1D(float64(v), i)
+		}
+	}
+}
+
+// gaborModel renders the unit-amplitude gabor for given parameters
+// into g, using the same geometry as Filter.ToTensor
+func gaborModel(p []float64, sy, sx int, g []float64) {
+	angf := -p[0]
+	k := 2 * math.Pi / p[1]
+	lenNorm := 1 / (2 * p[3] * p[3])
+	wdNorm := 1 / (2 * p[4] * p[4])
+	cos := math.Cos(angf)
+	sin := math.Sin(angf)
+	cx := 0.5*float64(sx-1) + p[5]
+	cy := 0.5*float64(sy-1) + p[6]
+	for y := 0; y < sy; y++ {
+		yf := float64(y) - cy
+		for x := 0; x < sx; x++ {
+			xf := float64(x) - cx
+			nx := xf*cos - yf*sin
+			ny := yf*cos + xf*sin
+			gauss := math.Exp(-(lenNorm*nx*nx + wdNorm*ny*ny))
+			g[y*sx+x] = gauss * math.Sin(k*ny+p[2])
+		}
+	}
+}
+
+// fitAmp returns the least-squares amplitude for fitting g to f,
+// and the resulting sum squared error
+func fitAmp(f, g []float64) (amp, sse float64) {
+	var fg, gg, ff float64
+	for i, fv := range f {
+		fg += fv * g[i]
+		gg += g[i] * g[i]
+		ff += fv * fv
+	}
+	if gg == 0 || math.IsNaN(gg) {
+		return 0, ff
+	}
+	amp = fg / gg
+	return amp, ff - amp*fg
+}
+
+// nelderMead minimizes given function starting from p, using the
+// Nelder-Mead simplex method, returning the best point and value
+func nelderMead(fun func(p []float64) float64, p0 []float64, maxIter int) ([]float64, float64) {
+	n := len(p0)
+	simp := make([][]float64, n+1)
+	vals := make([]float64, n+1)
+	for i := range simp {
+		simp[i] = append([]float64(nil), p0...)
+		if i > 0 {
+			d := 0.1 * math.Abs(p0[i-1])
+			if d < 0.2 {
+				d = 0.2
+			}
+			simp[i][i-1] += d
+		}
+		vals[i] = fun(simp[i])
+	}
+	ctr := make([]float64, n)
+	pt := func(a float64, b []float64) []float64 { // ctr + a * (ctr - b)
+		r := make([]float64, n)
+		for j := range r {
+			r[j] = ctr[j] + a*(ctr[j]-b[j])
+		}
+		return r
+	}
+	for it := 0; it < maxIter; it++ {
+		idx := make([]int, n+1)
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.Slice(idx, func(i, j int) bool { return vals[idx[i]] < vals[idx[j]] })
+		ns := make([][]float64, n+1)
+		nv := make([]float64, n+1)
+		for i, x := range idx {
+			ns[i], nv[i] = simp[x], vals[x]
+		}
+		simp, vals = ns, nv
+		if vals[n]-vals[0] <= 1e-12*(math.Abs(vals[0])+1e-20) {
+			break
+		}
+		clear(ctr)
+		for i := 0; i < n; i++ {
+			for j := range ctr {
+				ctr[j] += simp[i][j] / float64(n)
+			}
+		}
+		refl := pt(1, simp[n])
+		rv := fun(refl)
+		switch {
+		case rv < vals[0]:
+			exp := pt(2, simp[n])
+			if ev := fun(exp); ev < rv {
+				simp[n], vals[n] = exp, ev
+			} else {
+				simp[n], vals[n] = refl, rv
+			}
+		case rv < vals[n-1]:
+			simp[n], vals[n] = refl, rv
+		default:
+			con := pt(-0.5, simp[n])
+			if cv := fun(con); cv < vals[n] {
+				simp[n], vals[n] = con, cv
+			} else { // shrink toward best
+				for i := 1; i <= n; i++ {
+					for j := range simp[i] {
+						simp[i][j] = simp[0][j] + 0.5*(simp[i][j]-simp[0][j])
+					}
+					vals[i] = fun(simp[i])
+				}
+			}
+		}
+	}
+	bi := 0
+	for i := range vals {
+		if vals[i] < vals[bi] {
+			bi = i
+		}
+	}
+	return simp[bi], vals[bi]
+}
@@ -94,7 +94,25 @@ func (gf *Filter) SetSize(sz, spc int) {
 // ToTensor renders filters into the given table tensor.Tensor,
 // setting dimensions to [angle][Y][X] where Y = X = Size
 func (gf *Filter) ToTensor(tsr *tensor.Float32) {
-	tsr.SetShapeSizes(gf.NAngles, gf.Size, gf.Size)
+	gf.ToTensorPhases(tsr, gf.Phase)
+}
+
+// ToTensorPhases renders a multi-phase bank of filters into the given
+// tensor, with the given phases (in degrees) in place of Phase, setting
+// dimensions to [phase * angle][Y][X] where Y = X = Size, with all the
+// angles for the first phase first, and so on, e.g., phases 0, 90 for
+// quadrature pairs.  Use vfilter.ConvPhase to preserve the signed
+// responses of each phase.
+func (gf *Filter) ToTensorPhases(tsr *tensor.Float32, phases ...float32) {
+	tsr.SetShapeSizes(len(phases)*gf.NAngles, gf.Size, gf.Size)
+	for pi, phs := range phases {
+		gf.renderPhase(tsr, pi*gf.NAngles, phs)
+	}
+}
+
+// renderPhase renders the filters for all angles at given phase
+// into tsr, starting at filter index fst.
+func (gf *Filter) renderPhase(tsr *tensor.Float32, fst int, phase float32) {
 
 	ctr := 0.5 * float32(gf.Size-1)
 	angInc := math.Pi / float32(gf.NAngles)
@@ -108,10 +126,11 @@ func (gf *Filter) ToTensor(tsr *tensor.Float32) {
 	wdNorm := 1.0 / (2.0 * gsWd * gsWd)
 
 	twoPiNorm := (2.0 * math.Pi) / gf.WvLen
-	phsRad := math32.DegToRad(gf.Phase)
+	phsRad := math32.DegToRad(phase)
 
 	for ang := 0; ang < gf.NAngles; ang++ {
 		angf := -float32(ang) * angInc
+		fi := fst + ang
 
 		posSum := float32(0)
 		negSum := float32(0)
@@ -134,7 +153,7 @@ func (gf *Filter) ToTensor(tsr *tensor.Float32) {
 						negSum += -val
 					}
 				}
-				tsr.Set(val, ang, y, x)
+				tsr.Set(val, fi, y, x)
 			}
 		}
 		// renorm each half
@@ -142,13 +161,13 @@ func (gf *Filter) ToTensor(tsr *tensor.Float32) {
 		negNorm := float32(1) / negSum
 		for x := 0; x < gf.Size; x++ {
 			for y := 0; y < gf.Size; y++ {
-				val := tsr.Value(ang, y, x)
+				val := tsr.Value(fi, y, x)
 				if val > 0 {
 					val *= posNorm
 				} else if val < 0 {
 					val *= negNorm
 				}
-				tsr.Set(val, ang, y, x)
+				tsr.Set(val, fi, y, x)
 			}
 		}
 	}
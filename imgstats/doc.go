@@ -0,0 +1,29 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package imgstats computes summary statistics of image tensors, for
+characterizing stimulus sets before filtering, and manipulates
+their spectra.
+
+* Histogram computes the luminance histogram.
+
+* Moments computes the mean, RMS contrast (standard deviation),
+skew and kurtosis of the luminance values.
+
+* PowerSpectrum computes the radially-averaged 2D power spectrum,
+and SpectrumSlope its log-log slope, which is around -2 for natural
+images (i.e., 1/f^2 power, 1/f amplitude).
+
+* Params.Compute computes all of these into a Stats, which can be
+logged into a table.Table with one row per image via Stats.Log.
+
+* PhaseScramble, SwapAmplitude and SetSlope manipulate the amplitude
+and phase spectra of an image, producing controlled stimuli that
+dissociate spectral from structural contributions to V1 responses.
+
+* FFT2D and IFFT2D compute the forward and inverse 2D discrete Fourier
+transforms used by these, for use in other frequency-domain code.
+*/
+package imgstats
@@ -0,0 +1,97 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imgstats
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// fft computes the discrete Fourier transform of x in place,
+// using the radix-2 algorithm if the length is a power of 2,
+// and otherwise the direct O(n^2) computation, using tmp
+// as a buffer of the same length.
+func fft(x, tmp []complex128) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+	if n&(n-1) != 0 {
+		dft(x, tmp)
+		return
+	}
+	shift := 64 - bits.TrailingZeros(uint(n))
+	for i := 0; i < n; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for sz := 2; sz <= n; sz <<= 1 {
+		half := sz >> 1
+		wst := cmplx.Exp(complex(0, -2*math.Pi/float64(sz)))
+		for st := 0; st < n; st += sz {
+			w := complex(1, 0)
+			for k := 0; k < half; k++ {
+				a := x[st+k]
+				b := w * x[st+k+half]
+				x[st+k] = a + b
+				x[st+k+half] = a - b
+				w *= wst
+			}
+		}
+	}
+}
+
+// dft computes the direct discrete Fourier transform of x in place
+func dft(x, tmp []complex128) {
+	n := len(x)
+	tw := make([]complex128, n)
+	for m := range tw {
+		tw[m] = cmplx.Exp(complex(0, -2*math.Pi*float64(m)/float64(n)))
+	}
+	for k := 0; k < n; k++ {
+		var sum complex128
+		for i, v := range x {
+			sum += v * tw[(k*i)%n]
+		}
+		tmp[k] = sum
+	}
+	copy(x, tmp)
+}
+
+// FFT2D computes the 2D discrete Fourier transform of the
+// [ny, nx] row-major values in place, by rows then columns.
+func FFT2D(vals []complex128, ny, nx int) {
+	tmp := make([]complex128, max(ny, nx))
+	for y := 0; y < ny; y++ {
+		fft(vals[y*nx:(y+1)*nx], tmp)
+	}
+	col := make([]complex128, ny)
+	for x := 0; x < nx; x++ {
+		for y := 0; y < ny; y++ {
+			col[y] = vals[y*nx+x]
+		}
+		fft(col, tmp)
+		for y := 0; y < ny; y++ {
+			vals[y*nx+x] = col[y]
+		}
+	}
+}
+
+// IFFT2D computes the inverse 2D discrete Fourier transform of the
+// [ny, nx] row-major values in place, using the conjugate of the
+// forward transform.
+func IFFT2D(vals []complex128, ny, nx int) {
+	for i, v := range vals {
+		vals[i] = cmplx.Conj(v)
+	}
+	FFT2D(vals, ny, nx)
+	norm := complex(1/float64(ny*nx), 0)
+	for i, v := range vals {
+		vals[i] = cmplx.Conj(v) * norm
+	}
+}
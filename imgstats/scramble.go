@@ -0,0 +1,121 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imgstats
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+
+	"cogentcore.org/core/tensor"
+)
+
+// PhaseScramble generates into out a phase-scrambled version of given
+// grey-scale [Y, X] image tensor, which has the same amplitude spectrum
+// (and thus the same mean, RMS contrast and 1/f slope) but random phases,
+// destroying the image structure.  The random phases are taken from the
+// transform of white noise generated with rnd (global rand if nil), so
+// they have the symmetry needed for a real-valued result.  To scramble
+// the channels of a color image consistently, use a rand.Rand with the
+// same seed for each channel.
+func PhaseScramble(img, out *tensor.Float32, rnd *rand.Rand) {
+	ny := img.DimSize(0)
+	nx := img.DimSize(1)
+	vals := toComplex(img.Values)
+	FFT2D(vals, ny, nx)
+	noise := make([]complex128, ny*nx)
+	for i := range noise {
+		if rnd != nil {
+			noise[i] = complex(rnd.Float64(), 0)
+		} else {
+			noise[i] = complex(rand.Float64(), 0)
+		}
+	}
+	FFT2D(noise, ny, nx)
+	for i, v := range vals {
+		if i == 0 { // keep the mean
+			continue
+		}
+		vals[i] = v * cmplx.Rect(1, cmplx.Phase(noise[i]))
+	}
+	fromComplex(vals, ny, nx, out)
+}
+
+// SwapAmplitude generates into out an image with the amplitude spectrum
+// of amp and the phase spectrum of phase, which must be grey-scale
+// [Y, X] tensors of the same size.  Swapping both ways between two
+// images dissociates the contributions of the amplitude spectrum
+// (e.g., overall orientation and spatial frequency content) from the
+// structure carried by the phases.
+func SwapAmplitude(amp, phase, out *tensor.Float32) {
+	ny := amp.DimSize(0)
+	nx := amp.DimSize(1)
+	avals := toComplex(amp.Values)
+	FFT2D(avals, ny, nx)
+	pvals := toComplex(phase.Values)
+	FFT2D(pvals, ny, nx)
+	for i, v := range pvals {
+		avals[i] = cmplx.Rect(cmplx.Abs(avals[i]), cmplx.Phase(v))
+	}
+	fromComplex(avals, ny, nx, out)
+}
+
+// SetSlope generates into out a version of given grey-scale [Y, X]
+// image tensor with the same phase spectrum, mean and RMS contrast,
+// but with an amplitude spectrum following a power law with given
+// power spectrum slope (e.g., -2 for natural 1/f^2 power), as
+// measured by SpectrumSlope.  The amplitude depends only on the
+// spatial frequency, so any orientation bias is also removed.
+func SetSlope(img, out *tensor.Float32, slope float32) {
+	ny := img.DimSize(0)
+	nx := img.DimSize(1)
+	nmin := min(ny, nx)
+	vals := toComplex(img.Values)
+	FFT2D(vals, ny, nx)
+	var pw0, pw1 float64
+	for y := 0; y < ny; y++ {
+		fy := float64(freqIndex(y, ny)) * float64(nmin) / float64(ny)
+		for x := 0; x < nx; x++ {
+			i := y*nx + x
+			if i == 0 {
+				continue
+			}
+			fx := float64(freqIndex(x, nx)) * float64(nmin) / float64(nx)
+			f := math.Sqrt(fy*fy + fx*fx)
+			v := vals[i]
+			a := cmplx.Abs(v)
+			pw0 += a * a
+			na := math.Pow(f, 0.5*float64(slope))
+			pw1 += na * na
+			vals[i] = cmplx.Rect(na, cmplx.Phase(v))
+		}
+	}
+	if pw1 > 0 { // rescale to preserve the RMS contrast
+		gain := complex(math.Sqrt(pw0/pw1), 0)
+		for i := 1; i < len(vals); i++ {
+			vals[i] *= gain
+		}
+	}
+	fromComplex(vals, ny, nx, out)
+}
+
+// toComplex returns given tensor values as complex values
+func toComplex(tsr []float32) []complex128 {
+	vals := make([]complex128, len(tsr))
+	for i, v := range tsr {
+		vals[i] = complex(float64(v), 0)
+	}
+	return vals
+}
+
+// fromComplex computes the inverse transform of given values into
+// the [ny, nx] out tensor, using the real part
+func fromComplex(vals []complex128, ny, nx int, out *tensor.Float32) {
+	IFFT2D(vals, ny, nx)
+	out.SetShapeSizes(ny, nx)
+	for i, v := range vals {
+		out.Values[i] = float32(real(v))
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imgstats
+
+//go:generate core generate -add-types
+
+import (
+	"math"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// Params are the parameters for computing image statistics
+type Params struct {
+
+	// number of luminance histogram bins
+	NBins int `default:"32"`
+
+	// range of luminance values for the histogram -- values outside are counted in the first or last bin
+	Range minmax.F32
+
+	// amount of padding around the image to exclude from the statistics
+	PadWidth int
+
+	// apply a Hann window to the image before computing the power spectrum, to reduce artifactual power from the image edges
+	Window bool `default:"true"`
+}
+
+func (pr *Params) Defaults() {
+	pr.NBins = 32
+	pr.Range.Set(0, 1)
+	pr.Window = true
+}
+
+// Stats are the statistics of the luminance of one image
+type Stats struct {
+
+	// mean luminance
+	Mean float32
+
+	// RMS contrast: standard deviation of luminance
+	RMS float32
+
+	// skewness of luminance
+	Skew float32
+
+	// excess kurtosis of luminance (0 for a gaussian)
+	Kurtosis float32
+
+	// slope of the log-log radially-averaged power spectrum -- around -2 for natural images
+	Slope float32
+
+	// luminance histogram, normalized to sum to 1
+	Hist []float32
+
+	// radially-averaged power spectrum, indexed by spatial frequency in cycles per image (0 = DC)
+	Spectrum []float32
+}
+
+// Compute computes all of the statistics for given image tensor,
+// which is either a grey-scale [Y, X] or a [Channels, Y, X] tensor
+// (e.g., RGB), whose channels are averaged to compute luminance.
+func (pr *Params) Compute(img *tensor.Float32, st *Stats) {
+	var lum tensor.Float32
+	Luminance(img, &lum, pr.PadWidth)
+	st.Hist = Histogram(&lum, pr.NBins, pr.Range, st.Hist)
+	st.Mean, st.RMS, st.Skew, st.Kurtosis = Moments(&lum)
+	st.Spectrum = PowerSpectrum(&lum, pr.Window, st.Spectrum)
+	st.Slope = SpectrumSlope(st.Spectrum)
+}
+
+// Luminance computes the luminance [Y, X] tensor from given image
+// tensor, which is either a grey-scale [Y, X] or a [Channels, Y, X]
+// tensor, as the average over channels, excluding padWidth around
+// the edges.
+func Luminance(img, lum *tensor.Float32, padWidth int) {
+	nd := img.NumDims()
+	ny := img.DimSize(nd-2) - 2*padWidth
+	nx := img.DimSize(nd-1) - 2*padWidth
+	nc := 1
+	if nd == 3 {
+		nc = img.DimSize(0)
+	}
+	lum.SetShapeSizes(ny, nx)
+	sz := img.DimSize(nd-2) * img.DimSize(nd-1)
+	inv := 1 / float32(nc)
+	for y := 0; y < ny; y++ {
+		for x := 0; x < nx; x++ {
+			ii := (y+padWidth)*img.DimSize(nd-1) + x + padWidth
+			var sum float32
+			for c := 0; c < nc; c++ {
+				sum += img.Values[c*sz+ii]
+			}
+			lum.Values[y*nx+x] = sum * inv
+		}
+	}
+}
+
+// Histogram computes the histogram of the values in given tensor
+// with nbins bins over given range, normalized to sum to 1, using
+// given slice if it has sufficient capacity.  Values outside of the
+// range are counted in the first or last bin.
+func Histogram(lum *tensor.Float32, nbins int, rng minmax.F32, hist []float32) []float32 {
+	hist = hist[:0]
+	for i := 0; i < nbins; i++ {
+		hist = append(hist, 0)
+	}
+	n := len(lum.Values)
+	if n == 0 {
+		return hist
+	}
+	bsz := rng.Range() / float32(nbins)
+	for _, v := range lum.Values {
+		bi := 0
+		if bsz > 0 {
+			bi = min(max(int((v-rng.Min)/bsz), 0), nbins-1)
+		}
+		hist[bi]++
+	}
+	inv := 1 / float32(n)
+	for i := range hist {
+		hist[i] *= inv
+	}
+	return hist
+}
+
+// Moments returns the mean, standard deviation (RMS contrast), skewness
+// and excess kurtosis of the values in given tensor.  Skew and kurtosis
+// are 0 if there is no variance.
+func Moments(lum *tensor.Float32) (mean, std, skew, kurt float32) {
+	n := len(lum.Values)
+	if n == 0 {
+		return
+	}
+	var sum float64
+	for _, v := range lum.Values {
+		sum += float64(v)
+	}
+	mn := sum / float64(n)
+	var m2, m3, m4 float64
+	for _, v := range lum.Values {
+		d := float64(v) - mn
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	m2 /= float64(n)
+	m3 /= float64(n)
+	m4 /= float64(n)
+	mean = float32(mn)
+	std = float32(math.Sqrt(m2))
+	if m2 > 0 {
+		skew = float32(m3 / math.Pow(m2, 1.5))
+		kurt = float32(m4/(m2*m2) - 3)
+	}
+	return
+}
+
+// PowerSpectrum computes the radially-averaged 2D power spectrum of
+// given [Y, X] tensor, with the mean subtracted, using given slice if
+// it has sufficient capacity.  The result is indexed by the spatial
+// frequency in cycles per image (relative to the smaller dimension),
+// up to the Nyquist limit of half of the smaller dimension, with the
+// DC component at 0.  If window is true, a Hann window is applied first.
+func PowerSpectrum(lum *tensor.Float32, window bool, spec []float32) []float32 {
+	ny := lum.DimSize(0)
+	nx := lum.DimSize(1)
+	nmin := min(ny, nx)
+	nfreq := nmin/2 + 1
+	spec = spec[:0]
+	for i := 0; i < nfreq; i++ {
+		spec = append(spec, 0)
+	}
+	if ny == 0 || nx == 0 {
+		return spec
+	}
+	var sum float64
+	for _, v := range lum.Values {
+		sum += float64(v)
+	}
+	mn := sum / float64(len(lum.Values))
+	vals := make([]complex128, ny*nx)
+	for y := 0; y < ny; y++ {
+		wy := 1.0
+		if window {
+			wy = hann(y, ny)
+		}
+		for x := 0; x < nx; x++ {
+			wx := 1.0
+			if window {
+				wx = hann(x, nx)
+			}
+			vals[y*nx+x] = complex((float64(lum.Values[y*nx+x])-mn)*wy*wx, 0)
+		}
+	}
+	FFT2D(vals, ny, nx)
+	cnt := make([]int, nfreq)
+	norm := 1 / float64(ny*nx)
+	for y := 0; y < ny; y++ {
+		fy := float64(freqIndex(y, ny)) * float64(nmin) / float64(ny)
+		for x := 0; x < nx; x++ {
+			fx := float64(freqIndex(x, nx)) * float64(nmin) / float64(nx)
+			fi := int(math.Round(math.Sqrt(fy*fy + fx*fx)))
+			if fi >= nfreq {
+				continue
+			}
+			v := vals[y*nx+x]
+			spec[fi] += float32((real(v)*real(v) + imag(v)*imag(v)) * norm)
+			cnt[fi]++
+		}
+	}
+	for i, c := range cnt {
+		if c > 0 {
+			spec[i] /= float32(c)
+		}
+	}
+	return spec
+}
+
+// SpectrumSlope returns the slope of the least-squares linear fit of
+// log power vs. log frequency for given power spectrum, as computed by
+// PowerSpectrum, excluding the DC component and any zero values.
+func SpectrumSlope(spec []float32) float32 {
+	var n, sx, sy, sxx, sxy float32
+	for f := 1; f < len(spec); f++ {
+		if spec[f] <= 0 {
+			continue
+		}
+		lx := math32.Log(float32(f))
+		ly := math32.Log(spec[f])
+		n++
+		sx += lx
+		sy += ly
+		sxx += lx * lx
+		sxy += lx * ly
+	}
+	den := n*sxx - sx*sx
+	if n < 2 || den == 0 {
+		return 0
+	}
+	return (n*sxy - sx*sy) / den
+}
+
+// Log adds a row to given table with these statistics, for image of
+// given name, configuring the columns if the table has none, with
+// Name, Mean, RMS, Skew, Kurtosis, Slope, and Hist and Spectrum as
+// tensor cells sized by these statistics.  Images of different sizes
+// have different spectrum lengths, of which only as many as fit are logged.
+func (st *Stats) Log(dt *table.Table, name string) {
+	if dt.NumColumns() == 0 {
+		dt.AddStringColumn("Name")
+		for _, c := range []string{"Mean", "RMS", "Skew", "Kurtosis", "Slope"} {
+			dt.AddFloat32Column(c)
+		}
+		dt.AddFloat32Column("Hist", len(st.Hist))
+		dt.AddFloat32Column("Spectrum", len(st.Spectrum))
+	}
+	row := dt.NumRows()
+	dt.SetNumRows(row + 1)
+	dt.ColumnByIndex(0).SetStringRow(name, row, 0)
+	for c, v := range []float32{st.Mean, st.RMS, st.Skew, st.Kurtosis, st.Slope} {
+		dt.ColumnByIndex(c+1).SetFloatRow(float64(v), row, 0)
+	}
+	hc := dt.Column("Hist")
+	for i, v := range st.Hist {
+		hc.SetFloatRow(float64(v), row, i)
+	}
+	sc := dt.Column("Spectrum")
+	nf := min(len(st.Spectrum), sc.Tensor.DimSize(1))
+	for i, v := range st.Spectrum[:nf] {
+		sc.SetFloatRow(float64(v), row, i)
+	}
+}
+
+// freqIndex returns the signed frequency for given index of an
+// n-point discrete Fourier transform
+func freqIndex(i, n int) int {
+	if i > n/2 {
+		return i - n
+	}
+	return i
+}
+
+// hann returns the Hann window value at i of n points
+func hann(i, n int) float64 {
+	if n <= 1 {
+		return 1
+	}
+	return 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+}
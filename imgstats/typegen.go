@@ -0,0 +1,11 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package imgstats
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/imgstats.Params", IDName: "params", Doc: "Params are the parameters for computing image statistics", Fields: []types.Field{{Name: "NBins", Doc: "number of luminance histogram bins"}, {Name: "Range", Doc: "range of luminance values for the histogram -- values outside are counted in the first or last bin"}, {Name: "PadWidth", Doc: "amount of padding around the image to exclude from the statistics"}, {Name: "Window", Doc: "apply a Hann window to the image before computing the power spectrum, to reduce artifactual power from the image edges"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/imgstats.Stats", IDName: "stats", Doc: "Stats are the statistics of the luminance of one image", Fields: []types.Field{{Name: "Mean", Doc: "mean luminance"}, {Name: "RMS", Doc: "RMS contrast: standard deviation of luminance"}, {Name: "Skew", Doc: "skewness of luminance"}, {Name: "Kurtosis", Doc: "excess kurtosis of luminance (0 for a gaussian)"}, {Name: "Slope", Doc: "slope of the log-log radially-averaged power spectrum -- around -2 for natural images"}, {Name: "Hist", Doc: "luminance histogram, normalized to sum to 1"}, {Name: "Spectrum", Doc: "radially-averaged power spectrum, indexed by spatial frequency in cycles per image (0 = DC)"}}})
@@ -0,0 +1,60 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+import "cogentcore.org/core/math32"
+
+// Anneal contains parameters for annealed kWTA settling, where the
+// DelActThr stopping criterion starts looser and tightens to its final
+// value over the first Iters iterations, while the rate of activation
+// updating decays from the full ActDt, damping the oscillations that
+// otherwise occur with high-gain inputs until Iters is exhausted.
+// Convergence is always tested on the undamped change in activation,
+// so the slower updating does not itself cause settling to stop.
+type Anneal struct {
+
+	// whether to anneal the settling
+	On bool
+
+	// number of iterations over which the annealing schedule runs, after which the final values are used
+	Iters int `default:"10"`
+
+	// multiplier on DelActThr at the start of settling, which decays geometrically to 1 over Iters
+	ThrStart float32 `default:"4"`
+
+	// multiplier on the activation update rate at the end of the schedule, which decays geometrically from 1 over Iters
+	DtEnd float32 `default:"0.25"`
+}
+
+func (an *Anneal) Defaults() {
+	an.On = false
+	an.Iters = 10
+	an.ThrStart = 4
+	an.DtEnd = 0.25
+}
+
+func (an *Anneal) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return an.On
+	}
+}
+
+// Schedule returns the multipliers on DelActThr and on the activation
+// update rate for given settling iteration, which are both 1 if not On.
+func (an *Anneal) Schedule(cy int) (thr, dt float32) {
+	if !an.On {
+		return 1, 1
+	}
+	frac := float32(1)
+	if an.Iters > 1 {
+		frac = min(float32(cy)/float32(an.Iters-1), 1)
+	}
+	thr = math32.Pow(an.ThrStart, 1-frac)
+	dt = math32.Pow(an.DtEnd, frac)
+	return
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+import (
+	"slices"
+
+	"cogentcore.org/core/math32"
+)
+
+// InhibModes are the ways of computing the inhibition in KWTA
+type InhibModes int32 //enums:enum
+
+const (
+	// FFFB computes the inhibition from the feedforward and feedback
+	// inhibition of LayFFFB and PoolFFFB, which is the standard.
+	FFFB InhibModes = iota
+
+	// KWTAInhib is the classic Leabra KWTA_INHIB mode: the inhibition is
+	// placed between the threshold inhibition of the k-th and k+1-th most
+	// excited units, at Classic.Pt, so that roughly k units are active.
+	KWTAInhib
+
+	// KWTAAvgInhib is the classic Leabra KWTA_AVG_INHIB mode: the
+	// inhibition is placed between the average threshold inhibition of
+	// the top k most excited units and that of the remaining units,
+	// at Classic.AvgPt, which allows more flexibility in the number
+	// of active units than KWTAInhib.
+	KWTAAvgInhib
+)
+
+// Classic contains the parameters for the classic Leabra kWTA inhibition
+// modes, which compute the inhibition directly from the threshold
+// inhibition of each unit: the amount of inhibition that would put the
+// unit exactly at its activation threshold given its excitatory input.
+// This is computed once from the inputs, and held constant over the
+// settling iterations, for reproducing legacy Leabra vision models.
+type Classic struct {
+
+	// proportion of units within the entire layer to be active: k = Pct * N, rounded, and at least 1
+	LayPct float32 `default:"0.25"`
+
+	// proportion of units within each pool to be active: k = Pct * N, rounded, and at least 1
+	PoolPct float32 `default:"0.25"`
+
+	// for KWTAInhib, point between the k+1-th (0) and k-th (1) threshold inhibition values at which to place the inhibition
+	Pt float32 `default:"0.25"`
+
+	// for KWTAAvgInhib, point between the average of the remaining units (0) and the average of the top k units (1) threshold inhibition values at which to place the inhibition
+	AvgPt float32 `default:"0.6"`
+}
+
+func (cl *Classic) Defaults() {
+	cl.LayPct = 0.25
+	cl.PoolPct = 0.25
+	cl.Pt = 0.25
+	cl.AvgPt = 0.6
+}
+
+// GiThr returns the threshold inhibition for given excitatory input:
+// the inhibitory conductance that puts the unit exactly at its
+// activation threshold, i.e., the inverse of GeThrFromG.
+func (kwta *KWTA) GiThr(ge float32) float32 {
+	return (ge*kwta.Gbar.E*kwta.ThrSubErev.E - kwta.Gbar.L*kwta.ErevSubThr.L) / (kwta.Gbar.I * kwta.ErevSubThr.I)
+}
+
+// ClassicGi returns the classic kWTA inhibition for given excitatory
+// inputs, according to Mode, with k = pct * N active units, using buf
+// as a buffer for the threshold inhibition values, which is returned
+// for reuse.  Returns 0 if Mode is FFFB.
+func (kwta *KWTA) ClassicGi(ges []float32, pct float32, buf []float32) (float32, []float32) {
+	n := len(ges)
+	if kwta.Mode == FFFB || n == 0 {
+		return 0, buf
+	}
+	buf = buf[:0]
+	for _, ge := range ges {
+		buf = append(buf, kwta.GiThr(ge))
+	}
+	slices.Sort(buf)
+	slices.Reverse(buf)
+	k := min(max(int(math32.Round(pct*float32(n))), 1), n)
+	if k == n {
+		return buf[n-1], buf
+	}
+	switch kwta.Mode {
+	case KWTAInhib:
+		return buf[k] + kwta.Classic.Pt*(buf[k-1]-buf[k]), buf
+	default:
+		var ka, ra float32
+		for i, g := range buf {
+			if i < k {
+				ka += g
+			} else {
+				ra += g
+			}
+		}
+		ka /= float32(k)
+		ra /= float32(n - k)
+		return ra + kwta.Classic.AvgPt*(ka-ra), buf
+	}
+}
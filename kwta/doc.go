@@ -10,5 +10,21 @@ as computed by visual (or other modality) filtering routines.
 The inhibition is computed using the FFFB feedforward-feedback function
 along with standard noisy-X-over-X+1 (NXX1) function that computes a
 resulting activation based on the inhibition.
+
+Anneal optionally tightens the stopping criterion and damps the
+activation updates over settling iterations, for robust convergence
+with high-gain inputs that otherwise oscillate.
+
+KWTAPoolSelf and KWTALayerSelf add the unit-level self-inhibition
+from fffb.SelfInhib, based on recent activations maintained across frames.
+
+Mode selects the classic Leabra kWTA inhibition (KWTAInhib, between
+the threshold inhibition of the k-th and k+1-th most excited units, or
+KWTAAvgInhib, between the averages of the top k and the remaining units)
+instead of FFFB, for reproducing legacy Leabra vision models.
+
+TopK provides a much faster non-iterative alternative that directly
+selects the top k values within each pool, for cases where only the
+resulting sparsity is needed.
 */
 package kwta
@@ -0,0 +1,50 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package kwta
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _InhibModesValues = []InhibModes{0, 1, 2}
+
+// InhibModesN is the highest valid value for type InhibModes, plus one.
+const InhibModesN InhibModes = 3
+
+var _InhibModesValueMap = map[string]InhibModes{`FFFB`: 0, `KWTAInhib`: 1, `KWTAAvgInhib`: 2}
+
+var _InhibModesDescMap = map[InhibModes]string{0: `FFFB computes the inhibition from the feedforward and feedback inhibition of LayFFFB and PoolFFFB, which is the standard.`, 1: `KWTAInhib is the classic Leabra KWTA_INHIB mode: the inhibition is placed between the threshold inhibition of the k-th and k+1-th most excited units, at Classic.Pt, so that roughly k units are active.`, 2: `KWTAAvgInhib is the classic Leabra KWTA_AVG_INHIB mode: the inhibition is placed between the average threshold inhibition of the top k most excited units and that of the remaining units, at Classic.AvgPt, which allows more flexibility in the number of active units than KWTAInhib.`}
+
+var _InhibModesMap = map[InhibModes]string{0: `FFFB`, 1: `KWTAInhib`, 2: `KWTAAvgInhib`}
+
+// String returns the string representation of this InhibModes value.
+func (i InhibModes) String() string { return enums.String(i, _InhibModesMap) }
+
+// SetString sets the InhibModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *InhibModes) SetString(s string) error {
+	return enums.SetString(i, s, _InhibModesValueMap, "InhibModes")
+}
+
+// Int64 returns the InhibModes value as an int64.
+func (i InhibModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the InhibModes value from an int64.
+func (i *InhibModes) SetInt64(in int64) { *i = InhibModes(in) }
+
+// Desc returns the description of the InhibModes value.
+func (i InhibModes) Desc() string { return enums.Desc(i, _InhibModesDescMap) }
+
+// InhibModesValues returns all possible values for the type InhibModes.
+func InhibModesValues() []InhibModes { return _InhibModesValues }
+
+// Values returns all possible values for the type InhibModes.
+func (i InhibModes) Values() []enums.Enum { return enums.Values(_InhibModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i InhibModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *InhibModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "InhibModes")
+}
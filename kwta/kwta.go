@@ -7,26 +7,41 @@ package kwta
 //go:generate core generate -add-types
 
 import (
+	"context"
+	"slices"
+
 	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/fffb"
+	"github.com/emer/vision/v2/nproc"
 	"github.com/emer/vision/v2/nxx1"
 )
 
 // KWTA contains all the parameters needed for computing FFFB
 // (feedforward & feedback) inhibition that results in roughly
-// k-Winner-Take-All behavior.
+// k-Winner-Take-All behavior, or, according to Mode, the classic
+// Leabra kWTA inhibition.
 type KWTA struct {
 
 	// whether to run kWTA or not
 	On bool
 
+	// how to compute the inhibition: FFFB is the standard, and the others are the classic Leabra kWTA modes, using the Classic parameters -- the On flags of LayFFFB and PoolFFFB determine which levels of inhibition are applied in all modes
+	Mode InhibModes
+
+	// parameters for the classic Leabra kWTA inhibition modes
+	Classic Classic `display:"inline"`
+
 	// maximum number of iterations to perform
 	Iters int
 
 	// threshold on delta-activation (change in activation) for stopping updating of activations
 	DelActThr float32 `default:"0.005"`
 
+	// annealing of the settling, tightening DelActThr and damping the activation updates over iterations, for robust convergence with high-gain inputs
+	Anneal Anneal `display:"inline"`
+
 	// layer-level feedforward & feedback inhibition -- applied over entire set of values
 	LayFFFB fffb.Params `display:"inline"`
 
@@ -57,8 +72,11 @@ type KWTA struct {
 
 func (kwta *KWTA) Defaults() {
 	kwta.On = true
+	kwta.Mode = FFFB
+	kwta.Classic.Defaults()
 	kwta.Iters = 20
 	kwta.DelActThr = 0.005
+	kwta.Anneal.Defaults()
 	kwta.LayFFFB.Defaults()
 	kwta.PoolFFFB.Defaults()
 	kwta.LayFFFB.On = true
@@ -102,6 +120,27 @@ func (kwta *KWTA) ActFromG(geThr, ge, act float32) (nwAct, delAct float32) {
 // extGi is extra / external Gi inhibition per unit
 // -- e.g. from neighbor inhib -- must be size of raw, act.
 func (kwta *KWTA) KWTALayer(raw, act, extGi *tensor.Float32) {
+	kwta.kwtaLayer(raw, act, extGi, nil)
+}
+
+// KWTALayerSelf is KWTALayer with unit-level self-inhibition from
+// LayFFFB.Self, if On, based on the recent activation of each unit
+// in recAct, which is maintained by the caller across calls (e.g.,
+// frames), and is updated with the resulting activations.
+// recAct is reset to zeros if it is not the same shape as raw.
+func (kwta *KWTA) KWTALayerSelf(raw, act, extGi, recAct *tensor.Float32) {
+	if !kwta.LayFFFB.Self.On {
+		recAct = nil
+	}
+	kwta.kwtaLayer(raw, act, extGi, selfState(raw, recAct))
+	if recAct != nil {
+		kwta.LayFFFB.Self.UpdateRecent(recAct.Values, act.Values)
+	}
+}
+
+// kwtaLayer is the implementation of KWTALayer, with optional
+// recent activations for self-inhibition.
+func (kwta *KWTA) kwtaLayer(raw, act, extGi, recAct *tensor.Float32) {
 	inhib := fffb.Inhib{}
 	raws := raw.Values // these are ge
 
@@ -117,9 +156,19 @@ func (kwta *KWTA) KWTALayer(raw, act, extGi *tensor.Float32) {
 		inhib.Ge.UpdateValue(ge, int32(i))
 	}
 	inhib.Ge.CalcAvg()
+	var classicGi float32
+	if kwta.Mode != FFFB && kwta.LayFFFB.On {
+		classicGi, _ = kwta.ClassicGi(raws, kwta.Classic.LayPct, nil)
+	}
 
+	xs := make([]float32, len(acts)) // net input relative to threshold, then activation
 	for cy := 0; cy < kwta.Iters; cy++ {
-		kwta.LayFFFB.Inhib(&inhib)
+		thrm, dtm := kwta.Anneal.Schedule(cy)
+		if kwta.Mode == FFFB {
+			kwta.LayFFFB.Inhib(&inhib)
+		} else {
+			inhib.Gi = classicGi
+		}
 		inhib.Act.Init()
 		maxDelAct := float32(0)
 		for i := range acts {
@@ -127,15 +176,21 @@ func (kwta *KWTA) KWTALayer(raw, act, extGi *tensor.Float32) {
 			if extGi != nil {
 				gi += extGi.Values[i]
 			}
-			geThr := kwta.GeThrFromG(gi)
-			ge := raws[i]
-			nwAct, delAct := kwta.ActFromG(geThr, ge, acts[i])
+			if recAct != nil {
+				gi += kwta.LayFFFB.Self.Inhib(recAct.Values[i])
+			}
+			xs[i] = raws[i]*kwta.Gbar.E - kwta.GeThrFromG(gi)
+		}
+		kwta.XX1.NoisyXX1Slice(xs, xs)
+		for i, xact := range xs {
+			delAct := kwta.ActDt * (xact - acts[i])
+			nwAct := acts[i] + dtm*delAct
 			maxDelAct = math32.Max(maxDelAct, math32.Abs(delAct))
 			inhib.Act.UpdateValue(nwAct, int32(i))
 			acts[i] = nwAct
 		}
 		inhib.Act.CalcAvg()
-		if cy > 2 && maxDelAct < kwta.DelActThr {
+		if cy > 2 && maxDelAct < thrm*kwta.DelActThr {
 			break
 		}
 	}
@@ -152,6 +207,70 @@ func (kwta *KWTA) KWTALayer(raw, act, extGi *tensor.Float32) {
 // extGi is extra / external Gi inhibition per unit
 // -- e.g. from neighbor inhib -- must be size of raw, act.
 func (kwta *KWTA) KWTAPool(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *tensor.Float32) {
+	kwta.kwtaPool(context.Background(), raw, act, inhib, extGi, nil)
+}
+
+// KWTAPoolContext is KWTAPool with cancellation and progress reporting
+// via ctx: the context is checked during each iteration, returning
+// ctx.Err() if canceled, in which case act is only partially updated,
+// and the nproc.Progress function of ctx, if any, is called after each
+// iteration, with the total number of Iters, and once with all of the
+// Iters done if it settles before then, so it ends at done == total.
+func (kwta *KWTA) KWTAPoolContext(ctx context.Context, raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *tensor.Float32) error {
+	_, err := kwta.kwtaPool(ctx, raw, act, inhib, extGi, nil)
+	return err
+}
+
+// KWTAPoolSelf is KWTAPool with unit-level self-inhibition from
+// PoolFFFB.Self, if On, based on the recent activation of each unit
+// in recAct, which is maintained by the caller across calls (e.g.,
+// frames), and is updated with the resulting activations.
+// recAct is reset to zeros if it is not the same shape as raw.
+func (kwta *KWTA) KWTAPoolSelf(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi, recAct *tensor.Float32) {
+	if !kwta.PoolFFFB.Self.On {
+		recAct = nil
+	}
+	kwta.kwtaPool(context.Background(), raw, act, inhib, extGi, selfState(raw, recAct))
+	if recAct != nil {
+		kwta.PoolFFFB.Self.UpdateRecent(recAct.Values, act.Values)
+	}
+}
+
+// selfState returns given recent activation state for self-inhibition,
+// reset to zeros if not the same shape as raw, or nil if nil.
+func selfState(raw, recAct *tensor.Float32) *tensor.Float32 {
+	if recAct == nil {
+		return nil
+	}
+	if !slices.Equal(recAct.ShapeSizes(), raw.ShapeSizes()) {
+		tensor.SetShapeFrom(recAct, raw)
+		recAct.SetZeros()
+	}
+	return recAct
+}
+
+// KWTAPoolGi is KWTAPool that also records the converged inhibition
+// values into gi, which has shape [Y, X, 1, 2] with the same outer
+// Y, X layout as raw, with the pool-level Gi as the first value, and
+// the layer-level Gi as the second (the same for all pools), so that
+// the inhibition topography can be visualized or used as an explicit
+// inhibition signal.  The effective inhibition for each pool is the
+// max of these two (plus any extGi).
+func (kwta *KWTA) KWTAPoolGi(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi, gi *tensor.Float32) {
+	layGi, _ := kwta.kwtaPool(context.Background(), raw, act, inhib, extGi, nil)
+	layY := raw.DimSize(0)
+	layX := raw.DimSize(1)
+	gi.SetShapeSizes(layY, layX, 1, 2)
+	for pi := 0; pi < layY*layX; pi++ {
+		gi.Values[pi*2] = (*inhib)[pi].Gi
+		gi.Values[pi*2+1] = layGi
+	}
+}
+
+// kwtaPool is the implementation of KWTAPool, with optional recent
+// activations for self-inhibition, returning the final layer-level Gi,
+// with cancellation and progress via ctx.
+func (kwta *KWTA) kwtaPool(ctx context.Context, raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi, recAct *tensor.Float32) (float32, error) {
 	layInhib := fffb.Inhib{}
 
 	raws := raw.Values // these are ge
@@ -161,8 +280,6 @@ func (kwta *KWTA) KWTAPool(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *
 		extGi.SetShapeSizes(raw.Shape().Sizes...)
 	}
 
-	acts := act.Values
-
 	layY := raw.DimSize(0)
 	layX := raw.DimSize(1)
 	layN := layY * layX
@@ -180,6 +297,7 @@ func (kwta *KWTA) KWTAPool(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *
 	}
 
 	layInhib.Ge.Init()
+	var buf []float32
 	pi := 0
 	for ly := 0; ly < layY; ly++ {
 		for lx := 0; lx < layX; lx++ {
@@ -197,57 +315,121 @@ func (kwta *KWTA) KWTAPool(raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *
 				}
 			}
 			plInhib.Ge.CalcAvg()
+			if kwta.Mode != FFFB {
+				plInhib.Gi = 0
+				if kwta.PoolFFFB.On {
+					plInhib.Gi, buf = kwta.ClassicGi(raws[pui:pui+plN], kwta.Classic.PoolPct, buf)
+				}
+			}
 			pi++
 		}
 	}
 	layInhib.Ge.CalcAvg()
+	var classicGi float32
+	if kwta.Mode != FFFB && kwta.LayFFFB.On {
+		classicGi, _ = kwta.ClassicGi(raws, kwta.Classic.LayPct, buf)
+	}
 
+	// per-thread stats, indexed by the starting row of each thread
+	thrAct := make([]minmax.AvgMax32, layY)
+	thrDel := make([]float32, layY)
+	// net input scratch for each thread, at its starting row
+	xsBuf := make([]float32, layY*plN)
+	prog := nproc.Progress(ctx)
+	rctx := nproc.WithProgress(ctx, nil) // progress is per iteration
 	for cy := 0; cy < kwta.Iters; cy++ {
-		kwta.LayFFFB.Inhib(&layInhib)
+		thrm, dtm := kwta.Anneal.Schedule(cy)
+		if kwta.Mode == FFFB {
+			kwta.LayFFFB.Inhib(&layInhib)
+		} else {
+			layInhib.Gi = classicGi
+		}
 
+		for th := range thrAct {
+			thrAct[th].Init()
+			thrDel[th] = 0
+		}
+		err := nproc.RunContext(rctx, "KWTA", layY, func(st, n int) {
+			kwta.kwtaPoolThr(st, n, raw, act, inhib, extGi, recAct, &layInhib, dtm, &thrAct[st], &thrDel[st], xsBuf[st*plN:(st+1)*plN])
+		})
+		if err != nil {
+			return layInhib.Gi, err
+		}
 		layInhib.Act.Init()
 		maxDelAct := float32(0)
-		pi := 0
-		for ly := 0; ly < layY; ly++ {
-			for lx := 0; lx < layX; lx++ {
-				plInhib := &((*inhib)[pi])
+		for th := range thrAct {
+			ta := &thrAct[th]
+			layInhib.Act.UpdateFromOther(ta.Sum, ta.Max, ta.N, ta.MaxIndex)
+			maxDelAct = math32.Max(maxDelAct, thrDel[th])
+		}
+		layInhib.Act.CalcAvg()
+		if cy > 2 && maxDelAct < thrm*kwta.DelActThr {
+			// fmt.Printf("under thr at cycle: %v\n", cy)
+			if prog != nil { // settled early: report completion
+				prog(kwta.Iters, kwta.Iters)
+			}
+			break
+		}
+		if prog != nil {
+			prog(cy+1, kwta.Iters)
+		}
+	}
+	return layInhib.Gi, nil
+}
+
+// kwtaPoolThr is per-thread implementation of one cycle of KWTAPool
+// over layer rows starting at yst, with self-inhibition from recAct
+// if non-nil, and activation update rate
+// multiplier dtm, accumulating the layer-level activation stats
+// into lact and max delta activation into maxDel, using xs, with the
+// size of a pool, as scratch for the net input relative to threshold,
+// and then the activation.
+func (kwta *KWTA) kwtaPoolThr(yst, ny int, raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi, recAct *tensor.Float32, layInhib *fffb.Inhib, dtm float32, lact *minmax.AvgMax32, maxDel *float32, xs []float32) {
+	raws := raw.Values
+	acts := act.Values
+	layX := raw.DimSize(1)
+	plN := raw.DimSize(2) * raw.DimSize(3)
+	lact.Init()
+	maxDelAct := float32(0)
+	for ly := yst; ly < yst+ny; ly++ {
+		for lx := 0; lx < layX; lx++ {
+			pi := ly*layX + lx
+			plInhib := &((*inhib)[pi])
 
+			if kwta.Mode == FFFB {
 				kwta.PoolFFFB.Inhib(plInhib)
+			}
 
-				giPool := math32.Max(layInhib.Gi, plInhib.Gi)
-
-				plInhib.Act.Init()
-				pui := pi * plN
-				ui := 0
-				for py := 0; py < plY; py++ {
-					for px := 0; px < plX; px++ {
-						idx := pui + ui
-						gi := giPool
-						if extGi != nil {
-							eIn := extGi.Values[idx]
-							eGi := kwta.PoolFFFB.Gi * kwta.PoolFFFB.FFInhib(eIn, eIn)
-							gi = math32.Max(gi, eGi)
-						}
-						geThr := kwta.GeThrFromG(gi)
-						ge := raws[idx]
-						act := acts[idx]
-						nwAct, delAct := kwta.ActFromG(geThr, ge, act)
-						maxDelAct = math32.Max(maxDelAct, math32.Abs(delAct))
-						layInhib.Act.UpdateValue(nwAct, int32(idx))
-						plInhib.Act.UpdateValue(nwAct, int32(ui))
-						acts[idx] = nwAct
-
-						ui++
-					}
+			giPool := math32.Max(layInhib.Gi, plInhib.Gi)
+
+			plInhib.Act.Init()
+			pui := pi * plN
+			for ui := range xs {
+				idx := pui + ui
+				gi := giPool
+				if extGi != nil {
+					eIn := extGi.Values[idx]
+					eGi := kwta.PoolFFFB.Gi * kwta.PoolFFFB.FFInhib(eIn, eIn)
+					gi = math32.Max(gi, eGi)
 				}
-				plInhib.Act.CalcAvg()
-				pi++
+				if recAct != nil {
+					gi += kwta.PoolFFFB.Self.Inhib(recAct.Values[idx])
+				}
+				xs[ui] = raws[idx]*kwta.Gbar.E - kwta.GeThrFromG(gi)
 			}
-		}
-		layInhib.Act.CalcAvg()
-		if cy > 2 && maxDelAct < kwta.DelActThr {
-			// fmt.Printf("under thr at cycle: %v\n", cy)
-			break
+			kwta.XX1.NoisyXX1Slice(xs, xs)
+			for ui, xact := range xs {
+				idx := pui + ui
+				act := acts[idx]
+				delAct := kwta.ActDt * (xact - act)
+				nwAct := act + dtm*delAct
+				maxDelAct = math32.Max(maxDelAct, math32.Abs(delAct))
+				lact.UpdateValue(nwAct, int32(idx))
+				plInhib.Act.UpdateValue(nwAct, int32(ui))
+				acts[idx] = nwAct
+			}
+			plInhib.Act.CalcAvg()
 		}
 	}
+	*maxDel = maxDelAct
 }
@@ -0,0 +1,107 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+import (
+	"math/rand"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/fffb"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// serialKWTAPool is the original serial implementation of KWTAPool,
+// updating all of the pools in order within each settling iteration,
+// as a reference for the parallel version.
+func serialKWTAPool(kwta *KWTA, raw, act *tensor.Float32, inhib *fffb.Inhibs, extGi *tensor.Float32) {
+	layInhib := fffb.Inhib{}
+	raws := raw.Values
+	tensor.SetShapeFrom(act, raw)
+	act.SetZeros()
+	acts := act.Values
+	layY, layX := raw.DimSize(0), raw.DimSize(1)
+	layN := layY * layX
+	plN := raw.DimSize(2) * raw.DimSize(3)
+	*inhib = make(fffb.Inhibs, layN)
+
+	layInhib.Ge.Init()
+	for pi := range layN {
+		plInhib := &((*inhib)[pi])
+		plInhib.Ge.Init()
+		pui := pi * plN
+		for ui := range plN {
+			ge := raws[pui+ui]
+			layInhib.Ge.UpdateValue(ge, int32(pui+ui))
+			plInhib.Ge.UpdateValue(ge, int32(ui))
+		}
+		plInhib.Ge.CalcAvg()
+	}
+	layInhib.Ge.CalcAvg()
+
+	for cy := 0; cy < kwta.Iters; cy++ {
+		kwta.LayFFFB.Inhib(&layInhib)
+		layInhib.Act.Init()
+		maxDelAct := float32(0)
+		for pi := range layN {
+			plInhib := &((*inhib)[pi])
+			kwta.PoolFFFB.Inhib(plInhib)
+			giPool := math32.Max(layInhib.Gi, plInhib.Gi)
+			plInhib.Act.Init()
+			pui := pi * plN
+			for ui := range plN {
+				idx := pui + ui
+				gi := giPool
+				if extGi != nil {
+					eIn := extGi.Values[idx]
+					eGi := kwta.PoolFFFB.Gi * kwta.PoolFFFB.FFInhib(eIn, eIn)
+					gi = math32.Max(gi, eGi)
+				}
+				nwAct, delAct := kwta.ActFromG(kwta.GeThrFromG(gi), raws[idx], acts[idx])
+				maxDelAct = math32.Max(maxDelAct, math32.Abs(delAct))
+				layInhib.Act.UpdateValue(nwAct, int32(idx))
+				plInhib.Act.UpdateValue(nwAct, int32(ui))
+				acts[idx] = nwAct
+			}
+			plInhib.Act.CalcAvg()
+		}
+		layInhib.Act.CalcAvg()
+		if cy > 2 && maxDelAct < kwta.DelActThr {
+			break
+		}
+	}
+}
+
+func TestKWTAPoolSerial(t *testing.T) {
+	defer nproc.SetNumThreads(0)
+	rnd := rand.New(rand.NewSource(1))
+	raw := tensor.NewFloat32(12, 10, 2, 4)
+	extGi := tensor.NewFloat32(12, 10, 2, 4)
+	for i := range raw.Values {
+		raw.Values[i] = rnd.Float32()
+		extGi.Values[i] = 0.2 * rnd.Float32()
+	}
+	var kwta KWTA
+	kwta.Defaults()
+	for _, ext := range []*tensor.Float32{nil, extGi} {
+		var ref tensor.Float32
+		var refInhib fffb.Inhibs
+		serialKWTAPool(&kwta, raw, &ref, &refInhib, ext)
+		for _, nt := range []int{1, 2, 3, 8} {
+			nproc.SetNumThreads(nt)
+			var act tensor.Float32
+			var inhib fffb.Inhibs
+			kwta.KWTAPool(raw, &act, &inhib, ext)
+			tol := float32(1.0e-5)
+			for i, rv := range ref.Values {
+				if math32.Abs(act.Values[i]-rv) > tol {
+					t.Errorf("threads: %d extGi: %v  act[%d]: %g != serial: %g", nt, ext != nil, i, act.Values[i], rv)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kwta
+
+import (
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/nxx1"
+)
+
+// TopK is a fast, non-iterative alternative to KWTA that directly
+// selects the K largest values within each pool (or the whole layer),
+// using a heap, and sets all other values to zero.  This produces the
+// same sparsity as the KWTA settling process, with much less
+// computation, for cases where the detailed graded activations
+// computed by the FFFB inhibition dynamics are not needed.
+type TopK struct {
+
+	// whether to run top-k or not
+	On bool
+
+	// number of values to keep active within each pool (or layer)
+	K int `default:"4"`
+
+	// if true, the kept values are passed through the NoisyXX1 activation function, relative to a threshold midway between the K-th and K+1-th largest values, otherwise they are kept as is
+	Scale bool
+
+	// Noisy X/X+1 rate code activation function parameters, for Scale
+	XX1 nxx1.Params `display:"inline"`
+}
+
+func (tk *TopK) Defaults() {
+	tk.On = true
+	tk.K = 4
+	tk.Scale = false
+	tk.XX1.Defaults()
+}
+
+// Update must be called after any changes to parameters
+func (tk *TopK) Update() {
+	tk.XX1.Update()
+}
+
+// TopKLayer sets act to the K largest values of raw over the entire
+// tensor, with all other values set to zero.
+// act output tensor is set to same shape as raw inputs if not already.
+func (tk *TopK) TopKLayer(raw, act *tensor.Float32) {
+	act.SetShapeSizes(raw.Shape().Sizes...)
+	hp := make([]int, 0, tk.K+1)
+	tk.topK(raw.Values, act.Values, hp)
+}
+
+// TopKPool sets act to the K largest values of raw within each pool,
+// with all other values set to zero.  Tensors must be 4 dimensional,
+// with outer 2D as Y, X Layer and inner 2D as features (pools) per
+// location, as in KWTA.KWTAPool.
+// act output tensor is set to same shape as raw inputs if not already.
+func (tk *TopK) TopKPool(raw, act *tensor.Float32) {
+	act.SetShapeSizes(raw.Shape().Sizes...)
+	layY := raw.DimSize(0)
+	nproc.Run("TopKPool", layY, func(st, n int) {
+		tk.topKPoolThr(st, n, raw, act)
+	})
+}
+
+// topKPoolThr is per-thread implementation
+func (tk *TopK) topKPoolThr(yst, ny int, raw, act *tensor.Float32) {
+	layX := raw.DimSize(1)
+	plN := raw.DimSize(2) * raw.DimSize(3)
+	hp := make([]int, 0, tk.K+1)
+	for ly := yst; ly < yst+ny; ly++ {
+		for lx := 0; lx < layX; lx++ {
+			st := (ly*layX + lx) * plN
+			tk.topK(raw.Values[st:st+plN], act.Values[st:st+plN], hp)
+		}
+	}
+}
+
+// topK computes the top K of raw values into act, using hp as the
+// heap of indexes, with capacity K+1.
+func (tk *TopK) topK(raw, act []float32, hp []int) {
+	k := tk.K
+	clear(act)
+	if k <= 0 {
+		return
+	}
+	if k >= len(raw) {
+		copy(act, raw)
+		return
+	}
+	// min-heap of the k+1 largest values, so the root is the k+1-th
+	hp = hp[:0]
+	for i, v := range raw {
+		if len(hp) <= k {
+			hp = append(hp, i)
+			heapUp(raw, hp, len(hp)-1)
+		} else if v > raw[hp[0]] {
+			hp[0] = i
+			heapDown(raw, hp, 0)
+		}
+	}
+	kp1 := raw[hp[0]]
+	hp[0] = hp[k] // drop k+1-th
+	hp = hp[:k]
+	heapDown(raw, hp, 0)
+	if !tk.Scale {
+		for _, i := range hp {
+			act[i] = raw[i]
+		}
+		return
+	}
+	thr := 0.5 * (raw[hp[0]] + kp1)
+	for _, i := range hp {
+		act[i] = tk.XX1.NoisyXX1(raw[i] - thr)
+	}
+}
+
+// heapUp moves heap element i up to its place in the min-heap
+// of indexes into vals
+func heapUp(vals []float32, hp []int, i int) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if vals[hp[p]] <= vals[hp[i]] {
+			break
+		}
+		hp[p], hp[i] = hp[i], hp[p]
+		i = p
+	}
+}
+
+// heapDown moves heap element i down to its place in the min-heap
+// of indexes into vals
+func heapDown(vals []float32, hp []int, i int) {
+	n := len(hp)
+	for {
+		c := 2*i + 1
+		if c >= n {
+			break
+		}
+		if c+1 < n && vals[hp[c+1]] < vals[hp[c]] {
+			c++
+		}
+		if vals[hp[i]] <= vals[hp[c]] {
+			break
+		}
+		hp[i], hp[c] = hp[c], hp[i]
+		i = c
+	}
+}
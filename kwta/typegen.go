@@ -6,8 +6,16 @@ import (
 	"cogentcore.org/core/types"
 )
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.Anneal", IDName: "anneal", Doc: "Anneal contains parameters for annealed kWTA settling, where the\nDelActThr stopping criterion starts looser and tightens to its final\nvalue over the first Iters iterations, while the rate of activation\nupdating decays from the full ActDt, damping the oscillations that\notherwise occur with high-gain inputs until Iters is exhausted.\nConvergence is always tested on the undamped change in activation,\nso the slower updating does not itself cause settling to stop.", Fields: []types.Field{{Name: "On", Doc: "whether to anneal the settling"}, {Name: "Iters", Doc: "number of iterations over which the annealing schedule runs, after which the final values are used"}, {Name: "ThrStart", Doc: "multiplier on DelActThr at the start of settling, which decays geometrically to 1 over Iters"}, {Name: "DtEnd", Doc: "multiplier on the activation update rate at the end of the schedule, which decays geometrically from 1 over Iters"}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.Chans", IDName: "chans", Doc: "Chans are ion channels used in computing point-neuron activation function", Fields: []types.Field{{Name: "E", Doc: "excitatory sodium (Na) AMPA channels activated by synaptic glutamate"}, {Name: "L", Doc: "constant leak (potassium, K+) channels -- determines resting potential (typically higher than resting potential of K)"}, {Name: "I", Doc: "inhibitory chloride (Cl-) channels activated by synaptic GABA"}, {Name: "K", Doc: "gated / active potassium channels -- typically hyperpolarizing relative to leak / rest"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.KWTA", IDName: "kwta", Doc: "KWTA contains all the parameters needed for computing FFFB\n(feedforward & feedback) inhibition that results in roughly\nk-Winner-Take-All behavior.", Fields: []types.Field{{Name: "On", Doc: "whether to run kWTA or not"}, {Name: "Iters", Doc: "maximum number of iterations to perform"}, {Name: "DelActThr", Doc: "threshold on delta-activation (change in activation) for stopping updating of activations"}, {Name: "LayFFFB", Doc: "layer-level feedforward & feedback inhibition -- applied over entire set of values"}, {Name: "PoolFFFB", Doc: "pool-level (feature groups) feedforward and feedback inhibition -- applied within inner-most dimensions inside outer 2 dimensions (if Pool method is called)"}, {Name: "XX1", Doc: "Noisy X/X+1 rate code activation function parameters"}, {Name: "ActTau", Doc: "time constant for integrating activation"}, {Name: "Gbar", Doc: "maximal conductances levels for channels"}, {Name: "Erev", Doc: "reversal potentials for each channel"}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}, {Name: "ActDt"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.InhibModes", IDName: "inhib-modes", Doc: "InhibModes are the ways of computing the inhibition in KWTA"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.Classic", IDName: "classic", Doc: "Classic contains the parameters for the classic Leabra kWTA inhibition\nmodes, which compute the inhibition directly from the threshold\ninhibition of each unit: the amount of inhibition that would put the\nunit exactly at its activation threshold given its excitatory input.\nThis is computed once from the inputs, and held constant over the\nsettling iterations, for reproducing legacy Leabra vision models.", Fields: []types.Field{{Name: "LayPct", Doc: "proportion of units within the entire layer to be active: k = Pct * N, rounded, and at least 1"}, {Name: "PoolPct", Doc: "proportion of units within each pool to be active: k = Pct * N, rounded, and at least 1"}, {Name: "Pt", Doc: "for KWTAInhib, point between the k+1-th (0) and k-th (1) threshold inhibition values at which to place the inhibition"}, {Name: "AvgPt", Doc: "for KWTAAvgInhib, point between the average of the remaining units (0) and the average of the top k units (1) threshold inhibition values at which to place the inhibition"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.KWTA", IDName: "kwta", Doc: "KWTA contains all the parameters needed for computing FFFB\n(feedforward & feedback) inhibition that results in roughly\nk-Winner-Take-All behavior, or, according to Mode, the classic\nLeabra kWTA inhibition.", Fields: []types.Field{{Name: "On", Doc: "whether to run kWTA or not"}, {Name: "Mode", Doc: "how to compute the inhibition: FFFB is the standard, and the others are the classic Leabra kWTA modes, using the Classic parameters -- the On flags of LayFFFB and PoolFFFB determine which levels of inhibition are applied in all modes"}, {Name: "Classic", Doc: "parameters for the classic Leabra kWTA inhibition modes"}, {Name: "Iters", Doc: "maximum number of iterations to perform"}, {Name: "DelActThr", Doc: "threshold on delta-activation (change in activation) for stopping updating of activations"}, {Name: "Anneal", Doc: "annealing of the settling, tightening DelActThr and damping the activation updates over iterations, for robust convergence with high-gain inputs"}, {Name: "LayFFFB", Doc: "layer-level feedforward & feedback inhibition -- applied over entire set of values"}, {Name: "PoolFFFB", Doc: "pool-level (feature groups) feedforward and feedback inhibition -- applied within inner-most dimensions inside outer 2 dimensions (if Pool method is called)"}, {Name: "XX1", Doc: "Noisy X/X+1 rate code activation function parameters"}, {Name: "ActTau", Doc: "time constant for integrating activation"}, {Name: "Gbar", Doc: "maximal conductances levels for channels"}, {Name: "Erev", Doc: "reversal potentials for each channel"}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}, {Name: "ActDt"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.NeighInhib", IDName: "neigh-inhib", Doc: "NeighInhib adds an additional inhibition factor based on the same\nfeature along an orthogonal angle -- assumes inner-most X axis\nrepresents angle of gabor or related feature.\nThis helps reduce redundancy of feature code.", Fields: []types.Field{{Name: "On", Doc: "use neighborhood inhibition"}, {Name: "Gi", Doc: "overall value of the inhibition -- this is what is added into the unit Gi inhibition level"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/kwta.TopK", IDName: "top-k", Doc: "TopK is a fast, non-iterative alternative to KWTA that directly\nselects the K largest values within each pool (or the whole layer),\nusing a heap, and sets all other values to zero.  This produces the\nsame sparsity as the KWTA settling process, with much less\ncomputation, for cases where the detailed graded activations\ncomputed by the FFFB inhibition dynamics are not needed.", Fields: []types.Field{{Name: "On", Doc: "whether to run top-k or not"}, {Name: "K", Doc: "number of values to keep active within each pool (or layer)"}, {Name: "Scale", Doc: "if true, the kept values are passed through the NoisyXX1 activation function, relative to a threshold midway between the K-th and K+1-th largest values, otherwise they are kept as is"}, {Name: "XX1", Doc: "Noisy X/X+1 rate code activation function parameters, for Scale"}}})
@@ -0,0 +1,31 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package motion provides dorsal-stream (motion pathway) processing stages
+that operate on optic flow and direction-tuned motion signals.
+
+Optic flow fields are represented as 2-channel tensors with outer
+dimension as X, Y displacement components: [2, Y, X], as produced by
+vxform.Flow for ground-truth flow, with Y increasing upward by default.
+
+Direction-tuned (MT-like) outputs have the direction as the inner-most
+dimension, with directions evenly spaced around the full circle,
+starting with rightward motion and proceeding counter-clockwise
+(i.e., direction d is at angle 2 * Pi * d / NDirs), so that the
+opposite direction of d is d + NDirs / 2.  Speed-tuned outputs have
+shape [Y, X, Direction, Speed], with Speed as the inner-most dimension.
+
+* MST pools optic flow into templates for expansion, contraction,
+rotation, and translation flow fields, with a heading estimate
+(focus of expansion).
+
+* Opponent computes opponent motion (preferred minus anti-preferred
+direction) on direction-tuned motion-energy outputs, with rectification.
+
+* Energy computes direction and speed tuned motion energy from a
+sequence of frames using Reichardt-style correlation detectors, with
+multiple speed bands produced by temporal frequency scaling.
+*/
+package motion
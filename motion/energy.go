@@ -0,0 +1,187 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package motion
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Energy computes direction and speed tuned motion energy from a
+// sequence of image frames, using Reichardt-style correlation detectors:
+// the response for direction d at location p is the product of a
+// temporally delayed (low-pass filtered) image value at p and the current
+// image value at p + Dist pixels in direction d.
+// Multiple speed bands are produced by scaling the time constant of the
+// delay filter (i.e., temporal frequency scaling), with preferred speed
+// of roughly Dist / tau pixels per frame for each band.
+// These are half-detectors, which also respond to flicker and static
+// contrast -- use Opponent to remove these non-directional components.
+type Energy struct {
+
+	// number of directions, evenly spaced around the full circle starting with rightward
+	NDirs int `default:"8"`
+
+	// number of speed bands, from fastest to slowest
+	NSpeeds int `default:"3"`
+
+	// time constant in frames of the delay low-pass filter for the first (fastest) speed band
+	Tau float32 `default:"1"`
+
+	// multiplier on the time constant for each successive speed band: tau(s) = Tau * TauMult^s
+	TauMult float32 `default:"2"`
+
+	// distance in pixels between correlated points
+	Dist int `default:"2"`
+
+	// spacing of output units in pixels
+	Spacing int `default:"2"`
+
+	// overall gain multiplier on the outputs
+	Gain float32 `default:"4"`
+
+	// geometry of input, output
+	Geom vfilter.Geom `edit:"-"`
+
+	// delayed (low-pass filtered) image state for each speed band: [NSpeeds, Y, X]
+	Delayed tensor.Float32 `display:"no-inline"`
+
+	// motion energy output: [Y, X, NDirs, NSpeeds]
+	Out tensor.Float32 `display:"no-inline"`
+}
+
+func (me *Energy) Defaults() {
+	me.NDirs = 8
+	me.NSpeeds = 3
+	me.Tau = 1
+	me.TauMult = 2
+	me.Dist = 2
+	me.Spacing = 2
+	me.Gain = 4
+	me.Update()
+}
+
+// Update must be called after any changes to parameters
+func (me *Energy) Update() {
+	fsz := 2*me.Dist + 1
+	me.Geom.Set(image.Point{me.Dist + 1, me.Dist + 1}, image.Point{me.Spacing, me.Spacing}, image.Point{fsz, fsz})
+}
+
+// SpeedTau returns the delay time constant for given speed band
+func (me *Energy) SpeedTau(s int) float32 {
+	return me.Tau * math32.Pow(me.TauMult, float32(s))
+}
+
+// Speed returns the approximate preferred speed in pixels per frame
+// for given speed band.
+func (me *Energy) Speed(s int) float32 {
+	return float32(me.Dist) / me.SpeedTau(s)
+}
+
+// Init initializes the delayed state to the given image, so that
+// the first frame produces no motion signal beyond static contrast.
+// img must be a 2D [Y, X] grey image with a border of at least Dist+1.
+func (me *Energy) Init(img *tensor.Float32) {
+	sy := img.DimSize(0)
+	sx := img.DimSize(1)
+	me.Delayed.SetShapeSizes(me.NSpeeds, sy, sx)
+	n := sy * sx
+	for s := 0; s < me.NSpeeds; s++ {
+		copy(me.Delayed.Values[s*n:(s+1)*n], img.Values)
+	}
+}
+
+// Filter computes the motion energy for a new image frame, and
+// then updates the delayed state with the frame.
+// img must be a 2D [Y, X] grey image with a border of at least Dist+1,
+// and the same size for all frames (Init is called automatically
+// if the size changes).  Output has shape [Y, X, NDirs, NSpeeds].
+func (me *Energy) Filter(img *tensor.Float32) {
+	sy := img.DimSize(0)
+	sx := img.DimSize(1)
+	if me.Delayed.Len() != me.NSpeeds*sy*sx {
+		me.Init(img)
+	}
+	me.Geom.SetSize(image.Point{sx, sy})
+	me.Out.SetShapeSizes(me.Geom.Out.Y, me.Geom.Out.X, me.NDirs, me.NSpeeds)
+	nproc.Run("MotionEnergy", me.Geom.Out.Y, func(st, n int) {
+		me.filterThr(st, n, img)
+	})
+	n := sy * sx
+	for s := 0; s < me.NSpeeds; s++ {
+		dt := 1 / me.SpeedTau(s)
+		dl := me.Delayed.Values[s*n : (s+1)*n]
+		for i, v := range img.Values {
+			dl[i] += dt * (v - dl[i])
+		}
+	}
+}
+
+// filterThr is per-thread implementation
+func (me *Energy) filterThr(yst, ny int, img *tensor.Float32) {
+	sx := img.DimSize(1)
+	n := img.DimSize(0) * sx
+	dist := float32(me.Dist)
+	for yi := 0; yi < ny; yi++ {
+		y := yst + yi
+		iy := me.Geom.Border.Y + y*me.Geom.Spacing.Y
+		for x := 0; x < me.Geom.Out.X; x++ {
+			ix := me.Geom.Border.X + x*me.Geom.Spacing.X
+			for d := 0; d < me.NDirs; d++ {
+				ang := 2 * math32.Pi * float32(d) / float32(me.NDirs)
+				cv := Bilinear(img.Values, sx, float32(iy)+dist*math32.Sin(ang), float32(ix)+dist*math32.Cos(ang))
+				for s := 0; s < me.NSpeeds; s++ {
+					dv := me.Delayed.Values[s*n+iy*sx+ix]
+					me.Out.Set(me.Gain*dv*cv, y, x, d, s)
+				}
+			}
+		}
+	}
+}
+
+// Flow computes an optic flow field from the current motion energy
+// output, as the population vector sum over directions and speeds,
+// with each speed band weighted by its preferred speed.
+// Output flow has shape [2, Y, X] (see package docs).
+func (me *Energy) Flow(flow *tensor.Float32) {
+	sy := me.Out.DimSize(0)
+	sx := me.Out.DimSize(1)
+	flow.SetShapeSizes(2, sy, sx)
+	for y := 0; y < sy; y++ {
+		for x := 0; x < sx; x++ {
+			var fx, fy float32
+			for d := 0; d < me.NDirs; d++ {
+				ang := 2 * math32.Pi * float32(d) / float32(me.NDirs)
+				for s := 0; s < me.NSpeeds; s++ {
+					v := me.Out.Value(y, x, d, s) * me.Speed(s)
+					fx += v * math32.Cos(ang)
+					fy += v * math32.Sin(ang)
+				}
+			}
+			flow.Set(fx, 0, y, x)
+			flow.Set(fy, 1, y, x)
+		}
+	}
+}
+
+// Bilinear returns the bilinearly interpolated value of a row-major
+// 2D image with given X size, at floating-point coordinates y, x,
+// which must be within bounds.
+func Bilinear(vals []float32, sx int, y, x float32) float32 {
+	y0 := int(math32.Floor(y))
+	x0 := int(math32.Floor(x))
+	py := y - float32(y0)
+	px := x - float32(x0)
+	i := y0*sx + x0
+	v00 := vals[i]
+	v01 := vals[i+1]
+	v10 := vals[i+sx]
+	v11 := vals[i+sx+1]
+	return (1-py)*((1-px)*v00+px*v01) + py*((1-px)*v10+px*v11)
+}
@@ -0,0 +1,50 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package motion
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _TemplatesValues = []Templates{0, 1, 2, 3}
+
+// TemplatesN is the highest valid value for type Templates, plus one.
+const TemplatesN Templates = 4
+
+var _TemplatesValueMap = map[string]Templates{`Expansion`: 0, `Contraction`: 1, `CCWRotation`: 2, `CWRotation`: 3}
+
+var _TemplatesDescMap = map[Templates]string{0: `Expansion is radial flow outward from the center, as produced by forward self-motion toward the center (focus of expansion).`, 1: `Contraction is radial flow inward toward the center`, 2: `CCWRotation is counter-clockwise rotation around the center`, 3: `CWRotation is clockwise rotation around the center`}
+
+var _TemplatesMap = map[Templates]string{0: `Expansion`, 1: `Contraction`, 2: `CCWRotation`, 3: `CWRotation`}
+
+// String returns the string representation of this Templates value.
+func (i Templates) String() string { return enums.String(i, _TemplatesMap) }
+
+// SetString sets the Templates value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Templates) SetString(s string) error {
+	return enums.SetString(i, s, _TemplatesValueMap, "Templates")
+}
+
+// Int64 returns the Templates value as an int64.
+func (i Templates) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Templates value from an int64.
+func (i *Templates) SetInt64(in int64) { *i = Templates(in) }
+
+// Desc returns the description of the Templates value.
+func (i Templates) Desc() string { return enums.Desc(i, _TemplatesDescMap) }
+
+// TemplatesValues returns all possible values for the type Templates.
+func TemplatesValues() []Templates { return _TemplatesValues }
+
+// Values returns all possible values for the type Templates.
+func (i Templates) Values() []enums.Enum { return enums.Values(_TemplatesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Templates) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Templates) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "Templates")
+}
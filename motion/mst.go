@@ -0,0 +1,219 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package motion
+
+//go:generate core generate -add-types
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// Templates are the center-relative optic flow templates used by MST
+type Templates int32 //enums:enum
+
+const (
+	// Expansion is radial flow outward from the center, as produced by
+	// forward self-motion toward the center (focus of expansion).
+	Expansion Templates = iota
+
+	// Contraction is radial flow inward toward the center
+	Contraction
+
+	// CCWRotation is counter-clockwise rotation around the center
+	CCWRotation
+
+	// CWRotation is clockwise rotation around the center
+	CWRotation
+)
+
+// MST computes MST-like responses by pooling an optic flow field
+// into templates for expansion, contraction and rotation centered at
+// a grid of candidate centers, and for translation in NTrans directions.
+// Each response is the flow-magnitude-weighted average cosine between
+// the flow and the template direction, rectified to be positive,
+// so it is largely invariant to overall speed.
+type MST struct {
+
+	// number of candidate template centers along each axis, evenly spaced over the image
+	NCenters int `default:"9"`
+
+	// number of translation directions, evenly spaced around the circle starting with rightward
+	NTrans int `default:"8"`
+
+	// stride for sampling the flow field -- larger values are faster, and values less than 1 are treated as 1
+	Stride int `default:"2"`
+
+	// minimum flow magnitude for a location to contribute to the responses
+	MinMag float32 `default:"0.01"`
+
+	// proportion of the maximum Expansion response above which candidate centers contribute to the Heading estimate
+	HeadingThr float32 `default:"0.9"`
+
+	// center-relative template responses: [NCenters (Y), NCenters (X), TemplatesN]
+	Out tensor.Float32 `display:"no-inline"`
+
+	// translation template responses: [NTrans]
+	Trans tensor.Float32 `display:"no-inline"`
+
+	// estimated heading (focus of expansion), in normalized 0-1 image coordinates, in the same Y orientation as the flow
+	Heading math32.Vector2 `edit:"-"`
+}
+
+func (ms *MST) Defaults() {
+	ms.NCenters = 9
+	ms.NTrans = 8
+	ms.Stride = 2
+	ms.MinMag = 0.01
+	ms.HeadingThr = 0.9
+}
+
+// CenterPos returns the image position of candidate center index
+// ci along an axis of given size.
+func (ms *MST) CenterPos(ci, size int) float32 {
+	return (float32(ci) + 0.5) * float32(size) / float32(ms.NCenters)
+}
+
+// Filter computes the MST template responses and heading estimate from
+// given optic flow field, which has shape [2, Y, X] (see package docs).
+func (ms *MST) Filter(flow *tensor.Float32) {
+	ms.Out.SetShapeSizes(ms.NCenters, ms.NCenters, int(TemplatesN))
+	nc := ms.NCenters * ms.NCenters
+	nproc.Run("MST", nc, func(st, n int) {
+		ms.filterThr(st, n, flow)
+	})
+	ms.TransFilter(flow)
+	ms.ComputeHeading(flow.DimSize(1), flow.DimSize(2))
+}
+
+// filterThr is per-thread implementation
+func (ms *MST) filterThr(cst, ncs int, flow *tensor.Float32) {
+	sy := flow.DimSize(1)
+	sx := flow.DimSize(2)
+	stride := max(ms.Stride, 1)
+	for ci := 0; ci < ncs; ci++ {
+		c := cst + ci
+		cy := c / ms.NCenters
+		cx := c % ms.NCenters
+		ctr := math32.Vec2(ms.CenterPos(cx, sx), ms.CenterPos(cy, sy))
+		var exp, rot, msum float32
+		for y := 0; y < sy; y += stride {
+			for x := 0; x < sx; x += stride {
+				fv := math32.Vec2(flow.Value(0, y, x), flow.Value(1, y, x))
+				mag := fv.Length()
+				if mag < ms.MinMag {
+					continue
+				}
+				rv := math32.Vec2(float32(x), float32(y)).Sub(ctr)
+				rl := rv.Length()
+				if rl == 0 {
+					continue
+				}
+				rv = rv.DivScalar(rl)
+				exp += fv.Dot(rv)
+				rot += fv.X*(-rv.Y) + fv.Y*rv.X
+				msum += mag
+			}
+		}
+		if msum > 0 {
+			exp /= msum
+			rot /= msum
+		}
+		ms.Out.Set(math32.Max(exp, 0), cy, cx, int(Expansion))
+		ms.Out.Set(math32.Max(-exp, 0), cy, cx, int(Contraction))
+		ms.Out.Set(math32.Max(rot, 0), cy, cx, int(CCWRotation))
+		ms.Out.Set(math32.Max(-rot, 0), cy, cx, int(CWRotation))
+	}
+}
+
+// TransFilter computes the translation template responses into Trans
+func (ms *MST) TransFilter(flow *tensor.Float32) {
+	ms.Trans.SetShapeSizes(ms.NTrans)
+	sy := flow.DimSize(1)
+	sx := flow.DimSize(2)
+	stride := max(ms.Stride, 1)
+	var sum math32.Vector2
+	var msum float32
+	for y := 0; y < sy; y += stride {
+		for x := 0; x < sx; x += stride {
+			fv := math32.Vec2(flow.Value(0, y, x), flow.Value(1, y, x))
+			mag := fv.Length()
+			if mag < ms.MinMag {
+				continue
+			}
+			sum = sum.Add(fv)
+			msum += mag
+		}
+	}
+	if msum > 0 {
+		sum = sum.DivScalar(msum)
+	}
+	for d := 0; d < ms.NTrans; d++ {
+		ang := 2 * math32.Pi * float32(d) / float32(ms.NTrans)
+		tv := math32.Vec2(math32.Cos(ang), math32.Sin(ang))
+		ms.Trans.Set(math32.Max(sum.Dot(tv), 0), d)
+	}
+}
+
+// ComputeHeading computes the Heading estimate as the response-weighted
+// average position of the candidate centers whose Expansion response
+// is above HeadingThr of the maximum, for flow of given size.
+func (ms *MST) ComputeHeading(sy, sx int) {
+	mx := float32(0)
+	for cy := 0; cy < ms.NCenters; cy++ {
+		for cx := 0; cx < ms.NCenters; cx++ {
+			mx = math32.Max(mx, ms.Out.Value(cy, cx, int(Expansion)))
+		}
+	}
+	ms.Heading = math32.Vec2(0.5, 0.5)
+	if mx <= 0 {
+		return
+	}
+	thr := ms.HeadingThr * mx
+	var sum math32.Vector2
+	var wsum float32
+	for cy := 0; cy < ms.NCenters; cy++ {
+		for cx := 0; cx < ms.NCenters; cx++ {
+			ev := ms.Out.Value(cy, cx, int(Expansion))
+			if ev < thr {
+				continue
+			}
+			pos := math32.Vec2(ms.CenterPos(cx, sx)/float32(sx), ms.CenterPos(cy, sy)/float32(sy))
+			sum = sum.Add(pos.MulScalar(ev))
+			wsum += ev
+		}
+	}
+	ms.Heading = sum.DivScalar(wsum)
+}
+
+// FlowFromDirs computes an optic flow field from direction-tuned
+// (MT-like) responses, as the population vector sum over directions.
+// dirs must have the direction as the inner-most dimension, with the
+// outer two dimensions as Y, X (any dimensions in between are summed).
+// Output flow has shape [2, Y, X] (see package docs).
+func FlowFromDirs(dirs, flow *tensor.Float32) {
+	nd := dirs.NumDims()
+	sy := dirs.DimSize(0)
+	sx := dirs.DimSize(1)
+	ndir := dirs.DimSize(nd - 1)
+	nper := dirs.Len() / (sy * sx)
+	flow.SetShapeSizes(2, sy, sx)
+	for y := 0; y < sy; y++ {
+		for x := 0; x < sx; x++ {
+			st := (y*sx + x) * nper
+			var fx, fy float32
+			for i := 0; i < nper; i++ {
+				d := i % ndir
+				ang := 2 * math32.Pi * float32(d) / float32(ndir)
+				v := dirs.Values[st+i]
+				fx += v * math32.Cos(ang)
+				fy += v * math32.Sin(ang)
+			}
+			flow.Set(fx, 0, y, x)
+			flow.Set(fy, 1, y, x)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package motion
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Opponent computes opponent motion signals from direction-tuned
+// motion-energy outputs, as the response in the preferred direction
+// minus a weighted response in the anti-preferred (opposite) direction,
+// followed by rectification, as in standard MT models (e.g., Simoncelli &
+// Heeger, 1998).  This suppresses the non-directional flicker components
+// that drive both directions, substantially cleaning up direction maps
+// prior to pooling.
+type Opponent struct {
+
+	// whether to compute opponency -- if off, the input is just copied to the output
+	On bool
+
+	// weight on the anti-preferred direction response that is subtracted from the preferred direction response -- 1 = full opponency
+	Wt float32 `default:"1"`
+
+	// threshold subtracted from the opponent difference prior to rectification
+	Thr float32 `default:"0"`
+
+	// overall gain multiplier applied to the rectified output
+	Gain float32 `default:"1"`
+}
+
+func (op *Opponent) Defaults() {
+	op.On = true
+	op.Wt = 1
+	op.Thr = 0
+	op.Gain = 1
+}
+
+func (op *Opponent) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return op.On
+	}
+}
+
+// Filter computes the rectified opponent motion responses from given
+// direction-tuned input, which must have the direction as the
+// inner-most dimension (see package docs), with an even number of
+// directions so that the opposite of direction d is d + NDirs / 2.
+// Output has the same shape as the input.
+func (op *Opponent) Filter(in, out *tensor.Float32) {
+	op.FilterDim(in, out, in.NumDims()-1)
+}
+
+// FilterDim computes the rectified opponent motion responses from given
+// direction-tuned input, where dim is the direction dimension, e.g.,
+// 2 for the [Y, X, Direction, Speed] output of Energy.
+// There must be an even number of directions so that the opposite of
+// direction d is d + NDirs / 2.  Output has the same shape as the input.
+func (op *Opponent) FilterDim(in, out *tensor.Float32, dim int) {
+	tensor.SetShapeFrom(out, in)
+	if !op.On {
+		out.CopyFrom(in)
+		return
+	}
+	ndir := in.DimSize(dim)
+	hdir := ndir / 2
+	inner := 1
+	for d := dim + 1; d < in.NumDims(); d++ {
+		inner *= in.DimSize(d)
+	}
+	n := in.Len() / (ndir * inner)
+	for i := 0; i < n; i++ {
+		st := i * ndir * inner
+		for d := 0; d < ndir; d++ {
+			od := (d + hdir) % ndir
+			for j := 0; j < inner; j++ {
+				df := in.Values[st+d*inner+j] - op.Wt*in.Values[st+od*inner+j] - op.Thr
+				out.Values[st+d*inner+j] = op.Gain * math32.Max(df, 0)
+			}
+		}
+	}
+}
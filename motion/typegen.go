@@ -0,0 +1,15 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package motion
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/motion.Energy", IDName: "energy", Doc: "Energy computes direction and speed tuned motion energy from a\nsequence of image frames, using Reichardt-style correlation detectors:\nthe response for direction d at location p is the product of a\ntemporally delayed (low-pass filtered) image value at p and the current\nimage value at p + Dist pixels in direction d.\nMultiple speed bands are produced by scaling the time constant of the\ndelay filter (i.e., temporal frequency scaling), with preferred speed\nof roughly Dist / tau pixels per frame for each band.\nThese are half-detectors, which also respond to flicker and static\ncontrast -- use Opponent to remove these non-directional components.", Fields: []types.Field{{Name: "NDirs", Doc: "number of directions, evenly spaced around the full circle starting with rightward"}, {Name: "NSpeeds", Doc: "number of speed bands, from fastest to slowest"}, {Name: "Tau", Doc: "time constant in frames of the delay low-pass filter for the first (fastest) speed band"}, {Name: "TauMult", Doc: "multiplier on the time constant for each successive speed band: tau(s) = Tau * TauMult^s"}, {Name: "Dist", Doc: "distance in pixels between correlated points"}, {Name: "Spacing", Doc: "spacing of output units in pixels"}, {Name: "Gain", Doc: "overall gain multiplier on the outputs"}, {Name: "Geom", Doc: "geometry of input, output"}, {Name: "Delayed", Doc: "delayed (low-pass filtered) image state for each speed band: [NSpeeds, Y, X]"}, {Name: "Out", Doc: "motion energy output: [Y, X, NDirs, NSpeeds]"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/motion.Templates", IDName: "templates", Doc: "Templates are the center-relative optic flow templates used by MST"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/motion.MST", IDName: "mst", Doc: "MST computes MST-like responses by pooling an optic flow field\ninto templates for expansion, contraction and rotation centered at\na grid of candidate centers, and for translation in NTrans directions.\nEach response is the flow-magnitude-weighted average cosine between\nthe flow and the template direction, rectified to be positive,\nso it is largely invariant to overall speed.", Fields: []types.Field{{Name: "NCenters", Doc: "number of candidate template centers along each axis, evenly spaced over the image"}, {Name: "NTrans", Doc: "number of translation directions, evenly spaced around the circle starting with rightward"}, {Name: "Stride", Doc: "stride for sampling the flow field -- larger values are faster, and values less than 1 are treated as 1"}, {Name: "MinMag", Doc: "minimum flow magnitude for a location to contribute to the responses"}, {Name: "HeadingThr", Doc: "proportion of the maximum Expansion response above which candidate centers contribute to the Heading estimate"}, {Name: "Out", Doc: "center-relative template responses: [NCenters (Y), NCenters (X), TemplatesN]"}, {Name: "Trans", Doc: "translation template responses: [NTrans]"}, {Name: "Heading", Doc: "estimated heading (focus of expansion), in normalized 0-1 image coordinates, in the same Y orientation as the flow"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/motion.Opponent", IDName: "opponent", Doc: "Opponent computes opponent motion signals from direction-tuned\nmotion-energy outputs, as the response in the preferred direction\nminus a weighted response in the anti-preferred (opposite) direction,\nfollowed by rectification, as in standard MT models (e.g., Simoncelli &\nHeeger, 1998).  This suppresses the non-directional flicker components\nthat drive both directions, substantially cleaning up direction maps\nprior to pooling.", Fields: []types.Field{{Name: "On", Doc: "whether to compute opponency -- if off, the input is just copied to the output"}, {Name: "Wt", Doc: "weight on the anti-preferred direction response that is subtracted from the preferred direction response -- 1 = full opponency"}, {Name: "Thr", Doc: "threshold subtracted from the opponent difference prior to rectification"}, {Name: "Gain", Doc: "overall gain multiplier applied to the rectified output"}}})
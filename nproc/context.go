@@ -0,0 +1,68 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nproc
+
+import (
+	"context"
+	"sync"
+)
+
+// ProgressFunc is a function that is called to report the progress of
+// a long-running operation, with the number of jobs done out of total.
+type ProgressFunc func(done, total int)
+
+// progressKey is the context key for the ProgressFunc
+type progressKey struct{}
+
+// WithProgress returns a copy of ctx with given progress function,
+// which is called by RunContext as each job is done, and by other
+// context-aware operations (e.g., per iteration), for displaying
+// progress in a GUI.  The function is called from the worker
+// goroutines, one call at a time.  A nil function removes any
+// progress function, e.g., for nested operations.
+func WithProgress(ctx context.Context, fun ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fun)
+}
+
+// Progress returns the progress function of given context,
+// set by WithProgress, or nil if none.
+func Progress(ctx context.Context) ProgressFunc {
+	fun, _ := ctx.Value(progressKey{}).(ProgressFunc)
+	return fun
+}
+
+// RunContext is Run with cancellation and progress reporting via ctx:
+// the context is checked before each job, so that the operation stops
+// soon after ctx is canceled, returning ctx.Err(), and the Progress
+// function of ctx, if any, is called as each job is done.  Each job is
+// passed to fun individually (with n = 1) in this case.  If ctx cannot
+// be canceled and has no progress function, this is just Run.
+func RunContext(ctx context.Context, op string, njobs int, fun func(st, n int)) error {
+	prog := Progress(ctx)
+	if ctx.Done() == nil && prog == nil {
+		Run(op, njobs, fun)
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var mu sync.Mutex
+	done := 0
+	Run(op, njobs, func(st, n int) {
+		for j := st; j < st+n; j++ {
+			if ctx.Err() != nil {
+				return
+			}
+			fun(j, 1)
+			if prog != nil {
+				mu.Lock()
+				done++
+				prog(done, njobs)
+				mu.Unlock()
+			}
+		}
+	})
+	return ctx.Err()
+}
@@ -4,7 +4,13 @@
 
 /*
 Package nproc provides number of processors using slurm env var
-SLURM_CPUS_PER_TASK or runtime.NumCPU().
+SLURM_CPUS_PER_TASK or runtime.NumCPU(), along with
+tuned numbers of threads for specific operations (Threads),
+e.g., as determined by auto-tuning benchmarks.
+
+Run runs parallel operations on a shared worker pool, whose size is
+set by SetNumThreads (1 = no extra goroutines, e.g., for WASM),
+and RunContext adds cancellation and progress reporting (WithProgress).
 
 TODO: move this to dmem package once that is started.
 */
@@ -14,21 +20,32 @@ import (
 	"os"
 	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
-var NumCPUCache int
+// NumCPUCache is the number of threads returned by NumCPU, set by
+// SetNumThreads, or to the default on first use if 0.
+// It is atomic so that it is safe for concurrent use.
+var NumCPUCache atomic.Int32
 
-func NumCPU() int {
-	if NumCPUCache > 0 {
-		return NumCPUCache
-	}
+// defaultNumCPU returns the SLURM_CPUS_PER_TASK env var if set,
+// else runtime.NumCPU(), computed only once.
+var defaultNumCPU = sync.OnceValue(func() int32 {
 	ncs, ok := os.LookupEnv("SLURM_CPUS_PER_TASK")
 	if !ok {
-		NumCPUCache = runtime.NumCPU()
-	} else {
-		NumCPUCache, _ = strconv.Atoi(ncs)
+		return int32(runtime.NumCPU())
+	}
+	nc, _ := strconv.Atoi(ncs)
+	return int32(nc)
+})
+
+func NumCPU() int {
+	if nc := NumCPUCache.Load(); nc > 0 {
+		return int(nc)
 	}
-	return NumCPUCache
+	NumCPUCache.CompareAndSwap(0, defaultNumCPU())
+	return int(NumCPUCache.Load())
 }
 
 // ThreadNs computes number of threads and number of jobs per thread,
@@ -0,0 +1,127 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nproc
+
+import (
+	"sync"
+)
+
+// pool is a fixed set of worker goroutines that run tasks
+type pool struct {
+	// tasks are sent to the workers
+	tasks chan func()
+
+	// nworkers is the number of worker goroutines
+	nworkers int
+}
+
+var (
+	// thePool is the shared worker pool, started on first use
+	thePool *pool
+
+	// poolMu protects thePool
+	poolMu sync.Mutex
+)
+
+// SetNumThreads sets the total number of threads used for all parallel
+// operations, including the calling goroutine, which determines the
+// size of the shared worker pool used by Run, and caps the tuned
+// values from SetThreads.  1 runs everything in the calling goroutine,
+// with no extra goroutines at all, e.g., for WASM or other
+// single-threaded environments.  A value <= 0 resets to the default
+// NumCPU value.
+func SetNumThreads(n int) {
+	NumCPUCache.Store(int32(max(n, 0)))
+	nw := NumCPU() - 1
+	poolMu.Lock()
+	if thePool != nil && thePool.nworkers != nw {
+		thePool.stop()
+		thePool = nil
+	}
+	poolMu.Unlock()
+}
+
+// getPool returns the shared worker pool, with NumCPU() - 1 workers,
+// (re)starting it if needed.
+func getPool() *pool {
+	nw := NumCPU() - 1
+	poolMu.Lock()
+	defer poolMu.Unlock()
+	if thePool != nil && thePool.nworkers == nw {
+		return thePool
+	}
+	if thePool != nil {
+		thePool.stop()
+	}
+	thePool = &pool{tasks: make(chan func()), nworkers: nw}
+	for range nw {
+		go thePool.work()
+	}
+	return thePool
+}
+
+// work is the worker goroutine, which runs tasks until a nil task
+func (pl *pool) work() {
+	for task := range pl.tasks {
+		if task == nil {
+			return
+		}
+		task()
+	}
+}
+
+// stop stops the workers once they are done with any current tasks.
+// The tasks channel is not closed, so any concurrent Run that still
+// has the pool can safely send to it, running the task itself if
+// there is no worker to take it.
+func (pl *pool) stop() {
+	go func() {
+		for range pl.nworkers {
+			pl.tasks <- nil
+		}
+	}()
+}
+
+// Run runs fun over njobs divided into Threads(op) contiguous chunks
+// (as in ThreadNs), where fun is called with the starting job and
+// number of jobs in each chunk, returning when all are done.
+// Chunks are run by the shared worker pool and the calling goroutine,
+// which runs any chunks that no worker is free to take, so there is
+// no goroutine spawning per call, and calls can be nested without
+// deadlock.  If there is only one chunk, it is run directly in the
+// calling goroutine.  fun must be safe to call concurrently for
+// different chunks.
+func Run(op string, njobs int, fun func(st, n int)) {
+	if njobs <= 0 {
+		return
+	}
+	nthrs, nper, rmdr := ThreadNs(min(Threads(op), NumCPU()), njobs)
+	nchunk := nthrs
+	if rmdr > 0 {
+		nchunk++
+	}
+	if nchunk == 1 {
+		fun(0, njobs)
+		return
+	}
+	pl := getPool()
+	var wg sync.WaitGroup
+	for ch := 0; ch < nchunk-1; ch++ {
+		st := ch * nper
+		wg.Add(1)
+		task := func() {
+			fun(st, nper)
+			wg.Done()
+		}
+		select {
+		case pl.tasks <- task:
+		default:
+			task()
+		}
+	}
+	st := (nchunk - 1) * nper
+	fun(st, njobs-st)
+	wg.Wait()
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nproc
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+var (
+	// tuned is the map of tuned thread counts per operation
+	tuned = map[string]int{}
+
+	// tunedMu protects tuned
+	tunedMu sync.RWMutex
+)
+
+// Threads returns the number of threads to use for given operation
+// (e.g., "Conv", "MaxPool", "KWTA"), which is the tuned value set by
+// SetThreads (e.g., from auto-tuning benchmarks) if available,
+// otherwise NumCPU().
+func Threads(op string) int {
+	tunedMu.RLock()
+	n, ok := tuned[op]
+	tunedMu.RUnlock()
+	if ok && n > 0 {
+		return n
+	}
+	return NumCPU()
+}
+
+// SetThreads sets the tuned number of threads to use for given
+// operation -- a value <= 0 removes any tuned value.
+func SetThreads(op string, n int) {
+	tunedMu.Lock()
+	if n <= 0 {
+		delete(tuned, op)
+	} else {
+		tuned[op] = n
+	}
+	tunedMu.Unlock()
+}
+
+// TunedThreads returns a copy of all of the tuned thread counts
+func TunedThreads() map[string]int {
+	tunedMu.RLock()
+	defer tunedMu.RUnlock()
+	tt := make(map[string]int, len(tuned))
+	for op, n := range tuned {
+		tt[op] = n
+	}
+	return tt
+}
+
+// ResetThreads removes all tuned thread counts
+func ResetThreads() {
+	tunedMu.Lock()
+	tuned = map[string]int{}
+	tunedMu.Unlock()
+}
+
+// SaveThreads saves the tuned thread counts to given JSON file,
+// for reuse in later runs on the same machine.
+func SaveThreads(filename string) error {
+	b, err := json.MarshalIndent(TunedThreads(), "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// OpenThreads opens tuned thread counts from given JSON file,
+// as saved by SaveThreads, adding to any existing tuned values.
+func OpenThreads(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	tt := map[string]int{}
+	if err := json.Unmarshal(b, &tt); err != nil {
+		return err
+	}
+	for op, n := range tt {
+		SetThreads(op, n)
+	}
+	return nil
+}
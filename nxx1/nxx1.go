@@ -136,6 +136,46 @@ func (xp *Params) NoisyXX1(x float32) float32 {
 	}
 }
 
+// NoisyXX1Slice computes NoisyXX1 for each of the in values into out,
+// which must be at least as long as in, and can be the same as in.
+// The parameters are loaded once, and the piecewise function is inlined
+// with the most common above-threshold case tested first, producing
+// identical values to NoisyXX1 without the per-call overhead.
+func (xp *Params) NoisyXX1Slice(out, in []float32) {
+	out = out[:len(in)]
+	gain := xp.Gain
+	nvar := xp.NVar
+	gcRange := xp.GainCorRange
+	gainCor := xp.GainCor
+	interpRange := xp.InterpRange
+	interpVal := xp.InterpVal
+	sigValAt0 := xp.SigValAt0
+	sigMultEff := xp.SigMultEff
+	sigGainNVar := xp.SigGainNVar
+	for i, x := range in {
+		if x >= interpRange {
+			g := gain
+			if gcf := (gcRange - (x / nvar)) / gcRange; gcf >= 0 {
+				g = gain * (1 - gainCor*gcf)
+			}
+			gx := g * x
+			out[i] = gx / (gx + 1)
+			continue
+		}
+		if x >= 0 {
+			interp := 1 - ((interpRange - x) / interpRange)
+			out[i] = sigValAt0 + interp*interpVal
+			continue
+		}
+		ex := -(x * sigGainNVar)
+		if ex > 50 {
+			out[i] = 0
+			continue
+		}
+		out[i] = sigMultEff / (1 + math32.FastExp(ex))
+	}
+}
+
 // X11GainCorGain computes x/(x+1) with gain correction within GainCorRange
 // to compensate for convolution effects -- using external gain factor
 func (xp *Params) XX1GainCorGain(x, gain float32) float32 {
@@ -36,3 +36,45 @@ func TestXX1(t *testing.T) {
 	}
 	// fmt.Printf("ny vals: %v\n", ny)
 }
+
+func TestNoisyXX1Slice(t *testing.T) {
+	xx1 := Params{}
+	xx1.Defaults()
+
+	n := 2000
+	in := make([]float32, n)
+	out := make([]float32, n)
+	for i := range in {
+		in[i] = -0.2 + 0.6*float32(i)/float32(n)
+	}
+	in = append(in, 0, xx1.InterpRange, -0.5)
+	out = append(out, 0, 0, 0)
+	xx1.NoisyXX1Slice(out, in)
+	for i, x := range in {
+		if y := xx1.NoisyXX1(x); out[i] != y {
+			t.Errorf("NoisyXX1Slice err: x: %v, y: %v, NoisyXX1 y: %v\n", x, out[i], y)
+		}
+	}
+}
+
+func BenchmarkNoisyXX1(b *testing.B) {
+	xx1 := Params{}
+	xx1.Defaults()
+	in := make([]float32, 4096)
+	out := make([]float32, len(in))
+	for i := range in {
+		in[i] = -0.2 + 0.6*float32(i)/float32(len(in))
+	}
+	b.Run("Scalar", func(b *testing.B) {
+		for range b.N {
+			for i, x := range in {
+				out[i] = xx1.NoisyXX1(x)
+			}
+		}
+	})
+	b.Run("Slice", func(b *testing.B) {
+		for range b.N {
+			xx1.NoisyXX1Slice(out, in)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package perf
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"runtime"
+	"slices"
+	"time"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/pipeline"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Bench measures the throughput, latency and memory allocation of a
+// full Pipeline over a set of synthetic or provided images, at several
+// image sizes and thread counts, recording the results in the Results
+// table, so that performance regressions and hardware comparisons are
+// reproducible.  The thread count limits both GOMAXPROCS and the
+// threads of each parallel operation, and any tuned thread counts are
+// ignored during the benchmark, and restored afterward.
+type Bench struct {
+
+	// pipeline to benchmark -- if nil, a new pipeline is configured with Preset.  Its ImgSize is set to each of the Sizes during the benchmark, and restored afterward
+	Pipeline *pipeline.Pipeline `display:"-"`
+
+	// preset for the pipeline, if Pipeline is nil
+	Preset pipeline.Presets
+
+	// image sizes (width and height) to test -- if empty, the pipeline ImgSize is used
+	Sizes []int
+
+	// thread counts to test -- if empty, powers of 2 up to MaxThreads are used, plus MaxThreads itself
+	Threads []int
+
+	// maximum number of threads to test, if Threads is empty -- if 0, NumCPU() is used
+	MaxThreads int
+
+	// images to filter -- if empty, NImages synthetic images are generated.  Images are resized to each size before the timing
+	Images []image.Image `display:"-"`
+
+	// number of synthetic images to generate, if Images is empty
+	NImages int `default:"16"`
+
+	// number of timed passes over the images, after an initial warm-up pass
+	Reps int `default:"3"`
+
+	// random seed for the synthetic images
+	Seed int64 `default:"1"`
+
+	// benchmark results, with columns: Size, Threads, Images (number of timed images), Time (total seconds), Throughput (images per second), Latency, LatencyP95 and LatencyMax (seconds per image: mean, 95th percentile, max), AllocBytes and Mallocs (heap allocation per image), and HeapBytes (heap in use at the end)
+	Results *table.Table `display:"no-inline"`
+}
+
+func (bn *Bench) Defaults() {
+	bn.Preset = pipeline.LVis
+	bn.Sizes = []int{64, 128, 256}
+	bn.NImages = 16
+	bn.Reps = 3
+	bn.Seed = 1
+}
+
+// ThreadCounts returns the thread counts to test: Threads if set,
+// otherwise powers of 2 up to MaxThreads, plus MaxThreads itself.
+func (bn *Bench) ThreadCounts() []int {
+	if len(bn.Threads) > 0 {
+		return bn.Threads
+	}
+	return threadCounts(bn.MaxThreads)
+}
+
+// Synthetic returns n synthetic color images of given size, each a sum
+// of a few randomly oriented colored sine gratings with added noise,
+// which produce realistic levels of activity throughout the pipeline.
+func Synthetic(n int, size image.Point, seed int64) []image.Image {
+	rnd := rand.New(rand.NewSource(seed))
+	imgs := make([]image.Image, n)
+	for i := range imgs {
+		img := image.NewRGBA(image.Rectangle{Max: size})
+		var kx, ky, ph [3]float32
+		var col [3][3]float32
+		for g := range 3 {
+			ang := rnd.Float32() * math32.Pi
+			fr := (0.02 + 0.15*rnd.Float32()) * 2 * math32.Pi
+			kx[g] = fr * math32.Cos(ang)
+			ky[g] = fr * math32.Sin(ang)
+			ph[g] = rnd.Float32() * 2 * math32.Pi
+			for c := range 3 {
+				col[g][c] = rnd.Float32()
+			}
+		}
+		for y := range size.Y {
+			for x := range size.X {
+				var rgb [3]float32
+				for g := range 3 {
+					v := math32.Sin(kx[g]*float32(x) + ky[g]*float32(y) + ph[g])
+					for c := range 3 {
+						rgb[c] += v * col[g][c] / 3
+					}
+				}
+				var px [3]uint8
+				for c := range 3 {
+					v := 0.5 + 0.4*rgb[c] + 0.05*float32(rnd.NormFloat64())
+					px[c] = uint8(255 * math32.Clamp(v, 0, 1))
+				}
+				img.Set(x, y, color.RGBA{px[0], px[1], px[2], 255})
+			}
+		}
+		imgs[i] = img
+	}
+	return imgs
+}
+
+// Run runs the benchmarks for each size and thread count, recording
+// the results in the Results table, which is returned.
+func (bn *Bench) Run() *table.Table {
+	pl := bn.Pipeline
+	if pl == nil {
+		pl = &pipeline.Pipeline{}
+		pl.SetPreset(bn.Preset)
+	}
+	imgSize := pl.ImgSize
+	sizes := bn.Sizes
+	if len(sizes) == 0 {
+		sizes = []int{imgSize.X}
+	}
+	srcs := bn.Images
+	if len(srcs) == 0 {
+		mx := slices.Max(sizes)
+		srcs = Synthetic(max(bn.NImages, 1), image.Point{mx, mx}, bn.Seed)
+	}
+	tcs := bn.ThreadCounts()
+
+	tuned := nproc.TunedThreads()
+	ncpu := int(nproc.NumCPUCache.Load())
+	maxProcs := runtime.GOMAXPROCS(0)
+	defer func() {
+		pl.ImgSize = imgSize
+		nproc.ResetThreads()
+		for op, n := range tuned {
+			nproc.SetThreads(op, n)
+		}
+		nproc.SetNumThreads(ncpu)
+		runtime.GOMAXPROCS(maxProcs)
+	}()
+	nproc.ResetThreads()
+
+	bn.Results = table.New("Bench")
+	dt := bn.Results
+	dt.AddIntColumn("Size")
+	dt.AddIntColumn("Threads")
+	dt.AddIntColumn("Images")
+	for _, c := range []string{"Time", "Throughput", "Latency", "LatencyP95", "LatencyMax", "AllocBytes", "Mallocs", "HeapBytes"} {
+		dt.AddFloat64Column(c)
+	}
+	dt.SetNumRows(len(sizes) * len(tcs))
+	row := 0
+	for _, sz := range sizes {
+		pl.ImgSize = image.Point{sz, sz}
+		imgs := make([]image.Image, len(srcs))
+		for i, img := range srcs {
+			if img.Bounds().Size() != pl.ImgSize {
+				img = resizeImage(img, pl.ImgSize, pl.Resize)
+			}
+			imgs[i] = img
+		}
+		for _, nt := range tcs {
+			nproc.SetNumThreads(nt)
+			runtime.GOMAXPROCS(nt)
+			for _, img := range imgs { // warm up
+				pl.FilterImage(img)
+			}
+			bn.measure(pl, imgs, dt, row)
+			dt.Column("Size").SetFloat1D(float64(sz), row)
+			dt.Column("Threads").SetFloat1D(float64(nt), row)
+			row++
+		}
+	}
+	return dt
+}
+
+// measure runs the timed passes over given images and records
+// the results in given row of the table.
+func (bn *Bench) measure(pl *pipeline.Pipeline, imgs []image.Image, dt *table.Table, row int) {
+	reps := max(bn.Reps, 1)
+	lats := make([]float64, 0, reps*len(imgs))
+	var ms0, ms1 runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms0)
+	st := time.Now()
+	for range reps {
+		for _, img := range imgs {
+			ist := time.Now()
+			pl.FilterImage(img)
+			lats = append(lats, time.Since(ist).Seconds())
+		}
+	}
+	tot := time.Since(st).Seconds()
+	runtime.ReadMemStats(&ms1)
+	n := float64(len(lats))
+	var sum float64
+	for _, l := range lats {
+		sum += l
+	}
+	slices.Sort(lats)
+	p95 := lats[int(0.95*float64(len(lats)-1))]
+	dt.Column("Images").SetFloat1D(n, row)
+	dt.Column("Time").SetFloat1D(tot, row)
+	dt.Column("Throughput").SetFloat1D(n/tot, row)
+	dt.Column("Latency").SetFloat1D(sum/n, row)
+	dt.Column("LatencyP95").SetFloat1D(p95, row)
+	dt.Column("LatencyMax").SetFloat1D(lats[len(lats)-1], row)
+	dt.Column("AllocBytes").SetFloat1D(float64(ms1.TotalAlloc-ms0.TotalAlloc)/n, row)
+	dt.Column("Mallocs").SetFloat1D(float64(ms1.Mallocs-ms0.Mallocs)/n, row)
+	dt.Column("HeapBytes").SetFloat1D(float64(ms1.HeapInuse), row)
+}
+
+// SaveResults saves the Results table to given file,
+// as tab-separated values.
+func (bn *Bench) SaveResults(filename fsx.Filename) error {
+	return bn.Results.SaveCSV(filename, tensor.Tab, table.Headers)
+}
+
+// resizeImage returns given image resized to given size, with given
+// interpolation, on the tensor values, once for each size, so that
+// the resizing is not included in the benchmark.
+func resizeImage(img image.Image, size image.Point, mode vfilter.ResizeModes) image.Image {
+	sz := img.Bounds().Size()
+	raw := vfilter.Pool.Get(3, sz.Y, sz.X)
+	rs := vfilter.Pool.Get(3, size.Y, size.X)
+	defer vfilter.Pool.Put(raw, rs)
+	vfilter.RGBToTensor(img, raw, 0, true)
+	vfilter.ResizeTensor(raw, rs, size, 0, mode)
+	for i, v := range rs.Values {
+		rs.Values[i] = math32.Clamp(v, 0, 1)
+	}
+	return vfilter.RGBTensorToImage(nil, rs, 0, true)
+}
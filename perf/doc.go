@@ -0,0 +1,19 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package perf provides performance measurement and tuning for the
+filtering operations.
+
+* Tune benchmarks the main parallel operations (Conv, MaxPool, KWTA)
+at the configured image and filter sizes with varying numbers of
+threads, and records the fastest settings via nproc.SetThreads,
+which can be saved with nproc.SaveThreads for reuse.
+
+* Bench measures the throughput, latency and memory allocation of a
+full pipeline over synthetic or provided images, at several image
+sizes and thread counts, recording the results in a table.Table
+report, for tracking performance regressions and comparing hardware.
+*/
+package perf
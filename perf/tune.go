@@ -0,0 +1,143 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package perf
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"math/rand"
+	"time"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/vision/v2/fffb"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/kwta"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Tune benchmarks the main parallel operations (Conv, MaxPool, KWTA)
+// at the configured image and filter sizes with varying numbers of
+// threads, and records the fastest thread count for each operation via
+// nproc.SetThreads.  The default per-operation parallelism can be
+// suboptimal, e.g., with few filters on a machine with many cores.
+type Tune struct {
+
+	// size of the input image
+	ImgSize image.Point
+
+	// size of the gabor filters
+	FiltSize int `default:"12"`
+
+	// spacing of the gabor filters
+	Spacing int `default:"4"`
+
+	// number of gabor filter angles
+	NAngles int `default:"4"`
+
+	// number of repetitions of each benchmark -- the minimum time is used
+	Reps int `default:"5"`
+
+	// maximum number of threads to test -- if 0, NumCPU() is used
+	MaxThreads int
+
+	// benchmark results, with columns: Op, Threads, Time (seconds)
+	Results *table.Table `display:"no-inline"`
+}
+
+func (tu *Tune) Defaults() {
+	tu.ImgSize = image.Point{128, 128}
+	tu.FiltSize = 12
+	tu.Spacing = 4
+	tu.NAngles = 4
+	tu.Reps = 5
+}
+
+// ThreadCounts returns the thread counts to test: powers of 2 up
+// to MaxThreads, plus MaxThreads itself
+func (tu *Tune) ThreadCounts() []int {
+	return threadCounts(tu.MaxThreads)
+}
+
+// threadCounts returns powers of 2 up to given maximum number of
+// threads, plus the maximum itself, which is NumCPU() if <= 0.
+func threadCounts(mx int) []int {
+	if mx <= 0 {
+		mx = nproc.NumCPU()
+	}
+	var tc []int
+	for n := 1; n < mx; n *= 2 {
+		tc = append(tc, n)
+	}
+	return append(tc, mx)
+}
+
+// Run runs the benchmarks, records the results in the Results table,
+// sets the fastest thread counts with nproc.SetThreads, and returns
+// the map of fastest thread counts per operation.
+func (tu *Tune) Run() map[string]int {
+	rnd := rand.New(rand.NewSource(1))
+	gf := gabor.Filter{}
+	gf.Defaults()
+	gf.SetSize(tu.FiltSize, tu.Spacing)
+	gf.NAngles = tu.NAngles
+	var flt tensor.Float32
+	gf.ToTensor(&flt)
+	geom := vfilter.Geom{}
+	geom.Set(image.Point{0, 0}, image.Point{tu.Spacing, tu.Spacing}, image.Point{tu.FiltSize, tu.FiltSize})
+	var img, conv, pool, act tensor.Float32
+	img.SetShapeSizes(tu.ImgSize.Y+2*geom.FiltRt.Y, tu.ImgSize.X+2*geom.FiltRt.X)
+	for i := range img.Values {
+		img.Values[i] = rnd.Float32()
+	}
+	kw := kwta.KWTA{}
+	kw.Defaults()
+	var inhib fffb.Inhibs
+	vfilter.Conv(&geom, &flt, &img, &conv, gf.Gain)
+	ops := []struct {
+		name string
+		fun  func()
+	}{
+		{"Conv", func() { vfilter.Conv(&geom, &flt, &img, &conv, gf.Gain) }},
+		{"MaxPool", func() { vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, &conv, &pool) }},
+		{"KWTA", func() { kw.KWTAPool(&conv, &act, &inhib, nil) }},
+	}
+	tcs := tu.ThreadCounts()
+	tu.Results = table.New("Tune")
+	tu.Results.AddStringColumn("Op")
+	tu.Results.AddIntColumn("Threads")
+	tu.Results.AddFloat64Column("Time")
+	tu.Results.SetNumRows(len(ops) * len(tcs))
+	best := make(map[string]int)
+	row := 0
+	for _, op := range ops {
+		var bestT time.Duration
+		for _, nt := range tcs {
+			nproc.SetThreads(op.name, nt)
+			op.fun() // warm up
+			var mt time.Duration
+			for r := 0; r < max(tu.Reps, 1); r++ {
+				st := time.Now()
+				op.fun()
+				el := time.Since(st)
+				if r == 0 || el < mt {
+					mt = el
+				}
+			}
+			tu.Results.ColumnByIndex(0).SetStringRow(op.name, row, 0)
+			tu.Results.ColumnByIndex(1).SetFloat1D(float64(nt), row)
+			tu.Results.ColumnByIndex(2).SetFloat1D(mt.Seconds(), row)
+			row++
+			if bestT == 0 || mt < bestT {
+				bestT = mt
+				best[op.name] = nt
+			}
+		}
+		nproc.SetThreads(op.name, best[op.name])
+	}
+	return best
+}
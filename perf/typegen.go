@@ -0,0 +1,11 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package perf
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/perf.Bench", IDName: "bench", Doc: "Bench measures the throughput, latency and memory allocation of a\nfull Pipeline over a set of synthetic or provided images, at several\nimage sizes and thread counts, recording the results in the Results\ntable, so that performance regressions and hardware comparisons are\nreproducible.  The thread count limits both GOMAXPROCS and the\nthreads of each parallel operation, and any tuned thread counts are\nignored during the benchmark, and restored afterward.", Fields: []types.Field{{Name: "Pipeline", Doc: "pipeline to benchmark -- if nil, a new pipeline is configured with Preset.  Its ImgSize is set to each of the Sizes during the benchmark, and restored afterward"}, {Name: "Preset", Doc: "preset for the pipeline, if Pipeline is nil"}, {Name: "Sizes", Doc: "image sizes (width and height) to test -- if empty, the pipeline ImgSize is used"}, {Name: "Threads", Doc: "thread counts to test -- if empty, powers of 2 up to MaxThreads are used, plus MaxThreads itself"}, {Name: "MaxThreads", Doc: "maximum number of threads to test, if Threads is empty -- if 0, NumCPU() is used"}, {Name: "Images", Doc: "images to filter -- if empty, NImages synthetic images are generated.  Images are resized to each size before the timing"}, {Name: "NImages", Doc: "number of synthetic images to generate, if Images is empty"}, {Name: "Reps", Doc: "number of timed passes over the images, after an initial warm-up pass"}, {Name: "Seed", Doc: "random seed for the synthetic images"}, {Name: "Results", Doc: "benchmark results, with columns: Size, Threads, Images (number of timed images), Time (total seconds), Throughput (images per second), Latency, LatencyP95 and LatencyMax (seconds per image: mean, 95th percentile, max), AllocBytes and Mallocs (heap allocation per image), and HeapBytes (heap in use at the end)"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/perf.Tune", IDName: "tune", Doc: "Tune benchmarks the main parallel operations (Conv, MaxPool, KWTA)\nat the configured image and filter sizes with varying numbers of\nthreads, and records the fastest thread count for each operation via\nnproc.SetThreads.  The default per-operation parallelism can be\nsuboptimal, e.g., with few filters on a machine with many cores.", Fields: []types.Field{{Name: "ImgSize", Doc: "size of the input image"}, {Name: "FiltSize", Doc: "size of the gabor filters"}, {Name: "Spacing", Doc: "spacing of the gabor filters"}, {Name: "NAngles", Doc: "number of gabor filter angles"}, {Name: "Reps", Doc: "number of repetitions of each benchmark -- the minimum time is used"}, {Name: "MaxThreads", Doc: "maximum number of threads to test -- if 0, NumCPU() is used"}, {Name: "Results", Doc: "benchmark results, with columns: Op, Threads, Time (seconds)"}}})
@@ -0,0 +1,302 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package phasecong computes phase congruency features (Kovesi, 1999),
+from quadrature pairs of gabor filters (even = cosine, odd = sine)
+over multiple scales.  Phase congruency is high where the responses
+at all scales are in phase, as occurs at edges and lines, independent
+of the local contrast, so it provides contrast-invariant edge and
+corner maps as additional V1-level features.
+
+The outputs are the phase congruency per orientation, as a
+[Y, X, 1, Angle] tensor in the same format as V1 simple-cell outputs,
+and the edge and corner strength computed from the moments of phase
+congruency over orientations, as a [2, Y, X] tensor.
+*/
+package phasecong
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Filter specifies the computation of phase congruency from
+// quadrature gabor filters at NScales scales, each Mult times
+// larger than the previous one, all computed on the same Geom.
+type Filter struct {
+
+	// is this filter active?
+	On bool
+
+	// number of filter scales
+	NScales int `default:"4"`
+
+	// size and wavelength of the smallest scale filter, in pixels
+	MinSize int `default:"6"`
+
+	// scaling factor between successive filter sizes
+	Mult float32 `default:"2"`
+
+	// number of angles of filter orientation -- first angle is horizontal, as in gabor
+	NAngles int `default:"4"`
+
+	// spacing of the output, in pixels
+	Spacing int `default:"1"`
+
+	// gaussian sigma for the length dimension of the gabor filters, as a proportion of filter size
+	SigLen float32 `default:"0.3"`
+
+	// gaussian sigma for the width dimension of the gabor filters, as a proportion of filter size
+	SigWd float32 `default:"0.2"`
+
+	// number of standard deviations of the noise energy above the mean at which to set the noise threshold
+	K float32 `default:"2"`
+
+	// fractional measure of frequency spread below which phase congruency values are penalized
+	CutOff float32 `default:"0.5"`
+
+	// sharpness of the sigmoid function used to weight phase congruency by frequency spread
+	G float32 `default:"10"`
+
+	// small value to avoid division by zero
+	Eps float32 `default:"0.0001"`
+
+	// geometry for each scale, all with the same Border, so they have the same output size
+	Geoms []vfilter.Geom `edit:"-" display:"-"`
+
+	// even (cosine, phase 90) filters for each scale: [Angle, Y, X]
+	EvenTsrs []tensor.Float32 `display:"-"`
+
+	// odd (sine, phase 0) filters for each scale: [Angle, Y, X]
+	OddTsrs []tensor.Float32 `display:"-"`
+
+	// phase congruency per orientation: [Y, X, 1, Angle]
+	PCOut tensor.Float32 `display:"no-inline"`
+
+	// edge (maximum moment) and corner (minimum moment) strength: [2, Y, X]
+	EdgeCornerOut tensor.Float32 `display:"no-inline"`
+
+	// even filter outputs per scale, from vfilter.Conv: [Y, X, 2, Angle]
+	evenOut []tensor.Float32
+
+	// odd filter outputs per scale, from vfilter.Conv: [Y, X, 2, Angle]
+	oddOut []tensor.Float32
+}
+
+// NRows is the number of rows added to a V1All tensor by V1AllRows:
+// phase congruency, then edge and corner strength.
+const NRows = 3
+
+// Output indexes for the outer dimension of EdgeCornerOut
+const (
+	// Edge is the maximum moment of phase congruency over orientations
+	Edge = 0
+
+	// Corner is the minimum moment of phase congruency over orientations
+	Corner = 1
+)
+
+func (pc *Filter) Defaults() {
+	pc.On = true
+	pc.NScales = 4
+	pc.MinSize = 6
+	pc.Mult = 2
+	pc.NAngles = 4
+	pc.Spacing = 1
+	pc.SigLen = 0.3
+	pc.SigWd = 0.2
+	pc.K = 2
+	pc.CutOff = 0.5
+	pc.G = 10
+	pc.Eps = 0.0001
+	pc.Config()
+}
+
+func (pc *Filter) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return pc.On
+	}
+}
+
+// Size returns the filter size for given scale, rounded to an even number
+func (pc *Filter) Size(scale int) int {
+	sz := float32(pc.MinSize) * math32.Pow(pc.Mult, float32(scale))
+	return 2 * int(math32.Round(0.5*sz))
+}
+
+// Border returns the border (padding) needed on the input image,
+// for the largest filter size.
+func (pc *Filter) Border() int {
+	sz := pc.Size(pc.NScales - 1)
+	return sz - vfilter.LeftHalf(sz)
+}
+
+// Config renders the filters and configures the Geoms -- must be
+// called after any changes to the parameters.
+func (pc *Filter) Config() {
+	ns := pc.NScales
+	pc.Geoms = make([]vfilter.Geom, ns)
+	pc.EvenTsrs = make([]tensor.Float32, ns)
+	pc.OddTsrs = make([]tensor.Float32, ns)
+	pc.evenOut = make([]tensor.Float32, ns)
+	pc.oddOut = make([]tensor.Float32, ns)
+	brd := pc.Border()
+	var gf gabor.Filter
+	gf.Defaults()
+	gf.NAngles = pc.NAngles
+	gf.SigLen = pc.SigLen
+	gf.SigWd = pc.SigWd
+	for s := 0; s < ns; s++ {
+		sz := pc.Size(s)
+		gf.SetSize(sz, pc.Spacing)
+		gf.Phase = 90
+		gf.ToTensor(&pc.EvenTsrs[s])
+		gf.Phase = 0
+		gf.ToTensor(&pc.OddTsrs[s])
+		pc.Geoms[s].Set(image.Point{brd, brd}, image.Point{pc.Spacing, pc.Spacing}, image.Point{sz, sz})
+	}
+}
+
+// Filter computes phase congruency on given grey-scale 2D [Y, X] image
+// tensor, which must have a border (padding) of at least Border() pixels,
+// into PCOut and EdgeCornerOut.
+func (pc *Filter) Filter(img *tensor.Float32) {
+	if len(pc.Geoms) != pc.NScales {
+		pc.Config()
+	}
+	for s := 0; s < pc.NScales; s++ {
+		vfilter.Conv(&pc.Geoms[s], &pc.EvenTsrs[s], img, &pc.evenOut[s], 1)
+		vfilter.Conv(&pc.Geoms[s], &pc.OddTsrs[s], img, &pc.oddOut[s], 1)
+	}
+	ny := pc.Geoms[0].Out.Y
+	nx := pc.Geoms[0].Out.X
+	pc.PCOut.SetShapeSizes(ny, nx, 1, pc.NAngles)
+	pc.EdgeCornerOut.SetShapeSizes(2, ny, nx)
+
+	thr := make([]float32, pc.NAngles)
+	for ang := range thr {
+		thr[ang] = pc.noiseThr(ang)
+	}
+
+	nproc.Run("PhaseCong", ny, func(st, n int) {
+		pc.filterThr(st, n, thr)
+	})
+}
+
+// response returns the signed even and odd filter responses
+// at given scale, output index (y * nx + x) and angle
+func (pc *Filter) response(s, i, ang int) (e, o float32) {
+	nf := 2 * pc.NAngles
+	ev := pc.evenOut[s].Values[i*nf:]
+	ov := pc.oddOut[s].Values[i*nf:]
+	e = ev[ang] - ev[pc.NAngles+ang]
+	o = ov[ang] - ov[pc.NAngles+ang]
+	return
+}
+
+// noiseThr returns the noise energy threshold for given angle,
+// estimated from the median amplitude at the smallest scale,
+// assuming Rayleigh distributed noise, as in Kovesi's method.
+func (pc *Filter) noiseThr(ang int) float32 {
+	n := pc.Geoms[0].Out.Y * pc.Geoms[0].Out.X
+	amps := make([]float32, n)
+	for i := range amps {
+		e, o := pc.response(0, i, ang)
+		amps[i] = math32.Sqrt(e*e + o*o)
+	}
+	slices.Sort(amps)
+	tau := amps[n/2] / math32.Sqrt(math32.Log(4))
+	invMult := 1 / pc.Mult
+	totTau := tau * (1 - math32.Pow(invMult, float32(pc.NScales))) / (1 - invMult)
+	mean := totTau * math32.Sqrt(math32.Pi/2)
+	sigma := totTau * math32.Sqrt((4-math32.Pi)/2)
+	return mean + pc.K*sigma
+}
+
+// filterThr is per-thread implementation
+func (pc *Filter) filterThr(yst, ny int, thr []float32) {
+	nx := pc.Geoms[0].Out.X
+	nang := pc.NAngles
+	ns := pc.NScales
+	oy := pc.Geoms[0].Out.Y
+	angInc := math32.Pi / float32(nang)
+	es := make([]float32, ns)
+	os := make([]float32, ns)
+	for y := yst; y < yst+ny; y++ {
+		for x := 0; x < nx; x++ {
+			i := y*nx + x
+			var covx, covy, covxy float32
+			for ang := 0; ang < nang; ang++ {
+				var sumE, sumO, sumAn, maxAn float32
+				for s := 0; s < ns; s++ {
+					e, o := pc.response(s, i, ang)
+					es[s], os[s] = e, o
+					an := math32.Sqrt(e*e + o*o)
+					sumE += e
+					sumO += o
+					sumAn += an
+					maxAn = math32.Max(maxAn, an)
+				}
+				xe := math32.Sqrt(sumE*sumE+sumO*sumO) + pc.Eps
+				meanE := sumE / xe
+				meanO := sumO / xe
+				var energy float32
+				for s := 0; s < ns; s++ {
+					e, o := es[s], os[s]
+					energy += e*meanE + o*meanO - math32.Abs(e*meanO-o*meanE)
+				}
+				var wt float32 = 1
+				if ns > 1 {
+					width := (sumAn/(maxAn+pc.Eps) - 1) / float32(ns-1)
+					wt = 1 / (1 + math32.Exp((pc.CutOff-width)*pc.G))
+				}
+				pcv := wt * math32.Max(energy-thr[ang], 0) / (sumAn + pc.Eps)
+				pc.PCOut.Values[i*nang+ang] = pcv
+				angf := float32(ang) * angInc
+				pcx := pcv * math32.Cos(angf)
+				pcy := pcv * math32.Sin(angf)
+				covx += pcx * pcx
+				covy += pcy * pcy
+				covxy += pcx * pcy
+			}
+			covx /= 0.5 * float32(nang)
+			covy /= 0.5 * float32(nang)
+			covxy *= 4 / float32(nang)
+			denom := math32.Sqrt(covxy*covxy + (covx-covy)*(covx-covy))
+			pc.EdgeCornerOut.Values[Edge*oy*nx+i] = 0.5 * (covy + covx + denom)
+			pc.EdgeCornerOut.Values[Corner*oy*nx+i] = math32.Max(0.5*(covy+covx-denom), 0)
+		}
+	}
+}
+
+// V1AllRows adds the phase congruency outputs as NRows rows into given
+// V1All-style [Y, X, Rows, Angle] output tensor, starting at rowStart:
+// phase congruency per angle, then edge and corner strength
+// (replicated across angles).  The output must already be allocated
+// with sufficient rows, and the same Y, X size as the outputs,
+// otherwise an error is returned.
+func (pc *Filter) V1AllRows(out *tensor.Float32, rowStart int) error {
+	if err := vfilter.FeatAgg([]int{0}, rowStart, &pc.PCOut, out); err != nil {
+		return err
+	}
+	nang := out.DimSize(3)
+	for ang := 0; ang < nang; ang++ {
+		if err := vfilter.OuterAgg(ang, rowStart+1, &pc.EdgeCornerOut, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
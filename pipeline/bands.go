@@ -0,0 +1,59 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// SetBands sets the input to given multispectral or hyperspectral image
+// tensor, with band as the outer-most dimension: [Band, Y, X], unpadded,
+// as produced by vfilter.BandsToTensor with no padding, converting it to
+// the padded Img tensor, followed by the retina and LGN stages, which use
+// the Spectral projection of the bands to the LMS cone responses, so the
+// bands go through the same V1 filtering as RGB images.  The image must
+// already be ImgSize, if set, and have the number of bands of Spectral.
+func (pl *Pipeline) SetBands(bands *tensor.Float32) error {
+	if bands.NumDims() != 3 {
+		return fmt.Errorf("pipeline.SetBands: tensor must have shape [Band, Y, X], not %v", bands.ShapeSizes())
+	}
+	nb := bands.DimSize(0)
+	sy := bands.DimSize(1)
+	sx := bands.DimSize(2)
+	if nb != pl.Spectral.NBands() {
+		return fmt.Errorf("pipeline.SetBands: tensor has %d bands, Spectral projection has %d", nb, pl.Spectral.NBands())
+	}
+	if pl.ImgSize.X > 0 && pl.ImgSize.Y > 0 && (sx != pl.ImgSize.X || sy != pl.ImgSize.Y) {
+		return fmt.Errorf("pipeline.SetBands: tensor size %d x %d is not ImgSize %v", sx, sy, pl.ImgSize)
+	}
+	brd := pl.Border()
+	pl.Img.SetShapeSizes(nb, sy+2*brd, sx+2*brd)
+	py := sy + 2*brd
+	px := sx + 2*brd
+	for b := range nb {
+		for y := range sy {
+			si := (b*sy + y) * sx
+			copy(pl.Img.Values[(b*py+y+brd)*px+brd:], bands.Values[si:si+sx])
+		}
+	}
+	vfilter.Pad(&pl.Img, brd, pl.Pad)
+	pl.bands = true
+	pl.LGN()
+	return nil
+}
+
+// FilterBands runs the full pipeline on given multispectral or
+// hyperspectral image tensor, as in SetBands, computing the V1All
+// outputs for each scale in Scales.
+func (pl *Pipeline) FilterBands(bands *tensor.Float32) error {
+	if err := pl.SetBands(bands); err != nil {
+		return err
+	}
+	pl.Filter()
+	return nil
+}
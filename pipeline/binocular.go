@@ -0,0 +1,107 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"image"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/retina"
+)
+
+// Eyes are the two eyes for binocular filtering
+type Eyes int32 //enums:enum
+
+const (
+	// LeftEye is the left eye
+	LeftEye Eyes = iota
+
+	// RightEye is the right eye
+	RightEye
+)
+
+// FilterImages runs the pipeline in binocular mode on given left and
+// right eye images, filtering each with the same gabor banks and
+// parameters, into the EyeV1All outputs of each scale, which are then
+// interleaved into the Binoc output, as input for disparity and
+// rivalry models.  The pupil responds consensually to the mean
+// value of both images, applying the same gain to each eye.
+// The kWTA settling and any Motion rows for each eye use that eye's
+// own state from the previous call, as for successive frames.
+func (pl *Pipeline) FilterImages(left, right image.Image) {
+	brd := pl.Border()
+	var mean float32
+	for eye, img := range []image.Image{left, right} {
+		pl.imageToTensor(img, &pl.EyeImgs[eye])
+		mean += retina.MeanValue(&pl.EyeImgs[eye], brd)
+	}
+	gain := pl.Pupil.Step(0.5 * mean)
+	for eye := range EyesN {
+		ei := &pl.EyeImgs[eye]
+		if gain != 1 {
+			for i, v := range ei.Values {
+				ei.Values[i] = v * gain
+			}
+		}
+		pl.Range.Normalize(ei, brd)
+		pl.Contrast.Normalize(ei, brd)
+		tensor.SetShapeFrom(&pl.Img, ei)
+		pl.Img.CopyFrom(ei)
+		pl.bands = false
+		pl.opponents()
+		for si := range pl.Scales {
+			sc := &pl.Scales[si]
+			sc.Simple, sc.eyeSimple[eye] = sc.eyeSimple[eye], sc.Simple
+			sc.motion, sc.eyeMotion[eye] = sc.eyeMotion[eye], sc.motion
+		}
+		pl.Filter()
+		for si := range pl.Scales {
+			sc := &pl.Scales[si]
+			sc.Simple, sc.eyeSimple[eye] = sc.eyeSimple[eye], sc.Simple
+			sc.motion, sc.eyeMotion[eye] = sc.eyeMotion[eye], sc.motion
+			ev := &sc.EyeV1All[eye]
+			tensor.SetShapeFrom(ev, &sc.V1All)
+			ev.CopyFrom(&sc.V1All)
+		}
+	}
+	for si := range pl.Scales {
+		pl.Binocular(&pl.Scales[si])
+	}
+}
+
+// Binocular interleaves the EyeV1All outputs of given scale into its
+// Binoc tensor, with eye as the inner-most part of the row (feature)
+// dimension, so that the two eyes' versions of each feature are
+// adjacent, in ocular dominance organization: [Y, X, Row * Eye, Angle].
+func (pl *Pipeline) Binocular(sc *Scale) {
+	lv := &sc.EyeV1All[LeftEye]
+	ny := lv.DimSize(0)
+	nx := lv.DimSize(1)
+	nrow := lv.DimSize(2)
+	nang := lv.DimSize(3)
+	sc.Binoc.SetShapeSizes(ny, nx, nrow*int(EyesN), nang)
+	for y := range ny {
+		for x := range nx {
+			for r := range nrow {
+				for eye := range EyesN {
+					ev := &sc.EyeV1All[eye]
+					for a := range nang {
+						sc.Binoc.Set(ev.Value(y, x, r, a), y, x, r*int(EyesN)+int(eye), a)
+					}
+				}
+			}
+		}
+	}
+}
+
+// BinocRowNames returns the names of the rows in the Binoc outputs,
+// with the eye (L or R) appended to each of the RowNames.
+func (pl *Pipeline) BinocRowNames() []string {
+	var rows []string
+	for _, rn := range pl.RowNames() {
+		rows = append(rows, rn+"L", rn+"R")
+	}
+	return rows
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package pipeline provides a complete, pre-wired visual front end:
+retina (pupil gain) and LGN (color opponent conversion), then at each
+of one or more scales, V1 simple-cell gabor filtering with neighborhood
+inhibition and kWTA, V1 complex-cell length-sum and end-stop filtering,
+and aggregation into a V1All tensor, as used for input to a network.
+
+Named Presets configure the Pipeline to match the standard models,
+so new projects can get a validated front end in a few lines:
+
+	var pl pipeline.Pipeline
+	pl.SetPreset(pipeline.LVis)
+	pl.FilterImage(img)
+	// V1All features in pl.Scales[0].V1All, pl.Scales[1].V1All
+
+The Thermal preset is for thermal / infrared imagery: the raw
+single-channel values are mapped to 0-1 between low and high percentiles
+by Range (with optional polarity inversion), and used directly as the
+grey image, instead of going through the sRGB conversion.
+
+Save and Open save and restore the entire configured pipeline,
+including the rendered filters and geometry, so that an experiment's
+front end can be reconstructed exactly.
+
+Export saves the V1All output of a scale along with a JSON metadata
+sidecar describing the dimensions, row names, filter and gain parameters,
+and package version, and OpenExport reads it back, validating the tensor
+against its sidecar, for sharing filtered datasets.
+
+ExportNumPy runs the pipeline over a labeled dataset.Dataset, writing
+the stacked V1All outputs and metadata to X.npz and the class labels to
+y.npy, for direct use from NumPy-based tools.
+
+FilterBands runs the pipeline on a multispectral or hyperspectral
+[Band, Y, X] tensor, projected to the LMS cone responses by Spectral,
+so satellite or hyperspectral data goes through the same V1 filtering.
+
+FilterImages runs the pipeline in binocular mode on left and right eye
+images, filtered with the same gabor banks, and interleaves the two
+eyes' V1All outputs into the Binoc tensor of each scale, with eye as
+part of the feature dimension in ocular dominance organization, as
+input for disparity and rivalry models.
+
+Motion optionally adds 2 transient / motion rows at the end of V1All
+(see RowNames), for temporal models: FrameDiff for the rectified
+increase and decrease of the pooled simple-cell responses relative to
+the previous frame, or MotionEnergy for opponent motion energy in the
+two directions orthogonal to each angle.  Call InitMotion at the start
+of each new sequence of frames.
+
+FilterTTA runs the pipeline over a set of vxform transforms of the same
+image (test-time augmentation), and aggregates the V1All outputs of
+each scale into its TTA tensor, by max or mean, optionally aligning
+each output to the original image by the inverse of its transform,
+producing more stable features for evaluation.
+
+FilterPanorama filters panoramic images much wider than ImgSize in
+overlapping tiles, filling each tile's padding and margins from the
+neighboring panorama content (wrapped in azimuth if Panorama.Wrap), and
+stitches the tile centers into the Pano tensor of each scale, without
+seams between tiles.
+*/
+package pipeline
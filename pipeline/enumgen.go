@@ -0,0 +1,171 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package pipeline
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _EyesValues = []Eyes{0, 1}
+
+// EyesN is the highest valid value for type Eyes, plus one.
+const EyesN Eyes = 2
+
+var _EyesValueMap = map[string]Eyes{`LeftEye`: 0, `RightEye`: 1}
+
+var _EyesDescMap = map[Eyes]string{0: `LeftEye is the left eye`, 1: `RightEye is the right eye`}
+
+var _EyesMap = map[Eyes]string{0: `LeftEye`, 1: `RightEye`}
+
+// String returns the string representation of this Eyes value.
+func (i Eyes) String() string { return enums.String(i, _EyesMap) }
+
+// SetString sets the Eyes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Eyes) SetString(s string) error { return enums.SetString(i, s, _EyesValueMap, "Eyes") }
+
+// Int64 returns the Eyes value as an int64.
+func (i Eyes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Eyes value from an int64.
+func (i *Eyes) SetInt64(in int64) { *i = Eyes(in) }
+
+// Desc returns the description of the Eyes value.
+func (i Eyes) Desc() string { return enums.Desc(i, _EyesDescMap) }
+
+// EyesValues returns all possible values for the type Eyes.
+func EyesValues() []Eyes { return _EyesValues }
+
+// Values returns all possible values for the type Eyes.
+func (i Eyes) Values() []enums.Enum { return enums.Values(_EyesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Eyes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Eyes) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Eyes") }
+
+var _MotionRowsValues = []MotionRows{0, 1, 2}
+
+// MotionRowsN is the highest valid value for type MotionRows, plus one.
+const MotionRowsN MotionRows = 3
+
+var _MotionRowsValueMap = map[string]MotionRows{`NoMotion`: 0, `FrameDiff`: 1, `MotionEnergy`: 2}
+
+var _MotionRowsDescMap = map[MotionRows]string{0: `NoMotion includes no motion rows`, 1: `FrameDiff includes 2 transient rows: the rectified increase (On) and decrease (Off) of the pooled simple-cell responses (max over polarities) relative to the previous frame, for each angle.`, 2: `MotionEnergy includes 2 rows of opponent motion energy, computed by motion.Energy on the grey image, for the two directions orthogonal to each angle: Pos is the direction 90 degrees counter-clockwise from the edge direction of the angle on the screen (i.e., upward for horizontal edges), and Neg is the opposite direction. The max over speed bands is used.`}
+
+var _MotionRowsMap = map[MotionRows]string{0: `NoMotion`, 1: `FrameDiff`, 2: `MotionEnergy`}
+
+// String returns the string representation of this MotionRows value.
+func (i MotionRows) String() string { return enums.String(i, _MotionRowsMap) }
+
+// SetString sets the MotionRows value from its string representation,
+// and returns an error if the string is invalid.
+func (i *MotionRows) SetString(s string) error {
+	return enums.SetString(i, s, _MotionRowsValueMap, "MotionRows")
+}
+
+// Int64 returns the MotionRows value as an int64.
+func (i MotionRows) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the MotionRows value from an int64.
+func (i *MotionRows) SetInt64(in int64) { *i = MotionRows(in) }
+
+// Desc returns the description of the MotionRows value.
+func (i MotionRows) Desc() string { return enums.Desc(i, _MotionRowsDescMap) }
+
+// MotionRowsValues returns all possible values for the type MotionRows.
+func MotionRowsValues() []MotionRows { return _MotionRowsValues }
+
+// Values returns all possible values for the type MotionRows.
+func (i MotionRows) Values() []enums.Enum { return enums.Values(_MotionRowsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i MotionRows) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *MotionRows) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "MotionRows")
+}
+
+var _PresetsValues = []Presets{0, 1, 2, 3}
+
+// PresetsN is the highest valid value for type Presets, plus one.
+const PresetsN Presets = 4
+
+var _PresetsValueMap = map[string]Presets{`V1Gabor`: 0, `ColorGabor`: 1, `LVis`: 2, `Thermal`: 3}
+
+var _PresetsDescMap = map[Presets]string{0: `V1Gabor is a single-scale grey-scale pipeline, with 12 pixel gabors at a spacing of 4, as in the v1gabor example.`, 1: `ColorGabor is a single-scale color pipeline, with 12 pixel gabors at a spacing of 4, applied to the white-black, red-green and blue-yellow opponent channels, with separate pooled simple-cell rows for each color, as in the color_gabor example.`, 2: `LVis is the standard LVis front end: the ColorGabor pipeline at two scales: high resolution (12 pixel gabors at a spacing of 4) and low resolution (24 pixel gabors at a spacing of 8).`, 3: `Thermal is a single-scale grey-scale pipeline for thermal / infrared imagery, with 12 pixel gabors at a spacing of 4 as in V1Gabor, using percentile-based range normalization (Range) of the raw single-channel values directly, instead of the sRGB conversion.`}
+
+var _PresetsMap = map[Presets]string{0: `V1Gabor`, 1: `ColorGabor`, 2: `LVis`, 3: `Thermal`}
+
+// String returns the string representation of this Presets value.
+func (i Presets) String() string { return enums.String(i, _PresetsMap) }
+
+// SetString sets the Presets value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Presets) SetString(s string) error {
+	return enums.SetString(i, s, _PresetsValueMap, "Presets")
+}
+
+// Int64 returns the Presets value as an int64.
+func (i Presets) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Presets value from an int64.
+func (i *Presets) SetInt64(in int64) { *i = Presets(in) }
+
+// Desc returns the description of the Presets value.
+func (i Presets) Desc() string { return enums.Desc(i, _PresetsDescMap) }
+
+// PresetsValues returns all possible values for the type Presets.
+func PresetsValues() []Presets { return _PresetsValues }
+
+// Values returns all possible values for the type Presets.
+func (i Presets) Values() []enums.Enum { return enums.Values(_PresetsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Presets) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Presets) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Presets") }
+
+var _TTAAggsValues = []TTAAggs{0, 1}
+
+// TTAAggsN is the highest valid value for type TTAAggs, plus one.
+const TTAAggsN TTAAggs = 2
+
+var _TTAAggsValueMap = map[string]TTAAggs{`TTAMax`: 0, `TTAMean`: 1}
+
+var _TTAAggsDescMap = map[TTAAggs]string{0: `TTAMax takes the max over transforms`, 1: `TTAMean takes the mean over transforms`}
+
+var _TTAAggsMap = map[TTAAggs]string{0: `TTAMax`, 1: `TTAMean`}
+
+// String returns the string representation of this TTAAggs value.
+func (i TTAAggs) String() string { return enums.String(i, _TTAAggsMap) }
+
+// SetString sets the TTAAggs value from its string representation,
+// and returns an error if the string is invalid.
+func (i *TTAAggs) SetString(s string) error {
+	return enums.SetString(i, s, _TTAAggsValueMap, "TTAAggs")
+}
+
+// Int64 returns the TTAAggs value as an int64.
+func (i TTAAggs) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the TTAAggs value from an int64.
+func (i *TTAAggs) SetInt64(in int64) { *i = TTAAggs(in) }
+
+// Desc returns the description of the TTAAggs value.
+func (i TTAAggs) Desc() string { return enums.Desc(i, _TTAAggsDescMap) }
+
+// TTAAggsValues returns all possible values for the type TTAAggs.
+func TTAAggsValues() []TTAAggs { return _TTAAggsValues }
+
+// Values returns all possible values for the type TTAAggs.
+func (i TTAAggs) Values() []enums.Enum { return enums.Values(_TTAAggsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i TTAAggs) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *TTAAggs) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "TTAAggs") }
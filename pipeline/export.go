@@ -0,0 +1,197 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/kwta"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// MetaExt is the file extension added to an exported tensor file
+// name for its metadata sidecar file.
+const MetaExt = ".meta.json"
+
+// Dim describes one dimension of an exported tensor
+type Dim struct {
+
+	// name of the dimension
+	Name string
+
+	// size of the dimension
+	Size int
+}
+
+// Meta is the self-describing metadata for an exported V1All tensor,
+// which is saved as a JSON sidecar file, so that filtered datasets
+// can be shared and validated.
+type Meta struct {
+
+	// version of the vision packages that produced the tensor
+	Version string
+
+	// name and size of each dimension of the tensor, outer-most first
+	Dims []Dim
+
+	// names of the V1All rows (features)
+	Rows []string
+
+	// layout of the V1All rows from each of the feature sources
+	Layout []vfilter.LayoutEntry `json:",omitempty"`
+
+	// class label names, indexed by the labels, for bulk exports of a dataset
+	Classes []string `json:",omitempty"`
+
+	// the pipeline preset
+	Preset string
+
+	// name of the scale
+	Scale string
+
+	// V1 simple gabor filter parameters
+	Gabor gabor.Filter
+
+	// gabor filter geometry, including the Border padding
+	Geom vfilter.Geom
+
+	// whether color filtering was done
+	Color bool
+
+	// whether separate color rows were recorded
+	SepColor bool
+
+	// extra gain for color channels
+	ColorGain float32
+
+	// neighborhood inhibition parameters
+	NeighInhib kwta.NeighInhib
+
+	// kwta parameters
+	KWTA kwta.KWTA
+}
+
+// RowNames returns the names of the rows in the V1All outputs
+func (pl *Pipeline) RowNames() []string {
+	var sc Scale
+	pl.layout(&sc)
+	return sc.Layout.RowNames()
+}
+
+// Meta returns the metadata for the current V1All output of given scale
+func (pl *Pipeline) Meta(scale int) *Meta {
+	sc := &pl.Scales[scale]
+	md := &Meta{Version: vfilter.Version, Rows: pl.RowNames(), Preset: pl.Preset.String(), Scale: sc.Name, Gabor: sc.Gabor, Geom: sc.Geom, Color: pl.Color, SepColor: pl.SepColor, ColorGain: pl.ColorGain, NeighInhib: pl.NeighInhib, KWTA: pl.KWTA}
+	md.Layout = slices.Clone(sc.Layout.Entries)
+	names := []string{"Y", "X", "Row", "Angle"}
+	for i, sz := range sc.V1All.ShapeSizes() {
+		md.Dims = append(md.Dims, Dim{Name: names[i], Size: sz})
+	}
+	return md
+}
+
+// Export saves the current V1All output of given scale to given file
+// as tab-separated values (with Y as rows), along with its metadata
+// sidecar file, named filename + MetaExt.
+func (pl *Pipeline) Export(scale int, filename string) error {
+	if err := tensor.SaveCSV(&pl.Scales[scale].V1All, fsx.Filename(filename), tensor.Tab); err != nil {
+		return err
+	}
+	return pl.Meta(scale).Save(filename + MetaExt)
+}
+
+// Save saves the metadata to given JSON file
+func (md *Meta) Save(filename string) error {
+	b, err := json.MarshalIndent(md, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// Open opens the metadata from given JSON file
+func (md *Meta) Open(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	md.KWTA.Defaults() // for fields that are not saved
+	md.NeighInhib.Defaults()
+	return json.Unmarshal(b, md)
+}
+
+// Sizes returns the sizes of the dimensions
+func (md *Meta) Sizes() []int {
+	sz := make([]int, len(md.Dims))
+	for i, d := range md.Dims {
+		sz[i] = d.Size
+	}
+	return sz
+}
+
+// Validate returns an error if the shape of given tensor does not
+// match the dimensions in the metadata, or the number of rows does
+// not match the row names.
+func (md *Meta) Validate(tsr tensor.Tensor) error {
+	if sz := tsr.ShapeSizes(); !slices.Equal(sz, md.Sizes()) {
+		return fmt.Errorf("pipeline.Meta: tensor shape %v does not match metadata shape %v", sz, md.Sizes())
+	}
+	for _, d := range md.Dims {
+		if d.Name == "Row" && d.Size != len(md.Rows) {
+			return fmt.Errorf("pipeline.Meta: number of rows %d does not match number of row names %d", d.Size, len(md.Rows))
+		}
+	}
+	return nil
+}
+
+// OpenExport opens a tensor saved by Export into tsr, using its
+// metadata sidecar file to set the shape, and validating that the
+// file has exactly the number of values described by the metadata,
+// returning the metadata.
+func OpenExport(filename string, tsr *tensor.Float32) (*Meta, error) {
+	md := &Meta{}
+	if err := md.Open(filename + MetaExt); err != nil {
+		return nil, err
+	}
+	fp, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	cr := csv.NewReader(fp)
+	cr.Comma = tensor.Tab.Rune()
+	recs, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	tsr.SetShapeSizes(md.Sizes()...)
+	n := 0
+	for _, rec := range recs {
+		n += len(rec)
+	}
+	if n != tsr.Len() {
+		return nil, fmt.Errorf("pipeline.OpenExport: file %q has %d values, but metadata shape %v has %d", filename, n, md.Sizes(), tsr.Len())
+	}
+	idx := 0
+	for _, rec := range recs {
+		for _, s := range rec {
+			v, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				return nil, err
+			}
+			tsr.Values[idx] = float32(v)
+			idx++
+		}
+	}
+	return md, md.Validate(tsr)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"image"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/motion"
+)
+
+// MotionRows are the kinds of transient / motion rows that can be
+// included in the V1All outputs, for temporal models
+type MotionRows int32 //enums:enum
+
+const (
+	// NoMotion includes no motion rows
+	NoMotion MotionRows = iota
+
+	// FrameDiff includes 2 transient rows: the rectified increase (On) and
+	// decrease (Off) of the pooled simple-cell responses (max over
+	// polarities) relative to the previous frame, for each angle.
+	FrameDiff
+
+	// MotionEnergy includes 2 rows of opponent motion energy, computed by
+	// motion.Energy on the grey image, for the two directions orthogonal
+	// to each angle: Pos is the direction 90 degrees counter-clockwise
+	// from the edge direction of the angle on the screen (i.e., upward
+	// for horizontal edges), and Neg is the opposite direction.
+	// The max over speed bands is used.
+	MotionEnergy
+)
+
+// Motion has the parameters for the transient / motion rows of the
+// V1All outputs, which are computed from successive frames, using
+// state from the previous frame (see InitMotion).
+type Motion struct {
+
+	// which kind of motion rows to include in V1All, if any
+	Rows MotionRows
+
+	// gain multiplier on the motion row values
+	Gain float32 `default:"1"`
+
+	// number of speed bands of the motion energy, from fastest to slowest -- the max over speed bands is used
+	NSpeeds int `default:"2"`
+
+	// time constant in frames of the motion energy delay filter for the first (fastest) speed band
+	Tau float32 `default:"1"`
+
+	// multiplier on the time constant for each successive motion energy speed band
+	TauMult float32 `default:"2"`
+
+	// distance in pixels between points correlated for motion energy
+	Dist int `default:"2"`
+
+	// opponent motion, to remove the non-directional flicker and static contrast components of the motion energy
+	Opponent motion.Opponent `display:"inline"`
+}
+
+func (mt *Motion) Defaults() {
+	mt.Rows = NoMotion
+	mt.Gain = 1
+	mt.NSpeeds = 2
+	mt.Tau = 1
+	mt.TauMult = 2
+	mt.Dist = 2
+	mt.Opponent.Defaults()
+}
+
+func (mt *Motion) ShouldDisplay(field string) bool {
+	switch field {
+	case "Rows":
+		return true
+	case "Gain":
+		return mt.Rows != NoMotion
+	default:
+		return mt.Rows == MotionEnergy
+	}
+}
+
+// motionState is the per-scale state from previous frames used for
+// computing the motion rows
+type motionState struct {
+
+	// pooled simple-cell responses (max over polarities) of the previous frame, for FrameDiff
+	prev tensor.Float32
+
+	// motion energy filter with its delayed image state, for MotionEnergy
+	energy motion.Energy
+
+	// opponent motion energy output
+	opp tensor.Float32
+
+	// motion rows output: [Y, X, 2, Angle]
+	out tensor.Float32
+}
+
+// InitMotion initializes the motion state of each scale, so that the
+// next frame is treated as the first of a new sequence, producing no
+// motion signal.  Call at the start of each new sequence of frames.
+func (pl *Pipeline) InitMotion() {
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		sc.motion.prev.SetShapeSizes(0)
+		sc.motion.energy.Delayed.SetShapeSizes(0)
+		for eye := range EyesN {
+			ms := &sc.eyeMotion[eye]
+			ms.prev.SetShapeSizes(0)
+			ms.energy.Delayed.SetShapeSizes(0)
+		}
+	}
+}
+
+// configMotion configures the motion energy filter of given scale,
+// to produce outputs at the positions of the V1All outputs,
+// with directions orthogonal to each angle.
+func (pl *Pipeline) configMotion(sc *Scale, brd int) {
+	mt := &pl.Motion
+	spc := 2 * sc.Gabor.Spacing // V1All is 2x2 pooled
+	for _, ms := range []*motionState{&sc.motion, &sc.eyeMotion[LeftEye], &sc.eyeMotion[RightEye]} {
+		me := &ms.energy
+		me.Defaults()
+		me.NDirs = 2 * sc.Gabor.NAngles
+		me.NSpeeds = mt.NSpeeds
+		me.Tau = mt.Tau
+		me.TauMult = mt.TauMult
+		me.Dist = mt.Dist
+		me.Spacing = spc
+		me.Gain = 1
+		me.Update()
+		fsz := 2*me.Dist + 1
+		me.Geom.Set(image.Point{brd, brd}, image.Point{spc, spc}, image.Point{fsz, fsz})
+		me.Delayed.SetShapeSizes(0)
+	}
+}
+
+// MotionFilter computes the motion rows of given scale from the
+// current frame, with the same Y, X size as the pooled simple cells,
+// for the V1All output, updating the motion state for the next frame.
+func (pl *Pipeline) MotionFilter(sc *Scale) {
+	pt := &sc.PoolTsr
+	sc.motion.out.SetShapeSizes(pt.DimSize(0), pt.DimSize(1), 2, pt.DimSize(3))
+	switch pl.Motion.Rows {
+	case FrameDiff:
+		pl.frameDiff(sc)
+	case MotionEnergy:
+		pl.motionEnergy(sc)
+	}
+}
+
+// frameDiff computes the FrameDiff rows for given scale
+func (pl *Pipeline) frameDiff(sc *Scale) {
+	ms := &sc.motion
+	pt := &sc.PoolTsr
+	ny := pt.DimSize(0)
+	nx := pt.DimSize(1)
+	npol := pt.DimSize(2)
+	nang := pt.DimSize(3)
+	first := ms.prev.Len() != ny*nx*nang
+	ms.prev.SetShapeSizes(ny, nx, nang)
+	gain := pl.Motion.Gain
+	for y := range ny {
+		for x := range nx {
+			for a := range nang {
+				var v float32
+				for p := range npol {
+					v = max(v, pt.Value(y, x, p, a))
+				}
+				pv := v
+				if !first {
+					pv = ms.prev.Value(y, x, a)
+				}
+				ms.prev.Set(v, y, x, a)
+				ms.out.Set(gain*max(v-pv, 0), y, x, 0, a)
+				ms.out.Set(gain*max(pv-v, 0), y, x, 1, a)
+			}
+		}
+	}
+}
+
+// motionEnergy computes the MotionEnergy rows for given scale
+func (pl *Pipeline) motionEnergy(sc *Scale) {
+	ms := &sc.motion
+	me := &ms.energy
+	me.Filter(pl.GreyImage())
+	pl.Motion.Opponent.FilterDim(&me.Out, &ms.opp, 2)
+	ms.out.SetZeros()
+	ny := min(ms.opp.DimSize(0), ms.out.DimSize(0))
+	nx := min(ms.opp.DimSize(1), ms.out.DimSize(1))
+	nang := ms.out.DimSize(3)
+	ndir := me.NDirs
+	nspd := me.NSpeeds
+	gain := pl.Motion.Gain
+	for y := range ny {
+		for x := range nx {
+			for a := range nang {
+				// direction orthogonal to the edge direction of the angle,
+				// which is counter-clockwise on the screen, with Y down
+				pd := (a + 3*nang/2) % ndir
+				for r, d := range []int{pd, (pd + nang) % ndir} {
+					var v float32
+					for s := range nspd {
+						v = max(v, ms.opp.Value(y, x, d, s))
+					}
+					ms.out.Set(gain*v, y, x, r, a)
+				}
+			}
+		}
+	}
+}
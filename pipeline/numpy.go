@@ -0,0 +1,150 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/iox/imagex"
+	"github.com/emer/vision/v2/dataset"
+)
+
+// WriteNPY writes given data to given writer in the NumPy .npy format,
+// with given NumPy dtype descr (e.g., "<f4" for float32, "<i8" for
+// int64), and shape, with the data in row-major order, written
+// little-endian using encoding/binary, so it must be a fixed-size value
+// or slice of such values.
+func WriteNPY(w io.Writer, descr string, shape []int, data any) error {
+	if err := writeNPYHeader(w, descr, shape); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, data)
+}
+
+// writeNPYHeader writes the version 1.0 .npy header for given
+// dtype descr and shape, padded so that the data is 64-byte aligned.
+func writeNPYHeader(w io.Writer, descr string, shape []int) error {
+	dims := make([]string, len(shape))
+	for i, sz := range shape {
+		dims[i] = fmt.Sprint(sz)
+	}
+	shp := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shp += ","
+	}
+	hdr := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shp)
+	const pre = 10 // magic, version, header length
+	hdr += strings.Repeat(" ", 63-(pre+len(hdr))%64) + "\n"
+	b := make([]byte, pre, pre+len(hdr))
+	copy(b, "\x93NUMPY\x01\x00")
+	binary.LittleEndian.PutUint16(b[8:], uint16(len(hdr)))
+	b = append(b, hdr...)
+	_, err := w.Write(b)
+	return err
+}
+
+// writeNPYString writes given string as a 0-d NumPy unicode array,
+// which is read as a str by NumPy.
+func writeNPYString(w io.Writer, s string) error {
+	rs := []rune(s)
+	cs := make([]uint32, len(rs))
+	for i, r := range rs {
+		cs[i] = uint32(r)
+	}
+	return WriteNPY(w, fmt.Sprintf("<U%d", len(cs)), nil, cs)
+}
+
+// ExportNumPy runs the pipeline over all of the images in given dataset,
+// writing the stacked V1All outputs of given scale as X.npy within
+// X.npz in given directory, with shape [Sample, Y, X, Row, Angle],
+// along with a meta entry holding the JSON Meta, including the Classes
+// of the dataset, and the class indexes as int64 labels in y.npy,
+// so that they can be used directly from NumPy:
+//
+//	z = np.load("X.npz"); X = z["X"]; meta = json.loads(str(z["meta"]))
+//	y = np.load("y.npy")
+func (pl *Pipeline) ExportNumPy(ds *dataset.Dataset, scale int, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ns := len(ds.Samples)
+	labels := make([]int64, ns)
+	for i := range ds.Samples {
+		labels[i] = int64(ds.Samples[i].Class)
+	}
+	yf, err := os.Create(filepath.Join(dir, "y.npy"))
+	if err != nil {
+		return err
+	}
+	err = WriteNPY(yf, "<i8", []int{ns}, labels)
+	if cerr := yf.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	xf, err := os.Create(filepath.Join(dir, "X.npz"))
+	if err != nil {
+		return err
+	}
+	defer xf.Close()
+	zw := zip.NewWriter(xf)
+	xw, err := zw.Create("X.npy")
+	if err != nil {
+		return err
+	}
+	v1 := &pl.Scales[scale].V1All
+	nv := 0
+	for i := range ds.Samples {
+		sm := &ds.Samples[i]
+		img, _, err := imagex.Open(sm.File)
+		if err != nil {
+			return err
+		}
+		pl.FilterImage(img)
+		if i == 0 {
+			nv = len(v1.Values)
+			if err := writeNPYHeader(xw, "<f4", append([]int{ns}, v1.ShapeSizes()...)); err != nil {
+				return err
+			}
+		} else if len(v1.Values) != nv {
+			return fmt.Errorf("pipeline.ExportNumPy: image %q has a different V1All size -- ImgSize must be set", sm.File)
+		}
+		if err := binary.Write(xw, binary.LittleEndian, v1.Values); err != nil {
+			return err
+		}
+	}
+	if ns == 0 {
+		if err := writeNPYHeader(xw, "<f4", []int{0}); err != nil {
+			return err
+		}
+	}
+	md := pl.Meta(scale)
+	md.Dims = append([]Dim{{Name: "Sample", Size: ns}}, md.Dims...)
+	md.Classes = ds.Classes
+	b, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	mw, err := zw.Create("meta.npy")
+	if err != nil {
+		return err
+	}
+	if err := writeNPYString(mw, string(b)); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return xf.Close()
+}
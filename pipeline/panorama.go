@@ -0,0 +1,139 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Panorama has the parameters for filtering panoramic images that are
+// much wider than ImgSize, in overlapping tiles of ImgSize, which are
+// stitched into one seamless V1All-style output for each scale.
+type Panorama struct {
+
+	// whether the panorama wraps around in azimuth (i.e., a 360 degree panorama), so that the left and right edges are continuous -- otherwise the edges are extended, or reflected if Pad is Reflect
+	Wrap bool `default:"true"`
+
+	// margin on the left and right of each tile that overlaps with the neighboring tiles, and is discarded in stitching, in V1All units of the coarsest scale -- must cover the spatial extent of the complex cell and neighbor inhibition interactions
+	Margin int `default:"2"`
+}
+
+func (pn *Panorama) Defaults() {
+	pn.Wrap = true
+	pn.Margin = 2
+}
+
+// FilterPanorama runs the pipeline on given panoramic image, which is
+// resized to the ImgSize height, preserving its aspect ratio, with its
+// width rounded to a whole number of V1All units of the coarsest scale.
+// The image is filtered in overlapping tiles of ImgSize, spaced by the
+// tile width minus two Panorama margins, and the outputs of the center
+// of each tile, excluding the margins, are stitched into the Pano output
+// of each scale, with shape [Y, X, Row, Angle] over the entire panorama.
+// The padding and margins of each tile are filled with the actual
+// neighboring image content (wrapped around in azimuth if Wrap), instead
+// of the wrap-around of the tile itself, so that there are no seams
+// between tiles.  The retina stages (Pupil, Range, Contrast) are applied
+// once to the entire panorama.  The layer-level kWTA inhibition is still
+// computed for each tile, so the activity levels can differ slightly
+// between tiles with very different content.
+func (pl *Pipeline) FilterPanorama(img image.Image) error {
+	if pl.ImgSize.X <= 0 || pl.ImgSize.Y <= 0 || len(pl.Scales) == 0 {
+		return fmt.Errorf("pipeline.FilterPanorama: ImgSize and Scales must be configured")
+	}
+	unit := 0 // pixels per V1All unit of the coarsest scale
+	for si := range pl.Scales {
+		unit = max(unit, 2*pl.Scales[si].Gabor.Spacing)
+	}
+	tw := pl.ImgSize.X
+	th := pl.ImgSize.Y
+	mrg := pl.Panorama.Margin * unit
+	stride := tw - 2*mrg
+	if tw%unit != 0 || stride <= 0 {
+		return fmt.Errorf("pipeline.FilterPanorama: ImgSize.X %d must be a multiple of %d, and greater than 2 * Margin (%d pixels)", tw, unit, mrg)
+	}
+	sz := img.Bounds().Size()
+	pw := int(math32.Round(float32(sz.X)*float32(th)/float32(sz.Y)/float32(unit))) * unit
+	pw = max(pw, unit)
+	brd := pl.Border()
+	pano := vfilter.Pool.Get(3, th+2*brd, pw+2*brd)
+	defer vfilter.Pool.Put(pano)
+	if sz.X != pw || sz.Y != th {
+		pl.resizeToTensor(img, image.Point{pw, th}, pano, brd)
+	} else {
+		vfilter.RGBToTensor(img, pano, brd, false)
+	}
+	vfilter.Pad(pano, brd, pl.Pad)
+	pl.Pupil.StepImage(pano, brd)
+	pl.Range.Normalize(pano, brd)
+	pl.Contrast.Normalize(pano, brd)
+
+	ntiles := (pw + stride - 1) / stride
+	py := th + 2*brd
+	ppx := pw + 2*brd
+	tx := tw + 2*brd
+	pl.Img.SetShapeSizes(3, py, tx)
+	pl.bands = false
+	for t := range ntiles {
+		x0 := t*stride - mrg - brd // panorama column of the left edge of the padded tile
+		for x := range tx {
+			sx := x0 + x
+			switch {
+			case pl.Panorama.Wrap:
+				sx = ((sx % pw) + pw) % pw
+			case pl.Pad == vfilter.Reflect:
+				sx = vfilter.ReflectIndex(sx, pw)
+			default:
+				sx = min(max(sx, 0), pw-1)
+			}
+			sx += brd
+			for c := range 3 {
+				for y := range py {
+					pl.Img.Values[(c*py+y)*tx+x] = pano.Values[(c*py+y)*ppx+sx]
+				}
+			}
+		}
+		pl.opponents()
+		pl.Filter()
+		for si := range pl.Scales {
+			sc := &pl.Scales[si]
+			pl.stitchPanorama(sc, t, stride, mrg, pw)
+		}
+	}
+	return nil
+}
+
+// stitchPanorama copies the center of the current V1All output of given
+// scale for given tile into its Pano output.
+func (pl *Pipeline) stitchPanorama(sc *Scale, tile, stride, mrg, pw int) {
+	v1 := &sc.V1All
+	ny := v1.DimSize(0)
+	nx := v1.DimSize(1)
+	nrow := v1.DimSize(2)
+	nang := v1.DimSize(3)
+	cpx := 2 * sc.Gabor.Spacing // pixels per V1All unit
+	pnx := pw / cpx
+	if tile == 0 {
+		sc.Pano.SetShapeSizes(ny, pnx, nrow, nang)
+	}
+	kst := mrg / cpx
+	kn := stride / cpx
+	px0 := tile * kn
+	nc := nrow * nang
+	for y := range ny {
+		for k := range kn {
+			x := kst + k
+			px := px0 + k
+			if x >= nx || px >= pnx {
+				break
+			}
+			copy(sc.Pano.Values[(y*pnx+px)*nc:(y*pnx+px+1)*nc], v1.Values[(y*nx+x)*nc:(y*nx+x+1)*nc])
+		}
+	}
+}
@@ -0,0 +1,543 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+//go:generate core generate -add-types
+
+import (
+	"context"
+	"image"
+	"log"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/colorspace"
+	"github.com/emer/vision/v2/dataset"
+	"github.com/emer/vision/v2/fffb"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/kwta"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/retina"
+	"github.com/emer/vision/v2/v1complex"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Presets are named configurations of the Pipeline
+type Presets int32 //enums:enum
+
+const (
+	// V1Gabor is a single-scale grey-scale pipeline, with 12 pixel
+	// gabors at a spacing of 4, as in the v1gabor example.
+	V1Gabor Presets = iota
+
+	// ColorGabor is a single-scale color pipeline, with 12 pixel gabors
+	// at a spacing of 4, applied to the white-black, red-green and
+	// blue-yellow opponent channels, with separate pooled simple-cell
+	// rows for each color, as in the color_gabor example.
+	ColorGabor
+
+	// LVis is the standard LVis front end: the ColorGabor pipeline
+	// at two scales: high resolution (12 pixel gabors at a spacing
+	// of 4) and low resolution (24 pixel gabors at a spacing of 8).
+	LVis
+
+	// Thermal is a single-scale grey-scale pipeline for thermal / infrared
+	// imagery, with 12 pixel gabors at a spacing of 4 as in V1Gabor,
+	// using percentile-based range normalization (Range) of the raw
+	// single-channel values directly, instead of the sRGB conversion.
+	Thermal
+)
+
+// Scale has the parameters and outputs for one scale of V1 filtering
+type Scale struct {
+
+	// name of the scale, e.g., for naming network input layers
+	Name string
+
+	// V1 simple gabor filter parameters
+	Gabor gabor.Filter
+
+	// geometry of input, output for V1 simple-cell processing
+	Geom vfilter.Geom `edit:"-"`
+
+	// V1 simple gabor filter tensor
+	GaborTsr tensor.Float32 `display:"no-inline"`
+
+	// V1 simple gabor filter kwta output, per opponent channel
+	Simple [colorspace.OpponentsN]tensor.Float32 `display:"no-inline"`
+
+	// max over opponent channels of the V1 simple kwta outputs
+	MaxTsr tensor.Float32 `display:"no-inline"`
+
+	// max-pooled 2x2 of MaxTsr
+	PoolTsr tensor.Float32 `display:"no-inline"`
+
+	// max-pooled 2x2 of the red-green and blue-yellow simple outputs, if SepColor
+	ColorPoolTsrs [2]tensor.Float32 `display:"no-inline"`
+
+	// max-pooled 2x2 of the angle-only features (max over polarities) of MaxTsr
+	AngPoolTsr tensor.Float32 `display:"no-inline"`
+
+	// V1 complex length sum output
+	LenSumTsr tensor.Float32 `display:"no-inline"`
+
+	// V1 complex end stop output
+	EndStopTsr tensor.Float32 `display:"no-inline"`
+
+	// combined V1 output: length sum, end stops (2), pooled simple cells (2), then red-green (2) and blue-yellow (2) pooled simple cells if SepColor, then transient / motion rows (2) if Motion.Rows is set -- see Layout
+	V1All tensor.Float32 `display:"no-inline"`
+
+	// layout of the rows of V1All from each of the feature sources
+	Layout vfilter.Layout `display:"no-inline"`
+
+	// V1All output for each eye, from FilterImages
+	EyeV1All [EyesN]tensor.Float32 `display:"no-inline"`
+
+	// binocular V1 output from FilterImages, with the V1All rows of the two eyes interleaved, in ocular dominance organization: [Y, X, Row * Eye, Angle]
+	Binoc tensor.Float32 `display:"no-inline"`
+
+	// V1All output stitched over the tiles of a panoramic image, from FilterPanorama: [Y, X, Row, Angle] over the entire panorama
+	Pano tensor.Float32 `display:"no-inline"`
+
+	// V1All output aggregated over the transforms of test-time augmentation, from FilterTTA
+	TTA tensor.Float32 `display:"no-inline"`
+
+	// V1 simple kwta outputs for each eye, swapped with Simple during FilterImages, so each eye's kwta settling starts from its own prior state
+	eyeSimple [EyesN][colorspace.OpponentsN]tensor.Float32
+
+	// state from previous frames for the motion rows
+	motion motionState
+
+	// motion state for each eye, swapped with motion during FilterImages
+	eyeMotion [EyesN]motionState
+
+	// number of transforms aggregated into each TTA position, for the mean
+	ttaN tensor.Float32
+}
+
+// Pipeline is a complete visual front end, from an image to V1All
+// features at each scale.  Use SetPreset to configure it, and then
+// modify any parameters, followed by Config.
+type Pipeline struct {
+
+	// preset that the pipeline was last configured with
+	Preset Presets `edit:"-"`
+
+	// target image size to use -- images will be rescaled to this size
+	ImgSize image.Point
+
+	// interpolation used to rescale images to ImgSize
+	Resize vfilter.ResizeModes
+
+	// if true, do full color filtering -- else Black/White only
+	Color bool
+
+	// record separate rows in V1All for each color -- otherwise just records the max across all colors
+	SepColor bool
+
+	// extra gain for color channels -- lower contrast in general
+	ColorGain float32 `default:"8"`
+
+	// how to fill the padding border of the input image -- Reflect avoids the spurious edges at the borders of natural images produced by the default Wrap
+	Pad vfilter.EdgeModes
+
+	// retinal pupil gain -- off by default, and only relevant for sequences of frames
+	Pupil retina.Pupil
+
+	// percentile-based range mapping of each image to 0-1, with optional polarity inversion, for thermal / infrared imagery, after the pupil gain -- when On and not Color, the grey image is the linear mean of the image channels, bypassing the sRGB and LMS conversion -- off by default except for the Thermal preset
+	Range retina.RangeNorm
+
+	// normalization of each image to a target mean luminance and RMS contrast, after the pupil gain and Range -- off by default
+	Contrast retina.ContrastNorm
+
+	// transient / motion rows included in the V1All outputs, computed from successive frames -- off by default
+	Motion Motion
+
+	// tiling of panoramic images, for FilterPanorama
+	Panorama Panorama
+
+	// neighborhood inhibition for V1s -- each unit gets inhibition from same feature in nearest orthogonal neighbors -- reduces redundancy of feature code
+	NeighInhib kwta.NeighInhib
+
+	// kwta parameters for V1s
+	KWTA kwta.KWTA
+
+	// parameters and outputs for each scale, from fine to coarse
+	Scales []Scale
+
+	// projection of multispectral / hyperspectral image bands to the LMS cone responses, for SetBands
+	Spectral colorspace.Spectral `display:"-"`
+
+	// input image as a padded RGB tensor, or padded [Band, Y, X] tensor from SetBands
+	Img tensor.Float32 `display:"no-inline"`
+
+	// LMS components + opponents tensor version of image, only if Color
+	LMS tensor.Float32 `display:"no-inline"`
+
+	// greyscale (LMS GREY component) version of image, computed directly when not Color
+	Grey tensor.Float32 `display:"no-inline"`
+
+	// input images for each eye as padded RGB tensors, from FilterImages
+	EyeImgs [EyesN]tensor.Float32 `display:"no-inline"`
+
+	// inhibition values for V1s KWTA
+	Inhibs fffb.Inhibs `display:"no-inline"`
+
+	// whether Img has the bands from SetBands, instead of RGB
+	bands bool
+}
+
+func (pl *Pipeline) Defaults() {
+	pl.SetPreset(LVis)
+}
+
+// SetPreset sets all of the parameters according to given preset,
+// and calls Config.
+func (pl *Pipeline) SetPreset(preset Presets) {
+	pl.Preset = preset
+	pl.ImgSize = image.Point{128, 128}
+	pl.Resize = vfilter.Bilinear
+	pl.ColorGain = 8
+	pl.Pad = vfilter.Wrap
+	pl.Pupil.Defaults()
+	pl.Pupil.On = false
+	pl.Range.Defaults()
+	pl.Contrast.Defaults()
+	pl.Motion.Defaults()
+	pl.Panorama.Defaults()
+	pl.NeighInhib.Defaults()
+	pl.KWTA.Defaults()
+	switch preset {
+	case V1Gabor:
+		pl.Color = false
+		pl.SepColor = false
+		pl.Scales = make([]Scale, 1)
+		pl.Scales[0].SetSize("V1", 12, 4)
+	case ColorGabor:
+		pl.Color = true
+		pl.SepColor = true
+		pl.Scales = make([]Scale, 1)
+		pl.Scales[0].SetSize("V1", 12, 4)
+	case LVis:
+		pl.Color = true
+		pl.SepColor = true
+		pl.Scales = make([]Scale, 2)
+		pl.Scales[0].SetSize("V1h", 12, 4)
+		pl.Scales[1].SetSize("V1l", 24, 8)
+	case Thermal:
+		pl.Color = false
+		pl.SepColor = false
+		pl.Range.On = true
+		pl.Scales = make([]Scale, 1)
+		pl.Scales[0].SetSize("V1", 12, 4)
+	}
+	pl.Config()
+}
+
+// SetSize sets the name and gabor filter size and spacing, with
+// default gabor parameters otherwise.
+func (sc *Scale) SetSize(name string, sz, spc int) {
+	sc.Name = name
+	sc.Gabor.Defaults()
+	sc.Gabor.SetSize(sz, spc)
+}
+
+// Border returns the border (padding) needed on the input image,
+// for the largest filter.
+func (pl *Pipeline) Border() int {
+	brd := 0
+	for si := range pl.Scales {
+		sz := pl.Scales[si].Gabor.Size
+		brd = max(brd, sz-vfilter.LeftHalf(sz))
+	}
+	return brd
+}
+
+// Config renders the filters and configures the Geom of each scale,
+// and its motion energy filter,
+// all with the same Border -- must be called after any changes to
+// the parameters.
+func (pl *Pipeline) Config() {
+	brd := pl.Border()
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		sz := sc.Gabor.Size
+		spc := sc.Gabor.Spacing
+		sc.Gabor.ToTensor(&sc.GaborTsr)
+		sc.Geom.Set(image.Point{brd, brd}, image.Point{spc, spc}, image.Point{sz, sz})
+		sc.Geom.Pad = pl.Pad
+		pl.configMotion(sc, brd)
+	}
+}
+
+// NRows returns the number of rows in the V1All outputs
+func (pl *Pipeline) NRows() int {
+	var sc Scale
+	pl.layout(&sc)
+	return sc.Layout.NRows()
+}
+
+// SetImage resizes given image to ImgSize if needed, and converts
+// it to the padded Img tensor, followed by the retina and LGN stages.
+func (pl *Pipeline) SetImage(img image.Image) {
+	pl.imageToTensor(img, &pl.Img)
+	pl.bands = false
+	pl.LGN()
+}
+
+// imageToTensor resizes given image to ImgSize if needed, and converts
+// it to the given padded RGB tensor.
+func (pl *Pipeline) imageToTensor(img image.Image, tsr *tensor.Float32) {
+	brd := pl.Border()
+	if pl.ImgSize.X > 0 && pl.ImgSize.Y > 0 && img.Bounds().Size() != pl.ImgSize {
+		pl.resizeToTensor(img, pl.ImgSize, tsr, brd)
+	} else {
+		vfilter.RGBToTensor(img, tsr, brd, false)
+	}
+	vfilter.Pad(tsr, brd, pl.Pad)
+}
+
+// resizeToTensor converts given image to an RGB tensor of given size,
+// resized with the Resize interpolation, with given padding width,
+// which is not filled.
+func (pl *Pipeline) resizeToTensor(img image.Image, size image.Point, tsr *tensor.Float32, padWidth int) {
+	sz := img.Bounds().Size()
+	raw := vfilter.Pool.Get(3, sz.Y, sz.X)
+	defer vfilter.Pool.Put(raw)
+	vfilter.RGBToTensor(img, raw, 0, false)
+	vfilter.ResizeTensor(raw, tsr, size, padWidth, pl.Resize) // shapes are always valid
+}
+
+// resizeImage returns given image resized to ImgSize, with the Resize
+// interpolation, for operations on the image itself.
+func (pl *Pipeline) resizeImage(img image.Image) image.Image {
+	rs := vfilter.Pool.Get(3, pl.ImgSize.Y, pl.ImgSize.X)
+	defer vfilter.Pool.Put(rs)
+	pl.resizeToTensor(img, pl.ImgSize, rs, 0)
+	for i, v := range rs.Values {
+		rs.Values[i] = math32.Clamp(v, 0, 1)
+	}
+	return vfilter.RGBTensorToImage(nil, rs, 0, false)
+}
+
+// LGN applies the retinal pupil gain and contrast normalization
+// to the Img tensor, and converts
+// it to the LMS color opponents if Color, or otherwise directly to
+// just the Grey component, which is much faster.
+func (pl *Pipeline) LGN() {
+	pl.Pupil.StepImage(&pl.Img, pl.Border())
+	pl.Range.Normalize(&pl.Img, pl.Border())
+	pl.Contrast.Normalize(&pl.Img, pl.Border())
+	pl.opponents()
+}
+
+// opponents converts the Img tensor to the LMS color opponents if Color,
+// or otherwise directly to just the Grey component.
+func (pl *Pipeline) opponents() {
+	if pl.Range.On && !pl.Color {
+		pl.linearGrey()
+		return
+	}
+	if pl.bands {
+		if pl.Color {
+			pl.Spectral.TensorToLMSComps(&pl.LMS, &pl.Img)
+		} else {
+			pl.Spectral.TensorToGrey(&pl.Grey, &pl.Img)
+		}
+		return
+	}
+	if pl.Color {
+		colorspace.RGBTensorToLMSComps(&pl.LMS, &pl.Img)
+	} else {
+		colorspace.RGBTensorToGrey(&pl.Grey, &pl.Img)
+	}
+}
+
+// linearGrey computes the Grey image as the mean of the Img channels,
+// without any sRGB or LMS conversion, for non-visible imagery.
+func (pl *Pipeline) linearGrey() {
+	nc := pl.Img.DimSize(0)
+	sy := pl.Img.DimSize(1)
+	sx := pl.Img.DimSize(2)
+	n := sy * sx
+	pl.Grey.SetShapeSizes(sy, sx)
+	for i := range n {
+		var v float32
+		for c := range nc {
+			v += pl.Img.Values[c*n+i]
+		}
+		pl.Grey.Values[i] = v / float32(nc)
+	}
+}
+
+// GreyImage returns the greyscale image used for the WhiteBlack
+// channel, from LMS if Color, or Grey otherwise.
+func (pl *Pipeline) GreyImage() *tensor.Float32 {
+	if pl.Color {
+		return colorspace.Component(&pl.LMS, colorspace.GREY)
+	}
+	return &pl.Grey
+}
+
+// FilterImage runs the full pipeline on given image, with the
+// V1All outputs for each scale in Scales.
+func (pl *Pipeline) FilterImage(img image.Image) {
+	pl.SetImage(img)
+	pl.Filter()
+}
+
+// Filter runs V1 filtering at each scale on the current LMS or Grey image.
+func (pl *Pipeline) Filter() {
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		pl.V1Simple(sc)
+		pl.V1Complex(sc)
+		pl.V1All(sc)
+	}
+}
+
+// FilterImageContext is FilterImage with cancellation and progress
+// reporting via ctx, as in FilterContext.
+func (pl *Pipeline) FilterImageContext(ctx context.Context, img image.Image) error {
+	pl.SetImage(img)
+	return pl.FilterContext(ctx)
+}
+
+// FilterContext is Filter with cancellation and progress reporting via
+// ctx, for GUI applications: the context is checked before each of the
+// V1Simple, V1Complex and V1All stages of each scale, returning
+// ctx.Err() if canceled, and the nproc.Progress function of ctx,
+// if any, is called after each stage, with 3 stages per scale.
+func (pl *Pipeline) FilterContext(ctx context.Context) error {
+	prog := nproc.Progress(ctx)
+	stages := []func(sc *Scale){pl.V1Simple, pl.V1Complex, pl.V1All}
+	total := len(stages) * len(pl.Scales)
+	done := 0
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		for _, stage := range stages {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			stage(sc)
+			done++
+			if prog != nil {
+				prog(done, total)
+			}
+		}
+	}
+	return nil
+}
+
+// FilterFunc returns a dataset.FilterFunc that runs the pipeline on
+// an RGB image tensor from a dataset.Loader with Color on and a
+// PadWidth of Border(), and returns the V1All output of given scale.
+func (pl *Pipeline) FilterFunc(scale int) dataset.FilterFunc {
+	return func(img, out *tensor.Float32) {
+		tensor.SetShapeFrom(&pl.Img, img)
+		pl.Img.CopyFrom(img)
+		pl.bands = false
+		pl.LGN()
+		pl.Filter()
+		v1 := &pl.Scales[scale].V1All
+		tensor.SetShapeFrom(out, v1)
+		out.CopyFrom(v1)
+	}
+}
+
+// V1SimpleImg runs V1 simple gabor filtering on given opponent
+// channel image, with neighborhood inhibition and kwta, into act.
+// The raw filter output and neighbor inhibition are acquired from
+// vfilter.Pool, with the same shape as act from the previous frame,
+// and released at the end.
+func (pl *Pipeline) V1SimpleImg(sc *Scale, img, act *tensor.Float32, gain float32) {
+	raw := vfilter.Pool.GetLike(act)
+	extGi := vfilter.Pool.GetLike(act)
+	defer vfilter.Pool.Put(raw, extGi)
+	vfilter.Conv(&sc.Geom, &sc.GaborTsr, img, raw, gain*sc.Gabor.Gain)
+	if pl.NeighInhib.On {
+		pl.NeighInhib.Inhib4(raw, extGi)
+	} else {
+		tensor.SetShapeFrom(extGi, raw)
+		extGi.SetZeros()
+	}
+	if pl.KWTA.On {
+		pl.KWTA.KWTAPool(raw, act, &pl.Inhibs, extGi)
+	} else {
+		tensor.SetShapeFrom(act, raw)
+		act.CopyFrom(raw)
+	}
+}
+
+// V1Simple runs V1 simple filtering for given scale, on the grey
+// channel and the color channels if Color, with MaxTsr the max
+// over channels.
+func (pl *Pipeline) V1Simple(sc *Scale) {
+	wb := &sc.Simple[colorspace.WhiteBlack]
+	pl.V1SimpleImg(sc, pl.GreyImage(), wb, 1)
+	tensor.SetShapeFrom(&sc.MaxTsr, wb)
+	sc.MaxTsr.CopyFrom(wb)
+	if !pl.Color {
+		return
+	}
+	rg := &sc.Simple[colorspace.RedGreen]
+	pl.V1SimpleImg(sc, colorspace.Component(&pl.LMS, colorspace.LvMC), rg, pl.ColorGain)
+	by := &sc.Simple[colorspace.BlueYellow]
+	pl.V1SimpleImg(sc, colorspace.Component(&pl.LMS, colorspace.SvLMC), by, pl.ColorGain)
+	for i, vl := range sc.MaxTsr.Values {
+		sc.MaxTsr.Values[i] = max(vl, rg.Values[i], by.Values[i])
+	}
+}
+
+// V1Complex runs V1 complex filters on top of the V1 simple features
+// for given scale, computing angle-only, max-pooled versions first.
+// The angle-only features are acquired from vfilter.Pool, and
+// released once pooled into AngPoolTsr.
+func (pl *Pipeline) V1Complex(sc *Scale) {
+	vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, &sc.MaxTsr, &sc.PoolTsr)
+	angOnly := vfilter.Pool.Get(sc.MaxTsr.DimSize(0), sc.MaxTsr.DimSize(1), 1, sc.MaxTsr.DimSize(3))
+	vfilter.MaxReduceFilterY(&sc.MaxTsr, angOnly)
+	vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, angOnly, &sc.AngPoolTsr)
+	vfilter.Pool.Put(angOnly)
+	v1complex.LenSum4(&sc.AngPoolTsr, &sc.LenSumTsr)
+	v1complex.EndStop4(&sc.AngPoolTsr, &sc.LenSumTsr, &sc.EndStopTsr)
+}
+
+// V1All aggregates all the simple and complex features for given
+// scale into its V1All tensor.
+func (pl *Pipeline) V1All(sc *Scale) {
+	if pl.Color && pl.SepColor {
+		for ci := range sc.ColorPoolTsrs {
+			vfilter.MaxPool(image.Point{2, 2}, image.Point{2, 2}, &sc.Simple[colorspace.RedGreen+colorspace.Opponents(ci)], &sc.ColorPoolTsrs[ci])
+		}
+	}
+	if pl.Motion.Rows != NoMotion {
+		pl.MotionFilter(sc)
+	}
+	pl.layout(sc)
+	if err := sc.Layout.Build(&sc.V1All); err != nil {
+		log.Println(err)
+	}
+}
+
+// layout registers the feature sources of the V1All output of given
+// scale in its Layout, in order, which determines the rows of V1All.
+func (pl *Pipeline) layout(sc *Scale) {
+	ly := &sc.Layout
+	ly.Reset()
+	ly.Add("LenSum", &sc.LenSumTsr, []int{0})
+	ly.Add("EndStop", &sc.EndStopTsr, []int{0, 1}, "On", "Off")
+	ly.Add("Simple", &sc.PoolTsr, []int{0, 1}, "On", "Off")
+	if pl.Color && pl.SepColor {
+		ly.Add("RedGreen", &sc.ColorPoolTsrs[0], []int{0, 1}, "On", "Off")
+		ly.Add("BlueYellow", &sc.ColorPoolTsrs[1], []int{0, 1}, "On", "Off")
+	}
+	switch pl.Motion.Rows {
+	case FrameDiff:
+		ly.Add("Trans", &sc.motion.out, []int{0, 1}, "On", "Off")
+	case MotionEnergy:
+		ly.Add("Motion", &sc.motion.out, []int{0, 1}, "Pos", "Neg")
+	}
+}
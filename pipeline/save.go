@@ -0,0 +1,138 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/colorspace"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/kwta"
+	"github.com/emer/vision/v2/retina"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// SaveVersion is the version of the file format written by Save
+const SaveVersion = 1
+
+// savedTensor is a tensor with its shape, for saving
+type savedTensor struct {
+	Shape  []int
+	Values []float32
+}
+
+func newSavedTensor(tsr *tensor.Float32) savedTensor {
+	return savedTensor{Shape: tsr.ShapeSizes(), Values: tsr.Values}
+}
+
+func (st *savedTensor) toTensor(tsr *tensor.Float32) {
+	tsr.SetShapeSizes(st.Shape...)
+	copy(tsr.Values, st.Values)
+}
+
+// savedScale is the saved state of one Scale
+type savedScale struct {
+	Name   string
+	Gabor  gabor.Filter
+	Geom   vfilter.Geom
+	Filter savedTensor
+}
+
+// saved is the saved state of the Pipeline
+type saved struct {
+	Version    int
+	Preset     Presets
+	ImgSize    image.Point
+	Resize     vfilter.ResizeModes
+	Color      bool
+	SepColor   bool
+	ColorGain  float32
+	Pad        vfilter.EdgeModes
+	Pupil      retina.Pupil
+	Range      retina.RangeNorm
+	Contrast   retina.ContrastNorm
+	Motion     Motion
+	Spectral   colorspace.Spectral
+	Panorama   Panorama
+	NeighInhib kwta.NeighInhib
+	KWTA       kwta.KWTA
+	Scales     []savedScale
+}
+
+// Save saves the entire configured pipeline to given JSON file: all
+// of the parameters, plus the rendered filter tensors and geometry of
+// each scale, so that it can be reconstructed exactly with Open,
+// independent of any later changes to the defaults or filter rendering.
+func (pl *Pipeline) Save(filename string) error {
+	sv := saved{Version: SaveVersion, Preset: pl.Preset, ImgSize: pl.ImgSize, Resize: pl.Resize, Color: pl.Color, SepColor: pl.SepColor, ColorGain: pl.ColorGain, Pad: pl.Pad, Pupil: pl.Pupil, Range: pl.Range, Contrast: pl.Contrast, Motion: pl.Motion, Spectral: pl.Spectral, Panorama: pl.Panorama, NeighInhib: pl.NeighInhib, KWTA: pl.KWTA}
+	sv.Scales = make([]savedScale, len(pl.Scales))
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		sv.Scales[si] = savedScale{Name: sc.Name, Gabor: sc.Gabor, Geom: sc.Geom, Filter: newSavedTensor(&sc.GaborTsr)}
+	}
+	b, err := json.MarshalIndent(&sv, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// Open opens a pipeline previously saved with Save, restoring all of
+// the parameters, and the saved filter tensors and geometry as-is,
+// without calling Config (which would re-render the filters).
+func (pl *Pipeline) Open(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var sv saved // defaults are needed for any fields that are not saved
+	sv.Resize = vfilter.Bilinear
+	sv.Pupil.Defaults()
+	sv.Range.Defaults()
+	sv.Contrast.Defaults()
+	sv.Motion.Defaults()
+	sv.Panorama.Defaults()
+	sv.NeighInhib.Defaults()
+	sv.KWTA.Defaults()
+	if err := json.Unmarshal(b, &sv); err != nil {
+		return err
+	}
+	if sv.Version > SaveVersion {
+		return fmt.Errorf("pipeline.Open: file %q has version %d, newer than supported version %d", filename, sv.Version, SaveVersion)
+	}
+	pl.Preset = sv.Preset
+	pl.ImgSize = sv.ImgSize
+	pl.Resize = sv.Resize
+	pl.Color = sv.Color
+	pl.SepColor = sv.SepColor
+	pl.ColorGain = sv.ColorGain
+	pl.Pad = sv.Pad
+	pl.Pupil = sv.Pupil
+	pl.Pupil.Update()
+	pl.Pupil.Init()
+	pl.Range = sv.Range
+	pl.Contrast = sv.Contrast
+	pl.Motion = sv.Motion
+	pl.Spectral = sv.Spectral
+	pl.Panorama = sv.Panorama
+	pl.NeighInhib = sv.NeighInhib
+	pl.KWTA = sv.KWTA
+	pl.KWTA.Update()
+	pl.Scales = make([]Scale, len(sv.Scales))
+	for si := range sv.Scales {
+		ss := &sv.Scales[si]
+		sc := &pl.Scales[si]
+		sc.Name = ss.Name
+		sc.Gabor = ss.Gabor
+		sc.Geom = ss.Geom
+		ss.Filter.toTensor(&sc.GaborTsr)
+		pl.configMotion(sc, sc.Geom.Border.X)
+	}
+	return nil
+}
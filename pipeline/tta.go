@@ -0,0 +1,162 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pipeline
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/vxform"
+)
+
+// TTAAggs are the ways of aggregating V1All outputs over the
+// transforms of test-time augmentation
+type TTAAggs int32 //enums:enum
+
+const (
+	// TTAMax takes the max over transforms
+	TTAMax TTAAggs = iota
+
+	// TTAMean takes the mean over transforms
+	TTAMean
+)
+
+// FilterTTA runs the pipeline on each of the given transforms of the
+// image (test-time augmentation), and aggregates the V1All outputs
+// of each scale over transforms into its TTA tensor, producing more
+// stable features for evaluation.  If align, each output is first
+// aligned to the untransformed image by the inverse of its transform:
+// each V1All position samples the nearest position of the transformed
+// output, excluding positions that fall outside of it, and the angles
+// are shifted by the nearest whole number of angle steps to the rotation,
+// so alignment is approximate for rotations that are not a multiple
+// of the angle step.  Otherwise, the outputs are aggregated position
+// by position, which is appropriate for small transforms.
+func (pl *Pipeline) FilterTTA(img image.Image, xfs []vxform.XForm, agg TTAAggs, align bool) {
+	if pl.ImgSize.X > 0 && pl.ImgSize.Y > 0 && img.Bounds().Size() != pl.ImgSize {
+		// resize first, so the transforms apply to the ImgSize image
+		img = pl.resizeImage(img)
+	}
+	for xi := range xfs {
+		xf := &xfs[xi]
+		pl.FilterImage(xf.Image(img))
+		for si := range pl.Scales {
+			sc := &pl.Scales[si]
+			if xi == 0 {
+				tensor.SetShapeFrom(&sc.TTA, &sc.V1All)
+				sc.TTA.SetZeros()
+				ny, nx := sc.V1All.DimSize(0), sc.V1All.DimSize(1)
+				sc.ttaN.SetShapeSizes(ny, nx)
+				sc.ttaN.SetZeros()
+			}
+			pl.ttaAdd(sc, xf, agg, align)
+		}
+	}
+	if agg != TTAMean {
+		return
+	}
+	for si := range pl.Scales {
+		sc := &pl.Scales[si]
+		nc := sc.TTA.Len() / max(sc.ttaN.Len(), 1)
+		for pi, n := range sc.ttaN.Values {
+			if n == 0 {
+				continue
+			}
+			vs := sc.TTA.Values[pi*nc : (pi+1)*nc]
+			for i := range vs {
+				vs[i] /= n
+			}
+		}
+	}
+}
+
+// ttaAdd aggregates the current V1All output of given scale,
+// for given transform, into its TTA tensor.
+func (pl *Pipeline) ttaAdd(sc *Scale, xf *vxform.XForm, agg TTAAggs, align bool) {
+	v1 := &sc.V1All
+	ny := v1.DimSize(0)
+	nx := v1.DimSize(1)
+	nrow := v1.DimSize(2)
+	nang := v1.DimSize(3)
+	rot := math32.DegToRad(xf.Rot.Cur)
+	scX, scY := ttaScales(xf)
+	cos, sin := math32.Cos(rot), math32.Sin(rot)
+	ashift := 0
+	if align {
+		ashift = int(math32.Round(xf.Rot.Cur * float32(nang) / 180))
+	}
+	for y := range ny {
+		for x := range nx {
+			sy, sx := y, x
+			if align {
+				// position relative to the center, in units of half-size
+				py := (float32(y)+0.5)/float32(ny)*2 - 1
+				px := (float32(x)+0.5)/float32(nx)*2 - 1
+				// rotation (clockwise on the screen), scale, then translation
+				qx := scX*(cos*px-sin*py) + xf.TransX.Cur
+				qy := scY*(sin*px+cos*py) + xf.TransY.Cur
+				sx = int(math32.Floor((qx + 1) * 0.5 * float32(nx)))
+				sy = int(math32.Floor((qy + 1) * 0.5 * float32(ny)))
+				if sx < 0 || sx >= nx || sy < 0 || sy >= ny {
+					continue
+				}
+			}
+			pi := y*nx + x
+			sc.ttaN.Values[pi]++
+			for r := range nrow {
+				for a := range nang {
+					sa, sr := a, r
+					if ashift != 0 {
+						sa, sr = ttaAngle(a+ashift, r, nang)
+					}
+					v := v1.Value(sy, sx, sr, sa)
+					ti := (pi*nrow+r)*nang + a
+					if agg == TTAMax {
+						sc.TTA.Values[ti] = max(sc.TTA.Values[ti], v)
+					} else {
+						sc.TTA.Values[ti] += v
+					}
+				}
+			}
+		}
+	}
+}
+
+// ttaAngle returns the angle and row of the V1All output of a transformed
+// image that corresponds to given row of the original image, for the
+// given rotated angle index, which wraps around with a reversal of the
+// edge direction for each half turn, swapping the two polarities
+// (or end-stop directions) of the rows after the length-sum row.
+func ttaAngle(ang, row, nang int) (int, int) {
+	wrap := ang / nang
+	if ang < 0 {
+		wrap = (ang - nang + 1) / nang
+	}
+	ang -= wrap * nang
+	if wrap%2 != 0 && row > 0 {
+		row = 1 + ((row - 1) ^ 1)
+	}
+	return ang, row
+}
+
+// ttaScales returns the effective X and Y scaling of the image content
+// by given transform, as applied by XForm.Image: without an aspect
+// change, images scaled larger are not cropped, and are then resized
+// back to ImgSize by the pipeline, so the content is not scaled.
+func ttaScales(xf *vxform.XForm) (scX, scY float32) {
+	sc := xf.Scale.Cur
+	if sc <= 0 {
+		sc = 1
+	}
+	if asp := xf.Aspect.Cur; asp > 0 && asp != 1 {
+		sqa := math32.Sqrt(asp)
+		return sc * sqa, sc / sqa
+	}
+	if sc > 1 {
+		return 1, 1
+	}
+	return sc, sc
+}
@@ -0,0 +1,35 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package pipeline
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Eyes", IDName: "eyes", Doc: "Eyes are the two eyes for binocular filtering"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Dim", IDName: "dim", Doc: "Dim describes one dimension of an exported tensor", Fields: []types.Field{{Name: "Name", Doc: "name of the dimension"}, {Name: "Size", Doc: "size of the dimension"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Meta", IDName: "meta", Doc: "Meta is the self-describing metadata for an exported V1All tensor,\nwhich is saved as a JSON sidecar file, so that filtered datasets\ncan be shared and validated.", Fields: []types.Field{{Name: "Version", Doc: "version of the vision packages that produced the tensor"}, {Name: "Dims", Doc: "name and size of each dimension of the tensor, outer-most first"}, {Name: "Rows", Doc: "names of the V1All rows (features)"}, {Name: "Layout", Doc: "layout of the V1All rows from each of the feature sources"}, {Name: "Classes", Doc: "class label names, indexed by the labels, for bulk exports of a dataset"}, {Name: "Preset", Doc: "the pipeline preset"}, {Name: "Scale", Doc: "name of the scale"}, {Name: "Gabor", Doc: "V1 simple gabor filter parameters"}, {Name: "Geom", Doc: "gabor filter geometry, including the Border padding"}, {Name: "Color", Doc: "whether color filtering was done"}, {Name: "SepColor", Doc: "whether separate color rows were recorded"}, {Name: "ColorGain", Doc: "extra gain for color channels"}, {Name: "NeighInhib", Doc: "neighborhood inhibition parameters"}, {Name: "KWTA", Doc: "kwta parameters"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.MotionRows", IDName: "motion-rows", Doc: "MotionRows are the kinds of transient / motion rows that can be\nincluded in the V1All outputs, for temporal models"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Motion", IDName: "motion", Doc: "Motion has the parameters for the transient / motion rows of the\nV1All outputs, which are computed from successive frames, using\nstate from the previous frame (see InitMotion).", Fields: []types.Field{{Name: "Rows", Doc: "which kind of motion rows to include in V1All, if any"}, {Name: "Gain", Doc: "gain multiplier on the motion row values"}, {Name: "NSpeeds", Doc: "number of speed bands of the motion energy, from fastest to slowest -- the max over speed bands is used"}, {Name: "Tau", Doc: "time constant in frames of the motion energy delay filter for the first (fastest) speed band"}, {Name: "TauMult", Doc: "multiplier on the time constant for each successive motion energy speed band"}, {Name: "Dist", Doc: "distance in pixels between points correlated for motion energy"}, {Name: "Opponent", Doc: "opponent motion, to remove the non-directional flicker and static contrast components of the motion energy"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.motionState", IDName: "motion-state", Doc: "motionState is the per-scale state from previous frames used for\ncomputing the motion rows", Fields: []types.Field{{Name: "prev", Doc: "pooled simple-cell responses (max over polarities) of the previous frame, for FrameDiff"}, {Name: "energy", Doc: "motion energy filter with its delayed image state, for MotionEnergy"}, {Name: "opp", Doc: "opponent motion energy output"}, {Name: "out", Doc: "motion rows output: [Y, X, 2, Angle]"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Panorama", IDName: "panorama", Doc: "Panorama has the parameters for filtering panoramic images that are\nmuch wider than ImgSize, in overlapping tiles of ImgSize, which are\nstitched into one seamless V1All-style output for each scale.", Fields: []types.Field{{Name: "Wrap", Doc: "whether the panorama wraps around in azimuth (i.e., a 360 degree panorama), so that the left and right edges are continuous -- otherwise the edges are extended, or reflected if Pad is Reflect"}, {Name: "Margin", Doc: "margin on the left and right of each tile that overlaps with the neighboring tiles, and is discarded in stitching, in V1All units of the coarsest scale -- must cover the spatial extent of the complex cell and neighbor inhibition interactions"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Presets", IDName: "presets", Doc: "Presets are named configurations of the Pipeline"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Scale", IDName: "scale", Doc: "Scale has the parameters and outputs for one scale of V1 filtering", Fields: []types.Field{{Name: "Name", Doc: "name of the scale, e.g., for naming network input layers"}, {Name: "Gabor", Doc: "V1 simple gabor filter parameters"}, {Name: "Geom", Doc: "geometry of input, output for V1 simple-cell processing"}, {Name: "GaborTsr", Doc: "V1 simple gabor filter tensor"}, {Name: "Simple", Doc: "V1 simple gabor filter kwta output, per opponent channel"}, {Name: "MaxTsr", Doc: "max over opponent channels of the V1 simple kwta outputs"}, {Name: "PoolTsr", Doc: "max-pooled 2x2 of MaxTsr"}, {Name: "ColorPoolTsrs", Doc: "max-pooled 2x2 of the red-green and blue-yellow simple outputs, if SepColor"}, {Name: "AngPoolTsr", Doc: "max-pooled 2x2 of the angle-only features (max over polarities) of MaxTsr"}, {Name: "LenSumTsr", Doc: "V1 complex length sum output"}, {Name: "EndStopTsr", Doc: "V1 complex end stop output"}, {Name: "V1All", Doc: "combined V1 output: length sum, end stops (2), pooled simple cells (2), then red-green (2) and blue-yellow (2) pooled simple cells if SepColor, then transient / motion rows (2) if Motion.Rows is set -- see Layout"}, {Name: "Layout", Doc: "layout of the rows of V1All from each of the feature sources"}, {Name: "EyeV1All", Doc: "V1All output for each eye, from FilterImages"}, {Name: "Binoc", Doc: "binocular V1 output from FilterImages, with the V1All rows of the two eyes interleaved, in ocular dominance organization: [Y, X, Row * Eye, Angle]"}, {Name: "Pano", Doc: "V1All output stitched over the tiles of a panoramic image, from FilterPanorama: [Y, X, Row, Angle] over the entire panorama"}, {Name: "TTA", Doc: "V1All output aggregated over the transforms of test-time augmentation, from FilterTTA"}, {Name: "eyeSimple", Doc: "V1 simple kwta outputs for each eye, swapped with Simple during FilterImages, so each eye's kwta settling starts from its own prior state"}, {Name: "motion", Doc: "state from previous frames for the motion rows"}, {Name: "eyeMotion", Doc: "motion state for each eye, swapped with motion during FilterImages"}, {Name: "ttaN", Doc: "number of transforms aggregated into each TTA position, for the mean"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.Pipeline", IDName: "pipeline", Doc: "Pipeline is a complete visual front end, from an image to V1All\nfeatures at each scale.  Use SetPreset to configure it, and then\nmodify any parameters, followed by Config.", Fields: []types.Field{{Name: "Preset", Doc: "preset that the pipeline was last configured with"}, {Name: "ImgSize", Doc: "target image size to use -- images will be rescaled to this size"}, {Name: "Resize", Doc: "interpolation used to rescale images to ImgSize"}, {Name: "Color", Doc: "if true, do full color filtering -- else Black/White only"}, {Name: "SepColor", Doc: "record separate rows in V1All for each color -- otherwise just records the max across all colors"}, {Name: "ColorGain", Doc: "extra gain for color channels -- lower contrast in general"}, {Name: "Pad", Doc: "how to fill the padding border of the input image -- Reflect avoids the spurious edges at the borders of natural images produced by the default Wrap"}, {Name: "Pupil", Doc: "retinal pupil gain -- off by default, and only relevant for sequences of frames"}, {Name: "Range", Doc: "percentile-based range mapping of each image to 0-1, with optional polarity inversion, for thermal / infrared imagery, after the pupil gain -- when On and not Color, the grey image is the linear mean of the image channels, bypassing the sRGB and LMS conversion -- off by default except for the Thermal preset"}, {Name: "Contrast", Doc: "normalization of each image to a target mean luminance and RMS contrast, after the pupil gain and Range -- off by default"}, {Name: "Motion", Doc: "transient / motion rows included in the V1All outputs, computed from successive frames -- off by default"}, {Name: "Panorama", Doc: "tiling of panoramic images, for FilterPanorama"}, {Name: "NeighInhib", Doc: "neighborhood inhibition for V1s -- each unit gets inhibition from same feature in nearest orthogonal neighbors -- reduces redundancy of feature code"}, {Name: "KWTA", Doc: "kwta parameters for V1s"}, {Name: "Scales", Doc: "parameters and outputs for each scale, from fine to coarse"}, {Name: "Spectral", Doc: "projection of multispectral / hyperspectral image bands to the LMS cone responses, for SetBands"}, {Name: "Img", Doc: "input image as a padded RGB tensor, or padded [Band, Y, X] tensor from SetBands"}, {Name: "LMS", Doc: "LMS components + opponents tensor version of image, only if Color"}, {Name: "Grey", Doc: "greyscale (LMS GREY component) version of image, computed directly when not Color"}, {Name: "EyeImgs", Doc: "input images for each eye as padded RGB tensors, from FilterImages"}, {Name: "Inhibs", Doc: "inhibition values for V1s KWTA"}, {Name: "bands", Doc: "whether Img has the bands from SetBands, instead of RGB"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.savedTensor", IDName: "saved-tensor", Doc: "savedTensor is a tensor with its shape, for saving", Fields: []types.Field{{Name: "Shape"}, {Name: "Values"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.savedScale", IDName: "saved-scale", Doc: "savedScale is the saved state of one Scale", Fields: []types.Field{{Name: "Name"}, {Name: "Gabor"}, {Name: "Geom"}, {Name: "Filter"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.saved", IDName: "saved", Doc: "saved is the saved state of the Pipeline", Fields: []types.Field{{Name: "Version"}, {Name: "Preset"}, {Name: "ImgSize"}, {Name: "Resize"}, {Name: "Color"}, {Name: "SepColor"}, {Name: "ColorGain"}, {Name: "Pad"}, {Name: "Pupil"}, {Name: "Range"}, {Name: "Contrast"}, {Name: "Motion"}, {Name: "Spectral"}, {Name: "Panorama"}, {Name: "NeighInhib"}, {Name: "KWTA"}, {Name: "Scales"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/pipeline.TTAAggs", IDName: "tta-aggs", Doc: "TTAAggs are the ways of aggregating V1All outputs over the\ntransforms of test-time augmentation"})
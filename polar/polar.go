@@ -0,0 +1,281 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package polar provides a polar-separable filter bank, defined in the
+frequency domain as the product of ring (spatial frequency band) and
+wedge (orientation band) filters, for frequency / orientation
+decompositions of images, with a spatial rendering for use with
+vfilter.Conv.
+*/
+package polar
+
+//go:generate core generate -add-types
+
+import (
+	"math"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// polar.Filter specifies a polar-separable filter bank, with NRings
+// ring filters, log-spaced in spatial frequency between MinFreq and
+// MaxFreq, times NWedges wedge filters, evenly spaced in orientation.
+// Adjacent rings and wedges overlap with raised-cosine profiles, so
+// that the sum of the squared filters is uniform (1) over the covered
+// frequency band.
+type Filter struct {
+
+	// is this filter active?
+	On bool
+
+	// overall gain multiplier applied after filtering -- only relevant if not using renormalization (otherwize it just gets renormed away)
+	Gain float32 `default:"2"`
+
+	// size of the overall filter -- number of pixels wide and tall for a square matrix used to encode the filter, in both the frequency and spatial domains -- the lowest resolvable frequency is 1 / Size
+	Size int
+
+	// how far apart to space the centers of the filters -- 1 = every pixel, 2 = every other pixel, etc
+	Spacing int
+
+	// number of ring (spatial frequency band) filters
+	NRings int `default:"3"`
+
+	// number of wedge (orientation band) filters -- first angle is always horizontal, as in gabor -- 1 = no orientation tuning
+	NWedges int `default:"4"`
+
+	// lowest spatial frequency in cycles per pixel, at the center of the first ring -- should be >= 1 / Size
+	MinFreq float32 `default:"0.0625"`
+
+	// highest spatial frequency in cycles per pixel, at the center of the last ring -- max is 0.5 (Nyquist)
+	MaxFreq float32 `default:"0.25"`
+
+	// cut off the spatial filter (to zero) outside a circle of diameter = Size -- makes the filter more radially symmetric
+	CircleEdge bool `default:"true"`
+}
+
+func (pf *Filter) Defaults() {
+	pf.On = true
+	pf.Gain = 2
+	pf.Size = 16
+	pf.Spacing = 2
+	pf.NRings = 3
+	pf.NWedges = 4
+	pf.MinFreq = 0.0625
+	pf.MaxFreq = 0.25
+	pf.CircleEdge = true
+}
+
+func (pf *Filter) Update() {
+}
+
+func (pf *Filter) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return pf.On
+	}
+}
+
+// NFilters returns the total number of filters: NRings * NWedges
+func (pf *Filter) NFilters() int {
+	return pf.NRings * pf.NWedges
+}
+
+// RingFreq returns the center frequency of given ring, in cycles per pixel
+func (pf *Filter) RingFreq(ring int) float32 {
+	if pf.NRings <= 1 {
+		return pf.MinFreq
+	}
+	lmin := math32.Log2(pf.MinFreq)
+	lmax := math32.Log2(pf.MaxFreq)
+	return math32.Pow(2, lmin+float32(ring)*(lmax-lmin)/float32(pf.NRings-1))
+}
+
+// Ring returns the value of given ring filter at given frequency
+// magnitude, in cycles per pixel, which is a raised cosine in log
+// frequency, extending to the centers of the neighboring rings.
+func (pf *Filter) Ring(ring int, freq float32) float32 {
+	if freq <= 0 {
+		return 0
+	}
+	wd := float32(1) // octaves, if only one ring
+	if pf.NRings > 1 {
+		wd = math32.Log2(pf.MaxFreq/pf.MinFreq) / float32(pf.NRings-1)
+	}
+	d := math32.Log2(freq / pf.RingFreq(ring))
+	if math32.Abs(d) >= wd {
+		return 0
+	}
+	return math32.Cos(0.5 * math.Pi * d / wd)
+}
+
+// Wedge returns the value of given wedge filter at given frequency
+// orientation, in radians, which is a raised cosine in orientation,
+// extending to the centers of the neighboring wedges.  The wedges
+// are symmetric about the origin (orientations differing by Pi are
+// the same), so the spatial filters are real-valued.
+func (pf *Filter) Wedge(wedge int, ang float32) float32 {
+	if pf.NWedges <= 1 {
+		return 1
+	}
+	wd := math.Pi / float32(pf.NWedges)
+	// filter orientation is perpendicular to the frequency orientation
+	d := ang - 0.5*math.Pi - float32(wedge)*wd
+	d -= math.Pi * math32.Round(d/math.Pi)
+	if math32.Abs(d) >= wd {
+		return 0
+	}
+	return math32.Cos(0.5 * math.Pi * d / wd)
+}
+
+// freq returns the frequency (cycles per pixel) magnitude and
+// orientation for given frequency-domain coordinates, with DC
+// at the center (Size / 2).
+func (pf *Filter) freq(y, x int) (mag, ang float32) {
+	fy := float32(y-pf.Size/2) / float32(pf.Size)
+	fx := float32(x-pf.Size/2) / float32(pf.Size)
+	return math32.Hypot(fx, fy), math32.Atan2(fy, fx)
+}
+
+// RingsToTensor renders the frequency-domain ring filters into the
+// given tensor, setting dimensions to [ring][Y][X] where Y = X = Size,
+// with DC (zero frequency) at the center (Size / 2).
+func (pf *Filter) RingsToTensor(tsr *tensor.Float32) {
+	tsr.SetShapeSizes(pf.NRings, pf.Size, pf.Size)
+	for r := range pf.NRings {
+		for y := range pf.Size {
+			for x := range pf.Size {
+				mag, _ := pf.freq(y, x)
+				tsr.Set(pf.Ring(r, mag), r, y, x)
+			}
+		}
+	}
+}
+
+// WedgesToTensor renders the frequency-domain wedge filters into the
+// given tensor, setting dimensions to [wedge][Y][X] where Y = X = Size,
+// with DC (zero frequency) at the center (Size / 2).
+func (pf *Filter) WedgesToTensor(tsr *tensor.Float32) {
+	tsr.SetShapeSizes(pf.NWedges, pf.Size, pf.Size)
+	for w := range pf.NWedges {
+		for y := range pf.Size {
+			for x := range pf.Size {
+				_, ang := pf.freq(y, x)
+				tsr.Set(pf.Wedge(w, ang), w, y, x)
+			}
+		}
+	}
+}
+
+// FreqToTensor renders the frequency-domain ring * wedge filters into
+// the given tensor, setting dimensions to [ring * wedge][Y][X] where
+// Y = X = Size, with all the wedges for the first ring first, and so on,
+// with DC (zero frequency) at the center (Size / 2).
+func (pf *Filter) FreqToTensor(tsr *tensor.Float32) {
+	tsr.SetShapeSizes(pf.NFilters(), pf.Size, pf.Size)
+	for r := range pf.NRings {
+		for w := range pf.NWedges {
+			fi := r*pf.NWedges + w
+			for y := range pf.Size {
+				for x := range pf.Size {
+					mag, ang := pf.freq(y, x)
+					tsr.Set(pf.Ring(r, mag)*pf.Wedge(w, ang), fi, y, x)
+				}
+			}
+		}
+	}
+}
+
+// ToTensor renders the spatial-domain ring * wedge filters into the
+// given tensor, for use with vfilter.Conv, setting dimensions to
+// [ring * wedge][Y][X] where Y = X = Size, with all the wedges for the
+// first ring first, and so on.  The spatial filters are the (real,
+// even-symmetric) inverse Fourier transforms of the FreqToTensor
+// filters, with the positive and negative values each normalized to
+// sum to 1, as in gabor.
+func (pf *Filter) ToTensor(tsr *tensor.Float32) {
+	var frq tensor.Float32
+	pf.FreqToTensor(&frq)
+	tsr.SetShapeSizes(pf.NFilters(), pf.Size, pf.Size)
+	ctr := 0.5 * float32(pf.Size-1)
+	radius := float32(pf.Size) * 0.5
+	twoPiNorm := (2.0 * math.Pi) / float32(pf.Size)
+	for fi := range pf.NFilters() {
+		posSum := float32(0)
+		negSum := float32(0)
+		for y := range pf.Size {
+			for x := range pf.Size {
+				xf := float32(x) - ctr
+				yf := float32(y) - ctr
+				if pf.CircleEdge && math32.Hypot(xf, yf) > radius {
+					tsr.Set(0, fi, y, x)
+					continue
+				}
+				val := float32(0)
+				for v := range pf.Size {
+					for u := range pf.Size {
+						hv := frq.Value(fi, v, u)
+						if hv == 0 {
+							continue
+						}
+						val += hv * math32.Cos(twoPiNorm*(float32(u-pf.Size/2)*xf+float32(v-pf.Size/2)*yf))
+					}
+				}
+				if val > 0 {
+					posSum += val
+				} else if val < 0 {
+					negSum += -val
+				}
+				tsr.Set(val, fi, y, x)
+			}
+		}
+		// renorm each half
+		posNorm := float32(1)
+		if posSum > 0 {
+			posNorm = 1 / posSum
+		}
+		negNorm := float32(1)
+		if negSum > 0 {
+			negNorm = 1 / negSum
+		}
+		for y := range pf.Size {
+			for x := range pf.Size {
+				val := tsr.Value(fi, y, x)
+				if val > 0 {
+					val *= posNorm
+				} else if val < 0 {
+					val *= negNorm
+				}
+				tsr.Set(val, fi, y, x)
+			}
+		}
+	}
+}
+
+// ToTable renders filters into the given table.Table
+// setting columns named Freq and Angle to the ring center frequency
+// and wedge angle (in degrees), and columns named Filter and FreqFilter
+// to the spatial and frequency domain filters.
+// This is useful for display and validation purposes.
+func (pf *Filter) ToTable(tab *table.Table) {
+	nf := pf.NFilters()
+	tab.AddFloat32Column("Freq")
+	tab.AddFloat32Column("Angle")
+	tab.AddFloat32Column("Filter", pf.Size, pf.Size)
+	tab.AddFloat32Column("FreqFilter", pf.Size, pf.Size)
+	tab.SetNumRows(nf)
+	pf.ToTensor(tab.Columns.Values[2].(*tensor.Float32))
+	pf.FreqToTensor(tab.Columns.Values[3].(*tensor.Float32))
+	for r := range pf.NRings {
+		for w := range pf.NWedges {
+			fi := r*pf.NWedges + w
+			tab.ColumnByIndex(0).SetFloat1D(float64(pf.RingFreq(r)), fi)
+			tab.ColumnByIndex(1).SetFloat1D(float64(w)*180/float64(pf.NWedges), fi)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Anim accumulates per-frame visualization images, e.g., of selected
+// tensors over an image sequence, and writes them to an animated GIF
+// or MP4 video, so that temporal dynamics (adaptation, motion energy,
+// attention) can be inspected and shared.
+type Anim struct {
+
+	// frames per second for playback
+	FPS int `default:"10"`
+
+	// number of times to loop the GIF animation: 0 = forever, -1 = play once
+	LoopCount int
+
+	// frame images, added with Add
+	Frames []image.Image `display:"-"`
+}
+
+func (an *Anim) Defaults() {
+	an.FPS = 10
+	an.LoopCount = 0
+}
+
+// Reset removes all frames
+func (an *Anim) Reset() {
+	an.Frames = nil
+}
+
+// Add adds a frame, which is the given images tiled horizontally
+// (see Tile), e.g., renderings of different tensors for the same frame.
+func (an *Anim) Add(imgs ...image.Image) {
+	an.Frames = append(an.Frames, Tile(2, imgs...))
+}
+
+// Save saves the animation to given file, with the format
+// determined by the extension: .gif or .mp4
+func (an *Anim) Save(fname string) error {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case ".gif":
+		return an.SaveGIF(fname)
+	case ".mp4":
+		return an.SaveMP4(fname)
+	}
+	return fmt.Errorf("render.Anim.Save: extension must be .gif or .mp4: %s", fname)
+}
+
+// SaveGIF saves the frames to an animated GIF file, using the
+// Plan9 palette with Floyd-Steinberg dithering.
+func (an *Anim) SaveGIF(fname string) error {
+	if len(an.Frames) == 0 {
+		return errors.New("render.Anim.SaveGIF: no frames")
+	}
+	delay := 100 / max(an.FPS, 1)
+	ag := &gif.GIF{LoopCount: an.LoopCount}
+	for _, fr := range an.Frames {
+		bd := fr.Bounds()
+		pi := image.NewPaletted(bd, palette.Plan9)
+		draw.FloydSteinberg.Draw(pi, bd, fr, bd.Min)
+		ag.Image = append(ag.Image, pi)
+		ag.Delay = append(ag.Delay, delay)
+	}
+	f, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gif.EncodeAll(f, ag)
+}
+
+// SaveMP4 saves the frames to an MP4 video file, using the external
+// ffmpeg program, which must be installed on the path -- frames are
+// written as PNG files to a temporary directory and encoded in H.264.
+func (an *Anim) SaveMP4(fname string) error {
+	if len(an.Frames) == 0 {
+		return errors.New("render.Anim.SaveMP4: no frames")
+	}
+	ff, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("render.Anim.SaveMP4: ffmpeg is required: %w", err)
+	}
+	dir, err := os.MkdirTemp("", "anim")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	for i, fr := range an.Frames {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("%06d.png", i)))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, fr)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	// H.264 requires even image sizes
+	cmd := exec.Command(ff, "-y", "-loglevel", "error", "-framerate", fmt.Sprint(max(an.FPS, 1)),
+		"-i", filepath.Join(dir, "%06d.png"), "-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2",
+		"-c:v", "libx264", "-pix_fmt", "yuv420p", fname)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("render.Anim.SaveMP4: ffmpeg failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// Tile returns an image with the given images tiled horizontally,
+// separated by given gap in pixels, on a black background,
+// aligned at the top.
+func Tile(gap int, imgs ...image.Image) image.Image {
+	if len(imgs) == 1 {
+		return imgs[0]
+	}
+	var sz image.Point
+	for i, img := range imgs {
+		isz := img.Bounds().Size()
+		sz.X += isz.X
+		if i > 0 {
+			sz.X += gap
+		}
+		sz.Y = max(sz.Y, isz.Y)
+	}
+	tile := image.NewRGBA(image.Rectangle{Max: sz})
+	draw.Draw(tile, tile.Bounds(), image.Black, image.Point{}, draw.Src)
+	x := 0
+	for _, img := range imgs {
+		bd := img.Bounds()
+		draw.Draw(tile, image.Rectangle{Min: image.Point{x, 0}, Max: image.Point{x + bd.Dx(), bd.Dy()}}, img, bd.Min, draw.Src)
+		x += bd.Dx() + gap
+	}
+	return tile
+}
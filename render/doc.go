@@ -0,0 +1,20 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package render provides rendering of filter output tensors into
+color images, for figures and quick inspection outside of the
+tensor grid view.
+
+* V1 renders [Y, X, Polarity, Angle] V1 simple-cell outputs with
+hue = orientation, saturation = polarity, and value = activation.
+
+* LenSum and EndStop render the corresponding v1complex outputs,
+with hue = orientation for LenSum, and hue = end direction around
+the full circle for EndStop.  Grey renders any 2D tensor.
+
+* Anim accumulates per-frame renderings over an image sequence, and
+saves them to an animated GIF or MP4 video (the latter requires ffmpeg).
+*/
+package render
@@ -0,0 +1,11 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package render
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/render.Anim", IDName: "anim", Doc: "Anim accumulates per-frame visualization images, e.g., of selected\ntensors over an image sequence, and writes them to an animated GIF\nor MP4 video, so that temporal dynamics (adaptation, motion energy,\nattention) can be inspected and shared.", Fields: []types.Field{{Name: "FPS", Doc: "frames per second for playback"}, {Name: "LoopCount", Doc: "number of times to loop the GIF animation: 0 = forever, -1 = play once"}, {Name: "Frames", Doc: "frame images, added with Add"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/render.Render", IDName: "render", Doc: "Render has parameters for rendering feature tensors of shape\n[Y, X, Rows, Angle] into color images, where each unit is\nrendered as a Scale x Scale block of pixels, with color determined\nby the vector average over features of the unit.", Fields: []types.Field{{Name: "Scale", Doc: "number of pixels per unit in each dimension"}, {Name: "Max", Doc: "activation value that maps to full brightness -- if 0, the max over the tensor is used"}, {Name: "TopZero", Doc: "tensor has Y=0 at the top -- otherwise it is flipped with Y=0 at the bottom, as for image tensors"}}})
@@ -0,0 +1,246 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package render
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Render has parameters for rendering feature tensors of shape
+// [Y, X, Rows, Angle] into color images, where each unit is
+// rendered as a Scale x Scale block of pixels, with color determined
+// by the vector average over features of the unit.
+type Render struct {
+
+	// number of pixels per unit in each dimension
+	Scale int `default:"4"`
+
+	// activation value that maps to full brightness -- if 0, the max over the tensor is used
+	Max float32
+
+	// tensor has Y=0 at the top -- otherwise it is flipped with Y=0 at the bottom, as for image tensors
+	TopZero bool
+}
+
+func (rn *Render) Defaults() {
+	rn.Scale = 4
+	rn.Max = 0
+}
+
+// V1 renders a [Y, X, Polarity, Angle] V1 simple-cell output,
+// with hue = orientation (from the activation-weighted vector average
+// over angles, red = horizontal), saturation = polarity (full for
+// the first, on, polarity, and none for the second, off, polarity),
+// and value = the max activation over features.
+func (rn *Render) V1(tsr *tensor.Float32) *image.RGBA {
+	npol := tsr.DimSize(2)
+	return rn.render(tsr, func(vals []float32) (h, s float32) {
+		nang := tsr.DimSize(3)
+		h = orientHue(vals, npol, nang)
+		if npol < 2 {
+			return h, 1
+		}
+		var on, off float32
+		for a := 0; a < nang; a++ {
+			on += vals[a]
+			off += vals[nang+a]
+		}
+		s = 1
+		if on+off > 0 {
+			s = on / (on + off)
+		}
+		return h, s
+	})
+}
+
+// LenSum renders a [Y, X, 1, Angle] v1complex length-sum output,
+// with hue = orientation and value = max activation over angles.
+func (rn *Render) LenSum(tsr *tensor.Float32) *image.RGBA {
+	nrows := tsr.DimSize(2)
+	nang := tsr.DimSize(3)
+	return rn.render(tsr, func(vals []float32) (h, s float32) {
+		return orientHue(vals, nrows, nang), 1
+	})
+}
+
+// EndStop renders a [Y, X, 2, Angle] v1complex end-stop output,
+// where the two rows are the two opposite end directions, with
+// hue = end direction around the full circle (from the
+// activation-weighted vector average) and value = max activation.
+func (rn *Render) EndStop(tsr *tensor.Float32) *image.RGBA {
+	nrows := tsr.DimSize(2)
+	nang := tsr.DimSize(3)
+	return rn.render(tsr, func(vals []float32) (h, s float32) {
+		var vx, vy float32
+		for r := 0; r < nrows; r++ {
+			dir := float32(r % 2)
+			for a := 0; a < nang; a++ {
+				ang := math32.Pi * (float32(a)/float32(nang) + dir)
+				v := vals[r*nang+a]
+				vx += v * math32.Cos(ang)
+				vy += v * math32.Sin(ang)
+			}
+		}
+		return vecHue(vx, vy), 1
+	})
+}
+
+// Grey renders a 2D [Y, X] tensor, e.g., an image or any single
+// feature map, as a grey-scale image, with negative values rendered
+// as 0.
+func (rn *Render) Grey(tsr *tensor.Float32) *image.RGBA {
+	sc := max(rn.Scale, 1)
+	sy := tsr.DimSize(0)
+	sx := tsr.DimSize(1)
+	mx := rn.Max
+	if mx <= 0 {
+		for _, v := range tsr.Values {
+			mx = math32.Max(mx, v)
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, sx*sc, sy*sc))
+	for y := 0; y < sy; y++ {
+		iy := y
+		if !rn.TopZero {
+			iy = sy - 1 - y
+		}
+		for x := 0; x < sx; x++ {
+			var v float32
+			if mx > 0 {
+				v = math32.Clamp(tsr.Value(y, x)/mx, 0, 1)
+			}
+			g := uint8(255 * v)
+			clr := color.RGBA{g, g, g, 255}
+			for py := 0; py < sc; py++ {
+				for px := 0; px < sc; px++ {
+					img.SetRGBA(x*sc+px, iy*sc+py, clr)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// render renders given tensor using given function to compute
+// the hue and saturation from the feature values for each unit.
+func (rn *Render) render(tsr *tensor.Float32, hsfun func(vals []float32) (h, s float32)) *image.RGBA {
+	sc := max(rn.Scale, 1)
+	sy := tsr.DimSize(0)
+	sx := tsr.DimSize(1)
+	nf := tsr.DimSize(2) * tsr.DimSize(3)
+	mx := rn.Max
+	if mx <= 0 {
+		for _, v := range tsr.Values {
+			mx = math32.Max(mx, v)
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, sx*sc, sy*sc))
+	for y := 0; y < sy; y++ {
+		iy := y
+		if !rn.TopZero {
+			iy = sy - 1 - y
+		}
+		for x := 0; x < sx; x++ {
+			st := (y*sx + x) * nf
+			vals := tsr.Values[st : st+nf]
+			var vmax float32
+			for _, v := range vals {
+				vmax = math32.Max(vmax, v)
+			}
+			var v float32
+			if mx > 0 {
+				v = math32.Clamp(vmax/mx, 0, 1)
+			}
+			h, s := hsfun(vals)
+			clr := HSVToRGB(h, s, v)
+			for py := 0; py < sc; py++ {
+				for px := 0; px < sc; px++ {
+					img.SetRGBA(x*sc+px, iy*sc+py, clr)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// orientHue returns the hue in degrees for the orientation given by
+// the activation-weighted vector average over angles (which span
+// 180 degrees) and rows of given feature values, using the doubled
+// angle so that orientations 180 degrees apart are equivalent.
+func orientHue(vals []float32, nrows, nang int) float32 {
+	var vx, vy float32
+	for r := 0; r < nrows; r++ {
+		for a := 0; a < nang; a++ {
+			ang := 2 * math32.Pi * float32(a) / float32(nang)
+			v := vals[r*nang+a]
+			vx += v * math32.Cos(ang)
+			vy += v * math32.Sin(ang)
+		}
+	}
+	return vecHue(vx, vy)
+}
+
+// vecHue returns the hue in degrees [0..360) for given vector
+func vecHue(vx, vy float32) float32 {
+	h := math32.RadToDeg(math32.Atan2(vy, vx))
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// HSVToRGB converts hue (degrees), saturation, value (0-1) to
+// an RGBA color
+func HSVToRGB(h, s, v float32) color.RGBA {
+	c := v * s
+	hp := h / 60
+	x := c * (1 - math32.Abs(math32.Mod(hp, 2)-1))
+	var r, g, b float32
+	switch {
+	case hp < 1:
+		r, g = c, x
+	case hp < 2:
+		r, g = x, c
+	case hp < 3:
+		g, b = c, x
+	case hp < 4:
+		g, b = x, c
+	case hp < 5:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := v - c
+	return color.RGBA{uint8(255 * (r + m)), uint8(255 * (g + m)), uint8(255 * (b + m)), 255}
+}
+
+// Legend returns a legend image for the hues used for given number
+// of angles, with one block of size x size pixels per angle,
+// at full saturation and value.  If full is true, hues span the
+// full circle over 2 * nang directions as used in EndStop,
+// otherwise the orientation hues used in V1 and LenSum.
+func Legend(nang, size int, full bool) *image.RGBA {
+	n := nang
+	if full {
+		n = 2 * nang
+	}
+	img := image.NewRGBA(image.Rect(0, 0, n*size, size))
+	for a := 0; a < n; a++ {
+		h := 360 * float32(a) / float32(n)
+		clr := HSVToRGB(h, 1, 1)
+		for py := 0; py < size; py++ {
+			for px := 0; px < size; px++ {
+				img.SetRGBA(a*size+px, py, clr)
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retina
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// ContrastNorm rescales each image to a target mean luminance and RMS
+// contrast (standard deviation of the luminance), so that images with
+// heterogeneous exposure produce comparable V1 activation levels.
+// The luminance is the average over channels, and the same linear
+// transform is applied to all channels.  The statistics of the last
+// image, including the proportion of values clipped to the 0-1 range,
+// are recorded, for monitoring the effects on a dataset.
+type ContrastNorm struct {
+
+	// whether to apply contrast normalization
+	On bool
+
+	// target mean luminance
+	Mean float32 `default:"0.5"`
+
+	// target RMS contrast: standard deviation of the luminance
+	RMS float32 `default:"0.2"`
+
+	// maximum gain applied to the contrast, to avoid amplifying noise in nearly uniform images
+	MaxGain float32 `default:"10"`
+
+	// clip the resulting values to the 0-1 range
+	Clip bool `default:"true"`
+
+	// mean luminance of the last image, prior to normalization
+	InMean float32 `edit:"-"`
+
+	// RMS contrast of the last image, prior to normalization
+	InRMS float32 `edit:"-"`
+
+	// contrast gain applied to the last image
+	Gain float32 `edit:"-"`
+
+	// proportion of values of the last image clipped at 0
+	ClipLo float32 `edit:"-"`
+
+	// proportion of values of the last image clipped at 1
+	ClipHi float32 `edit:"-"`
+}
+
+func (cn *ContrastNorm) Defaults() {
+	cn.On = false
+	cn.Mean = 0.5
+	cn.RMS = 0.2
+	cn.MaxGain = 10
+	cn.Clip = true
+}
+
+func (cn *ContrastNorm) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return cn.On
+	}
+}
+
+// Normalize rescales the values of given image tensor in place to the
+// target Mean and RMS contrast, computing the statistics excluding
+// padWidth of padding on the two inner-most (Y, X) dimensions, and
+// applying the transform to all values including the padding.
+// The image can be grey (2D) or have any number of outer components
+// (e.g., RGB).  Does nothing if not On.
+func (cn *ContrastNorm) Normalize(img *tensor.Float32, padWidth int) {
+	if !cn.On {
+		return
+	}
+	cn.InMean, cn.InRMS = LumStats(img, padWidth)
+	cn.Gain = cn.MaxGain
+	if cn.InRMS > 0 {
+		cn.Gain = min(cn.RMS/cn.InRMS, cn.MaxGain)
+	}
+	nlo, nhi := 0, 0
+	for i, v := range img.Values {
+		v = cn.Mean + cn.Gain*(v-cn.InMean)
+		if cn.Clip {
+			if v < 0 {
+				v = 0
+				nlo++
+			} else if v > 1 {
+				v = 1
+				nhi++
+			}
+		}
+		img.Values[i] = v
+	}
+	n := float32(max(len(img.Values), 1))
+	cn.ClipLo = float32(nlo) / n
+	cn.ClipHi = float32(nhi) / n
+}
+
+// LumStats returns the mean and standard deviation (RMS contrast) of the
+// luminance of given image tensor, as the average over any outer
+// components (e.g., RGB), excluding padWidth of padding on the two
+// inner-most (Y, X) dimensions.
+func LumStats(img *tensor.Float32, padWidth int) (mean, rms float32) {
+	nd := img.NumDims()
+	sy := img.DimSize(nd - 2)
+	sx := img.DimSize(nd - 1)
+	nc := img.Len() / (sy * sx)
+	var sum, ssq float64
+	n := 0
+	for y := padWidth; y < sy-padWidth; y++ {
+		for x := padWidth; x < sx-padWidth; x++ {
+			var lum float32
+			for c := range nc {
+				lum += img.Values[c*sy*sx+y*sx+x]
+			}
+			lum /= float32(nc)
+			sum += float64(lum)
+			ssq += float64(lum * lum)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	m := sum / float64(n)
+	return float32(m), math32.Sqrt(float32(max(ssq/float64(n)-m*m, 0)))
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package retina provides models of the optical and early retinal stages
+that operate on the whole image prior to spatial filtering.
+
+* Pupil models the pupil diameter as a function of the mean field
+luminance, which in turn scales the retinal illuminance over time.
+This provides realistic global gain dynamics for pipelines that
+respond to a sequence of video frames: sudden increases in luminance
+are partially compensated by fast constriction, while decreases are
+compensated by slower dilation.
+
+* ContrastNorm rescales each image to a target mean luminance and RMS
+contrast, reporting the proportion of clipped values, so that datasets
+with heterogeneous exposure produce comparable V1 activation levels.
+
+* RangeNorm maps the values between low and high percentiles to the
+0-1 range, with optional polarity inversion, for thermal / infrared
+imagery with arbitrary raw value ranges.
+*/
+package retina
@@ -0,0 +1,162 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retina
+
+//go:generate core generate -add-types
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Pupil models the pupil diameter as a function of mean field luminance,
+// using the Moon & Spencer (1944) steady-state function, with separate
+// time constants for constriction and dilation.
+// The resulting Gain is the retinal illuminance relative to a pupil of
+// RefDiam diameter (i.e., proportional to pupil area), which should be
+// multiplied into the image prior to any adaptation or noise stages.
+type Pupil struct {
+
+	// whether to apply the pupil model -- if off, Gain is always 1
+	On bool
+
+	// luminance in cd/m^2 corresponding to an image value of 1 -- image values are assumed to be linear in luminance
+	MaxLum float32 `default:"200"`
+
+	// time constant in frames for constriction of the pupil (in response to increases in luminance) -- constriction is faster than dilation
+	ConTau float32 `default:"3"`
+
+	// time constant in frames for dilation of the pupil (in response to decreases in luminance)
+	DilTau float32 `default:"10"`
+
+	// reference pupil diameter in mm at which Gain = 1 -- the default corresponds to the steady-state diameter for a mid-level (MaxLum / 2) luminance
+	RefDiam float32 `default:"2.9"`
+
+	// minimum pupil diameter in mm
+	MinDiam float32 `default:"2"`
+
+	// maximum pupil diameter in mm
+	MaxDiam float32 `default:"8"`
+
+	// current pupil diameter in mm
+	Diam float32 `edit:"-"`
+
+	// current retinal illuminance gain = (Diam / RefDiam)^2
+	Gain float32 `edit:"-"`
+
+	// current retinal illuminance in trolands = luminance * pupil area in mm^2
+	Trolands float32 `edit:"-"`
+
+	// rate = 1 / tau
+	ConDt float32 `display:"-" json:"-" xml:"-"`
+
+	// rate = 1 / tau
+	DilDt float32 `display:"-" json:"-" xml:"-"`
+}
+
+func (pp *Pupil) Defaults() {
+	pp.On = true
+	pp.MaxLum = 200
+	pp.ConTau = 3
+	pp.DilTau = 10
+	pp.RefDiam = 2.9
+	pp.MinDiam = 2
+	pp.MaxDiam = 8
+	pp.Update()
+	pp.Init()
+}
+
+// Update must be called after any changes to parameters
+func (pp *Pupil) Update() {
+	pp.ConDt = 1 / pp.ConTau
+	pp.DilDt = 1 / pp.DilTau
+}
+
+func (pp *Pupil) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return pp.On
+	}
+}
+
+// Init resets the pupil to the reference diameter, with a Gain of 1
+func (pp *Pupil) Init() {
+	pp.Diam = pp.RefDiam
+	pp.Gain = 1
+	pp.Trolands = 0
+}
+
+// SteadyDiam returns the steady-state pupil diameter in mm for given
+// field luminance in cd/m^2, using the Moon & Spencer (1944) function:
+// D = 4.9 - 3 * tanh(0.4 * log10(L)), clipped to the Min, Max range.
+func (pp *Pupil) SteadyDiam(lum float32) float32 {
+	lum = math32.Max(lum, 1.0e-4)
+	d := 4.9 - 3*math32.Tanh(0.4*math32.Log10(lum))
+	return math32.Clamp(d, pp.MinDiam, pp.MaxDiam)
+}
+
+// Step updates the pupil diameter for one frame given the mean image
+// value (0-1 normalized, linear in luminance), and returns the
+// resulting retinal illuminance Gain.
+func (pp *Pupil) Step(meanVal float32) float32 {
+	if !pp.On {
+		pp.Gain = 1
+		return pp.Gain
+	}
+	lum := meanVal * pp.MaxLum
+	trg := pp.SteadyDiam(lum)
+	if trg < pp.Diam {
+		pp.Diam += pp.ConDt * (trg - pp.Diam)
+	} else {
+		pp.Diam += pp.DilDt * (trg - pp.Diam)
+	}
+	rd := pp.Diam / pp.RefDiam
+	pp.Gain = rd * rd
+	pp.Trolands = lum * 0.25 * math32.Pi * pp.Diam * pp.Diam
+	return pp.Gain
+}
+
+// StepImage updates the pupil for one frame based on the mean value
+// of the given image tensor, excluding padWidth of padding on the
+// two inner-most (Y, X) dimensions, and then multiplies all of the
+// image values by the resulting Gain.  The image can be grey (2D)
+// or have any number of outer components (e.g., RGB).
+func (pp *Pupil) StepImage(img *tensor.Float32, padWidth int) float32 {
+	gain := pp.Step(MeanValue(img, padWidth))
+	if gain == 1 {
+		return gain
+	}
+	for i, v := range img.Values {
+		img.Values[i] = v * gain
+	}
+	return gain
+}
+
+// MeanValue returns the mean value of given image tensor, excluding
+// padWidth of padding on the two inner-most (Y, X) dimensions.
+// The image can be grey (2D) or have any number of outer components.
+func MeanValue(img *tensor.Float32, padWidth int) float32 {
+	nd := img.NumDims()
+	sy := img.DimSize(nd - 2)
+	sx := img.DimSize(nd - 1)
+	nc := img.Len() / (sy * sx)
+	var sum float32
+	n := 0
+	for c := 0; c < nc; c++ {
+		cst := c * sy * sx
+		for y := padWidth; y < sy-padWidth; y++ {
+			for x := padWidth; x < sx-padWidth; x++ {
+				sum += img.Values[cst+y*sx+x]
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float32(n)
+}
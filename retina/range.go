@@ -0,0 +1,120 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retina
+
+import (
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// RangeNorm maps the range of values of each image between given low
+// and high percentiles linearly to the 0-1 range, with clipping, and
+// optional polarity inversion, for single-channel non-visible imagery
+// such as thermal / infrared, where the raw values have an arbitrary
+// range that is typically concentrated in a small part of the full
+// sensor range, and a few extreme values (e.g., the sun or hot engines)
+// would otherwise dominate a min-max mapping.  The values of the last
+// image at the percentiles are recorded, for monitoring.
+type RangeNorm struct {
+
+	// whether to apply range normalization
+	On bool
+
+	// low percentile of the values (0-1) that is mapped to 0
+	LoPct float32 `default:"0.01"`
+
+	// high percentile of the values (0-1) that is mapped to 1
+	HiPct float32 `default:"0.99"`
+
+	// invert the polarity of the values after mapping, e.g., for white-hot vs. black-hot thermal imagery
+	Invert bool
+
+	// value of the last image at the LoPct percentile
+	Lo float32 `edit:"-"`
+
+	// value of the last image at the HiPct percentile
+	Hi float32 `edit:"-"`
+
+	// buffer for sorting the values
+	sorted []float32
+}
+
+func (rn *RangeNorm) Defaults() {
+	rn.On = false
+	rn.LoPct = 0.01
+	rn.HiPct = 0.99
+	rn.Invert = false
+}
+
+func (rn *RangeNorm) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return rn.On
+	}
+}
+
+// Normalize maps the values of given image tensor in place, computing
+// the percentiles of the luminance (average over any outer components,
+// e.g., RGB) excluding padWidth of padding on the two inner-most (Y, X)
+// dimensions, and applying the mapping to all values including the
+// padding.  The image can be grey (2D) or have any number of outer
+// components.  Does nothing if not On.
+func (rn *RangeNorm) Normalize(img *tensor.Float32, padWidth int) {
+	if !rn.On {
+		return
+	}
+	nd := img.NumDims()
+	sy := img.DimSize(nd - 2)
+	sx := img.DimSize(nd - 1)
+	nc := img.Len() / (sy * sx)
+	rn.sorted = rn.sorted[:0]
+	for y := padWidth; y < sy-padWidth; y++ {
+		for x := padWidth; x < sx-padWidth; x++ {
+			var lum float32
+			for c := range nc {
+				lum += img.Values[c*sy*sx+y*sx+x]
+			}
+			rn.sorted = append(rn.sorted, lum/float32(nc))
+		}
+	}
+	if len(rn.sorted) == 0 {
+		return
+	}
+	slices.Sort(rn.sorted)
+	rn.Lo = Percentile(rn.sorted, rn.LoPct)
+	rn.Hi = Percentile(rn.sorted, rn.HiPct)
+	rng := rn.Hi - rn.Lo
+	for i, v := range img.Values {
+		if rng > 0 {
+			v = math32.Clamp((v-rn.Lo)/rng, 0, 1)
+		} else {
+			v = 0.5
+		}
+		if rn.Invert {
+			v = 1 - v
+		}
+		img.Values[i] = v
+	}
+}
+
+// Percentile returns the value at given percentile (0-1) of given
+// sorted values, linearly interpolating between adjacent values.
+func Percentile(sorted []float32, pct float32) float32 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	p := math32.Clamp(pct, 0, 1) * float32(n-1)
+	i := int(p)
+	if i >= n-1 {
+		return sorted[n-1]
+	}
+	f := p - float32(i)
+	return sorted[i] + f*(sorted[i+1]-sorted[i])
+}
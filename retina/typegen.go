@@ -0,0 +1,13 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package retina
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/retina.ContrastNorm", IDName: "contrast-norm", Doc: "ContrastNorm rescales each image to a target mean luminance and RMS\ncontrast (standard deviation of the luminance), so that images with\nheterogeneous exposure produce comparable V1 activation levels.\nThe luminance is the average over channels, and the same linear\ntransform is applied to all channels.  The statistics of the last\nimage, including the proportion of values clipped to the 0-1 range,\nare recorded, for monitoring the effects on a dataset.", Fields: []types.Field{{Name: "On", Doc: "whether to apply contrast normalization"}, {Name: "Mean", Doc: "target mean luminance"}, {Name: "RMS", Doc: "target RMS contrast: standard deviation of the luminance"}, {Name: "MaxGain", Doc: "maximum gain applied to the contrast, to avoid amplifying noise in nearly uniform images"}, {Name: "Clip", Doc: "clip the resulting values to the 0-1 range"}, {Name: "InMean", Doc: "mean luminance of the last image, prior to normalization"}, {Name: "InRMS", Doc: "RMS contrast of the last image, prior to normalization"}, {Name: "Gain", Doc: "contrast gain applied to the last image"}, {Name: "ClipLo", Doc: "proportion of values of the last image clipped at 0"}, {Name: "ClipHi", Doc: "proportion of values of the last image clipped at 1"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/retina.Pupil", IDName: "pupil", Doc: "Pupil models the pupil diameter as a function of mean field luminance,\nusing the Moon & Spencer (1944) steady-state function, with separate\ntime constants for constriction and dilation.\nThe resulting Gain is the retinal illuminance relative to a pupil of\nRefDiam diameter (i.e., proportional to pupil area), which should be\nmultiplied into the image prior to any adaptation or noise stages.", Fields: []types.Field{{Name: "On", Doc: "whether to apply the pupil model -- if off, Gain is always 1"}, {Name: "MaxLum", Doc: "luminance in cd/m^2 corresponding to an image value of 1 -- image values are assumed to be linear in luminance"}, {Name: "ConTau", Doc: "time constant in frames for constriction of the pupil (in response to increases in luminance) -- constriction is faster than dilation"}, {Name: "DilTau", Doc: "time constant in frames for dilation of the pupil (in response to decreases in luminance)"}, {Name: "RefDiam", Doc: "reference pupil diameter in mm at which Gain = 1 -- the default corresponds to the steady-state diameter for a mid-level (MaxLum / 2) luminance"}, {Name: "MinDiam", Doc: "minimum pupil diameter in mm"}, {Name: "MaxDiam", Doc: "maximum pupil diameter in mm"}, {Name: "Diam", Doc: "current pupil diameter in mm"}, {Name: "Gain", Doc: "current retinal illuminance gain = (Diam / RefDiam)^2"}, {Name: "Trolands", Doc: "current retinal illuminance in trolands = luminance * pupil area in mm^2"}, {Name: "ConDt", Doc: "rate = 1 / tau"}, {Name: "DilDt", Doc: "rate = 1 / tau"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/retina.RangeNorm", IDName: "range-norm", Doc: "RangeNorm maps the range of values of each image between given low\nand high percentiles linearly to the 0-1 range, with clipping, and\noptional polarity inversion, for single-channel non-visible imagery\nsuch as thermal / infrared, where the raw values have an arbitrary\nrange that is typically concentrated in a small part of the full\nsensor range, and a few extreme values (e.g., the sun or hot engines)\nwould otherwise dominate a min-max mapping.  The values of the last\nimage at the percentiles are recorded, for monitoring.", Fields: []types.Field{{Name: "On", Doc: "whether to apply range normalization"}, {Name: "LoPct", Doc: "low percentile of the values (0-1) that is mapped to 0"}, {Name: "HiPct", Doc: "high percentile of the values (0-1) that is mapped to 1"}, {Name: "Invert", Doc: "invert the polarity of the values after mapping, e.g., for white-hot vs. black-hot thermal imagery"}, {Name: "Lo", Doc: "value of the last image at the LoPct percentile"}, {Name: "Hi", Doc: "value of the last image at the HiPct percentile"}, {Name: "sorted", Doc: "buffer for sorting the values"}}})
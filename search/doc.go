@@ -0,0 +1,18 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package search generates visual search array stimuli, for attention
+and search modeling with the V1 filtering and kWTA stages.
+
+An Array places a target item (if present) and distractor items at
+random cells of a grid, with random position jitter within each cell,
+drawing them onto a background RGB [3, Y, X] image tensor.  Items are
+oriented bars, colored squares and circles, or T and L shapes, at any
+angle and color, so that classic feature (e.g., color or orientation
+pop-out) and conjunction (e.g., T among Ls) searches can be constructed.
+The ground-truth target location is recorded as a pixel position and
+as a map over grid cells.
+*/
+package search
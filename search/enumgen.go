@@ -0,0 +1,50 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package search
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _ItemTypesValues = []ItemTypes{0, 1, 2, 3, 4}
+
+// ItemTypesN is the highest valid value for type ItemTypes, plus one.
+const ItemTypesN ItemTypes = 5
+
+var _ItemTypesValueMap = map[string]ItemTypes{`Bar`: 0, `Square`: 1, `Circle`: 2, `T`: 3, `L`: 4}
+
+var _ItemTypesDescMap = map[ItemTypes]string{0: `Bar is an oriented bar, Width wide, with Angle = 0 horizontal`, 1: `Square is a filled square`, 2: `Circle is a filled circle`, 3: `T is a T shape, with strokes Width wide, upright at Angle = 0`, 4: `L is an L shape, with strokes Width wide, upright at Angle = 0`}
+
+var _ItemTypesMap = map[ItemTypes]string{0: `Bar`, 1: `Square`, 2: `Circle`, 3: `T`, 4: `L`}
+
+// String returns the string representation of this ItemTypes value.
+func (i ItemTypes) String() string { return enums.String(i, _ItemTypesMap) }
+
+// SetString sets the ItemTypes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ItemTypes) SetString(s string) error {
+	return enums.SetString(i, s, _ItemTypesValueMap, "ItemTypes")
+}
+
+// Int64 returns the ItemTypes value as an int64.
+func (i ItemTypes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ItemTypes value from an int64.
+func (i *ItemTypes) SetInt64(in int64) { *i = ItemTypes(in) }
+
+// Desc returns the description of the ItemTypes value.
+func (i ItemTypes) Desc() string { return enums.Desc(i, _ItemTypesDescMap) }
+
+// ItemTypesValues returns all possible values for the type ItemTypes.
+func ItemTypesValues() []ItemTypes { return _ItemTypesValues }
+
+// Values returns all possible values for the type ItemTypes.
+func (i ItemTypes) Values() []enums.Enum { return enums.Values(_ItemTypesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ItemTypes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ItemTypes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ItemTypes")
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// ItemTypes are the types of search array items
+type ItemTypes int32 //enums:enum
+
+const (
+	// Bar is an oriented bar, Width wide, with Angle = 0 horizontal
+	Bar ItemTypes = iota
+
+	// Square is a filled square
+	Square
+
+	// Circle is a filled circle
+	Circle
+
+	// T is a T shape, with strokes Width wide, upright at Angle = 0
+	T
+
+	// L is an L shape, with strokes Width wide, upright at Angle = 0
+	L
+)
+
+// Item specifies a search array item
+type Item struct {
+
+	// type of item
+	Type ItemTypes
+
+	// rotation angle in degrees, counter-clockwise
+	Angle float32
+
+	// RGB color, with values 0-1
+	Color [3]float32
+
+	// width of bars and strokes, as a proportion of the item size
+	Width float32 `default:"0.2"`
+}
+
+// Placed is an item placed in an array
+type Placed struct {
+
+	// the item
+	Item Item
+
+	// center position, in tensor X, Y coordinates
+	Pos image.Point
+
+	// grid cell
+	Cell image.Point
+}
+
+// Array generates search arrays of a Target among Distractors,
+// placed at random cells of a Grid, with random Jitter.
+type Array struct {
+
+	// number of grid cells in each dimension -- must have at least SetSize cells in total
+	Grid image.Point
+
+	// set size: total number of items, including the target if present
+	SetSize int `default:"8"`
+
+	// size of each item, in pixels
+	ItemSize int `default:"16"`
+
+	// random jitter of each item position, as a proportion of the free space in the cell around the item
+	Jitter float32 `default:"1"`
+
+	// probability that the target is present in each array
+	Present float32 `default:"1"`
+
+	// the target item
+	Target Item
+
+	// distractor items, which are selected at random for each distractor position
+	Distractors []Item
+
+	// items placed by the last Generate, with the target first if present
+	Items []Placed `edit:"-"`
+
+	// whether the target is present in the last Generate
+	TargetPresent bool `edit:"-"`
+
+	// center position of the target, in tensor X, Y coordinates, from the last Generate -- (-1, -1) if not present
+	TargetPos image.Point `edit:"-"`
+
+	// target location over grid cells: [Grid.Y, Grid.X], with 1 at the target cell and 0 elsewhere
+	TargetMap tensor.Float32 `display:"no-inline"`
+}
+
+func (sa *Array) Defaults() {
+	sa.Grid = image.Point{6, 6}
+	sa.SetSize = 8
+	sa.ItemSize = 16
+	sa.Jitter = 1
+	sa.Present = 1
+	sa.Target = Item{Type: Bar, Angle: 45, Color: [3]float32{1, 1, 1}, Width: 0.2}
+	sa.Distractors = []Item{{Type: Bar, Angle: 135, Color: [3]float32{1, 1, 1}, Width: 0.2}}
+}
+
+// SetBackground sets given RGB image tensor to given size and
+// uniform color, for use as the background in Generate.
+func SetBackground(img *tensor.Float32, size image.Point, clr [3]float32) {
+	img.SetShapeSizes(3, size.Y, size.X)
+	n := size.Y * size.X
+	for c := 0; c < 3; c++ {
+		vals := img.Values[c*n : (c+1)*n]
+		for i := range vals {
+			vals[i] = clr[c]
+		}
+	}
+}
+
+// Generate draws a new search array onto given RGB [3, Y, X]
+// background image tensor, using given random number generator
+// (global rand if nil), recording the placed Items and ground-truth
+// target location.
+func (sa *Array) Generate(img *tensor.Float32, rnd *rand.Rand) {
+	rf := rand.Float32
+	perm := rand.Perm
+	if rnd != nil {
+		rf = rnd.Float32
+		perm = rnd.Perm
+	}
+	ncell := sa.Grid.X * sa.Grid.Y
+	nitem := min(sa.SetSize, ncell)
+	sa.TargetMap.SetShapeSizes(sa.Grid.Y, sa.Grid.X)
+	sa.TargetMap.SetZeros()
+	sa.TargetPresent = rf() < sa.Present
+	sa.TargetPos = image.Point{-1, -1}
+	sa.Items = sa.Items[:0]
+
+	ny := img.DimSize(1)
+	nx := img.DimSize(2)
+	csz := image.Point{nx / sa.Grid.X, ny / sa.Grid.Y}
+	free := image.Point{max(csz.X-sa.ItemSize, 0), max(csz.Y-sa.ItemSize, 0)}
+	cells := perm(ncell)[:nitem]
+	for i, ci := range cells {
+		cell := image.Point{ci % sa.Grid.X, ci / sa.Grid.X}
+		var it Item
+		switch {
+		case i == 0 && sa.TargetPresent:
+			it = sa.Target
+		case len(sa.Distractors) > 0:
+			it = sa.Distractors[min(int(rf()*float32(len(sa.Distractors))), len(sa.Distractors)-1)]
+		default:
+			continue
+		}
+		jx := sa.Jitter * (rf() - 0.5) * float32(free.X)
+		jy := sa.Jitter * (rf() - 0.5) * float32(free.Y)
+		pos := image.Point{cell.X*csz.X + csz.X/2 + int(math32.Round(jx)), cell.Y*csz.Y + csz.Y/2 + int(math32.Round(jy))}
+		sa.Items = append(sa.Items, Placed{Item: it, Pos: pos, Cell: cell})
+		DrawItem(img, &it, pos, sa.ItemSize)
+		if i == 0 && sa.TargetPresent {
+			sa.TargetPos = pos
+			sa.TargetMap.Set(1, cell.Y, cell.X)
+		}
+	}
+}
+
+// DrawItem draws given item onto given RGB [3, Y, X] image tensor,
+// centered at given position in tensor X, Y coordinates, at given size
+// in pixels, blending the item color according to the proportion of
+// each pixel covered, using 3x3 supersampling.
+func DrawItem(img *tensor.Float32, it *Item, pos image.Point, size int) {
+	ny := img.DimSize(1)
+	nx := img.DimSize(2)
+	n := ny * nx
+	half := 0.5 * float32(size)
+	ext := int(math32.Ceil(half * math32.Sqrt2))
+	rad := math32.DegToRad(it.Angle)
+	cos := math32.Cos(rad)
+	sin := math32.Sin(rad)
+	const nsub = 3
+	for y := max(pos.Y-ext, 0); y <= min(pos.Y+ext, ny-1); y++ {
+		for x := max(pos.X-ext, 0); x <= min(pos.X+ext, nx-1); x++ {
+			cnt := 0
+			for sy := 0; sy < nsub; sy++ {
+				dy := float32(y-pos.Y) + (float32(sy)+0.5)/nsub - 0.5
+				for sx := 0; sx < nsub; sx++ {
+					dx := float32(x-pos.X) + (float32(sx)+0.5)/nsub - 0.5
+					u := dx*cos + dy*sin
+					v := dy*cos - dx*sin
+					if it.Inside(u/half, v/half) {
+						cnt++
+					}
+				}
+			}
+			if cnt == 0 {
+				continue
+			}
+			cov := float32(cnt) / (nsub * nsub)
+			for c := 0; c < 3; c++ {
+				iv := &img.Values[c*n+y*nx+x]
+				*iv = (1-cov)**iv + cov*it.Color[c]
+			}
+		}
+	}
+}
+
+// Inside returns whether given point, in item coordinates normalized
+// so that the item extends from -1 to 1 in each dimension, with u
+// along the item's horizontal axis and v along its vertical axis,
+// is inside the item.
+func (it *Item) Inside(u, v float32) bool {
+	w := it.Width
+	au := math32.Abs(u)
+	av := math32.Abs(v)
+	switch it.Type {
+	case Bar:
+		return au <= 1 && av <= w
+	case Square:
+		return au <= 1 && av <= 1
+	case Circle:
+		return u*u+v*v <= 1
+	case T:
+		return (au <= 1 && v >= 1-2*w && v <= 1) || (au <= w && av <= 1)
+	case L:
+		return (u >= -1 && u <= -1+2*w && av <= 1) || (au <= 1 && v >= -1 && v <= -1+2*w)
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package search
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/search.ItemTypes", IDName: "item-types", Doc: "ItemTypes are the types of search array items"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/search.Item", IDName: "item", Doc: "Item specifies a search array item", Fields: []types.Field{{Name: "Type", Doc: "type of item"}, {Name: "Angle", Doc: "rotation angle in degrees, counter-clockwise"}, {Name: "Color", Doc: "RGB color, with values 0-1"}, {Name: "Width", Doc: "width of bars and strokes, as a proportion of the item size"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/search.Placed", IDName: "placed", Doc: "Placed is an item placed in an array", Fields: []types.Field{{Name: "Item", Doc: "the item"}, {Name: "Pos", Doc: "center position, in tensor X, Y coordinates"}, {Name: "Cell", Doc: "grid cell"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/search.Array", IDName: "array", Doc: "Array generates search arrays of a Target among Distractors,\nplaced at random cells of a Grid, with random Jitter.", Fields: []types.Field{{Name: "Grid", Doc: "number of grid cells in each dimension -- must have at least SetSize cells in total"}, {Name: "SetSize", Doc: "set size: total number of items, including the target if present"}, {Name: "ItemSize", Doc: "size of each item, in pixels"}, {Name: "Jitter", Doc: "random jitter of each item position, as a proportion of the free space in the cell around the item"}, {Name: "Present", Doc: "probability that the target is present in each array"}, {Name: "Target", Doc: "the target item"}, {Name: "Distractors", Doc: "distractor items, which are selected at random for each distractor position"}, {Name: "Items", Doc: "items placed by the last Generate, with the target first if present"}, {Name: "TargetPresent", Doc: "whether the target is present in the last Generate"}, {Name: "TargetPos", Doc: "center position of the target, in tensor X, Y coordinates, from the last Generate -- (-1, -1) if not present"}, {Name: "TargetMap", Doc: "target location over grid cells: [Grid.Y, Grid.X], with 1 at the target cell and 0 elsewhere"}}})
@@ -0,0 +1,20 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package simil provides similarity and dissimilarity metrics between
+pairs of V1 filter output tensors, e.g., V1All tensors of shape
+[Y, X, Rows, Angle], so that image-pair generalization and invariance
+analyses can be run directly on the filter outputs.
+
+* Cosine and Correlation compare the full tensors.
+
+* RowCorrelation computes the correlation separately for each feature
+row (e.g., length-sum, end-stop, simple-cell polarities).
+
+* OrientEMD computes the earth mover's distance between the orientation
+distributions, which is sensitive to how far orientations have shifted,
+not just whether they match.
+*/
+package simil
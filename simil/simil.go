@@ -0,0 +1,193 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simil
+
+import (
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Cosine returns the cosine similarity between the values of two
+// same-sized tensors: sum(a*b) / sqrt(sum(a^2) * sum(b^2)),
+// which is 0 if either tensor is all zeros.
+func Cosine(a, b *tensor.Float32) float32 {
+	return cosine(a.Values, b.Values)
+}
+
+// CosineDist returns the cosine dissimilarity: 1 - Cosine
+func CosineDist(a, b *tensor.Float32) float32 {
+	return 1 - Cosine(a, b)
+}
+
+// Correlation returns the Pearson correlation between the values
+// of two same-sized tensors, i.e., the cosine of the mean-subtracted
+// values, which is 0 if either tensor has no variance.
+func Correlation(a, b *tensor.Float32) float32 {
+	return correlation(a.Values, b.Values, 1, 1)
+}
+
+// CorrelationDist returns the correlation dissimilarity: 1 - Correlation
+func CorrelationDist(a, b *tensor.Float32) float32 {
+	return 1 - Correlation(a, b)
+}
+
+// RowCorrelation computes the Pearson correlation separately for each
+// feature row of two same-shaped [Y, X, Rows, Angle] tensors (e.g.,
+// V1All), over all Y, X positions and angles, returning a slice
+// with one correlation per row.
+func RowCorrelation(a, b *tensor.Float32) []float32 {
+	nrows := a.DimSize(2)
+	nang := a.DimSize(3)
+	rc := make([]float32, nrows)
+	for r := range rc {
+		rc[r] = correlation(a.Values[r*nang:], b.Values[r*nang:], nang, nrows*nang)
+	}
+	return rc
+}
+
+// OrientHist computes the orientation distribution of a
+// [Y, X, Rows, Angle] tensor, as the sum over positions and rows of
+// the (positive) activations for each angle, normalized to sum to 1.
+// If pos is non-nil, only the position y, x is used.
+func OrientHist(tsr *tensor.Float32, pos *[2]int) []float32 {
+	nrows := tsr.DimSize(2)
+	nang := tsr.DimSize(3)
+	nf := nrows * nang
+	hist := make([]float32, nang)
+	vals := tsr.Values
+	if pos != nil {
+		st := (pos[0]*tsr.DimSize(1) + pos[1]) * nf
+		vals = vals[st : st+nf]
+	}
+	for i, v := range vals {
+		if v > 0 {
+			hist[i%nang] += v
+		}
+	}
+	var sum float32
+	for _, v := range hist {
+		sum += v
+	}
+	if sum > 0 {
+		for i := range hist {
+			hist[i] /= sum
+		}
+	}
+	return hist
+}
+
+// OrientEMD returns the earth mover's distance between the overall
+// orientation distributions (OrientHist) of two [Y, X, Rows, Angle]
+// tensors, which have the same number of angles, in units of angle
+// steps.  Orientation is circular, with the last angle adjacent to
+// the first, so the maximum distance is NAngles / 2 (for 2 orthogonal
+// orientations, e.g., horizontal vs. vertical).
+func OrientEMD(a, b *tensor.Float32) float32 {
+	return CircEMD(OrientHist(a, nil), OrientHist(b, nil))
+}
+
+// OrientEMDLocal returns the average over Y, X positions of the
+// earth mover's distance between the orientation distributions
+// at each position, weighted by the total activation at each
+// position (summed over both tensors), so that empty positions
+// do not contribute.
+func OrientEMDLocal(a, b *tensor.Float32) float32 {
+	sy := a.DimSize(0)
+	sx := a.DimSize(1)
+	nf := a.DimSize(2) * a.DimSize(3)
+	var sum, wsum float32
+	for y := 0; y < sy; y++ {
+		for x := 0; x < sx; x++ {
+			st := (y*sx + x) * nf
+			var wt float32
+			for i := st; i < st+nf; i++ {
+				wt += math32.Max(a.Values[i], 0) + math32.Max(b.Values[i], 0)
+			}
+			if wt == 0 {
+				continue
+			}
+			pos := [2]int{y, x}
+			sum += wt * CircEMD(OrientHist(a, &pos), OrientHist(b, &pos))
+			wsum += wt
+		}
+	}
+	if wsum == 0 {
+		return 0
+	}
+	return sum / wsum
+}
+
+// CircEMD returns the earth mover's distance between two circular
+// distributions over the same number of bins, each of which should
+// sum to 1, in units of bins.  This is the minimum over circular
+// shifts of the L1 norm of the cumulative difference, which is
+// attained at its median (Rabin et al., 2008).
+func CircEMD(p, q []float32) float32 {
+	n := len(p)
+	cum := make([]float32, n)
+	var c float32
+	for i := range n {
+		c += p[i] - q[i]
+		cum[i] = c
+	}
+	srt := slices.Clone(cum)
+	slices.Sort(srt)
+	med := srt[n/2]
+	var emd float32
+	for _, v := range cum {
+		emd += math32.Abs(v - med)
+	}
+	return emd
+}
+
+// cosine returns the cosine similarity between two slices
+func cosine(a, b []float32) float32 {
+	var ab, aa, bb float32
+	for i, av := range a {
+		bv := b[i]
+		ab += av * bv
+		aa += av * av
+		bb += bv * bv
+	}
+	if aa == 0 || bb == 0 {
+		return 0
+	}
+	return ab / math32.Sqrt(aa*bb)
+}
+
+// correlation returns the Pearson correlation between values in
+// two slices, using runs of n values starting every stride values
+func correlation(a, b []float32, n, stride int) float32 {
+	var am, bm float32
+	cnt := 0
+	for st := 0; st+n <= len(a); st += stride {
+		for i := st; i < st+n; i++ {
+			am += a[i]
+			bm += b[i]
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return 0
+	}
+	am /= float32(cnt)
+	bm /= float32(cnt)
+	var ab, aa, bb float32
+	for st := 0; st+n <= len(a); st += stride {
+		for i := st; i < st+n; i++ {
+			ad := a[i] - am
+			bd := b[i] - bm
+			ab += ad * bd
+			aa += ad * ad
+			bb += bd * bd
+		}
+	}
+	if aa == 0 || bb == 0 {
+		return 0
+	}
+	return ab / math32.Sqrt(aa*bb)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package smooth
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// Bilateral specifies bilateral filtering, where each pixel is replaced
+// by the weighted average of its neighbors within Radius, with weights
+// given by the product of a gaussian of the spatial distance and a
+// gaussian of the difference in value, so that neighbors across an edge
+// contribute little, and edges are preserved.
+type Bilateral struct {
+
+	// whether to do bilateral filtering
+	On bool
+
+	// radius of the neighborhood, in pixels -- typically 2 * SigSpace
+	Radius int `default:"3"`
+
+	// gaussian sigma of the spatial weighting, in pixels
+	SigSpace float32 `default:"1.5"`
+
+	// gaussian sigma of the value (range) weighting, in image value units -- differences above this are treated as edges
+	SigRange float32 `default:"0.1"`
+}
+
+func (bf *Bilateral) Defaults() {
+	bf.On = true
+	bf.Radius = 3
+	bf.SigSpace = 1.5
+	bf.SigRange = 0.1
+}
+
+func (bf *Bilateral) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return bf.On
+	}
+}
+
+// Filter applies bilateral filtering to the in image, into out, which
+// can be the same as in.  Neighbors outside the image boundaries are
+// excluded.  If not On, out is just a copy of in.
+func (bf *Bilateral) Filter(in, out *tensor.Float32) {
+	src := in.Clone().(*tensor.Float32)
+	tensor.SetShapeFrom(out, in)
+	if !bf.On {
+		out.CopyFrom(src)
+		return
+	}
+	rad := bf.Radius
+	sw := 2*rad + 1
+	spc := make([]float32, sw*sw)
+	spNorm := 1 / (2 * bf.SigSpace * bf.SigSpace)
+	for dy := -rad; dy <= rad; dy++ {
+		for dx := -rad; dx <= rad; dx++ {
+			spc[(dy+rad)*sw+dx+rad] = math32.FastExp(-float32(dy*dy+dx*dx) * spNorm)
+		}
+	}
+	ny, _ := imageSize(in)
+	nproc.Run("Bilateral", ny, func(st, n int) {
+		bf.filterThr(st, n, spc, src, out)
+	})
+}
+
+// filterThr is per-thread implementation over ny rows starting at yst,
+// with spatial weights spc.
+func (bf *Bilateral) filterThr(yst, ny int, spc []float32, in, out *tensor.Float32) {
+	sy, sx := imageSize(in)
+	nc := in.Len() / (sy * sx)
+	vals := in.Values
+	rad := bf.Radius
+	sw := 2*rad + 1
+	rgNorm := 1 / (2 * bf.SigRange * bf.SigRange * float32(nc))
+	sums := make([]float32, nc)
+	for y := yst; y < yst+ny; y++ {
+		for x := 0; x < sx; x++ {
+			pi := y*sx + x
+			for c := range sums {
+				sums[c] = 0
+			}
+			wsum := float32(0)
+			for dy := -rad; dy <= rad; dy++ {
+				yy := y + dy
+				if yy < 0 || yy >= sy {
+					continue
+				}
+				for dx := -rad; dx <= rad; dx++ {
+					xx := x + dx
+					if xx < 0 || xx >= sx {
+						continue
+					}
+					ni := yy*sx + xx
+					d2 := float32(0)
+					for c := range nc {
+						d := vals[c*sy*sx+ni] - vals[c*sy*sx+pi]
+						d2 += d * d
+					}
+					wt := spc[(dy+rad)*sw+dx+rad] * math32.FastExp(-d2*rgNorm)
+					wsum += wt
+					for c := range nc {
+						sums[c] += wt * vals[c*sy*sx+ni]
+					}
+				}
+			}
+			for c := range nc {
+				out.Values[c*sy*sx+pi] = sums[c] / wsum
+			}
+		}
+	}
+}
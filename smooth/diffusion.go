@@ -0,0 +1,156 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package smooth provides edge-preserving smoothing of grey or RGB image
+tensors, to denoise inputs (e.g., noisy sensor data) without destroying
+the edges that drive gabor filtering: Diffusion does Perona-Malik
+anisotropic diffusion, and Bilateral does bilateral filtering.
+
+Images can be grey (2D [Y, X]) or have any number of outer components
+(e.g., RGB [3, Y, X]), in which case the edges are determined jointly
+over the components, so that the smoothing is the same for each.
+*/
+package smooth
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// Conductances are the edge-stopping conduction functions for Diffusion
+type Conductances int32 //enums:enum
+
+const (
+	// ExpConduct is exp(-(g/K)^2), which favors high-contrast edges
+	// over low-contrast ones.
+	ExpConduct Conductances = iota
+
+	// QuadConduct is 1 / (1 + (g/K)^2), which favors wide regions
+	// over smaller ones.
+	QuadConduct
+)
+
+// Diffusion specifies Perona-Malik anisotropic diffusion, which
+// iteratively diffuses image values between neighboring pixels,
+// with a conductance that decreases with the gradient between them,
+// so that uniform regions are smoothed while edges are preserved.
+type Diffusion struct {
+
+	// whether to do diffusion
+	On bool
+
+	// number of diffusion iterations -- more iterations produce more smoothing
+	Iters int `default:"10"`
+
+	// gradient magnitude (in image value units) at which the conductance is substantially reduced -- gradients above this are treated as edges
+	K float32 `default:"0.1"`
+
+	// rate of diffusion per iteration -- must be <= 0.25 for stability
+	Lambda float32 `default:"0.2" max:"0.25"`
+
+	// edge-stopping conduction function
+	Conduct Conductances
+}
+
+func (df *Diffusion) Defaults() {
+	df.On = true
+	df.Iters = 10
+	df.K = 0.1
+	df.Lambda = 0.2
+	df.Conduct = ExpConduct
+}
+
+func (df *Diffusion) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return df.On
+	}
+}
+
+// Conductance returns the conductance for given squared gradient
+func (df *Diffusion) Conductance(g2 float32) float32 {
+	r := g2 / (df.K * df.K)
+	if df.Conduct == QuadConduct {
+		return 1 / (1 + r)
+	}
+	return math32.FastExp(-r)
+}
+
+// Filter applies Iters of diffusion to the in image, into out, which
+// can be the same as in.  There is no diffusion across the image
+// boundaries.  If not On, out is just a copy of in.
+func (df *Diffusion) Filter(in, out *tensor.Float32) {
+	cur := in.Clone().(*tensor.Float32)
+	if !df.On {
+		tensor.SetShapeFrom(out, in)
+		out.CopyFrom(cur)
+		return
+	}
+	nxt := cur.Clone().(*tensor.Float32)
+	ny, _ := imageSize(in)
+	for range df.Iters {
+		nproc.Run("Diffusion", ny, func(st, n int) {
+			df.filterThr(st, n, cur, nxt)
+		})
+		cur, nxt = nxt, cur
+	}
+	tensor.SetShapeFrom(out, in)
+	out.CopyFrom(cur)
+}
+
+// filterThr is per-thread implementation of one iteration of
+// diffusion over ny rows starting at yst, from cur into nxt.
+func (df *Diffusion) filterThr(yst, ny int, cur, nxt *tensor.Float32) {
+	sy, sx := imageSize(cur)
+	nc := cur.Len() / (sy * sx)
+	vals := cur.Values
+	nbrs := [4]image.Point{{0, -1}, {0, 1}, {-1, 0}, {1, 0}}
+	var cd [4]float32
+	for y := yst; y < yst+ny; y++ {
+		for x := 0; x < sx; x++ {
+			pi := y*sx + x
+			for ni, nb := range nbrs {
+				yy, xx := y+nb.Y, x+nb.X
+				cd[ni] = 0
+				if yy < 0 || yy >= sy || xx < 0 || xx >= sx {
+					continue
+				}
+				ni2 := yy*sx + xx
+				g2 := float32(0)
+				for c := range nc {
+					d := vals[c*sy*sx+ni2] - vals[c*sy*sx+pi]
+					g2 += d * d
+				}
+				cd[ni] = df.Conductance(g2 / float32(nc))
+			}
+			for c := range nc {
+				ci := c*sy*sx + pi
+				v := vals[ci]
+				sum := float32(0)
+				for ni, nb := range nbrs {
+					if cd[ni] == 0 {
+						continue
+					}
+					sum += cd[ni] * (vals[c*sy*sx+(y+nb.Y)*sx+x+nb.X] - v)
+				}
+				nxt.Values[ci] = v + df.Lambda*sum
+			}
+		}
+	}
+}
+
+// imageSize returns the size of the two inner-most (Y, X) dimensions
+// of given image tensor.
+func imageSize(img *tensor.Float32) (sy, sx int) {
+	nd := img.NumDims()
+	return img.DimSize(nd - 2), img.DimSize(nd - 1)
+}
@@ -0,0 +1,50 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package smooth
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _ConductancesValues = []Conductances{0, 1}
+
+// ConductancesN is the highest valid value for type Conductances, plus one.
+const ConductancesN Conductances = 2
+
+var _ConductancesValueMap = map[string]Conductances{`ExpConduct`: 0, `QuadConduct`: 1}
+
+var _ConductancesDescMap = map[Conductances]string{0: `ExpConduct is exp(-(g/K)^2), which favors high-contrast edges over low-contrast ones.`, 1: `QuadConduct is 1 / (1 + (g/K)^2), which favors wide regions over smaller ones.`}
+
+var _ConductancesMap = map[Conductances]string{0: `ExpConduct`, 1: `QuadConduct`}
+
+// String returns the string representation of this Conductances value.
+func (i Conductances) String() string { return enums.String(i, _ConductancesMap) }
+
+// SetString sets the Conductances value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Conductances) SetString(s string) error {
+	return enums.SetString(i, s, _ConductancesValueMap, "Conductances")
+}
+
+// Int64 returns the Conductances value as an int64.
+func (i Conductances) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Conductances value from an int64.
+func (i *Conductances) SetInt64(in int64) { *i = Conductances(in) }
+
+// Desc returns the description of the Conductances value.
+func (i Conductances) Desc() string { return enums.Desc(i, _ConductancesDescMap) }
+
+// ConductancesValues returns all possible values for the type Conductances.
+func ConductancesValues() []Conductances { return _ConductancesValues }
+
+// Values returns all possible values for the type Conductances.
+func (i Conductances) Values() []enums.Enum { return enums.Values(_ConductancesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Conductances) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Conductances) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "Conductances")
+}
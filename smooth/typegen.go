@@ -0,0 +1,13 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package smooth
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/smooth.Bilateral", IDName: "bilateral", Doc: "Bilateral specifies bilateral filtering, where each pixel is replaced\nby the weighted average of its neighbors within Radius, with weights\ngiven by the product of a gaussian of the spatial distance and a\ngaussian of the difference in value, so that neighbors across an edge\ncontribute little, and edges are preserved.", Fields: []types.Field{{Name: "On", Doc: "whether to do bilateral filtering"}, {Name: "Radius", Doc: "radius of the neighborhood, in pixels -- typically 2 * SigSpace"}, {Name: "SigSpace", Doc: "gaussian sigma of the spatial weighting, in pixels"}, {Name: "SigRange", Doc: "gaussian sigma of the value (range) weighting, in image value units -- differences above this are treated as edges"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/smooth.Conductances", IDName: "conductances", Doc: "Conductances are the edge-stopping conduction functions for Diffusion"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/smooth.Diffusion", IDName: "diffusion", Doc: "Diffusion specifies Perona-Malik anisotropic diffusion, which\niteratively diffuses image values between neighboring pixels,\nwith a conductance that decreases with the gradient between them,\nso that uniform regions are smoothed while edges are preserved.", Fields: []types.Field{{Name: "On", Doc: "whether to do diffusion"}, {Name: "Iters", Doc: "number of diffusion iterations -- more iterations produce more smoothing"}, {Name: "K", Doc: "gradient magnitude (in image value units) at which the conductance is substantially reduced -- gradients above this are treated as edges"}, {Name: "Lambda", Doc: "rate of diffusion per iteration -- must be <= 0.25 for stability"}, {Name: "Conduct", Doc: "edge-stopping conduction function"}}})
@@ -0,0 +1,291 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+package steerpyr provides a steerable pyramid decomposition of images
+(Simoncelli & Freeman, 1995): a multi-scale, multi-orientation,
+overcomplete and invertible decomposition, computed in the frequency
+domain, as a principled alternative to resizing and filtering an image
+at multiple scales.
+
+The image is split into a high-pass residual and a low-pass band,
+which is then recursively split into NAngles oriented bands and a
+low-pass band, which is downsampled by 2 for the next level, with
+the final low-pass band as the low-pass residual.  The radial and
+angular filters satisfy the condition that the sum of their squares
+is 1, so that Synthesize exactly inverts Analyze.
+*/
+package steerpyr
+
+//go:generate core generate -add-types
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/imgstats"
+)
+
+// Pyramid specifies a steerable pyramid, and holds the bands
+// from the last Analyze.
+type Pyramid struct {
+
+	// number of levels (scales) of oriented bands, each half the size of the previous one -- the image size must be divisible by 2^Levels
+	Levels int `default:"4"`
+
+	// number of orientations of bands at each level -- first angle is always horizontal, as in gabor -- the angular filters are cos^(NAngles-1), so more angles produce more narrowly tuned bands
+	NAngles int `default:"4"`
+
+	// high-pass residual: [Y, X]
+	HiResid tensor.Float32 `display:"no-inline"`
+
+	// oriented bands for each level, from fine to coarse: [Angle, Y, X], with Y, X half the size of the previous level
+	Bands []tensor.Float32 `display:"no-inline"`
+
+	// low-pass residual, at half the size of the last level: [Y, X]
+	LoResid tensor.Float32 `display:"no-inline"`
+}
+
+func (sp *Pyramid) Defaults() {
+	sp.Levels = 4
+	sp.NAngles = 4
+}
+
+// MaxLevels returns the maximum number of levels for given image size,
+// which must be divisible by 2^Levels, with at least 4 pixels in each
+// dimension at the last level.
+func MaxLevels(ny, nx int) int {
+	lev := 0
+	for ny%2 == 0 && nx%2 == 0 && ny >= 8 && nx >= 8 {
+		ny /= 2
+		nx /= 2
+		lev++
+	}
+	return lev
+}
+
+// Analyze computes the steerable pyramid decomposition of given
+// grey-scale 2D [Y, X] image, into HiResid, Bands and LoResid.
+func (sp *Pyramid) Analyze(img *tensor.Float32) error {
+	ny := img.DimSize(0)
+	nx := img.DimSize(1)
+	if ml := MaxLevels(ny, nx); sp.Levels > ml {
+		return fmt.Errorf("steerpyr.Analyze: image size %d x %d only supports %d levels, not %d", ny, nx, ml, sp.Levels)
+	}
+	dft := make([]complex128, ny*nx)
+	for i, v := range img.Values {
+		dft[i] = complex(float64(v), 0)
+	}
+	imgstats.FFT2D(dft, ny, nx)
+
+	hi := make([]complex128, len(dft))
+	for i := range dft {
+		l := logRad(i, ny, nx)
+		hi[i] = dft[i] * complex(hiMask(l-1), 0)
+		dft[i] *= complex(loMask(l-1), 0)
+	}
+	toReal(hi, ny, nx, &sp.HiResid)
+
+	sp.Bands = make([]tensor.Float32, sp.Levels)
+	band := make([]complex128, len(dft))
+	ph := cmplx.Pow(complex(0, -1), complex(float64(sp.NAngles-1), 0))
+	for lev := range sp.Levels {
+		bt := &sp.Bands[lev]
+		bt.SetShapeSizes(sp.NAngles, ny, nx)
+		for ang := range sp.NAngles {
+			for i := range dft {
+				band[i] = dft[i] * ph * complex(hiMask(logRad(i, ny, nx))*sp.angleMask(ang, i, ny, nx), 0)
+			}
+			imgstats.IFFT2D(band[:ny*nx], ny, nx)
+			for i := range ny * nx {
+				bt.Values[ang*ny*nx+i] = float32(real(band[i]))
+			}
+		}
+		for i := range dft {
+			dft[i] *= complex(loMask(logRad(i, ny, nx)), 0)
+		}
+		dft = downsample(dft, ny, nx)
+		ny /= 2
+		nx /= 2
+	}
+	toReal(dft, ny, nx, &sp.LoResid)
+	return nil
+}
+
+// Synthesize reconstructs the image from the current HiResid, Bands
+// and LoResid into given grey-scale 2D [Y, X] image, which is an exact
+// inverse of Analyze (up to numerical precision) if the bands have
+// not been modified.
+func (sp *Pyramid) Synthesize(img *tensor.Float32) {
+	ny := sp.LoResid.DimSize(0)
+	nx := sp.LoResid.DimSize(1)
+	dft := toDFT(&sp.LoResid)
+	for lev := len(sp.Bands) - 1; lev >= 0; lev-- {
+		dft = upsample(dft, ny, nx)
+		ny *= 2
+		nx *= 2
+		for i := range dft {
+			dft[i] *= complex(loMask(logRad(i, ny, nx)), 0)
+		}
+		bt := &sp.Bands[lev]
+		nang := bt.DimSize(0)
+		ph := cmplx.Pow(complex(0, 1), complex(float64(nang-1), 0))
+		band := make([]complex128, ny*nx)
+		for ang := range nang {
+			for i := range band {
+				band[i] = complex(float64(bt.Values[ang*ny*nx+i]), 0)
+			}
+			imgstats.FFT2D(band, ny, nx)
+			for i := range dft {
+				dft[i] += band[i] * ph * complex(hiMask(logRad(i, ny, nx))*sp.angleMask(ang, i, ny, nx), 0)
+			}
+		}
+	}
+	hi := toDFT(&sp.HiResid)
+	for i := range dft {
+		l := logRad(i, ny, nx)
+		dft[i] = dft[i]*complex(loMask(l-1), 0) + hi[i]*complex(hiMask(l-1), 0)
+	}
+	toReal(dft, ny, nx, img)
+}
+
+// logRad returns the log2 of the radial frequency for given index into
+// an [ny, nx] DFT, normalized so that the Nyquist frequency is 1 (0 in log2)
+// along each axis.
+func logRad(i, ny, nx int) float64 {
+	fy, fx := freqs(i, ny, nx)
+	return 0.5 * math.Log2(fy*fy+fx*fx)
+}
+
+// freqs returns the Y, X frequencies for given index into an [ny, nx] DFT,
+// normalized so that the Nyquist frequency is 1 along each axis.
+func freqs(i, ny, nx int) (fy, fx float64) {
+	y := i / nx
+	x := i % nx
+	if y >= ny/2 {
+		y -= ny
+	}
+	if x >= nx/2 {
+		x -= nx
+	}
+	return float64(2*y) / float64(ny), float64(2*x) / float64(nx)
+}
+
+// hiMask is the radial high-pass filter for given log2 radial frequency,
+// with a raised-cosine transition over the octave from -2 to -1.
+func hiMask(l float64) float64 {
+	switch {
+	case l <= -2:
+		return 0
+	case l >= -1:
+		return 1
+	}
+	return math.Cos(0.5 * math.Pi * (-1 - l))
+}
+
+// loMask is the radial low-pass filter for given log2 radial frequency,
+// complementary to hiMask: hiMask^2 + loMask^2 = 1.
+func loMask(l float64) float64 {
+	switch {
+	case l <= -2:
+		return 1
+	case l >= -1:
+		return 0
+	}
+	return math.Cos(0.5 * math.Pi * (l + 2))
+}
+
+// angleMask is the angular filter for given angle at given index into an
+// [ny, nx] DFT: cos^(NAngles-1) of the frequency orientation relative to
+// the angle, normalized so that the sum of squares over angles is 1.
+// As in gabor, angle 0 is a horizontal filter, with vertical frequency.
+func (sp *Pyramid) angleMask(ang, i, ny, nx int) float64 {
+	fy, fx := freqs(i, ny, nx)
+	if fy == 0 && fx == 0 {
+		return 0
+	}
+	n := sp.NAngles - 1
+	th := math.Atan2(fy, fx) - 0.5*math.Pi - float64(ang)*math.Pi/float64(sp.NAngles)
+	return angleNorm(sp.NAngles) * math.Pow(math.Cos(th), float64(n))
+}
+
+// angleNorm returns the normalization factor for nang cos^(nang-1)
+// angular filters, so that the sum of their squares is 1:
+// 2^(n) n! / sqrt(nang (2n)!), where n = nang-1.
+func angleNorm(nang int) float64 {
+	n := nang - 1
+	lfn, _ := math.Lgamma(float64(n + 1))
+	lf2n, _ := math.Lgamma(float64(2*n + 1))
+	return math.Exp(float64(n)*math.Ln2 + lfn - 0.5*(math.Log(float64(nang))+lf2n))
+}
+
+// downsample returns the [ny/2, nx/2] DFT with the frequencies of given
+// [ny, nx] DFT below half the Nyquist frequency, scaled by 1/4
+// so that the spatial values are preserved.
+func downsample(dft []complex128, ny, nx int) []complex128 {
+	hy := ny / 2
+	hx := nx / 2
+	ds := make([]complex128, hy*hx)
+	for y := range hy {
+		sy := y
+		if y >= hy/2 {
+			sy += ny - hy
+		}
+		for x := range hx {
+			sx := x
+			if x >= hx/2 {
+				sx += nx - hx
+			}
+			ds[y*hx+x] = 0.25 * dft[sy*nx+sx]
+		}
+	}
+	return ds
+}
+
+// upsample returns the [2*ny, 2*nx] DFT with the frequencies of given
+// [ny, nx] DFT, and zeros for the higher frequencies: the inverse of
+// downsample.
+func upsample(dft []complex128, ny, nx int) []complex128 {
+	dy := 2 * ny
+	dx := 2 * nx
+	us := make([]complex128, dy*dx)
+	for y := range ny {
+		sy := y
+		if y >= ny/2 {
+			sy += dy - ny
+		}
+		for x := range nx {
+			sx := x
+			if x >= nx/2 {
+				sx += dx - nx
+			}
+			us[sy*dx+sx] = 4 * dft[y*nx+x]
+		}
+	}
+	return us
+}
+
+// toDFT returns the DFT of given 2D tensor
+func toDFT(tsr *tensor.Float32) []complex128 {
+	ny := tsr.DimSize(0)
+	nx := tsr.DimSize(1)
+	dft := make([]complex128, ny*nx)
+	for i, v := range tsr.Values {
+		dft[i] = complex(float64(v), 0)
+	}
+	imgstats.FFT2D(dft, ny, nx)
+	return dft
+}
+
+// toReal sets given 2D tensor to the real part of the inverse DFT
+// of given [ny, nx] DFT values, which are modified in the process.
+func toReal(dft []complex128, ny, nx int, tsr *tensor.Float32) {
+	imgstats.IFFT2D(dft, ny, nx)
+	tsr.SetShapeSizes(ny, nx)
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(real(dft[i]))
+	}
+}
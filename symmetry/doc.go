@@ -0,0 +1,17 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package symmetry detects local mirror symmetry from oriented V1 filter
+outputs, as an additional mid-level feature channel.
+
+For each candidate symmetry axis orientation at each location, the
+oriented responses at positions mirrored on either side of the axis
+are correlated, pairing each orientation with its mirror-image
+orientation about the axis.  The result is a symmetry-axis map in the
+same [Y, X, 1, Angle] format as the V1 outputs, where the Angle is
+the orientation of the axis, which can be added as a row of a V1All
+tensor with V1AllRows.
+*/
+package symmetry
@@ -0,0 +1,139 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package symmetry
+
+//go:generate core generate -add-types
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Filter computes local mirror symmetry from [Y, X, Polarity, Angle]
+// V1 simple-cell outputs, with the candidate axis orientations being
+// the same as the feature angles, so that the mirror image of each
+// angle about each axis is also one of the angles.
+// Polarities are summed, as mirroring reverses the polarity of edges.
+type Filter struct {
+
+	// is this filter active?
+	On bool
+
+	// maximum distance from the axis of the mirrored positions, in units of feature positions
+	Dist int `default:"6"`
+
+	// minimum distance from the axis of the mirrored positions -- positions closer than this are excluded, as they overlap with the features on the axis itself
+	MinDist int `default:"1"`
+
+	// half-length of the axis segment over which mirrored positions are integrated, in units of feature positions
+	Len int `default:"2"`
+
+	// minimum product of the summed squared responses on both sides for a non-zero symmetry score, so that blank regions are not counted as symmetric
+	Thr float32 `default:"0.01"`
+
+	// symmetry per axis orientation: [Y, X, 1, Angle] -- the normalized correlation (cosine) between the mirrored responses, from 0 to 1
+	Out tensor.Float32 `display:"no-inline"`
+}
+
+// NRows is the number of rows added to a V1All tensor by V1AllRows
+const NRows = 1
+
+func (sf *Filter) Defaults() {
+	sf.On = true
+	sf.Dist = 6
+	sf.MinDist = 1
+	sf.Len = 2
+	sf.Thr = 0.01
+}
+
+func (sf *Filter) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return sf.On
+	}
+}
+
+// Mirror returns the index of the mirror image of given angle index
+// about the axis at given angle index, for nang evenly spaced angles
+// over 180 degrees.
+func Mirror(ang, axis, nang int) int {
+	return ((2*axis-ang)%nang + nang) % nang
+}
+
+// Filter computes the symmetry maps from given [Y, X, Polarity, Angle]
+// V1 simple-cell outputs, into Out.
+func (sf *Filter) Filter(v1 *tensor.Float32) {
+	ny := v1.DimSize(0)
+	nang := v1.DimSize(3)
+	sf.Out.SetShapeSizes(ny, v1.DimSize(1), 1, nang)
+	nproc.Run("Symmetry", ny, func(st, n int) {
+		sf.filterThr(st, n, v1)
+	})
+}
+
+// filterThr is per-thread implementation
+func (sf *Filter) filterThr(yst, nyr int, v1 *tensor.Float32) {
+	ny := v1.DimSize(0)
+	nx := v1.DimSize(1)
+	npol := v1.DimSize(2)
+	nang := v1.DimSize(3)
+	nf := npol * nang
+	// resp returns the polarity-summed response at given position and angle
+	resp := func(y, x, ang int) float32 {
+		if y < 0 || y >= ny || x < 0 || x >= nx {
+			return 0
+		}
+		var sum float32
+		for p := 0; p < npol; p++ {
+			sum += v1.Values[(y*nx+x)*nf+p*nang+ang]
+		}
+		return sum
+	}
+	for y := yst; y < yst+nyr; y++ {
+		for x := 0; x < nx; x++ {
+			for axis := 0; axis < nang; axis++ {
+				th := math32.Pi * float32(axis) / float32(nang)
+				ax, ay := math32.Cos(th), math32.Sin(th)
+				var sumPM, sumPP, sumMM float32
+				for l := -sf.Len; l <= sf.Len; l++ {
+					cx := float32(x) + float32(l)*ax
+					cy := float32(y) + float32(l)*ay
+					for d := sf.MinDist; d <= sf.Dist; d++ {
+						// normal to the axis: (-ay, ax)
+						px := int(math32.Round(cx - float32(d)*ay))
+						py := int(math32.Round(cy + float32(d)*ax))
+						mx := int(math32.Round(cx + float32(d)*ay))
+						my := int(math32.Round(cy - float32(d)*ax))
+						for ang := 0; ang < nang; ang++ {
+							pv := resp(py, px, ang)
+							mv := resp(my, mx, Mirror(ang, axis, nang))
+							sumPM += pv * mv
+							sumPP += pv * pv
+							sumMM += mv * mv
+						}
+					}
+				}
+				var sym float32
+				if den := sumPP * sumMM; den > sf.Thr {
+					sym = sumPM / math32.Sqrt(den)
+				}
+				sf.Out.Values[(y*nx+x)*nang+axis] = sym
+			}
+		}
+	}
+}
+
+// V1AllRows adds the symmetry output as NRows rows into given V1All-style
+// [Y, X, Rows, Angle] output tensor, starting at rowStart.
+// The output must already be allocated with sufficient rows, and the
+// same Y, X size and number of angles as the symmetry output,
+// otherwise an error is returned.
+func (sf *Filter) V1AllRows(out *tensor.Float32, rowStart int) error {
+	return vfilter.FeatAgg([]int{0}, rowStart, &sf.Out, out)
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package texture measures V1 texture statistics of grey-scale images
+and synthesizes new images (metamers) that match the statistics of
+a target image.
+
+The statistics are the pixel moments and histogram, and the mean
+quadrature (phase-invariant) gabor energy at each scale and angle,
+i.e., the average V1 complex-cell response.
+
+Synth iteratively adjusts a noise image to match the target statistics
+without gradients, in the manner of Heeger & Bergen (1995): each gabor
+channel is rescaled toward its target energy and the changes are
+projected back to the image via vfilter.Deconv, followed by
+matching the pixel histogram exactly.  The per-iteration distance to
+the target statistics, in Synth.Errs, validates that the statistics
+capture the texture.
+*/
+package texture
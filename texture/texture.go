@@ -0,0 +1,339 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package texture
+
+//go:generate core generate -add-types
+
+import (
+	"image"
+	"math/rand"
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/gabor"
+	"github.com/emer/vision/v2/imgstats"
+	"github.com/emer/vision/v2/vfilter"
+)
+
+// Stats are the V1 texture statistics of a grey-scale image
+type Stats struct {
+
+	// mean pixel value
+	Mean float32
+
+	// RMS contrast: standard deviation of pixel values
+	RMS float32
+
+	// skewness of pixel values
+	Skew float32
+
+	// excess kurtosis of pixel values
+	Kurtosis float32
+
+	// mean quadrature gabor energy for each scale and angle: [Scale * NAngles + Angle]
+	Energy []float32
+
+	// sorted pixel values, for matching the histogram
+	Sorted []float32 `display:"-"`
+}
+
+// Dist returns the distance between these statistics and given other
+// ones: the summed absolute difference in gabor energy relative to
+// the summed other energy, plus the absolute difference in RMS
+// contrast relative to the other RMS.
+func (st *Stats) Dist(o *Stats) float32 {
+	var sum, tot float32
+	for i, e := range o.Energy {
+		if i < len(st.Energy) {
+			sum += math32.Abs(st.Energy[i] - e)
+		}
+		tot += e
+	}
+	var d float32
+	if tot > 0 {
+		d = sum / tot
+	}
+	if o.RMS > 0 {
+		d += math32.Abs(st.RMS-o.RMS) / o.RMS
+	}
+	return d
+}
+
+// Synth measures texture Stats with quadrature gabor filters at
+// NScales scales, and synthesizes images matching the Stats of a
+// target image.  Images are grey-scale [Y, X] tensors without
+// padding, which are wrapped around for filtering, so textures are
+// treated as periodic.
+type Synth struct {
+
+	// number of gabor filter scales
+	NScales int `default:"3"`
+
+	// size and wavelength of the smallest scale gabor filter, in pixels -- each scale is twice the size of the previous one
+	MinSize int `default:"6"`
+
+	// number of gabor filter angles
+	NAngles int `default:"4"`
+
+	// number of synthesis iterations
+	NIters int `default:"20"`
+
+	// step size for the changes projected back from the gabor channels on each iteration
+	Step float32 `default:"1"`
+
+	// random seed for the initial noise image
+	Seed int64
+
+	// statistics of the target image
+	Target Stats `display:"no-inline"`
+
+	// statistics of the synthesized image, after the last iteration
+	Stats Stats `display:"no-inline"`
+
+	// distance from the synthesized to the target statistics (see Stats.Dist) at the start of each iteration, and after the last one
+	Errs []float32
+
+	// geometry for each scale, all with the same Border
+	Geoms []vfilter.Geom `edit:"-" display:"-"`
+
+	// quadrature gabor filters for each scale: [2 * NAngles, Y, X], with odd (sine) filters first, then even (cosine)
+	FiltTsrs []tensor.Float32 `display:"-"`
+
+	// inverse of the peak gain of filtering followed by Deconv for each scale, which normalizes the projected changes
+	projNorm []float32
+
+	// padded image
+	pad tensor.Float32
+
+	// reconstruction of changes from one scale, padded
+	rec tensor.Float32
+
+	// filter outputs per scale
+	resp []tensor.Float32
+}
+
+func (sy *Synth) Defaults() {
+	sy.NScales = 3
+	sy.MinSize = 6
+	sy.NAngles = 4
+	sy.NIters = 20
+	sy.Step = 1
+	sy.Config()
+}
+
+// Size returns the filter size for given scale
+func (sy *Synth) Size(scale int) int {
+	return sy.MinSize << scale
+}
+
+// Config renders the filters and configures the Geoms -- must be
+// called after any changes to the parameters.
+func (sy *Synth) Config() {
+	ns := sy.NScales
+	sy.Geoms = make([]vfilter.Geom, ns)
+	sy.FiltTsrs = make([]tensor.Float32, ns)
+	sy.resp = make([]tensor.Float32, ns)
+	sy.projNorm = make([]float32, ns)
+	szMax := sy.Size(ns - 1)
+	brd := szMax - vfilter.LeftHalf(szMax)
+	var gf gabor.Filter
+	gf.Defaults()
+	gf.NAngles = sy.NAngles
+	var even tensor.Float32
+	for s := 0; s < ns; s++ {
+		sz := sy.Size(s)
+		gf.SetSize(sz, 1)
+		gf.Phase = 0
+		gf.ToTensor(&sy.FiltTsrs[s])
+		gf.Phase = 90
+		gf.ToTensor(&even)
+		ft := &sy.FiltTsrs[s]
+		ft.SetShapeSizes(2*sy.NAngles, sz, sz)
+		copy(ft.Values[sy.NAngles*sz*sz:], even.Values)
+		sy.Geoms[s].Set(image.Point{brd, brd}, image.Point{1, 1}, image.Point{sz, sz})
+		sy.projNorm[s] = 1 / peakGain(ft, float32(sz))
+	}
+}
+
+// peakGain returns the gain of filtering with all of the given
+// filters followed by Deconv, for a sine wave of given wavelength,
+// which is the sum of the squared magnitudes of the filter frequency
+// responses, maximized over directions.
+func peakGain(ft *tensor.Float32, wvLen float32) float32 {
+	nf := ft.DimSize(0)
+	sz := ft.DimSize(1)
+	k := 2 * math32.Pi / wvLen
+	var mx float32
+	for d := 0; d < 16; d++ {
+		ang := math32.Pi * float32(d) / 16
+		kx := k * math32.Cos(ang)
+		ky := k * math32.Sin(ang)
+		var sum float32
+		for f := 0; f < nf; f++ {
+			var re, im float32
+			for y := 0; y < sz; y++ {
+				for x := 0; x < sz; x++ {
+					v := ft.Value(f, y, x)
+					ph := kx*float32(x) + ky*float32(y)
+					re += v * math32.Cos(ph)
+					im -= v * math32.Sin(ph)
+				}
+			}
+			sum += re*re + im*im
+		}
+		mx = math32.Max(mx, sum)
+	}
+	return mx
+}
+
+// Measure computes the texture statistics of given grey-scale
+// [Y, X] image into st, leaving the filter outputs for each scale
+// in resp.
+func (sy *Synth) Measure(img *tensor.Float32, st *Stats) {
+	if len(sy.Geoms) != sy.NScales {
+		sy.Config()
+	}
+	st.Mean, st.RMS, st.Skew, st.Kurtosis = imgstats.Moments(img)
+	st.Sorted = append(st.Sorted[:0], img.Values...)
+	slices.Sort(st.Sorted)
+
+	sy.padImage(img)
+	na := sy.NAngles
+	st.Energy = slices.Grow(st.Energy[:0], sy.NScales*na)[:sy.NScales*na]
+	clear(st.Energy)
+	for s := 0; s < sy.NScales; s++ {
+		out := &sy.resp[s]
+		vfilter.Conv(&sy.Geoms[s], &sy.FiltTsrs[s], &sy.pad, out, 1)
+		nf := 2 * na
+		for i := 0; i < out.DimSize(0)*out.DimSize(1); i++ {
+			ov := out.Values[i*2*nf : (i+1)*2*nf]
+			for f := 0; f < nf; f++ {
+				v := ov[f] - ov[nf+f]
+				st.Energy[s*na+f%na] += v * v
+			}
+		}
+		norm := 1 / float32(out.DimSize(0)*out.DimSize(1))
+		for a := 0; a < na; a++ {
+			st.Energy[s*na+a] *= norm
+		}
+	}
+}
+
+// Synth synthesizes into out an image matching the texture statistics
+// of given grey-scale [Y, X] target image, of the same size, starting
+// from gaussian noise with the target mean and RMS contrast.
+// The target statistics are in Target and the final ones in Stats,
+// with the distance between them on each iteration in Errs.
+func (sy *Synth) Synth(target, out *tensor.Float32) {
+	sy.Measure(target, &sy.Target)
+	ny := target.DimSize(0)
+	nx := target.DimSize(1)
+	out.SetShapeSizes(ny, nx)
+	rnd := rand.New(rand.NewSource(sy.Seed))
+	for i := range out.Values {
+		out.Values[i] = sy.Target.Mean + sy.Target.RMS*float32(rnd.NormFloat64())
+	}
+	MatchHist(out, sy.Target.Sorted)
+	sy.Errs = sy.Errs[:0]
+	for it := 0; it < sy.NIters; it++ {
+		sy.Measure(out, &sy.Stats)
+		sy.Errs = append(sy.Errs, sy.Stats.Dist(&sy.Target))
+		sy.project(out)
+		MatchHist(out, sy.Target.Sorted)
+	}
+	sy.Measure(out, &sy.Stats)
+	sy.Errs = append(sy.Errs, sy.Stats.Dist(&sy.Target))
+}
+
+// project rescales the filter outputs from the last Measure of img
+// toward the Target energy, and adds the resulting changes, projected
+// back to the image with Deconv, normalized by the peak filter gain.
+func (sy *Synth) project(img *tensor.Float32) {
+	na := sy.NAngles
+	nf := 2 * na
+	brd := sy.Geoms[0].Border
+	ny := img.DimSize(0)
+	nx := img.DimSize(1)
+	gains := make([]float32, na)
+	for s := 0; s < sy.NScales; s++ {
+		for a := range gains {
+			gains[a] = 0
+			if ce := sy.Stats.Energy[s*na+a]; ce > 0 {
+				gains[a] = math32.Sqrt(sy.Target.Energy[s*na+a]/ce) - 1
+			}
+		}
+		out := &sy.resp[s]
+		for i := 0; i < out.DimSize(0)*out.DimSize(1); i++ {
+			ov := out.Values[i*2*nf : (i+1)*2*nf]
+			for f := 0; f < nf; f++ {
+				ov[f] *= gains[f%na]
+				ov[nf+f] *= gains[f%na]
+			}
+		}
+		sy.rec.SetShapeSizes(sy.pad.Shape().Sizes...)
+		sy.rec.SetZeros()
+		vfilter.Deconv(&sy.Geoms[s], &sy.FiltTsrs[s], &sy.rec, out, 1)
+		step := sy.Step * sy.projNorm[s]
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				// note: Deconv reconstructs the negative of the filtered image
+				img.Values[y*nx+x] -= step * sy.rec.Value(y+brd.Y, x+brd.X)
+			}
+		}
+	}
+}
+
+// padImage copies given image into the padded image, wrapping
+// around the edges.
+func (sy *Synth) padImage(img *tensor.Float32) {
+	brd := sy.Geoms[0].Border.X
+	ny := img.DimSize(0)
+	nx := img.DimSize(1)
+	sy.pad.SetShapeSizes(ny+2*brd, nx+2*brd)
+	for y := 0; y < ny; y++ {
+		copy(sy.pad.Values[(y+brd)*(nx+2*brd)+brd:], img.Values[y*nx:(y+1)*nx])
+	}
+	vfilter.WrapPad(&sy.pad, brd)
+}
+
+// MatchHist sets the values of given tensor to have the same
+// histogram as given sorted values, preserving their rank order.
+// If the number of values differs, the sorted values are
+// linearly interpolated.
+func MatchHist(tsr *tensor.Float32, sorted []float32) {
+	n := len(tsr.Values)
+	ns := len(sorted)
+	if n == 0 || ns == 0 {
+		return
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortFunc(idx, func(a, b int) int {
+		switch {
+		case tsr.Values[a] < tsr.Values[b]:
+			return -1
+		case tsr.Values[a] > tsr.Values[b]:
+			return 1
+		}
+		return 0
+	})
+	for r, i := range idx {
+		if n == ns {
+			tsr.Values[i] = sorted[r]
+			continue
+		}
+		p := float32(0)
+		if n > 1 {
+			p = float32(r) * float32(ns-1) / float32(n-1)
+		}
+		lo := int(p)
+		hi := min(lo+1, ns-1)
+		w := p - float32(lo)
+		tsr.Values[i] = (1-w)*sorted[lo] + w*sorted[hi]
+	}
+}
@@ -0,0 +1,11 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package texture
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/texture.Stats", IDName: "stats", Doc: "Stats are the V1 texture statistics of a grey-scale image", Fields: []types.Field{{Name: "Mean", Doc: "mean pixel value"}, {Name: "RMS", Doc: "RMS contrast: standard deviation of pixel values"}, {Name: "Skew", Doc: "skewness of pixel values"}, {Name: "Kurtosis", Doc: "excess kurtosis of pixel values"}, {Name: "Energy", Doc: "mean quadrature gabor energy for each scale and angle: [Scale * NAngles + Angle]"}, {Name: "Sorted", Doc: "sorted pixel values, for matching the histogram"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/texture.Synth", IDName: "synth", Doc: "Synth measures texture Stats with quadrature gabor filters at\nNScales scales, and synthesizes images matching the Stats of a\ntarget image.  Images are grey-scale [Y, X] tensors without\npadding, which are wrapped around for filtering, so textures are\ntreated as periodic.", Fields: []types.Field{{Name: "NScales", Doc: "number of gabor filter scales"}, {Name: "MinSize", Doc: "size and wavelength of the smallest scale gabor filter, in pixels -- each scale is twice the size of the previous one"}, {Name: "NAngles", Doc: "number of gabor filter angles"}, {Name: "NIters", Doc: "number of synthesis iterations"}, {Name: "Step", Doc: "step size for the changes projected back from the gabor channels on each iteration"}, {Name: "Seed", Doc: "random seed for the initial noise image"}, {Name: "Target", Doc: "statistics of the target image"}, {Name: "Stats", Doc: "statistics of the synthesized image, after the last iteration"}, {Name: "Errs", Doc: "distance from the synthesized to the target statistics (see Stats.Dist) at the start of each iteration, and after the last one"}, {Name: "Geoms", Doc: "geometry for each scale, all with the same Border"}, {Name: "FiltTsrs", Doc: "quadrature gabor filters for each scale: [2 * NAngles, Y, X], with odd (sine) filters first, then even (cosine)"}, {Name: "projNorm", Doc: "inverse of the peak gain of filtering followed by Deconv for each scale, which normalizes the projected changes"}, {Name: "pad", Doc: "padded image"}, {Name: "rec", Doc: "reconstruction of changes from one scale, padded"}, {Name: "resp", Doc: "filter outputs per scale"}}})
@@ -22,5 +22,9 @@ minus a set of same-orientation stopping features at the end of the
 line.  Thus, it responds maximally where a line ends.
 The length sum is one to the "left" of the current position
 and the off features are one to the "right".
+
+* CombineScales combines length sum (or end stop) outputs across
+the scales of an image pyramid, at aligned locations, by max or
+weighted sum, producing scale-invariant contour features.
 */
 package v1complex
@@ -7,8 +7,6 @@ package v1complex
 //go:generate core generate -add-types
 
 import (
-	"sync"
-
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
@@ -51,24 +49,13 @@ func EndStop4(act, lsum, estop *tensor.Float32) {
 	nang := act.DimSize(3)
 
 	estop.SetShapeSizes(layY, layX, 2*plY, nang) // 2 = 2 directions
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, plY*nang)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go endStop4Thr(&wg, f, nper, act, lsum, estop)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go endStop4Thr(&wg, f, rmdr, act, lsum, estop)
-	}
-	wg.Wait()
+	nproc.Run("EndStop4", plY*nang, func(st, n int) {
+		endStop4Thr(st, n, act, lsum, estop)
+	})
 }
 
 // endStop4Thr is per-thread implementation
-func endStop4Thr(wg *sync.WaitGroup, fno, nf int, act, lsum, estop *tensor.Float32) {
+func endStop4Thr(fno, nf int, act, lsum, estop *tensor.Float32) {
 	layY := act.DimSize(0)
 	layX := act.DimSize(1)
 
@@ -112,5 +99,4 @@ func endStop4Thr(wg *sync.WaitGroup, fno, nf int, act, lsum, estop *tensor.Float
 			}
 		}
 	}
-	wg.Done()
 }
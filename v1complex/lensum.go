@@ -5,8 +5,6 @@
 package v1complex
 
 import (
-	"sync"
-
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
 )
@@ -30,24 +28,13 @@ func LenSum4(act, lsum *tensor.Float32) {
 	lsum.SetShapeSizes(act.Shape().Sizes...)
 	plY := act.DimSize(2)
 	nang := act.DimSize(3)
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nang*plY)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go lenSum4Thr(&wg, f, nper, act, lsum)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go lenSum4Thr(&wg, f, rmdr, act, lsum)
-	}
-	wg.Wait()
+	nproc.Run("LenSum4", nang*plY, func(st, n int) {
+		lenSum4Thr(st, n, act, lsum)
+	})
 }
 
 // lenSum4Thr is per-thread implementation
-func lenSum4Thr(wg *sync.WaitGroup, fno, nf int, act, lsum *tensor.Float32) {
+func lenSum4Thr(fno, nf int, act, lsum *tensor.Float32) {
 
 	acts := act.Values
 	lsums := lsum.Values
@@ -90,5 +77,4 @@ func lenSum4Thr(wg *sync.WaitGroup, fno, nf int, act, lsum *tensor.Float32) {
 			}
 		}
 	}
-	wg.Done()
 }
@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package v1complex
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// CombineScales combines length-sum (or other complex feature)
+// outputs computed at multiple scales of an image pyramid, from
+// fine to coarse, into out, at the resolution of the first (finest)
+// scale, producing scale-invariant contour features.
+// Each output location is combined with the location at the same
+// relative position in each coarser scale.
+// If wts is nil, the max over scales is computed, otherwise the sum
+// of each scale's values times its weight, with one weight per scale.
+// All inputs must be 4D tensors with the same inner 2D feature shape.
+func CombineScales(srcs []*tensor.Float32, wts []float32, out *tensor.Float32) {
+	fine := srcs[0]
+	out.SetShapeSizes(fine.Shape().Sizes...)
+	layY := fine.DimSize(0)
+	nproc.Run("CombineScales", layY, func(st, n int) {
+		combineScalesThr(st, n, srcs, wts, out)
+	})
+}
+
+// combineScalesThr is per-thread implementation
+func combineScalesThr(yst, ny int, srcs []*tensor.Float32, wts []float32, out *tensor.Float32) {
+	layY := out.DimSize(0)
+	layX := out.DimSize(1)
+	nf := out.DimSize(2) * out.DimSize(3)
+	for ly := yst; ly < yst+ny; ly++ {
+		for lx := 0; lx < layX; lx++ {
+			ov := out.Values[(ly*layX+lx)*nf : (ly*layX+lx+1)*nf]
+			for si, src := range srcs {
+				sy := src.DimSize(0)
+				sx := src.DimSize(1)
+				cy := min(int((float32(ly)+0.5)*float32(sy)/float32(layY)), sy-1)
+				cx := min(int((float32(lx)+0.5)*float32(sx)/float32(layX)), sx-1)
+				sv := src.Values[(cy*sx+cx)*nf : (cy*sx+cx+1)*nf]
+				switch {
+				case wts != nil && si == 0:
+					for i, v := range sv {
+						ov[i] = wts[0] * v
+					}
+				case wts != nil:
+					for i, v := range sv {
+						ov[i] += wts[si] * v
+					}
+				case si == 0:
+					copy(ov, sv)
+				default:
+					for i, v := range sv {
+						ov[i] = math32.Max(ov[i], v)
+					}
+				}
+			}
+		}
+	}
+}
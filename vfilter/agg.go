@@ -7,7 +7,8 @@ package vfilter
 //go:generate core generate -add-types
 
 import (
-	"sync"
+	"fmt"
+	"strconv"
 
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
@@ -17,30 +18,66 @@ import (
 // to another.  One row (inner-most of 4D dimensions) is assumed to be
 // an angle, common across feature rows.
 // srcRows is the list of rows in the source to copy.
-// outStart is starting row in output to start copy -- srcRows will
+// trgStart is starting row in output to start copy -- srcRows will
 // be contiguous in output from that row up.
-// no bounds checking is done on output so it will just fail if
-// there isn't enough room -- allocate the output size before calling!
-func FeatAgg(srcRows []int, trgStart int, src, out *tensor.Float32) {
+// Returns an error, without changing the output, if the shapes do not
+// match or there isn't enough room in the output -- allocate the output
+// size before calling, or use FeatAggGrow.
+func FeatAgg(srcRows []int, trgStart int, src, out *tensor.Float32) error {
+	if err := checkFeatAgg("FeatAgg", srcRows, trgStart, src, out); err != nil {
+		return err
+	}
+	featAgg(srcRows, trgStart, src, out)
+	return nil
+}
+
+// FeatAggGrow is FeatAgg, where the row dimension of the output is grown
+// as needed to fit the rows, preserving the existing rows, and an empty
+// output is allocated with the Y, X and angle sizes of the source.
+func FeatAggGrow(srcRows []int, trgStart int, src, out *tensor.Float32) error {
+	if src.NumDims() != 4 {
+		return fmt.Errorf("vfilter.FeatAggGrow: source must have shape [Y, X, Row, Angle], not %v", src.ShapeSizes())
+	}
+	err := growFeat("FeatAggGrow", out, src.DimSize(0), src.DimSize(1), trgStart+len(srcRows), src.DimSize(3))
+	if err != nil {
+		return err
+	}
+	return FeatAgg(srcRows, trgStart, src, out)
+}
+
+// checkFeatAgg returns an error if the FeatAgg args are not valid
+func checkFeatAgg(fun string, srcRows []int, trgStart int, src, out *tensor.Float32) error {
+	if src.NumDims() != 4 {
+		return fmt.Errorf("vfilter.%s: source must have shape [Y, X, Row, Angle], not %v", fun, src.ShapeSizes())
+	}
+	if out.NumDims() != 4 {
+		return fmt.Errorf("vfilter.%s: output must have shape [Y, X, Row, Angle], not %v", fun, out.ShapeSizes())
+	}
+	if src.DimSize(0) != out.DimSize(0) || src.DimSize(1) != out.DimSize(1) || src.DimSize(3) != out.DimSize(3) {
+		return fmt.Errorf("vfilter.%s: source shape %v does not match output shape %v in Y, X, Angle", fun, src.ShapeSizes(), out.ShapeSizes())
+	}
+	nr := src.DimSize(2)
+	for _, sr := range srcRows {
+		if sr < 0 || sr >= nr {
+			return fmt.Errorf("vfilter.%s: source row %d is out of range for %d rows", fun, sr, nr)
+		}
+	}
+	if trgStart < 0 || trgStart+len(srcRows) > out.DimSize(2) {
+		return fmt.Errorf("vfilter.%s: output rows %d to %d are out of range for %d rows", fun, trgStart, trgStart+len(srcRows), out.DimSize(2))
+	}
+	return nil
+}
+
+// featAgg implements FeatAgg, without any checking
+func featAgg(srcRows []int, trgStart int, src, out *tensor.Float32) {
 	nang := src.DimSize(3)
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nang)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go featAggThr(&wg, f, nper, srcRows, trgStart, src, out)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go featAggThr(&wg, f, rmdr, srcRows, trgStart, src, out)
-	}
-	wg.Wait()
+	nproc.Run("FeatAgg", nang, func(st, n int) {
+		featAggThr(st, n, srcRows, trgStart, src, out)
+	})
 }
 
 // featAggThr is per-thread implementation
-func featAggThr(wg *sync.WaitGroup, fno, nf int, srcRows []int, trgStart int, src, out *tensor.Float32) {
+func featAggThr(fno, nf int, srcRows []int, trgStart int, src, out *tensor.Float32) {
 	ny := src.DimSize(0)
 	nx := src.DimSize(1)
 	for fi := 0; fi < nf; fi++ {
@@ -54,25 +91,135 @@ func featAggThr(wg *sync.WaitGroup, fno, nf int, srcRows []int, trgStart int, sr
 			}
 		}
 	}
-	wg.Done()
 }
 
-// OuterAgg does simple aggregation of outer-most dimension from tensor
+// OuterAgg does simple aggregation of the outer dimensions from tensor
 // into another 4D tensor, with Y, X as outer-most two dimensions,
 // starting at given inner-most feature offset, and inner row-wise offset.
-// inner row-wise dimension maps the outer-most dimension of source tensor.
-// no bounds checking is done on output so it will just fail if
-// there isn't enough room -- allocate the output size before calling!
-func OuterAgg(innerPos, rowOff int, src, out *tensor.Float32) {
-	nout := src.DimSize(0)
-	ny := src.DimSize(1)
-	nx := src.DimSize(2)
-	for y := 0; y < ny; y++ {
-		for x := 0; x < nx; x++ {
-			for f := 0; f < nout; f++ {
-				sv := src.Value(f, y, x)
-				out.Set(sv, y, x, rowOff+f, innerPos)
+// The source has Y, X as its two inner-most dimensions, and any number
+// of outer dimensions (e.g., [Row, Y, X], or [Color, Polarity, Y, X]),
+// which are mapped in row-major order to the inner row-wise dimension.
+// A 2D [Y, X] source is a single row.  Computation is parallel
+// over Y rows, for large images.
+// Returns an error, without changing the output, if the shapes do not
+// match or there isn't enough room in the output -- allocate the output
+// size before calling, or use OuterAggGrow.
+func OuterAgg(innerPos, rowOff int, src, out *tensor.Float32) error {
+	nout, ny, nx, err := outerAggShape("OuterAgg", src)
+	if err != nil {
+		return err
+	}
+	if out.NumDims() != 4 {
+		return fmt.Errorf("vfilter.OuterAgg: output must have shape [Y, X, Row, Angle], not %v", out.ShapeSizes())
+	}
+	if ny != out.DimSize(0) || nx != out.DimSize(1) {
+		return fmt.Errorf("vfilter.OuterAgg: source shape %v does not match output shape %v in Y, X", src.ShapeSizes(), out.ShapeSizes())
+	}
+	if rowOff < 0 || rowOff+nout > out.DimSize(2) {
+		return fmt.Errorf("vfilter.OuterAgg: output rows %d to %d are out of range for %d rows", rowOff, rowOff+nout, out.DimSize(2))
+	}
+	if innerPos < 0 || innerPos >= out.DimSize(3) {
+		return fmt.Errorf("vfilter.OuterAgg: inner position %d is out of range for %d angles", innerPos, out.DimSize(3))
+	}
+	nr := out.DimSize(2)
+	nang := out.DimSize(3)
+	nproc.Run("OuterAgg", ny, func(st, n int) {
+		for y := st; y < st+n; y++ {
+			for x := 0; x < nx; x++ {
+				oi := ((y*nx+x)*nr+rowOff)*nang + innerPos
+				for f := 0; f < nout; f++ {
+					out.Values[oi+f*nang] = src.Values[(f*ny+y)*nx+x]
+				}
 			}
 		}
+	})
+	return nil
+}
+
+// outerAggShape returns the number of outer rows and the Y, X size
+// of given OuterAgg source, or an error if it is less than 2D.
+func outerAggShape(fun string, src *tensor.Float32) (nout, ny, nx int, err error) {
+	nd := src.NumDims()
+	if nd < 2 {
+		return 0, 0, 0, fmt.Errorf("vfilter.%s: source must have Y, X as the inner-most dimensions, not %v", fun, src.ShapeSizes())
+	}
+	ny, nx = src.DimSize(nd-2), src.DimSize(nd-1)
+	nout = 1
+	for d := 0; d < nd-2; d++ {
+		nout *= src.DimSize(d)
+	}
+	return
+}
+
+// OuterAggGrow is OuterAgg, where the row and inner-most dimensions of
+// the output are grown as needed to fit, preserving the existing values,
+// and an empty output is allocated with the Y, X sizes of the source.
+func OuterAggGrow(innerPos, rowOff int, src, out *tensor.Float32) error {
+	nout, ny, nx, err := outerAggShape("OuterAggGrow", src)
+	if err != nil {
+		return err
+	}
+	err = growFeat("OuterAggGrow", out, ny, nx, rowOff+nout, innerPos+1)
+	if err != nil {
+		return err
+	}
+	return OuterAgg(innerPos, rowOff, src, out)
+}
+
+// growFeat grows the row and angle dimensions of given feature tensor
+// [Y, X, Row, Angle] to at least the given sizes, preserving the
+// existing values, with any new values 0.  An empty tensor is
+// allocated with the given sizes.
+func growFeat(fun string, out *tensor.Float32, ny, nx, nrows, nang int) error {
+	if out.Len() == 0 {
+		out.SetShapeSizes(ny, nx, max(nrows, 0), max(nang, 0))
+		return nil
+	}
+	if out.NumDims() != 4 {
+		return fmt.Errorf("vfilter.%s: output must have shape [Y, X, Row, Angle], not %v", fun, out.ShapeSizes())
+	}
+	if out.DimSize(0) != ny || out.DimSize(1) != nx {
+		return fmt.Errorf("vfilter.%s: output shape %v does not match Y, X size %d, %d", fun, out.ShapeSizes(), ny, nx)
+	}
+	cr, ca := out.DimSize(2), out.DimSize(3)
+	if nrows <= cr && nang <= ca {
+		return nil
+	}
+	nr, na := max(nrows, cr), max(nang, ca)
+	old := out.Clone().(*tensor.Float32)
+	out.SetShapeSizes(ny, nx, nr, na)
+	out.SetZeros()
+	for y := range ny {
+		for x := range nx {
+			for r := range cr {
+				oi := ((y*nx+x)*nr + r) * na
+				ii := ((y*nx+x)*cr + r) * ca
+				copy(out.Values[oi:oi+ca], old.Values[ii:ii+ca])
+			}
+		}
+	}
+	return nil
+}
+
+// FeatCat concatenates all of the rows of given source tensors, in
+// order, into a combined V1All-style output: [Y, X, Row, Angle], where
+// each source is either [Y, X, Row, Angle], or [Row, Y, X], in which
+// case each row is replicated across all angles, as in Layout, which
+// should be used to also record the resulting row layout.
+// Returns an error, without changing the output, if the sources have
+// incompatible shapes.
+func FeatCat(out *tensor.Float32, srcs ...*tensor.Float32) error {
+	var ly Layout
+	for i, src := range srcs {
+		nm := "src" + strconv.Itoa(i)
+		if src != nil && src.NumDims() == 3 {
+			ly.AddOuter(nm, src, nil)
+		} else {
+			ly.Add(nm, src, nil)
+		}
+	}
+	if err := ly.Build(out); err != nil {
+		return fmt.Errorf("vfilter.FeatCat: %w", err)
 	}
+	return nil
 }
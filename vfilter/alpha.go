@@ -0,0 +1,167 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/tensor"
+)
+
+// Alpha specifies how the alpha (transparency) channel of an image
+// is handled in converting it to a tensor, by RGBToTensorAlpha and
+// RGBToGreyAlpha.  Without compositing, the color values are used
+// regardless of alpha, so that transparent pixels typically have
+// their stored color of black, which produces spurious filter
+// responses at the edges of sprites with transparency.
+type Alpha struct {
+
+	// composite the image over the Background color, so that transparent pixels have the background color, and partially transparent ones are blended with it
+	Composite bool
+
+	// background color to composite the image over
+	Background color.RGBA
+}
+
+func (al *Alpha) Defaults() {
+	al.Composite = true
+	al.Background = color.RGBA{128, 128, 128, 255}
+}
+
+func (al *Alpha) ShouldDisplay(field string) bool {
+	switch field {
+	case "Background":
+		return al.Composite
+	default:
+		return true
+	}
+}
+
+// RGBA returns the color values to use for given color, according
+// to the Alpha settings (al can be nil for no compositing),
+// along with the alpha value.
+func (al *Alpha) RGBA(cv color.Color) (r, g, b, a float32) {
+	r, g, b, a = colors.ToFloat32(cv)
+	if al == nil || !al.Composite {
+		return
+	}
+	br, bg, bb, _ := colors.ToFloat32(al.Background)
+	r = a*r + (1-a)*br
+	g = a*g + (1-a)*bg
+	b = a*b + (1-a)*bb
+	return
+}
+
+// RGBToTensorAlpha converts an RGB input image to an RGB tensor
+// with outer dimension as RGB components, as in RGBToTensor, handling
+// the alpha channel according to al (which can be nil for none).
+// If mask is non-nil, it is set to the alpha values, with the same
+// padding as the tensor, which is 0 (transparent): [Y, X].
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func RGBToTensorAlpha(img image.Image, tsr *tensor.Float32, padWidth int, topZero bool, al *Alpha, mask *tensor.Float32) {
+	bd := img.Bounds()
+	sz := bd.Size()
+	tsr.SetShapeSizes(3, sz.Y+2*padWidth, sz.X+2*padWidth)
+	if mask != nil {
+		mask.SetShapeSizes(sz.Y+2*padWidth, sz.X+2*padWidth)
+		mask.SetZeros()
+	}
+	for y := 0; y < sz.Y; y++ {
+		for x := 0; x < sz.X; x++ {
+			sy := y
+			if !topZero {
+				sy = (sz.Y - 1) - y
+			}
+			r, g, b, a := al.RGBA(img.At(bd.Min.X+x, bd.Min.Y+sy))
+			tsr.Set(r, 0, y+padWidth, x+padWidth)
+			tsr.Set(g, 1, y+padWidth, x+padWidth)
+			tsr.Set(b, 2, y+padWidth, x+padWidth)
+			if mask != nil {
+				mask.Set(a, y+padWidth, x+padWidth)
+			}
+		}
+	}
+}
+
+// RGBToGreyAlpha converts an RGB input image to a greyscale tensor,
+// as in RGBToGrey, handling the alpha channel according to al
+// (which can be nil for none).
+// If mask is non-nil, it is set to the alpha values, with the same
+// padding as the tensor, which is 0 (transparent): [Y, X].
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func RGBToGreyAlpha(img image.Image, tsr *tensor.Float32, padWidth int, topZero bool, al *Alpha, mask *tensor.Float32) {
+	bd := img.Bounds()
+	sz := bd.Size()
+	tsr.SetShapeSizes(sz.Y+2*padWidth, sz.X+2*padWidth)
+	if mask != nil {
+		mask.SetShapeSizes(sz.Y+2*padWidth, sz.X+2*padWidth)
+		mask.SetZeros()
+	}
+	for y := 0; y < sz.Y; y++ {
+		for x := 0; x < sz.X; x++ {
+			sy := y
+			if !topZero {
+				sy = (sz.Y - 1) - y
+			}
+			r, g, b, a := al.RGBA(img.At(bd.Min.X+x, bd.Min.Y+sy))
+			gv := (r + g + b) / 3
+			tsr.Set(gv, y+padWidth, x+padWidth)
+			if mask != nil {
+				mask.Set(a, y+padWidth, x+padWidth)
+			}
+		}
+	}
+}
+
+// EdgeAvgMask returns the average value around the effective edge of
+// image at padWidth in from each side, as in EdgeAvg, weighted by the
+// alpha values in mask (as from RGBToGreyAlpha), so that transparent
+// pixels are ignored.  If the edge is entirely transparent, it returns
+// the unweighted EdgeAvg.
+func EdgeAvgMask(tsr, mask *tensor.Float32, padWidth int) float32 {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	ey, ex := sy-2*padWidth, sx-2*padWidth
+	if ey <= 0 || ex <= 0 {
+		return 0
+	}
+	var sum, wt float32
+	add := func(y, x int) {
+		w := mask.Value(y, x)
+		sum += w * tsr.Value(y, x)
+		wt += w
+	}
+	for y := padWidth; y < padWidth+ey; y++ {
+		add(y, padWidth)
+		if ex > 1 {
+			add(y, padWidth+ex-1)
+		}
+	}
+	for x := padWidth + 1; x < padWidth+ex-1; x++ {
+		add(padWidth, x)
+		if ey > 1 {
+			add(padWidth+ey-1, x)
+		}
+	}
+	if wt == 0 {
+		return EdgeAvg(tsr, padWidth)
+	}
+	return sum / wt
+}
+
+// FadePadMask fades given padding width of float32 image around sides
+// gradually fading the edge value toward a mean edge value, as in
+// FadePad, with the mean computed by EdgeAvgMask, ignoring
+// transparent pixels according to the alpha values in mask.
+func FadePadMask(tsr, mask *tensor.Float32, padWidth int) {
+	fadePad(tsr, padWidth, EdgeAvgMask(tsr, mask, padWidth))
+}
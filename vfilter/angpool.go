@@ -0,0 +1,130 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// AngPools are the ways of pooling over neighboring angles in AngPool
+type AngPools int32 //enums:enum
+
+const (
+	// AngMax takes the max over the neighboring angles
+	AngMax AngPools = iota
+
+	// AngAvg takes the average over the neighboring angles, weighted
+	// by a gaussian of the circular distance between angles
+	AngAvg
+)
+
+// AngPool performs pooling over neighboring angles (orientations)
+// at each position, complementing the spatial pooling of MaxPool,
+// producing outputs with a broader orientation bandwidth, like complex
+// cells, without spatial downsampling.  Angles are circular over 180
+// degrees, so the neighbors of the last angle include the first angle:
+// as the edge direction is reversed across this wrap-around, the
+// opposite polarity is used for inputs with 2 polarities.
+type AngPool struct {
+
+	// type of pooling over angles
+	Pool AngPools
+
+	// number of neighboring angles on either side of each angle to pool over -- limited to less than half the number of angles, so each angle is included only once
+	Width int `default:"1"`
+
+	// for AngAvg, standard deviation of the gaussian weighting, in angle steps
+	Sigma float32 `default:"1"`
+}
+
+func (ap *AngPool) Defaults() {
+	ap.Pool = AngMax
+	ap.Width = 1
+	ap.Sigma = 1
+}
+
+func (ap *AngPool) ShouldDisplay(field string) bool {
+	switch field {
+	case "Sigma":
+		return ap.Pool == AngAvg
+	default:
+		return true
+	}
+}
+
+// Weights returns the normalized weights for each neighboring angle
+// offset from -width to +width, for given number of angles,
+// where width is Width limited to less than half the angles.
+func (ap *AngPool) Weights(nang int) []float32 {
+	wd := max(min(ap.Width, (nang-1)/2), 0)
+	wts := make([]float32, 2*wd+1)
+	var sum float32
+	for i := range wts {
+		k := float32(i - wd)
+		w := float32(1)
+		if ap.Pool == AngAvg && ap.Sigma > 0 {
+			w = math32.Exp(-k * k / (2 * ap.Sigma * ap.Sigma))
+		}
+		wts[i] = w
+		sum += w
+	}
+	if ap.Pool == AngAvg {
+		for i := range wts {
+			wts[i] /= sum
+		}
+	}
+	return wts
+}
+
+// Filter pools over neighboring angles of given input,
+// which must have shape: Y, X, Polarities, Angles,
+// into the output with the same shape.
+func (ap *AngPool) Filter(in, out *tensor.Float32) {
+	tensor.SetShapeFrom(out, in)
+	npol := in.DimSize(2)
+	nang := in.DimSize(3)
+	wts := ap.Weights(nang)
+	nf := npol * nang
+	nproc.Run("AngPool", nf, func(st, n int) {
+		ap.filterThr(st, n, wts, in, out)
+	})
+}
+
+// filterThr is per-thread implementation
+func (ap *AngPool) filterThr(fno, nf int, wts []float32, in, out *tensor.Float32) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	npol := in.DimSize(2)
+	nang := in.DimSize(3)
+	wd := len(wts) / 2
+	for fi := 0; fi < nf; fi++ {
+		f := fno + fi
+		pol := f / nang
+		ang := f % nang
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				var ov float32
+				for i, w := range wts {
+					na, np := ang+i-wd, pol
+					if na < 0 || na >= nang {
+						na = (na + nang) % nang
+						if npol == 2 {
+							np = 1 - pol
+						}
+					}
+					iv := in.Value(y, x, np, na)
+					if ap.Pool == AngMax {
+						ov = max(ov, iv)
+					} else {
+						ov += w * iv
+					}
+				}
+				out.Set(ov, y, x, pol, ang)
+			}
+		}
+	}
+}
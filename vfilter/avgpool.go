@@ -0,0 +1,71 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"context"
+	"image"
+
+	"cogentcore.org/core/tensor"
+)
+
+// AvgPool performs average-pooling over given pool size and spacing,
+// as in MaxPool, which preserves more information about the strength
+// of graded activity than max pooling.
+// Pooling is sensitive to the feature structure of the input, which
+// must have shape: Y, X, Polarities, Angles.
+func AvgPool(psize, spc image.Point, in, out *tensor.Float32) {
+	sumPool(psize, spc, in, out, PoolAvg, false)
+}
+
+// AvgPoolT is the generic version of AvgPool, for any Float tensor type,
+// e.g., tensor.Float64.
+func AvgPoolT[T Float](psize, spc image.Point, in, out *tensor.Number[T]) {
+	sumPool(psize, spc, in, out, PoolAvg, false)
+}
+
+// L2Pool performs energy (L2) pooling over given pool size and spacing,
+// as in MaxPool: the square root of the sum of squares over the pool,
+// which is the standard energy model of complex cells.
+// Pooling is sensitive to the feature structure of the input, which
+// must have shape: Y, X, Polarities, Angles.
+// See L2PoolEnergy to also pool over the polarities or phases.
+func L2Pool(psize, spc image.Point, in, out *tensor.Float32) {
+	sumPool(psize, spc, in, out, PoolL2, false)
+}
+
+// L2PoolT is the generic version of L2Pool, for any Float tensor type,
+// e.g., tensor.Float64.
+func L2PoolT[T Float](psize, spc image.Point, in, out *tensor.Number[T]) {
+	sumPool(psize, spc, in, out, PoolL2, false)
+}
+
+// L2PoolEnergy performs energy (L2) pooling over given pool size and
+// spacing as in L2Pool, also summing the squares over the Polarities
+// dimension, which is the phase-invariant energy of a complex cell when
+// applied to the ConvPhase output of quadrature gabor pairs
+// (Y, X, Phase, Angle), or the on and off polarities of Conv output.
+// The output has shape: Y, X, 1, Angles.
+func L2PoolEnergy(psize, spc image.Point, in, out *tensor.Float32) {
+	sumPool(psize, spc, in, out, PoolL2, true)
+}
+
+// L2PoolEnergyT is the generic version of L2PoolEnergy, for any Float
+// tensor type, e.g., tensor.Float64.
+func L2PoolEnergyT[T Float](psize, spc image.Point, in, out *tensor.Number[T]) {
+	sumPool(psize, spc, in, out, PoolL2, true)
+}
+
+// sumPool implements the sum-based pooling functions, summing over
+// the polarities if overPol.
+func sumPool[T Float](psize, spc image.Point, in, out *tensor.Number[T], op PoolOps, overPol bool) {
+	osz := PoolOut(psize, spc, image.Point{in.DimSize(1), in.DimSize(0)})
+	opol := in.DimSize(2)
+	if overPol {
+		opol = 1
+	}
+	out.SetShapeSizes(osz.Y, osz.X, opol, in.DimSize(3))
+	pool(context.Background(), psize, spc, image.Point{}, in, out, op, overPol)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// ConvBatch performs convolution of filter over a batch of images into
+// out, as in Conv, with the images as the outer-most dimension of imgs:
+// [Image, Y, X] for grey images, or [Image, Channel, Y, X] for
+// multi-channel images with 4D [Filter, Channel, Y, X] filters as in ConvN.
+// Computation is parallel across both images and filters, so all threads
+// are used even for small numbers of filters, and out is reused with no
+// allocation when its shape is unchanged, for preprocessing large
+// datasets in batches.  The images must have border (padding) as for Conv.
+// Out shape dims are: Image, Y, X, Polarity (2), Filter.
+func ConvBatch(geom *Geom, flt *tensor.Float32, imgs, out *tensor.Float32, gain float32) {
+	convBatch(geom, flt, Channels(imgs), out, gain)
+}
+
+// ConvImages performs convolution of filter over a slice of images into
+// out, as in ConvBatch, where the images must all be the same size.
+// Out shape dims are: Image, Y, X, Polarity (2), Filter.
+func ConvImages(geom *Geom, flt *tensor.Float32, imgs []*tensor.Float32, out *tensor.Float32, gain float32) {
+	convBatch(geom, flt, imgs, out, gain)
+}
+
+// convBatch implements ConvBatch and ConvImages
+func convBatch(geom *Geom, flt *tensor.Float32, imgs []*tensor.Float32, out *tensor.Float32, gain float32) {
+	nimg := len(imgs)
+	nf := flt.DimSize(0)
+	fd := flt.NumDims()
+	geom.FiltSz = image.Point{flt.DimSize(fd - 1), flt.DimSize(fd - 2)}
+	geom.UpdtFilt()
+	if nimg == 0 {
+		out.SetShapeSizes(0, 0, 0, 2, nf)
+		return
+	}
+	id := imgs[0].NumDims()
+	geom.SetSize(image.Point{imgs[0].DimSize(id - 1), imgs[0].DimSize(id - 2)})
+	out.SetShapeSizes(nimg, int(geom.Out.Y), int(geom.Out.X), 2, nf)
+	nproc.Run("ConvBatch", nimg*nf, func(st, n int) {
+		convBatchThr(geom, st, n, flt, imgs, out, gain)
+	})
+}
+
+// convBatchThr is per-thread implementation, for nj jobs starting at jno,
+// where each job is one filter of one image
+func convBatchThr(geom *Geom, jno, nj int, flt *tensor.Float32, imgs []*tensor.Float32, out *tensor.Float32, gain float32) {
+	nf := flt.DimSize(0)
+	var ov tensor.Float32
+	for j := jno; j < jno+nj; {
+		i := j / nf
+		f := j % nf
+		n := min(nf-f, jno+nj-j)
+		ChannelView(out, i, &ov)
+		if flt.NumDims() == 4 {
+			convNThr(geom, f, n, flt, imgs[i], &ov, gain)
+		} else {
+			convThr(geom, f, n, 0, geom.Out.Y, flt, imgs[i], &ov, gain, false, nil)
+		}
+		j += n
+	}
+}
+
+// MaxPoolBatch performs MaxPool over a batch of inputs, with the images
+// as the outer-most dimension: [Image, Y, X, Polarities, Angles], as
+// from ConvBatch, parallel across both images and features.
+// Out shape dims are: Image, Y, X, Polarities, Angles.
+func MaxPoolBatch(psize, spc image.Point, in, out *tensor.Float32) {
+	poolBatch(psize, spc, in, out, PoolMax, false)
+}
+
+// AvgPoolBatch performs AvgPool over a batch of inputs, as in MaxPoolBatch.
+func AvgPoolBatch(psize, spc image.Point, in, out *tensor.Float32) {
+	poolBatch(psize, spc, in, out, PoolAvg, false)
+}
+
+// L2PoolBatch performs L2Pool over a batch of inputs, as in MaxPoolBatch.
+func L2PoolBatch(psize, spc image.Point, in, out *tensor.Float32) {
+	poolBatch(psize, spc, in, out, PoolL2, false)
+}
+
+// L2PoolEnergyBatch performs L2PoolEnergy over a batch of inputs,
+// as in MaxPoolBatch.  Out shape dims are: Image, Y, X, 1, Angles.
+func L2PoolEnergyBatch(psize, spc image.Point, in, out *tensor.Float32) {
+	poolBatch(psize, spc, in, out, PoolL2, true)
+}
+
+// poolBatch implements the batch pooling functions
+func poolBatch(psize, spc image.Point, in, out *tensor.Float32, op PoolOps, overPol bool) {
+	nimg := in.DimSize(0)
+	osz := PoolOut(psize, spc, image.Point{in.DimSize(2), in.DimSize(1)})
+	opol := in.DimSize(3)
+	if overPol {
+		opol = 1
+	}
+	nang := in.DimSize(4)
+	out.SetShapeSizes(nimg, osz.Y, osz.X, opol, nang)
+	nproc.Run("PoolBatch", nimg*opol*nang, func(st, n int) {
+		poolBatchThr(st, n, psize, spc, in, out, op, overPol)
+	})
+}
+
+// poolBatchThr is per-thread implementation, for nj jobs starting at jno,
+// where each job is one feature of one image
+func poolBatchThr(jno, nj int, psize, spc image.Point, in, out *tensor.Float32, op PoolOps, overPol bool) {
+	nfi := out.DimSize(3) * out.DimSize(4)
+	var iv, ov tensor.Float32
+	for j := jno; j < jno+nj; {
+		i := j / nfi
+		f := j % nfi
+		n := min(nfi-f, jno+nj-j)
+		ChannelView(in, i, &iv)
+		ChannelView(out, i, &ov)
+		poolThr(f, n, psize, spc, image.Point{}, &iv, &ov, op, overPol)
+		j += n
+	}
+}
@@ -5,25 +5,67 @@
 package vfilter
 
 import (
+	"context"
 	"image"
-	"sync"
 
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
 )
 
+// Float is the constraint for the tensor value types supported by the
+// generic versions of the filtering functions (e.g., ConvT, MaxPoolT),
+// for use with tensor.Float64 as well as tensor.Float32, which use
+// the same filtering code, with no conversion.
+type Float interface {
+	~float32 | ~float64
+}
+
 // Conv performs convolution of filter over img into out.
 // img *must* have border (padding) so that filters are
 // applied without any bounds checking -- wrapping etc is all
 // done in the padding process, which is much more efficient.
 // Computation is parallel in number of different filter types
-// (outer dim of flt) as that will be most memory efficient.
+// (outer dim of flt) as that will be most memory efficient,
+// unless there are fewer filters than threads, in which case it
+// is parallel over output rows, so that all threads are used.
 // img must be a 2D tensor of image values (convert RGB to grey first).
 // Everything must be organized row major as tensor default.
 // Out shape dims are: Y, X, Polarity (2), Angle
 // where the 2 polarities (on, off) are for positive and and
 // negative filter values, respectively.
+// The filter taps are spread out by the geom Dilation, if set.
 func Conv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	conv(context.Background(), geom, flt, img, out, gain, 0, nil)
+}
+
+// ConvContext is Conv with cancellation and progress reporting via ctx
+// (see nproc.RunContext), returning ctx.Err() if canceled, in which
+// case out is only partially computed.
+func ConvContext(ctx context.Context, geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) error {
+	return conv(ctx, geom, flt, img, out, gain, 0, nil)
+}
+
+// ConvT is the generic version of Conv, for any Float tensor type,
+// e.g., tensor.Float64.
+func ConvT[T Float](geom *Geom, flt, img, out *tensor.Number[T], gain T) {
+	conv(context.Background(), geom, flt, img, out, gain, 0, nil)
+}
+
+// ConvPhase performs convolution of filter over img into out, as in Conv,
+// but preserving the signed response of each filter instead of splitting
+// it into on and off polarities, for multi-phase filter banks
+// (e.g., quadrature pairs), so that the phase information is available
+// for energy and disparity computations.
+// flt has nPhase phases in the outer dimension, each with the same
+// number of angles: [Phase * Angle, Y, X], as from gabor ToTensorPhases.
+// Out shape dims are: Y, X, Phase, Angle
+func ConvPhase(geom *Geom, flt *tensor.Float32, nPhase int, img, out *tensor.Float32, gain float32) {
+	conv(context.Background(), geom, flt, img, out, gain, nPhase, nil)
+}
+
+// conv implements Conv, or ConvPhase if nPhase > 0, accumulating
+// statistics into cs if non-nil, with cancellation via ctx
+func conv[T Float](ctx context.Context, geom *Geom, flt, img, out *tensor.Number[T], gain T, nPhase int, cs *ConvStats) error {
 	nf := flt.DimSize(0)
 	fy := flt.DimSize(1)
 	fx := flt.DimSize(2)
@@ -33,54 +75,76 @@ func Conv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float3
 
 	imgSz := image.Point{img.DimSize(1), img.DimSize(0)}
 	geom.SetSize(imgSz)
-	out.SetShapeSizes(int(geom.Out.Y), int(geom.Out.X), 2, nf)
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nf)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go convThr(&wg, geom, f, nper, flt, img, out, gain)
+	if nPhase > 0 {
+		out.SetShapeSizes(int(geom.Out.Y), int(geom.Out.X), nPhase, nf/nPhase)
+	} else {
+		out.SetShapeSizes(int(geom.Out.Y), int(geom.Out.X), 2, nf)
 	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go convThr(&wg, geom, f, rmdr, flt, img, out, gain)
+	signed := nPhase > 0
+	if nf < nproc.Threads("Conv") && geom.Out.Y > nf {
+		return convRows(ctx, geom, flt, img, out, gain, signed, cs)
 	}
-	wg.Wait()
+	return nproc.RunContext(ctx, "Conv", nf, func(st, n int) {
+		convThr(geom, st, n, 0, geom.Out.Y, flt, img, out, gain, signed, cs)
+	})
+}
+
+// convRows is the row-parallel version of Conv, for small numbers
+// of filters, where each thread computes all filters over a subset
+// of output rows, as in ConvDiff.
+func convRows[T Float](ctx context.Context, geom *Geom, flt, img, out *tensor.Number[T], gain T, signed bool, cs *ConvStats) error {
+	nf := flt.DimSize(0)
+	return nproc.RunContext(ctx, "Conv", geom.Out.Y, func(st, n int) {
+		convThr(geom, 0, nf, st, n, flt, img, out, gain, signed, cs)
+	})
 }
 
-// convThr is per-thread implementation
-func convThr(wg *sync.WaitGroup, geom *Geom, fno, nf int, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+// convThr is per-thread implementation, for nf filters starting at fno,
+// over ny output rows starting at yst, writing signed outputs if signed,
+// accumulating statistics into cs if non-nil
+func convThr[T Float](geom *Geom, fno, nf, yst, ny int, flt, img, out *tensor.Number[T], gain T, signed bool, cs *ConvStats) {
 	ist := geom.Border.Sub(geom.FiltLt)
+	dl := geom.Dil()
 	fsz := int(geom.FiltSz.Y) * int(geom.FiltSz.X)
+	nft := flt.DimSize(0)
+	var acc *convAcc
+	if cs != nil {
+		acc = newConvAcc(nf)
+	}
 	for fi := 0; fi < nf; fi++ {
 		f := fno + fi
 		fst := f * fsz
-		for y := 0; y < geom.Out.Y; y++ {
+		for y := yst; y < yst+ny; y++ {
 			iy := int(ist.Y + y*geom.Spacing.Y)
 			for x := 0; x < geom.Out.X; x++ {
 				ix := ist.X + x*geom.Spacing.X
-				sum := float32(0)
+				sum := T(0)
 				fi := 0
 				for fy := 0; fy < geom.FiltSz.Y; fy++ {
 					for fx := 0; fx < geom.FiltSz.X; fx++ {
-						iv := img.Value(iy+fy, ix+fx)
+						iv := img.Value(iy+fy*dl.Y, ix+fx*dl.X)
 						fv := flt.Values[fst+fi]
 						sum += iv * fv
 						fi++
 					}
 				}
 				sum *= gain
-				if sum > 0 {
+				if acc != nil {
+					acc.add(f-fno, float32(sum))
+				}
+				if signed {
+					out.Values[(y*geom.Out.X+x)*nft+f] = sum
+				} else if sum > 0 {
 					out.Set(sum, y, x, 0, f)
-					out.Set(float32(0), y, x, 1, f)
+					out.Set(0, y, x, 1, f)
 				} else {
-					out.Set(float32(0), y, x, 0, f)
+					out.Set(0, y, x, 0, f)
 					out.Set(-sum, y, x, 1, f)
 				}
 			}
 		}
 	}
-	wg.Done()
+	if cs != nil {
+		cs.merge(fno, ny*geom.Out.X, acc)
+	}
 }
@@ -6,7 +6,6 @@ package vfilter
 
 import (
 	"image"
-	"sync"
 
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
@@ -19,9 +18,16 @@ import (
 // Computation is parallel in image lines.
 // img must be a 2D tensor of image values (convert RGB to grey first).
 // Everything must be organized row major as tensor default.
-// Output has 2 outer dims for positive vs. negative values, inner is Y, X
-// todo: add option to interleave polarity as inner-most dim.
+// Output has 2 outer dims for positive vs. negative values, inner is Y, X,
+// or the polarities are the inner-most dim if geom OutLayout is
+// PolarityInner: [Y, X, 1, 2].
 func Conv1(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	Conv1T(geom, flt, img, out, gain)
+}
+
+// Conv1T is the generic version of Conv1, for any Float tensor type,
+// e.g., tensor.Float64.
+func Conv1T[T Float](geom *Geom, flt, img, out *tensor.Number[T], gain T) {
 	fy := flt.DimSize(0)
 	fx := flt.DimSize(1)
 
@@ -30,36 +36,26 @@ func Conv1(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float
 
 	imgSz := image.Point{img.DimSize(1), img.DimSize(0)}
 	geom.SetSize(imgSz)
-	out.SetShapeSizes(2, int(geom.Out.Y), int(geom.Out.X))
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, geom.Out.Y)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		yst := th * nper
-		go conv1Thr(&wg, geom, yst, nper, flt, img, out, gain)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		yst := nthrs * nper
-		go conv1Thr(&wg, geom, yst, rmdr, flt, img, out, gain)
-	}
-	wg.Wait()
+	geom.SetPolShape(out)
+	nproc.Run("Conv1", geom.Out.Y, func(st, n int) {
+		conv1Thr(geom, st, n, flt, img, out, gain)
+	})
 }
 
 // conv1Thr is per-thread implementation
-func conv1Thr(wg *sync.WaitGroup, geom *Geom, yst, ny int, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+func conv1Thr[T Float](geom *Geom, yst, ny int, flt, img, out *tensor.Number[T], gain T) {
 	ist := geom.Border.Sub(geom.FiltLt)
+	dl := geom.Dil()
 	for yi := 0; yi < ny; yi++ {
 		y := yst + yi
 		iy := int(ist.Y + y*geom.Spacing.Y)
 		for x := 0; x < geom.Out.X; x++ {
 			ix := ist.X + x*geom.Spacing.X
-			sum := float32(0)
+			sum := T(0)
 			fi := 0
 			for fy := 0; fy < geom.FiltSz.Y; fy++ {
 				for fx := 0; fx < geom.FiltSz.X; fx++ {
-					iv := img.Value(iy+fy, ix+fx)
+					iv := img.Value(iy+fy*dl.Y, ix+fx*dl.X)
 					fv := flt.Values[fi]
 					sum += iv * fv
 					fi++
@@ -67,13 +63,12 @@ func conv1Thr(wg *sync.WaitGroup, geom *Geom, yst, ny int, flt *tensor.Float32,
 			}
 			sum *= gain
 			if sum > 0 {
-				out.Set(sum, 0, y, x)
-				out.Set(float32(0), 1, y, x)
+				out.Values[geom.polIndex(0, y, x)] = sum
+				out.Values[geom.polIndex(1, y, x)] = 0
 			} else {
-				out.Set(float32(0), 0, y, x)
-				out.Set(-sum, 1, y, x)
+				out.Values[geom.polIndex(0, y, x)] = 0
+				out.Values[geom.polIndex(1, y, x)] = -sum
 			}
 		}
 	}
-	wg.Done()
 }
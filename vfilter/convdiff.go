@@ -5,8 +5,8 @@
 package vfilter
 
 import (
+	"context"
 	"image"
-	"sync"
 
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
@@ -21,8 +21,28 @@ import (
 // Computation is parallel in image lines.
 // img must be a 2D tensor of image values (grey or single components).
 // Everything must be organized row major as tensor default.
-// Output has 2 outer dims for positive vs. negative values, inner is Y, X
+// Output has 2 outer dims for positive vs. negative values, inner is Y, X,
+// or the polarities are the inner-most dim if geom OutLayout is
+// PolarityInner: [Y, X, 1, 2].
 func ConvDiff(geom *Geom, fltOn, fltOff *tensor.Float32, imgOn, imgOff, out *tensor.Float32, gain, gainOn float32) {
+	convDiff(context.Background(), geom, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
+}
+
+// ConvDiffT is the generic version of ConvDiff, for any Float tensor
+// type, e.g., tensor.Float64.
+func ConvDiffT[T Float](geom *Geom, fltOn, fltOff, imgOn, imgOff, out *tensor.Number[T], gain, gainOn T) {
+	convDiff(context.Background(), geom, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
+}
+
+// ConvDiffContext is ConvDiff with cancellation and progress reporting
+// via ctx (see nproc.RunContext), returning ctx.Err() if canceled,
+// in which case out is only partially computed.
+func ConvDiffContext(ctx context.Context, geom *Geom, fltOn, fltOff *tensor.Float32, imgOn, imgOff, out *tensor.Float32, gain, gainOn float32) error {
+	return convDiff(ctx, geom, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
+}
+
+// convDiff implements ConvDiff, with cancellation via ctx
+func convDiff[T Float](ctx context.Context, geom *Geom, fltOn, fltOff, imgOn, imgOff, out *tensor.Number[T], gain, gainOn T) error {
 	fy := fltOn.DimSize(0)
 	fx := fltOn.DimSize(1)
 
@@ -31,36 +51,26 @@ func ConvDiff(geom *Geom, fltOn, fltOff *tensor.Float32, imgOn, imgOff, out *ten
 
 	imgSz := image.Point{imgOn.DimSize(1), imgOn.DimSize(0)}
 	geom.SetSize(imgSz)
-	out.SetShapeSizes(2, int(geom.Out.Y), int(geom.Out.X))
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, geom.Out.Y)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		yst := th * nper
-		go convDiffThr(&wg, geom, yst, nper, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		yst := nthrs * nper
-		go convDiffThr(&wg, geom, yst, rmdr, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
-	}
-	wg.Wait()
+	geom.SetPolShape(out)
+	return nproc.RunContext(ctx, "ConvDiff", geom.Out.Y, func(st, n int) {
+		convDiffThr(geom, st, n, fltOn, fltOff, imgOn, imgOff, out, gain, gainOn)
+	})
 }
 
 // convDiffThr is per-thread implementation
-func convDiffThr(wg *sync.WaitGroup, geom *Geom, yst, ny int, fltOn, fltOff *tensor.Float32, imgOn, imgOff, out *tensor.Float32, gain, gainOn float32) {
+func convDiffThr[T Float](geom *Geom, yst, ny int, fltOn, fltOff, imgOn, imgOff, out *tensor.Number[T], gain, gainOn T) {
 	ist := geom.Border.Sub(geom.FiltLt)
+	dl := geom.Dil()
 	for yi := 0; yi < ny; yi++ {
 		y := yst + yi
 		iy := int(ist.Y + y*geom.Spacing.Y)
 		for x := 0; x < geom.Out.X; x++ {
 			ix := ist.X + x*geom.Spacing.X
-			var sumOn, sumOff float32
+			var sumOn, sumOff T
 			fi := 0
 			for fy := 0; fy < geom.FiltSz.Y; fy++ {
 				for fx := 0; fx < geom.FiltSz.X; fx++ {
-					idx := imgOn.Shape().IndexTo1D(iy+fy, ix+fx)
+					idx := imgOn.Shape().IndexTo1D(iy+fy*dl.Y, ix+fx*dl.X)
 					sumOn += imgOn.Values[idx] * fltOn.Values[fi]
 					sumOff += imgOff.Values[idx] * fltOff.Values[fi]
 					fi++
@@ -68,13 +78,12 @@ func convDiffThr(wg *sync.WaitGroup, geom *Geom, yst, ny int, fltOn, fltOff *ten
 			}
 			diff := gain * (gainOn*sumOn - sumOff)
 			if diff > 0 {
-				out.Set(diff, 0, y, x)
-				out.Set(0, 1, y, x)
+				out.Values[geom.polIndex(0, y, x)] = diff
+				out.Values[geom.polIndex(1, y, x)] = 0
 			} else {
-				out.Set(0, 0, y, x)
-				out.Set(-diff, 1, y, x)
+				out.Values[geom.polIndex(0, y, x)] = 0
+				out.Values[geom.polIndex(1, y, x)] = -diff
 			}
 		}
 	}
-	wg.Done()
 }
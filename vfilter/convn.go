@@ -0,0 +1,72 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// ConvN performs convolution of multi-channel filters over a
+// multi-channel img into out, as in Conv, summing over the channels
+// for each filter, so that each filter can combine the channels in
+// arbitrary ways, e.g., double-opponent color filters, or learned
+// filters over RGB or multispectral bands.
+// img must be a 3D tensor: [Channel, Y, X], with border (padding)
+// as for Conv (e.g., WrapPadRGB for 3 channels), and flt must be
+// 4D: [Filter, Channel, Y, X], with the same number of channels.
+// Out shape dims are: Y, X, Polarity (2), Filter, as in Conv.
+func ConvN(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	nf := flt.DimSize(0)
+	geom.FiltSz = image.Point{flt.DimSize(3), flt.DimSize(2)}
+	geom.UpdtFilt()
+
+	imgSz := image.Point{img.DimSize(2), img.DimSize(1)}
+	geom.SetSize(imgSz)
+	out.SetShapeSizes(int(geom.Out.Y), int(geom.Out.X), 2, nf)
+	nproc.Run("ConvN", nf, func(st, n int) {
+		convNThr(geom, st, n, flt, img, out, gain)
+	})
+}
+
+// convNThr is per-thread implementation
+func convNThr(geom *Geom, fno, nf int, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	ist := geom.Border.Sub(geom.FiltLt)
+	dl := geom.Dil()
+	nc := flt.DimSize(1)
+	fsz := geom.FiltSz.Y * geom.FiltSz.X
+	fcsz := nc * fsz
+	for fi := 0; fi < nf; fi++ {
+		f := fno + fi
+		fst := f * fcsz
+		for y := 0; y < geom.Out.Y; y++ {
+			iy := ist.Y + y*geom.Spacing.Y
+			for x := 0; x < geom.Out.X; x++ {
+				ix := ist.X + x*geom.Spacing.X
+				sum := float32(0)
+				for c := 0; c < nc; c++ {
+					fi := fst + c*fsz
+					for fy := 0; fy < geom.FiltSz.Y; fy++ {
+						for fx := 0; fx < geom.FiltSz.X; fx++ {
+							iv := img.Value(c, iy+fy*dl.Y, ix+fx*dl.X)
+							sum += iv * flt.Values[fi]
+							fi++
+						}
+					}
+				}
+				sum *= gain
+				if sum > 0 {
+					out.Set(sum, y, x, 0, f)
+					out.Set(float32(0), y, x, 1, f)
+				} else {
+					out.Set(float32(0), y, x, 0, f)
+					out.Set(-sum, y, x, 1, f)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/tensor"
+)
+
+// ConvSafe performs convolution of filter over img into out, as in Conv,
+// but for an image without any padding: the image is copied into a
+// padded buffer, with the border extended according to given edge mode
+// (e.g., Zero or Clamp), so that the caller does not need to pad the
+// image first.  The padding width is the larger of the Geom Border
+// dimensions, which is at least the right half of the filter (FiltRt),
+// and the Border and Pad of geom are set accordingly, so the output
+// covers the entire image: Out = image size / Spacing.
+// The In size of geom is the padded size, as used by Conv.
+// Returns an error if the image is not 2D, the filter is not 3D,
+// the Spacing is not positive, or the image is too small to Wrap,
+// instead of reading out of range.
+func ConvSafe(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32, mode EdgeModes) error {
+	if img.NumDims() != 2 {
+		return fmt.Errorf("vfilter.ConvSafe: image must be 2D, has shape: %v", img.ShapeSizes())
+	}
+	if flt.NumDims() != 3 {
+		return fmt.Errorf("vfilter.ConvSafe: filter must be 3D [Filter, Y, X], has shape: %v", flt.ShapeSizes())
+	}
+	if geom.Spacing.X <= 0 || geom.Spacing.Y <= 0 {
+		return fmt.Errorf("vfilter.ConvSafe: Spacing must be positive: %v", geom.Spacing)
+	}
+	geom.FiltSz.X = flt.DimSize(2)
+	geom.FiltSz.Y = flt.DimSize(1)
+	geom.UpdtFilt()
+	pw := max(geom.Border.X, geom.Border.Y)
+	geom.Border.X = pw
+	geom.Border.Y = pw
+	geom.Pad = mode
+	sy := img.DimSize(0)
+	sx := img.DimSize(1)
+	if mode == Wrap && (sy < pw || sx < pw) {
+		return fmt.Errorf("vfilter.ConvSafe: image size %d x %d is smaller than the padding %d, which cannot be wrapped", sx, sy, pw)
+	}
+	psx := sx + 2*pw
+	pimg := Pool.Get(sy+2*pw, psx)
+	defer Pool.Put(pimg)
+	for y := 0; y < sy; y++ {
+		copy(pimg.Values[(y+pw)*psx+pw:], img.Values[y*sx:(y+1)*sx])
+	}
+	Pad(pimg, pw, mode)
+	Conv(geom, flt, pimg, out, gain)
+	return nil
+}
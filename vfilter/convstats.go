@@ -0,0 +1,143 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"context"
+	"sync"
+
+	"cogentcore.org/core/tensor"
+)
+
+// ConvStatTypes are the per-filter statistics accumulated by ConvStats
+type ConvStatTypes int32 //enums:enum
+
+const (
+	// ConvMean is the mean response over all output positions
+	ConvMean ConvStatTypes = iota
+
+	// ConvMax is the maximum response over all output positions
+	ConvMax
+
+	// ConvNonZero is the fraction of output positions with a non-zero response
+	ConvNonZero
+)
+
+// ConvStats accumulates per-filter response statistics over Conv
+// passes, computed during the pass itself, so that the gain balance
+// across filters and scales can be monitored over a dataset without
+// a second sweep over the outputs.  Use the ConvStats Conv method in
+// place of Conv to accumulate.  It is safe for concurrent use.
+type ConvStats struct {
+
+	// number of Conv passes accumulated since Init
+	N int `edit:"-"`
+
+	// per-filter statistics over all output positions of all passes since Init: [Filter, Polarity (on, off), ConvStatTypes]
+	Stats tensor.Float32 `display:"no-inline"`
+
+	// accumulated sums per filter and polarity
+	sum []float64
+
+	// accumulated max per filter and polarity
+	max []float32
+
+	// accumulated count of non-zero responses per filter and polarity
+	nonZero []float64
+
+	// accumulated count of output positions per filter
+	count []float64
+
+	// mutex for merging per-thread accumulators
+	mu sync.Mutex
+}
+
+// Init resets the accumulated statistics
+func (cs *ConvStats) Init() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.N = 0
+	cs.sum = nil
+	cs.Stats.SetShapeSizes(0, 2, int(ConvStatTypesN))
+}
+
+// Conv does Conv with the given args, accumulating the statistics
+// of the outputs, and updating Stats.  The statistics are reset if
+// the number of filters differs from the previous pass.
+func (cs *ConvStats) Conv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	nf := flt.DimSize(0)
+	cs.mu.Lock()
+	if len(cs.count) != nf || cs.sum == nil {
+		cs.N = 0
+		cs.sum = make([]float64, 2*nf)
+		cs.max = make([]float32, 2*nf)
+		cs.nonZero = make([]float64, 2*nf)
+		cs.count = make([]float64, nf)
+	}
+	cs.mu.Unlock()
+	conv(context.Background(), geom, flt, img, out, gain, 0, cs)
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.N++
+	cs.Stats.SetShapeSizes(nf, 2, int(ConvStatTypesN))
+	for f := range nf {
+		for p := range 2 {
+			i := 2*f + p
+			cnt := max(cs.count[f], 1)
+			cs.Stats.Set(float32(cs.sum[i]/cnt), f, p, int(ConvMean))
+			cs.Stats.Set(cs.max[i], f, p, int(ConvMax))
+			cs.Stats.Set(float32(cs.nonZero[i]/cnt), f, p, int(ConvNonZero))
+		}
+	}
+}
+
+// Stat returns given statistic for given filter and polarity
+// (0 = on, 1 = off), as of the last Conv.
+func (cs *ConvStats) Stat(filter, polarity int, stat ConvStatTypes) float32 {
+	return cs.Stats.Value(filter, polarity, int(stat))
+}
+
+// merge adds given per-thread accumulator for filters starting at fno,
+// each over n output positions.
+func (cs *ConvStats) merge(fno, n int, acc *convAcc) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for fi := range len(acc.sum) / 2 {
+		f := fno + fi
+		cs.count[f] += float64(n)
+		for p := range 2 {
+			i, ai := 2*f+p, 2*fi+p
+			cs.sum[i] += acc.sum[ai]
+			cs.max[i] = max(cs.max[i], acc.max[ai])
+			cs.nonZero[i] += acc.nonZero[ai]
+		}
+	}
+}
+
+// convAcc is a per-thread accumulator of ConvStats,
+// indexed by filter and polarity.
+type convAcc struct {
+	sum     []float64
+	max     []float32
+	nonZero []float64
+}
+
+func newConvAcc(nf int) *convAcc {
+	return &convAcc{sum: make([]float64, 2*nf), max: make([]float32, 2*nf), nonZero: make([]float64, 2*nf)}
+}
+
+// add adds given signed response for given filter index
+func (ac *convAcc) add(fi int, v float32) {
+	i := 2 * fi
+	if v < 0 {
+		i++
+		v = -v
+	}
+	ac.sum[i] += float64(v)
+	ac.max[i] = max(ac.max[i], v)
+	if v > 0 {
+		ac.nonZero[i]++
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"slices"
 
 	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
 )
 
 // Deconv performs reverse convolution of filter -- given output of filter,
@@ -22,6 +23,8 @@ import (
 // Out shape dims are: Y, X, Polarity (2), Angle
 // where the 2 polarities (on, off) are for positive and and
 // negative filter values, respectively.
+// Computation is parallel over filters, with each thread accumulating
+// into a separate image, which are summed into img.
 func Deconv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
 	nf := flt.DimSize(0)
 	fy := flt.DimSize(1)
@@ -37,27 +40,52 @@ func Deconv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain floa
 		log.Printf("Deconv output shape not correct for input\n")
 		return
 	}
+	// each thread accumulates into its own image, indexed by its starting
+	// filter, which are then summed in order, independent of timing
+	accs := make([]*tensor.Float32, nf)
+	nproc.Run("Deconv", nf, func(st, n int) {
+		acc := Pool.GetLike(img)
+		acc.SetZeros()
+		deconvThr(geom, st, n, flt, acc, out)
+		accs[st] = acc
+	})
+	for _, acc := range accs {
+		if acc == nil {
+			continue
+		}
+		for i, v := range acc.Values {
+			img.Values[i] += v
+		}
+		Pool.Put(acc)
+	}
+}
+
+// deconvThr is per-thread implementation, accumulating into acc
+func deconvThr(geom *Geom, fno, nf int, flt *tensor.Float32, acc, out *tensor.Float32) {
 	ist := geom.Border.Sub(geom.FiltLt)
-	fsz := fx * fy
-	for f := 0; f < nf; f++ {
+	dl := geom.Dil()
+	fsz := geom.FiltSz.X * geom.FiltSz.Y
+	isx := geom.In.X
+	nof := out.DimSize(3)
+	for f := fno; f < fno+nf; f++ {
 		fst := f * fsz
 		for y := 0; y < geom.Out.Y; y++ {
-			iy := int(ist.Y + y*geom.Spacing.Y)
+			iy := ist.Y + y*geom.Spacing.Y
 			for x := 0; x < geom.Out.X; x++ {
 				ix := ist.X + x*geom.Spacing.X
-				act := float32(0)
-				if av := out.Value(y, x, 1, f); av > 0 {
-					act = av
-				} else {
-					act = -out.Value(y, x, 0, f)
+				oi := ((y*geom.Out.X+x)*2)*nof + f
+				act := out.Values[oi+nof]
+				if act <= 0 {
+					act = -out.Values[oi]
 				}
-				fi := 0
+				if act == 0 {
+					continue
+				}
+				fi := fst
 				for fy := 0; fy < geom.FiltSz.Y; fy++ {
+					av := acc.Values[(iy+fy*dl.Y)*isx+ix:]
 					for fx := 0; fx < geom.FiltSz.X; fx++ {
-						fv := flt.Values[fst+fi]
-						iv := act * fv
-						iv += img.Value(iy+fy, ix+fx)
-						img.Set(iv, iy+fy, ix+fx)
+						av[fx*dl.X] += act * flt.Values[fi]
 						fi++
 					}
 				}
@@ -65,3 +93,59 @@ func Deconv(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain floa
 		}
 	}
 }
+
+// DeconvCoverage computes into cov the filter coverage of each image
+// pixel for Deconv with given geometry and filters: the sum of the
+// squared filter values over all filters and output positions that
+// include the pixel.  cov has the same shape as the image.
+// geom must already be configured for the image, e.g., by Conv or Deconv.
+func DeconvCoverage(geom *Geom, flt *tensor.Float32, cov *tensor.Float32) {
+	nf := flt.DimSize(0)
+	fy := flt.DimSize(1)
+	fx := flt.DimSize(2)
+	fsz := fx * fy
+	cov.SetShapeSizes(geom.In.Y, geom.In.X)
+	cov.SetZeros()
+	fsq := make([]float32, fsz)
+	for f := 0; f < nf; f++ {
+		for i, fv := range flt.Values[f*fsz : (f+1)*fsz] {
+			fsq[i] += fv * fv
+		}
+	}
+	ist := geom.Border.Sub(geom.FiltLt)
+	dl := geom.Dil()
+	isx := geom.In.X
+	for y := 0; y < geom.Out.Y; y++ {
+		iy := ist.Y + y*geom.Spacing.Y
+		for x := 0; x < geom.Out.X; x++ {
+			ix := ist.X + x*geom.Spacing.X
+			for py := 0; py < fy; py++ {
+				cv := cov.Values[(iy+py*dl.Y)*isx+ix:]
+				for px := 0; px < fx; px++ {
+					cv[px*dl.X] += fsq[py*fx+px]
+				}
+			}
+		}
+	}
+}
+
+// DeconvNorm performs Deconv, normalized by the filter coverage of
+// each pixel (see DeconvCoverage), so that pixels covered by many
+// overlapping filters are not over-counted, giving a properly
+// normalized reconstruction of the image.  The img is set to the
+// reconstruction, rather than accumulating into it as in Deconv, and
+// pixels with no coverage (e.g., the border) are set to 0.
+func DeconvNorm(geom *Geom, flt *tensor.Float32, img, out *tensor.Float32, gain float32) {
+	img.SetZeros()
+	Deconv(geom, flt, img, out, gain)
+	cov := Pool.GetLike(img)
+	DeconvCoverage(geom, flt, cov)
+	for i, c := range cov.Values {
+		if c > 0 {
+			img.Values[i] /= c
+		} else {
+			img.Values[i] = 0
+		}
+	}
+	Pool.Put(cov)
+}
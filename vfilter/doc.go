@@ -8,21 +8,167 @@ These apply tensor.Tensor filters to a 2D visual input via Conv
 (convolution) function, using filter-parallel approach:
 Each go routine does a different filter in a set of filters,
 e.g., different angles of Gabor filters.  This is coarse-grained,
-strictly parallel, and thus very efficient.
+strictly parallel, and thus very efficient.  The filters are run on
+a shared worker pool (see nproc.Run), with no goroutines spawned per
+call, and SetNumThreads sets the number of threads, where 1 runs
+everything in the calling goroutine (e.g., for WASM).
+
+ConvT, Conv1T, ConvDiffT, MaxPoolT, AvgPoolT, L2PoolT and L2PoolEnergyT
+are generic versions for any Float tensor type (e.g., tensor.Float64),
+sharing the same filtering code as the float32 versions.
+
+ConvContext, ConvDiffContext and MaxPoolContext take a context.Context
+for cancellation and progress reporting (see nproc.WithProgress),
+for long-running filtering in GUI applications.
 
 image.go contains routines for converting an image into the float32
 tensor.Float32 that is required for doing the convolution.
 * RGBToGrey converts an RGB image to a greyscale float32.
+* BandsToTensor converts the single-band images of a multispectral image
+to a [Band, Y, X] tensor, with bands as the outer dimension as for RGB.
+* RGBToTensorAlpha and RGBToGreyAlpha handle the alpha channel, compositing
+over a background color per the Alpha settings, and optionally returning
+the alpha values as a mask, which EdgeAvgMask and FadePadMask use to
+ignore transparent pixels.
+* Gray16ToTensor and NRGBA64ToTensor convert 16 bit images at full
+precision, and GreyTensorToImage16 produces a 16 bit image.
+* FloatImage holds float32 HDR or scientific image data (e.g., decoded
+from floating point TIFF or EXR files), which FloatImageToTensor
+converts without quantizing or clamping.
+
+ConvStats accumulates per-filter response statistics (mean, max,
+fraction non-zero) during Conv passes, for monitoring gain balance
+over a dataset.
+
+Conv1 and ConvDiff produce on / off polarity outputs for a single
+filter, with the polarities as the outer dimension by default, or as
+the inner-most dimension ([Y, X, 1, 2]) with Geom OutLayout set to
+PolarityInner, for direct use as the 4D pools of kwta.KWTAPool.
+
+ConvN convolves multi-channel filters [Filter, Channel, Y, X] over a
+multi-channel [Channel, Y, X] input (e.g., RGB), summing over channels
+per filter, for true color kernels such as double-opponent filters.
+
+ConvBatch and ConvImages filter a batch of images, parallel across
+both images and filters, reusing the output tensor across calls, and
+MaxPoolBatch, AvgPoolBatch, L2PoolBatch and L2PoolEnergyBatch pool
+the resulting [Image, Y, X, Polarity, Angle] batch outputs.
+
+ConvPhase preserves the signed response of each filter of a multi-phase
+bank (e.g., gabor quadrature pairs), instead of on / off polarities,
+and PhaseEnergy computes the phase-invariant energy from these.
 
 MaxPool function does Max-pooling over filtered results to reduce
 dimensionality, consistent with standard DCNN approaches.
+AvgPool does average-pooling with the same geometry, which preserves
+more information about the strength of graded activity.
+L2Pool does energy pooling (square root of the sum of squares), the
+standard complex-cell energy model, and L2PoolEnergy also pools over
+the polarities or phases, for phase-invariant complex cells from
+quadrature gabor pairs (see ConvPhase).
+Any combination of pool size and spacing can be used, including
+overlapping pools (e.g., 3x3 with a spacing of 1), with PoolOut giving
+the output size, and PoolPadded pools over a padded input with the
+same centered geometry as Conv.
+
+AngPool pools over neighboring angles at each position (max or
+gaussian-weighted average), broadening the orientation tuning without
+spatial downsampling, complementing MaxPool.
+
+HOG pools oriented filter outputs into block-normalized cell histograms
+over angles, as in the classic histogram of oriented gradients features,
+for recognition baselines.
+
+Layout builds a combined V1All-style [Y, X, Row, Angle] feature tensor
+from named feature sources, computing the row offsets, validating the
+shapes of the sources, and recording the resulting row layout as
+metadata, instead of calling FeatAgg and OuterAgg with hand-computed
+row offsets.  FeatAgg and OuterAgg return an error if the output does
+not fit, and FeatAggGrow and OuterAggGrow grow the output as needed.
+FeatCat concatenates a list of sources in one call.  OuterAgg maps
+any number of outer dimensions of its source (e.g., color and polarity
+of DoG outputs) to rows, in parallel over Y rows.
 
 Geom manages the geometry for going from an input image to the
-filtered output of that image.
+filtered output of that image.  Its Dilation spreads out the filter
+taps (atrous convolution), for larger receptive fields without larger
+filters, in Conv and the other convolution functions, and Deconv.
+Validate reports problems with the geometry, including a Border that
+had to be increased to fit the filter, and Save and Open persist it
+as JSON, e.g., in config files.
+
+GeomSet chains the geometry of multiple filtering and pooling stages
+(e.g., V1s, pooling, V1c), to map between the units of each stage and
+the input image pixels: RF returns the pixels that feed a unit, and
+Covering and Nearest return the units that cover a pixel, e.g., for
+receptive field visualization and attention routing.
 
 Unlike the C++ version, no wrapping or clipping is supported directly:
 all input images must be padded so that the filters can be applied with
 appropriate padding border, guaranteeing that there are no bounds issues.
-See WrapPad for wrapping-based padding.
+See WrapPad for wrapping-based padding, FadePad for fading to the
+mean edge value, and ReflectPad for mirroring the image at its edges,
+which avoids the spurious edges that wrapping produces at the borders
+of natural images.
+
+Pad provides a single entry point for all of these, along with
+clamping, zero and noise filling, selected by an EdgeModes parameter,
+which can also be recorded in the Geom Pad field.
+NoisePadRand fills the padding with noise from a seedable random source,
+sampled from the histogram of the edge values if NoiseFill Hist is set,
+instead of the gaussian of NoisePad, to match skewed or multimodal edges.
+
+PadFeatures (and WrapPadFeatures, FadePadFeatures) pad the outer
+spatial dimensions of 4D [Y, X, Polarity, Angle] feature tensors, as
+from Conv, and PaddedFeatures adds the padding to a Conv output, so
+that second-stage filters (e.g., V2) can be applied to each map
+(from FeatureMap) with Conv, without any bounds checking.
+
+ConvSafe pads an unpadded image internally, with a given EdgeModes,
+before calling Conv, for tensors from other sources that have not been
+padded, returning an error for invalid shapes instead of panicking.
+
+ConvSep convolves separable filters, given as row and column 1D
+filters, in two passes, which is much faster than Conv for larger
+separable filters such as gaussian blurs.
+
+Pyramid filters each level of a gaussian (or box) image pyramid with
+the same filters, for octave-spaced spatial frequency scales, keeping
+the output of each level, and aggregating all levels into one tensor
+at the resolution of the first level.  Downsample reduces an image to
+half its size as for each pyramid level.
+
+LogPolar resamples an image into a log-polar (foveated) representation
+around a fixation point, specified by a LogPolarGeom, with resolution
+decreasing with eccentricity as in the retinotopic map of V1, and
+LogPolarInverse maps it back into image coordinates.
+
+ConvTiled convolves a large image in tiles, getting the image region
+of each tile, including the halo needed by the filters, from an
+ImageSource, and writing each output tile into the full output and/or
+passing it to a TileFunc, so that very large images can be filtered
+without a padded copy of the whole image.
+
+ResizeTensor resizes image tensors directly, with Bilinear, Bicubic or
+Lanczos interpolation and anti-aliasing when downsampling, so that
+images do not need to be resized as image.Image values first.
+
+LocalContrastNorm divides each pixel, or each filter output, by the
+gaussian-weighted standard deviation over its neighborhood, after
+subtracting the local mean, as a retina / LGN front end.
+
+Deconv projects filter outputs back to an image, in parallel over
+filters, and DeconvNorm normalizes the reconstruction by the summed
+filter coverage of each pixel (DeconvCoverage), so it is not darker
+where the filters overlap less, e.g., at larger spacings.
+
+MSE, PSNR and SSIM score how well an image is preserved, e.g., by the
+round trip of filtering and reconstruction with Deconv, to compare
+filter banks, kwta and pooling settings in automated tests.
+
+GlobalPool (GlobalMaxPool, GlobalAvgPool) collapses the spatial
+dimensions of a 4D feature tensor into a 1D feature vector, e.g., a
+fixed-length image descriptor for classifiers from V1All outputs,
+and FeatPool and FeatReduce pool over the Polarity or Angle dimension.
 */
 package vfilter
@@ -0,0 +1,304 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _AngPoolsValues = []AngPools{0, 1}
+
+// AngPoolsN is the highest valid value for type AngPools, plus one.
+const AngPoolsN AngPools = 2
+
+var _AngPoolsValueMap = map[string]AngPools{`AngMax`: 0, `AngAvg`: 1}
+
+var _AngPoolsDescMap = map[AngPools]string{0: `AngMax takes the max over the neighboring angles`, 1: `AngAvg takes the average over the neighboring angles, weighted by a gaussian of the circular distance between angles`}
+
+var _AngPoolsMap = map[AngPools]string{0: `AngMax`, 1: `AngAvg`}
+
+// String returns the string representation of this AngPools value.
+func (i AngPools) String() string { return enums.String(i, _AngPoolsMap) }
+
+// SetString sets the AngPools value from its string representation,
+// and returns an error if the string is invalid.
+func (i *AngPools) SetString(s string) error {
+	return enums.SetString(i, s, _AngPoolsValueMap, "AngPools")
+}
+
+// Int64 returns the AngPools value as an int64.
+func (i AngPools) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the AngPools value from an int64.
+func (i *AngPools) SetInt64(in int64) { *i = AngPools(in) }
+
+// Desc returns the description of the AngPools value.
+func (i AngPools) Desc() string { return enums.Desc(i, _AngPoolsDescMap) }
+
+// AngPoolsValues returns all possible values for the type AngPools.
+func AngPoolsValues() []AngPools { return _AngPoolsValues }
+
+// Values returns all possible values for the type AngPools.
+func (i AngPools) Values() []enums.Enum { return enums.Values(_AngPoolsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i AngPools) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *AngPools) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "AngPools") }
+
+var _ConvStatTypesValues = []ConvStatTypes{0, 1, 2}
+
+// ConvStatTypesN is the highest valid value for type ConvStatTypes, plus one.
+const ConvStatTypesN ConvStatTypes = 3
+
+var _ConvStatTypesValueMap = map[string]ConvStatTypes{`ConvMean`: 0, `ConvMax`: 1, `ConvNonZero`: 2}
+
+var _ConvStatTypesDescMap = map[ConvStatTypes]string{0: `ConvMean is the mean response over all output positions`, 1: `ConvMax is the maximum response over all output positions`, 2: `ConvNonZero is the fraction of output positions with a non-zero response`}
+
+var _ConvStatTypesMap = map[ConvStatTypes]string{0: `ConvMean`, 1: `ConvMax`, 2: `ConvNonZero`}
+
+// String returns the string representation of this ConvStatTypes value.
+func (i ConvStatTypes) String() string { return enums.String(i, _ConvStatTypesMap) }
+
+// SetString sets the ConvStatTypes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ConvStatTypes) SetString(s string) error {
+	return enums.SetString(i, s, _ConvStatTypesValueMap, "ConvStatTypes")
+}
+
+// Int64 returns the ConvStatTypes value as an int64.
+func (i ConvStatTypes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ConvStatTypes value from an int64.
+func (i *ConvStatTypes) SetInt64(in int64) { *i = ConvStatTypes(in) }
+
+// Desc returns the description of the ConvStatTypes value.
+func (i ConvStatTypes) Desc() string { return enums.Desc(i, _ConvStatTypesDescMap) }
+
+// ConvStatTypesValues returns all possible values for the type ConvStatTypes.
+func ConvStatTypesValues() []ConvStatTypes { return _ConvStatTypesValues }
+
+// Values returns all possible values for the type ConvStatTypes.
+func (i ConvStatTypes) Values() []enums.Enum { return enums.Values(_ConvStatTypesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ConvStatTypes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ConvStatTypes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ConvStatTypes")
+}
+
+var _OutputLayoutsValues = []OutputLayouts{0, 1}
+
+// OutputLayoutsN is the highest valid value for type OutputLayouts, plus one.
+const OutputLayoutsN OutputLayouts = 2
+
+var _OutputLayoutsValueMap = map[string]OutputLayouts{`OnOffOuter`: 0, `PolarityInner`: 1}
+
+var _OutputLayoutsDescMap = map[OutputLayouts]string{0: `OnOffOuter has the polarities as the outer dimension: [2, Y, X], with the on (positive) values first.`, 1: `PolarityInner has the polarities interleaved as the inner-most dimension: [Y, X, 1, 2], which is the 4D pool structure used by kwta.KWTAPool, with a pool of on, off units at each location.`}
+
+var _OutputLayoutsMap = map[OutputLayouts]string{0: `OnOffOuter`, 1: `PolarityInner`}
+
+// String returns the string representation of this OutputLayouts value.
+func (i OutputLayouts) String() string { return enums.String(i, _OutputLayoutsMap) }
+
+// SetString sets the OutputLayouts value from its string representation,
+// and returns an error if the string is invalid.
+func (i *OutputLayouts) SetString(s string) error {
+	return enums.SetString(i, s, _OutputLayoutsValueMap, "OutputLayouts")
+}
+
+// Int64 returns the OutputLayouts value as an int64.
+func (i OutputLayouts) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the OutputLayouts value from an int64.
+func (i *OutputLayouts) SetInt64(in int64) { *i = OutputLayouts(in) }
+
+// Desc returns the description of the OutputLayouts value.
+func (i OutputLayouts) Desc() string { return enums.Desc(i, _OutputLayoutsDescMap) }
+
+// OutputLayoutsValues returns all possible values for the type OutputLayouts.
+func OutputLayoutsValues() []OutputLayouts { return _OutputLayoutsValues }
+
+// Values returns all possible values for the type OutputLayouts.
+func (i OutputLayouts) Values() []enums.Enum { return enums.Values(_OutputLayoutsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i OutputLayouts) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *OutputLayouts) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "OutputLayouts")
+}
+
+var _EdgeModesValues = []EdgeModes{0, 1, 2, 3, 4, 5}
+
+// EdgeModesN is the highest valid value for type EdgeModes, plus one.
+const EdgeModesN EdgeModes = 6
+
+var _EdgeModesValueMap = map[string]EdgeModes{`Wrap`: 0, `Fade`: 1, `Reflect`: 2, `Clamp`: 3, `Zero`: 4, `Noise`: 5}
+
+var _EdgeModesDescMap = map[EdgeModes]string{0: `Wrap wraps the image around, as in WrapPad: the padding on the left side is from the right side of the image, etc.`, 1: `Fade fades the edge values toward the mean edge value, as in FadePad.`, 2: `Reflect mirrors the image at its edges, as in ReflectPad.`, 3: `Clamp extends the edge values into the padding, as in ClampPad.`, 4: `Zero fills the padding with zeros, as in ZeroPad.`, 5: `Noise fills the padding with gaussian noise with the mean and standard deviation of the edge values, as in NoisePad.`}
+
+var _EdgeModesMap = map[EdgeModes]string{0: `Wrap`, 1: `Fade`, 2: `Reflect`, 3: `Clamp`, 4: `Zero`, 5: `Noise`}
+
+// String returns the string representation of this EdgeModes value.
+func (i EdgeModes) String() string { return enums.String(i, _EdgeModesMap) }
+
+// SetString sets the EdgeModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *EdgeModes) SetString(s string) error {
+	return enums.SetString(i, s, _EdgeModesValueMap, "EdgeModes")
+}
+
+// Int64 returns the EdgeModes value as an int64.
+func (i EdgeModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the EdgeModes value from an int64.
+func (i *EdgeModes) SetInt64(in int64) { *i = EdgeModes(in) }
+
+// Desc returns the description of the EdgeModes value.
+func (i EdgeModes) Desc() string { return enums.Desc(i, _EdgeModesDescMap) }
+
+// EdgeModesValues returns all possible values for the type EdgeModes.
+func EdgeModesValues() []EdgeModes { return _EdgeModesValues }
+
+// Values returns all possible values for the type EdgeModes.
+func (i EdgeModes) Values() []enums.Enum { return enums.Values(_EdgeModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i EdgeModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *EdgeModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "EdgeModes")
+}
+
+var _PoolOpsValues = []PoolOps{0, 1, 2}
+
+// PoolOpsN is the highest valid value for type PoolOps, plus one.
+const PoolOpsN PoolOps = 3
+
+var _PoolOpsValueMap = map[string]PoolOps{`PoolMax`: 0, `PoolAvg`: 1, `PoolL2`: 2}
+
+var _PoolOpsDescMap = map[PoolOps]string{0: `PoolMax takes the max over the pool, as in MaxPool`, 1: `PoolAvg takes the average over the pool, as in AvgPool`, 2: `PoolL2 takes the square root of the sum of squares over the pool, as in L2Pool`}
+
+var _PoolOpsMap = map[PoolOps]string{0: `PoolMax`, 1: `PoolAvg`, 2: `PoolL2`}
+
+// String returns the string representation of this PoolOps value.
+func (i PoolOps) String() string { return enums.String(i, _PoolOpsMap) }
+
+// SetString sets the PoolOps value from its string representation,
+// and returns an error if the string is invalid.
+func (i *PoolOps) SetString(s string) error {
+	return enums.SetString(i, s, _PoolOpsValueMap, "PoolOps")
+}
+
+// Int64 returns the PoolOps value as an int64.
+func (i PoolOps) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the PoolOps value from an int64.
+func (i *PoolOps) SetInt64(in int64) { *i = PoolOps(in) }
+
+// Desc returns the description of the PoolOps value.
+func (i PoolOps) Desc() string { return enums.Desc(i, _PoolOpsDescMap) }
+
+// PoolOpsValues returns all possible values for the type PoolOps.
+func PoolOpsValues() []PoolOps { return _PoolOpsValues }
+
+// Values returns all possible values for the type PoolOps.
+func (i PoolOps) Values() []enums.Enum { return enums.Values(_PoolOpsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i PoolOps) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *PoolOps) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "PoolOps") }
+
+var _PyramidModesValues = []PyramidModes{0, 1}
+
+// PyramidModesN is the highest valid value for type PyramidModes, plus one.
+const PyramidModesN PyramidModes = 2
+
+var _PyramidModesValueMap = map[string]PyramidModes{`PyrGauss`: 0, `PyrBox`: 1}
+
+var _PyramidModesDescMap = map[PyramidModes]string{0: `PyrGauss blurs with a 5 tap binomial (gaussian) kernel before taking every other pixel, as in the classic Burt &amp; Adelson gaussian pyramid, which minimizes aliasing.`, 1: `PyrBox averages each 2x2 block of pixels, which is faster, but has more aliasing of high spatial frequencies.`}
+
+var _PyramidModesMap = map[PyramidModes]string{0: `PyrGauss`, 1: `PyrBox`}
+
+// String returns the string representation of this PyramidModes value.
+func (i PyramidModes) String() string { return enums.String(i, _PyramidModesMap) }
+
+// SetString sets the PyramidModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *PyramidModes) SetString(s string) error {
+	return enums.SetString(i, s, _PyramidModesValueMap, "PyramidModes")
+}
+
+// Int64 returns the PyramidModes value as an int64.
+func (i PyramidModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the PyramidModes value from an int64.
+func (i *PyramidModes) SetInt64(in int64) { *i = PyramidModes(in) }
+
+// Desc returns the description of the PyramidModes value.
+func (i PyramidModes) Desc() string { return enums.Desc(i, _PyramidModesDescMap) }
+
+// PyramidModesValues returns all possible values for the type PyramidModes.
+func PyramidModesValues() []PyramidModes { return _PyramidModesValues }
+
+// Values returns all possible values for the type PyramidModes.
+func (i PyramidModes) Values() []enums.Enum { return enums.Values(_PyramidModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i PyramidModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *PyramidModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "PyramidModes")
+}
+
+var _ResizeModesValues = []ResizeModes{0, 1, 2}
+
+// ResizeModesN is the highest valid value for type ResizeModes, plus one.
+const ResizeModesN ResizeModes = 3
+
+var _ResizeModesValueMap = map[string]ResizeModes{`Bilinear`: 0, `Bicubic`: 1, `Lanczos`: 2}
+
+var _ResizeModesDescMap = map[ResizeModes]string{0: `Bilinear interpolates linearly between the 2 nearest pixels in each direction, which is fast, but somewhat blurry.`, 1: `Bicubic uses the Catmull-Rom cubic kernel over the 4 nearest pixels in each direction, which is sharper than Bilinear.`, 2: `Lanczos uses the Lanczos windowed sinc kernel over the 6 nearest pixels in each direction, which is the sharpest, and best preserves the spatial frequencies that drive the filters.`}
+
+var _ResizeModesMap = map[ResizeModes]string{0: `Bilinear`, 1: `Bicubic`, 2: `Lanczos`}
+
+// String returns the string representation of this ResizeModes value.
+func (i ResizeModes) String() string { return enums.String(i, _ResizeModesMap) }
+
+// SetString sets the ResizeModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ResizeModes) SetString(s string) error {
+	return enums.SetString(i, s, _ResizeModesValueMap, "ResizeModes")
+}
+
+// Int64 returns the ResizeModes value as an int64.
+func (i ResizeModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ResizeModes value from an int64.
+func (i *ResizeModes) SetInt64(in int64) { *i = ResizeModes(in) }
+
+// Desc returns the description of the ResizeModes value.
+func (i ResizeModes) Desc() string { return enums.Desc(i, _ResizeModesDescMap) }
+
+// ResizeModesValues returns all possible values for the type ResizeModes.
+func ResizeModesValues() []ResizeModes { return _ResizeModesValues }
+
+// Values returns all possible values for the type ResizeModes.
+func (i ResizeModes) Values() []enums.Enum { return enums.Values(_ResizeModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ResizeModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ResizeModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ResizeModes")
+}
@@ -4,7 +4,29 @@
 
 package vfilter
 
-import "image"
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+
+	"cogentcore.org/core/tensor"
+)
+
+// OutputLayouts are the layouts of the on / off polarity outputs
+// of Conv1 and ConvDiff.
+type OutputLayouts int32 //enums:enum
+
+const (
+	// OnOffOuter has the polarities as the outer dimension: [2, Y, X],
+	// with the on (positive) values first.
+	OnOffOuter OutputLayouts = iota
+
+	// PolarityInner has the polarities interleaved as the inner-most
+	// dimension: [Y, X, 1, 2], which is the 4D pool structure used
+	// by kwta.KWTAPool, with a pool of on, off units at each location.
+	PolarityInner
+)
 
 // Geom contains the filtering geometry info for a given filter pass.
 type Geom struct {
@@ -24,11 +46,45 @@ type Geom struct {
 	// full size of filter
 	FiltSz image.Point
 
-	// computed size of left/top size of filter
+	// dilation (atrous) factor: spacing between the filter taps in the input, so that the filter covers (FiltSz-1)*Dilation+1 pixels, for larger receptive fields without larger filters -- 0 or 1 is no dilation
+	Dilation image.Point
+
+	// computed size of left/top size of the (dilated) filter extent
 	FiltLt image.Point
 
-	// computed size of right/bottom size of filter (FiltSz - FiltLeft)
+	// computed size of right/bottom size of the (dilated) filter extent (FiltExt - FiltLeft)
 	FiltRt image.Point
+
+	// how the border of the input was padded (see Pad) -- set by the caller for reference, and not used by Conv
+	Pad EdgeModes
+
+	// layout of the on / off polarities in the output of Conv1 and ConvDiff: OnOffOuter [2, Y, X] or PolarityInner [Y, X, 1, 2], which has the 4D pool structure of kwta.KWTAPool
+	OutLayout OutputLayouts
+
+	// whether UpdtFilt has increased a non-zero Border to FiltRt since the last Set, reported by Validate
+	clamped bool
+
+	// the Border prior to being increased by UpdtFilt
+	preClamp image.Point
+}
+
+// SetPolShape sets the shape of given on / off polarity output
+// of Conv1 or ConvDiff according to OutLayout, for the current Out size.
+func (ge *Geom) SetPolShape(out tensor.Values) {
+	if ge.OutLayout == PolarityInner {
+		out.SetShapeSizes(ge.Out.Y, ge.Out.X, 1, 2)
+	} else {
+		out.SetShapeSizes(2, ge.Out.Y, ge.Out.X)
+	}
+}
+
+// polIndex returns the index into the Values of on / off polarity
+// output for given polarity and output position, according to OutLayout.
+func (ge *Geom) polIndex(pol, y, x int) int {
+	if ge.OutLayout == PolarityInner {
+		return (y*ge.Out.X+x)*2 + pol
+	}
+	return (pol*ge.Out.Y+y)*ge.Out.X + x
 }
 
 // Set sets the basic geometry params
@@ -36,6 +92,7 @@ func (ge *Geom) Set(border, spacing, filtSz image.Point) {
 	ge.Border = border
 	ge.Spacing = spacing
 	ge.FiltSz = filtSz
+	ge.clamped = false
 	ge.UpdtFilt()
 }
 
@@ -47,11 +104,37 @@ func LeftHalf(x int) int {
 	return (x - 1) / 2
 }
 
-// UpdtFilt updates filter sizes, and ensures that Border >= FiltRt
+// Dil returns the Dilation, with values < 1 set to 1 (no dilation)
+func (ge *Geom) Dil() image.Point {
+	return image.Point{max(ge.Dilation.X, 1), max(ge.Dilation.Y, 1)}
+}
+
+// FiltExt returns the extent of the filter in the input,
+// including Dilation: (FiltSz-1)*Dilation+1
+func (ge *Geom) FiltExt() image.Point {
+	dl := ge.Dil()
+	ext := image.Point{(ge.FiltSz.X-1)*dl.X + 1, (ge.FiltSz.Y-1)*dl.Y + 1}
+	if ge.FiltSz.X <= 0 {
+		ext.X = 0
+	}
+	if ge.FiltSz.Y <= 0 {
+		ext.Y = 0
+	}
+	return ext
+}
+
+// UpdtFilt updates filter sizes, and ensures that Border >= FiltRt.
+// A zero Border is thus set to the minimum FiltRt, but increasing a
+// non-zero Border is reported as an error by Validate.
 func (ge *Geom) UpdtFilt() {
-	ge.FiltLt.X = LeftHalf(ge.FiltSz.X)
-	ge.FiltLt.Y = LeftHalf(ge.FiltSz.Y)
-	ge.FiltRt = ge.FiltSz.Sub(ge.FiltLt)
+	ext := ge.FiltExt()
+	ge.FiltLt.X = LeftHalf(ext.X)
+	ge.FiltLt.Y = LeftHalf(ext.Y)
+	ge.FiltRt = ext.Sub(ge.FiltLt)
+	if !ge.clamped && ((ge.Border.X > 0 && ge.Border.X < ge.FiltRt.X) || (ge.Border.Y > 0 && ge.Border.Y < ge.FiltRt.Y)) {
+		ge.clamped = true
+		ge.preClamp = ge.Border
+	}
 	if ge.Border.X < ge.FiltRt.X {
 		ge.Border.X = ge.FiltRt.X
 	}
@@ -60,10 +143,79 @@ func (ge *Geom) UpdtFilt() {
 	}
 }
 
-// SetSize sets the input size, and computes output from that.
+// SetSize sets the input size, and computes output from that,
+// separately for each axis, so the Spacing can differ in X and Y.
 func (ge *Geom) SetSize(inSize image.Point) {
 	ge.In = inSize
 	b2 := ge.Border.Mul(2)
 	av := ge.In.Sub(b2)
-	ge.Out = av.Div(ge.Spacing.X) // only 1
+	ge.Out = image.Point{av.X / ge.Spacing.X, av.Y / ge.Spacing.Y}
+}
+
+// Validate returns a descriptive error for the first problem found with
+// the geometry, or nil if there are none: non-positive Spacing, negative
+// filter size or Dilation, a Border that is less than FiltRt (including
+// a non-zero one that was increased by UpdtFilt since the last Set,
+// which would otherwise hide a misconfigured Border), filter sizes or an Out size
+// that are not up to date with FiltSz and In (see UpdtFilt and SetSize),
+// or an In size that is too small for the Border.
+func (ge *Geom) Validate() error {
+	if ge.Spacing.X <= 0 || ge.Spacing.Y <= 0 {
+		return fmt.Errorf("vfilter.Geom.Validate: Spacing must be positive: %v", ge.Spacing)
+	}
+	if ge.FiltSz.X < 0 || ge.FiltSz.Y < 0 {
+		return fmt.Errorf("vfilter.Geom.Validate: FiltSz must not be negative: %v", ge.FiltSz)
+	}
+	if ge.Dilation.X < 0 || ge.Dilation.Y < 0 {
+		return fmt.Errorf("vfilter.Geom.Validate: Dilation must not be negative: %v", ge.Dilation)
+	}
+	ext := ge.FiltExt()
+	lt := image.Point{LeftHalf(ext.X), LeftHalf(ext.Y)}
+	rt := ext.Sub(lt)
+	if ge.FiltLt != lt || ge.FiltRt != rt {
+		return fmt.Errorf("vfilter.Geom.Validate: FiltLt %v and FiltRt %v do not match FiltSz %v and Dilation %v: call UpdtFilt", ge.FiltLt, ge.FiltRt, ge.FiltSz, ge.Dilation)
+	}
+	if ge.clamped {
+		return fmt.Errorf("vfilter.Geom.Validate: Border %v is less than FiltRt %v, and was increased to %v by UpdtFilt", ge.preClamp, rt, ge.Border)
+	}
+	if ge.Border.X < rt.X || ge.Border.Y < rt.Y {
+		return fmt.Errorf("vfilter.Geom.Validate: Border %v is less than FiltRt %v", ge.Border, rt)
+	}
+	if ge.In == (image.Point{}) {
+		return nil
+	}
+	av := ge.In.Sub(ge.Border.Mul(2))
+	if av.X <= 0 || av.Y <= 0 {
+		return fmt.Errorf("vfilter.Geom.Validate: In size %v is too small for Border %v", ge.In, ge.Border)
+	}
+	out := image.Point{av.X / ge.Spacing.X, av.Y / ge.Spacing.Y}
+	if ge.Out != out {
+		return fmt.Errorf("vfilter.Geom.Validate: Out size %v does not match In size %v, which has Out size %v: call SetSize", ge.Out, ge.In, out)
+	}
+	return nil
+}
+
+// Save saves the geometry to given JSON file, so that it can be
+// persisted with config files, and restored with Open.
+func (ge *Geom) Save(filename string) error {
+	b, err := json.MarshalIndent(ge, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// Open opens the geometry from given JSON file, as saved by Save,
+// returning any error from Validate for the geometry as opened.
+func (ge *Geom) Open(filename string) error {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var g Geom
+	if err := json.Unmarshal(b, &g); err != nil {
+		return err
+	}
+	*ge = g
+	return ge.Validate()
 }
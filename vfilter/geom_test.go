@@ -0,0 +1,321 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// anisoSpacing is the rectangular spacing used in the tests
+var anisoSpacing = image.Point{2, 4}
+
+func randTensor(rnd *rand.Rand, off float32, sizes ...int) *tensor.Float32 {
+	tsr := tensor.NewFloat32(sizes...)
+	for i := range tsr.Values {
+		tsr.Values[i] = rnd.Float32() - off
+	}
+	return tsr
+}
+
+// refConv returns the direct convolution of filter f at output y, x
+func refConv(geom *Geom, flt, img *tensor.Float32, f, y, x int) float32 {
+	ist := geom.Border.Sub(geom.FiltLt)
+	iy := ist.Y + y*geom.Spacing.Y
+	ix := ist.X + x*geom.Spacing.X
+	var sum float32
+	for fy := 0; fy < geom.FiltSz.Y; fy++ {
+		for fx := 0; fx < geom.FiltSz.X; fx++ {
+			sum += flt.Value(f, fy, fx) * img.Value(iy+fy, ix+fx)
+		}
+	}
+	return sum
+}
+
+func TestGeomAnisoSetSize(t *testing.T) {
+	var geom Geom
+	geom.Set(image.Point{2, 2}, anisoSpacing, image.Point{4, 4})
+	geom.SetSize(image.Point{24, 20})
+	if want := (image.Point{10, 4}); geom.Out != want {
+		t.Errorf("Out: %v != %v", geom.Out, want)
+	}
+	geom.Spacing = image.Point{4, 2}
+	geom.SetSize(image.Point{24, 20})
+	if want := (image.Point{5, 8}); geom.Out != want {
+		t.Errorf("Out: %v != %v", geom.Out, want)
+	}
+}
+
+func TestConvAniso(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	img := randTensor(rnd, 0, 28, 22)
+	flt := randTensor(rnd, 0.5, 3, 6, 6)
+	var geom Geom
+	geom.Set(image.Point{3, 3}, anisoSpacing, image.Point{6, 6})
+	var out tensor.Float32
+	Conv(&geom, flt, img, &out, 1)
+	if want := []int{5, 8, 2, 3}; !slices.Equal(out.ShapeSizes(), want) {
+		t.Fatalf("Conv shape: %v != %v", out.ShapeSizes(), want)
+	}
+	for y := 0; y < geom.Out.Y; y++ {
+		for x := 0; x < geom.Out.X; x++ {
+			for f := 0; f < 3; f++ {
+				ref := refConv(&geom, flt, img, f, y, x)
+				got := out.Value(y, x, 0, f) - out.Value(y, x, 1, f)
+				if math32.Abs(got-ref) > 1.0e-5 {
+					t.Errorf("Conv %d, %d, %d: %g != %g", y, x, f, got, ref)
+				}
+			}
+		}
+	}
+
+	// Conv1 and ConvDiff with the first filter
+	flt1 := tensor.NewFloat32(6, 6)
+	copy(flt1.Values, flt.Values[:36])
+	var out1, outd tensor.Float32
+	geom1 := Geom{}
+	geom1.Set(image.Point{3, 3}, anisoSpacing, image.Point{6, 6})
+	Conv1(&geom1, flt1, img, &out1, 1)
+	if want := []int{2, 5, 8}; !slices.Equal(out1.ShapeSizes(), want) {
+		t.Fatalf("Conv1 shape: %v != %v", out1.ShapeSizes(), want)
+	}
+	zero := tensor.NewFloat32(6, 6)
+	ConvDiff(&geom1, flt1, zero, img, img, &outd, 1, 1)
+	if want := []int{2, 5, 8}; !slices.Equal(outd.ShapeSizes(), want) {
+		t.Fatalf("ConvDiff shape: %v != %v", outd.ShapeSizes(), want)
+	}
+	for y := 0; y < geom.Out.Y; y++ {
+		for x := 0; x < geom.Out.X; x++ {
+			ref := out.Value(y, x, 0, 0) - out.Value(y, x, 1, 0)
+			if got := out1.Value(0, y, x) - out1.Value(1, y, x); math32.Abs(got-ref) > 1.0e-5 {
+				t.Errorf("Conv1 %d, %d: %g != %g", y, x, got, ref)
+			}
+			if got := outd.Value(0, y, x) - outd.Value(1, y, x); math32.Abs(got-ref) > 1.0e-5 {
+				t.Errorf("ConvDiff %d, %d: %g != %g", y, x, got, ref)
+			}
+		}
+	}
+}
+
+func TestDeconvAniso(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	flt := randTensor(rnd, 0.5, 2, 4, 4)
+	var geom Geom
+	geom.Set(image.Point{2, 2}, anisoSpacing, image.Point{4, 4})
+	img := tensor.NewFloat32(20, 24)
+	geom.SetSize(image.Point{24, 20})
+	act := tensor.NewFloat32(geom.Out.Y, geom.Out.X, 2, 2)
+	act.Set(1, 1, 2, 1, 0) // one off unit
+	Deconv(&geom, flt, img, act, 1)
+	if geom.Out != (image.Point{10, 4}) {
+		t.Fatalf("Out: %v", geom.Out)
+	}
+	ist := geom.Border.Sub(geom.FiltLt)
+	iy := ist.Y + 1*geom.Spacing.Y
+	ix := ist.X + 2*geom.Spacing.X
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 24; x++ {
+			var want float32
+			if y >= iy && y < iy+4 && x >= ix && x < ix+4 {
+				want = flt.Value(0, y-iy, x-ix)
+			}
+			if got := img.Value(y, x); math32.Abs(got-want) > 1.0e-6 {
+				t.Errorf("Deconv %d, %d: %g != %g", y, x, got, want)
+			}
+		}
+	}
+}
+
+func TestPoolAniso(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	in := randTensor(rnd, 0, 8, 12, 2, 3)
+	var out, avg, l2, l2e, un tensor.Float32
+	spc := image.Point{3, 2}
+	for _, psize := range []image.Point{spc, spc.Mul(2)} {
+		MaxPool(psize, spc, in, &out)
+		AvgPool(psize, spc, in, &avg)
+		L2Pool(psize, spc, in, &l2)
+		L2PoolEnergy(psize, spc, in, &l2e)
+		oy, ox := 4, 4
+		if psize != spc {
+			oy, ox = 3, 3
+		}
+		if want := []int{oy, ox, 2, 3}; !slices.Equal(out.ShapeSizes(), want) || !slices.Equal(avg.ShapeSizes(), want) {
+			t.Fatalf("MaxPool %v shape: %v, AvgPool shape: %v != %v", psize, out.ShapeSizes(), avg.ShapeSizes(), want)
+		}
+		if want := []int{oy, ox, 1, 3}; !slices.Equal(l2e.ShapeSizes(), want) {
+			t.Fatalf("L2PoolEnergy %v shape: %v != %v", psize, l2e.ShapeSizes(), want)
+		}
+		for y := 0; y < oy; y++ {
+			for x := 0; x < ox; x++ {
+				var mx, sum, ssq, ssqe float32
+				for py := 0; py < psize.Y; py++ {
+					for px := 0; px < psize.X; px++ {
+						iv := in.Value(y*spc.Y+py, x*spc.X+px, 1, 2)
+						mx = max(mx, iv)
+						sum += iv
+						ssq += iv * iv
+						ov := in.Value(y*spc.Y+py, x*spc.X+px, 0, 2)
+						ssqe += iv*iv + ov*ov
+					}
+				}
+				if got := out.Value(y, x, 1, 2); got != mx {
+					t.Errorf("MaxPool %v %d, %d: %g != %g", psize, y, x, got, mx)
+				}
+				mean := sum / float32(psize.X*psize.Y)
+				if got := avg.Value(y, x, 1, 2); math32.Abs(got-mean) > 1.0e-6 {
+					t.Errorf("AvgPool %v %d, %d: %g != %g", psize, y, x, got, mean)
+				}
+				if got := l2.Value(y, x, 1, 2); math32.Abs(got-math32.Sqrt(ssq)) > 1.0e-5 {
+					t.Errorf("L2Pool %v %d, %d: %g != %g", psize, y, x, got, math32.Sqrt(ssq))
+				}
+				if got := l2e.Value(y, x, 0, 2); math32.Abs(got-math32.Sqrt(ssqe)) > 1.0e-5 {
+					t.Errorf("L2PoolEnergy %v %d, %d: %g != %g", psize, y, x, got, math32.Sqrt(ssqe))
+				}
+			}
+		}
+		if psize != spc {
+			continue
+		}
+		tensor.SetShapeFrom(&un, in)
+		UnPool(psize, spc, &un, &out, false)
+		for py := 0; py < psize.Y; py++ {
+			for px := 0; px < psize.X; px++ {
+				if v := un.Value(spc.Y+py, spc.X+px, 1, 2); v != out.Value(1, 1, 1, 2) {
+					t.Errorf("UnPool %d, %d: %g != %g", py, px, v, out.Value(1, 1, 1, 2))
+				}
+			}
+		}
+	}
+}
+
+func TestGeomValidate(t *testing.T) {
+	var geom Geom
+	geom.Set(image.Point{0, 0}, image.Point{2, 2}, image.Point{6, 6})
+	if err := geom.Validate(); err != nil {
+		t.Errorf("zero Border: %v", err)
+	}
+	geom.SetSize(image.Point{30, 26})
+	if err := geom.Validate(); err != nil {
+		t.Errorf("SetSize: %v", err)
+	}
+	geom.In.X = 40
+	if err := geom.Validate(); err == nil {
+		t.Errorf("expected error for In that does not match Out")
+	}
+	geom.Set(image.Point{1, 1}, image.Point{2, 2}, image.Point{6, 6})
+	if err := geom.Validate(); err == nil {
+		t.Errorf("expected error for Border increased by UpdtFilt")
+	}
+	geom.Set(image.Point{3, 3}, image.Point{0, 2}, image.Point{6, 6})
+	if err := geom.Validate(); err == nil {
+		t.Errorf("expected error for zero Spacing")
+	}
+	geom.Set(image.Point{3, 3}, image.Point{2, 2}, image.Point{6, 6})
+	geom.FiltSz = image.Point{8, 8}
+	if err := geom.Validate(); err == nil {
+		t.Errorf("expected error for FiltSz without UpdtFilt")
+	}
+}
+
+func TestGeomSaveOpen(t *testing.T) {
+	var geom Geom
+	geom.Set(image.Point{6, 6}, anisoSpacing, image.Point{5, 7})
+	geom.Dilation = image.Point{2, 1}
+	geom.UpdtFilt()
+	if err := geom.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	geom.SetSize(image.Point{40, 36})
+	geom.Pad = Reflect
+	geom.OutLayout = PolarityInner
+	fn := t.TempDir() + "/geom.json"
+	if err := geom.Save(fn); err != nil {
+		t.Fatal(err)
+	}
+	var og Geom
+	if err := og.Open(fn); err != nil {
+		t.Fatal(err)
+	}
+	if og != geom {
+		t.Errorf("Open: %v != %v", og, geom)
+	}
+}
+
+// nonZeroUnits returns the bounding rectangle of the units (X, Y) of
+// given [Y, X, Polarity, Filter] output with non-zero on values
+func nonZeroUnits(out *tensor.Float32) image.Rectangle {
+	var r image.Rectangle
+	for y := range out.DimSize(0) {
+		for x := range out.DimSize(1) {
+			if out.Value(y, x, 0, 0) > 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+func TestGeomSet(t *testing.T) {
+	isz := image.Point{30, 26}
+	var g1, g3 Geom
+	g1.Set(image.Point{3, 3}, image.Point{2, 2}, image.Point{5, 5})
+	g3.Set(image.Point{0, 0}, image.Point{1, 1}, image.Point{3, 3})
+	var gs GeomSet
+	gs.Add("V1s", &g1)
+	gs.AddPool("Pool", image.Point{2, 2}, image.Point{2, 2})
+	gs.Add("V1c", &g3)
+	gs.SetSize(isz)
+
+	ones := func(sz int) *tensor.Float32 {
+		flt := tensor.NewFloat32(1, sz, sz)
+		for i := range flt.Values {
+			flt.Values[i] = 1
+		}
+		return flt
+	}
+	var o1, o2, o3 tensor.Float32
+	for _, p := range []image.Point{{0, 0}, {7, 5}, {29, 25}, {12, 20}, {1, 24}} {
+		img := tensor.NewFloat32(isz.Y, isz.X)
+		img.Set(1, p.Y, p.X)
+		if err := ConvSafe(&g1, ones(5), img, &o1, 1, Zero); err != nil {
+			t.Fatal(err)
+		}
+		MaxPool(image.Point{2, 2}, image.Point{2, 2}, &o1, &o2)
+		pimg := tensor.NewFloat32(o2.DimSize(0), o2.DimSize(1))
+		for y := range o2.DimSize(0) {
+			for x := range o2.DimSize(1) {
+				pimg.Set(o2.Value(y, x, 0, 0), y, x)
+			}
+		}
+		if err := ConvSafe(&g3, ones(3), pimg, &o3, 1, Zero); err != nil {
+			t.Fatal(err)
+		}
+		for si, out := range []*tensor.Float32{&o1, &o2, &o3} {
+			if sz := (image.Point{out.DimSize(1), out.DimSize(0)}); sz != gs.Stages[si].Out {
+				t.Errorf("stage %d Out: %v != %v", si, gs.Stages[si].Out, sz)
+			}
+			cov := gs.Covering(si, p)
+			if nz := nonZeroUnits(out); nz != cov {
+				t.Errorf("stage %d pixel %v Covering: %v != %v", si, p, cov, nz)
+			}
+			for y := cov.Min.Y; y < cov.Max.Y; y++ {
+				for x := cov.Min.X; x < cov.Max.X; x++ {
+					if !p.In(gs.RF(si, image.Point{x, y})) {
+						t.Errorf("stage %d pixel %v not in RF of unit %v: %v", si, p, image.Point{x, y}, gs.RF(si, image.Point{x, y}))
+					}
+				}
+			}
+			if nr := gs.Nearest(si, p); !cov.Empty() && !nr.In(cov) {
+				t.Errorf("stage %d pixel %v Nearest %v not in Covering %v", si, p, nr, cov)
+			}
+		}
+	}
+}
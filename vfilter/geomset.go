@@ -0,0 +1,191 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+
+	"cogentcore.org/core/math32"
+)
+
+// GeomStage is one filtering or pooling stage of a GeomSet
+type GeomStage struct {
+
+	// name of the stage, e.g., V1s
+	Name string
+
+	// geometry of a filtering stage (if not Pool), where the input is the output of the prior stage (or the input image for the first stage), padded by Border on all sides
+	Geom Geom `display:"inline"`
+
+	// whether this is a pooling stage, as in MaxPool, with PoolSize and PoolSpacing
+	Pool bool
+
+	// size of the pools, for a pooling stage
+	PoolSize image.Point
+
+	// spacing of the pools, for a pooling stage
+	PoolSpacing image.Point
+
+	// size of the output of this stage, computed by SetSize
+	Out image.Point `edit:"-"`
+}
+
+// mapping returns the offset, stride and extent of the stage: output
+// unit y takes input from [off + y*stride, off + y*stride + ext) of
+// its (unpadded) input
+func (gs *GeomStage) mapping() (off, stride, ext image.Point) {
+	if gs.Pool {
+		return image.Point{}, gs.PoolSpacing, gs.PoolSize
+	}
+	return gs.Geom.FiltLt.Mul(-1), gs.Geom.Spacing, gs.Geom.FiltExt()
+}
+
+// GeomSet chains the geometry of a sequence of filtering and pooling
+// stages, e.g., V1s filtering, followed by pooling, followed by V1c
+// filtering, and computes the cumulative mapping between the units of
+// each stage and the pixels of the input image, e.g., for visualizing
+// receptive fields, and routing attention.  Each filtering stage is
+// assumed to pad its input (the output of the prior stage, or the
+// input image) by its Border on all sides, as in ConvSafe and Pad,
+// so that pixel coordinates are those of the unpadded input image.
+// Receptive fields are the bounding rectangles of all the input pixels
+// that feed a unit, including any gaps from Dilation, and can extend
+// beyond the input image into the padding.
+type GeomSet struct {
+
+	// stages, in order from the input image
+	Stages []GeomStage
+
+	// size of the (unpadded) input image, set by SetSize
+	In image.Point `edit:"-"`
+}
+
+// Add adds a filtering stage with given geometry, which must have the
+// filter sizes set (e.g., by Set), and returns its index.
+func (gs *GeomSet) Add(name string, geom *Geom) int {
+	gs.Stages = append(gs.Stages, GeomStage{Name: name, Geom: *geom})
+	return len(gs.Stages) - 1
+}
+
+// AddPool adds a pooling stage with given pool size and spacing,
+// as in MaxPool, and returns its index.
+func (gs *GeomSet) AddPool(name string, psize, spc image.Point) int {
+	gs.Stages = append(gs.Stages, GeomStage{Name: name, Pool: true, PoolSize: psize, PoolSpacing: spc})
+	return len(gs.Stages) - 1
+}
+
+// Index returns the index of the stage with given name, or -1 if not found
+func (gs *GeomSet) Index(name string) int {
+	for i := range gs.Stages {
+		if gs.Stages[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetSize computes the Out size of each stage for given (unpadded)
+// input image size, setting the In and Out of the Geom of each
+// filtering stage, where In includes the Border padding.
+func (gs *GeomSet) SetSize(in image.Point) {
+	gs.In = in
+	for i := range gs.Stages {
+		st := &gs.Stages[i]
+		if st.Pool {
+			st.Out = PoolOut(st.PoolSize, st.PoolSpacing, in)
+		} else {
+			st.Geom.SetSize(in.Add(st.Geom.Border.Mul(2)))
+			st.Out = st.Geom.Out
+		}
+		in = st.Out
+	}
+}
+
+// Mapping returns the cumulative mapping from the units of given stage
+// to the input image: unit (y, x) receives input from the pixels in
+// [off + unit*stride, off + unit*stride + ext) along each axis.
+func (gs *GeomSet) Mapping(stage int) (off, stride, ext image.Point) {
+	stride = image.Point{1, 1}
+	ext = image.Point{1, 1}
+	for i := 0; i <= stage; i++ {
+		so, ss, se := gs.Stages[i].mapping()
+		off = off.Add(image.Point{so.X * stride.X, so.Y * stride.Y})
+		ext = image.Point{(se.X-1)*stride.X + ext.X, (se.Y-1)*stride.Y + ext.Y}
+		stride = image.Point{ss.X * stride.X, ss.Y * stride.Y}
+	}
+	return
+}
+
+// RF returns the receptive field of given unit (X, Y) of given stage:
+// the rectangle of input image pixels that feed it.
+func (gs *GeomSet) RF(stage int, unit image.Point) image.Rectangle {
+	off, stride, ext := gs.Mapping(stage)
+	mn := off.Add(image.Point{unit.X * stride.X, unit.Y * stride.Y})
+	return image.Rectangle{Min: mn, Max: mn.Add(ext)}
+}
+
+// Center returns the center of the receptive field of given unit (X, Y)
+// of given stage, in input image pixel coordinates.
+func (gs *GeomSet) Center(stage int, unit image.Point) math32.Vector2 {
+	rf := gs.RF(stage, unit)
+	return math32.Vec2(0.5*float32(rf.Min.X+rf.Max.X-1), 0.5*float32(rf.Min.Y+rf.Max.Y-1))
+}
+
+// Covering returns the rectangle of units (X, Y) of given stage whose
+// receptive fields include given input image pixel (X, Y).  If SetSize
+// has been called, this is computed stage by stage, limited to the
+// units in the Out size of each stage, so that units whose inputs
+// are only padding (or are dropped by pooling) are excluded.
+// The rectangle is empty if there are no such units.
+func (gs *GeomSet) Covering(stage int, pixel image.Point) image.Rectangle {
+	r := image.Rectangle{Min: pixel, Max: pixel.Add(image.Point{1, 1})}
+	if gs.In != (image.Point{}) {
+		r = r.Intersect(image.Rectangle{Max: gs.In})
+	}
+	for i := 0; i <= stage && !r.Empty(); i++ {
+		st := &gs.Stages[i]
+		off, stride, ext := st.mapping()
+		r = image.Rectangle{
+			Min: image.Point{ceilDiv(r.Min.X-off.X-ext.X+1, stride.X), ceilDiv(r.Min.Y-off.Y-ext.Y+1, stride.Y)},
+			Max: image.Point{floorDiv(r.Max.X-1-off.X, stride.X) + 1, floorDiv(r.Max.Y-1-off.Y, stride.Y) + 1},
+		}
+		if st.Out != (image.Point{}) {
+			r = r.Intersect(image.Rectangle{Max: st.Out})
+		}
+	}
+	if r.Empty() {
+		return image.Rectangle{}
+	}
+	return r
+}
+
+// Nearest returns the unit (X, Y) of given stage whose receptive field
+// center is nearest to given input image pixel (X, Y), limited to the
+// Out size of the stage if SetSize has been called.
+func (gs *GeomSet) Nearest(stage int, pixel image.Point) image.Point {
+	off, stride, ext := gs.Mapping(stage)
+	near := func(p, o, s, e, n int) int {
+		u := int(math32.Round((float32(p-o) - 0.5*float32(e-1)) / float32(s)))
+		if n > 0 {
+			u = min(max(u, 0), n-1)
+		}
+		return u
+	}
+	out := gs.Stages[stage].Out
+	return image.Point{near(pixel.X, off.X, stride.X, ext.X, out.X), near(pixel.Y, off.Y, stride.Y, ext.Y, out.Y)}
+}
+
+// floorDiv returns a / b rounded toward negative infinity, for b > 0
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// ceilDiv returns a / b rounded toward positive infinity, for b > 0
+func ceilDiv(a, b int) int {
+	return -floorDiv(-a, b)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// ReduceFunc reduces the given values to a single value, for FeatReduce.
+type ReduceFunc func(vals []float32) float32
+
+// Reduce returns the reduction of given values according to the
+// pooling operation: max, average, or square root of the sum of
+// squares, as a ReduceFunc.  Unlike the spatial pooling functions,
+// the max is over the values as given, so it can be negative.
+func (op PoolOps) Reduce(vals []float32) float32 {
+	if len(vals) == 0 {
+		return 0
+	}
+	switch op {
+	case PoolAvg:
+		var sum float32
+		for _, v := range vals {
+			sum += v
+		}
+		return sum / float32(len(vals))
+	case PoolL2:
+		var sum float32
+		for _, v := range vals {
+			sum += v * v
+		}
+		return math32.Sqrt(sum)
+	default:
+		mx := vals[0]
+		for _, v := range vals[1:] {
+			mx = max(mx, v)
+		}
+		return mx
+	}
+}
+
+// GlobalPool pools over all of the spatial positions of given 4D
+// feature tensor: [Y, X, Polarity, Angle] with given operation, into
+// the 1D out: [Polarity * Angle], with the features in the same order
+// as in the input, e.g., to produce a fixed-length image descriptor
+// for a classifier from V1All outputs, regardless of the image size.
+// Computation is parallel over features.
+func GlobalPool(op PoolOps, in, out *tensor.Float32) {
+	npos := in.DimSize(0) * in.DimSize(1)
+	nf := in.DimSize(2) * in.DimSize(3)
+	out.SetShapeSizes(nf)
+	nproc.Run("GlobalPool", nf, func(st, n int) {
+		vals := make([]float32, npos)
+		for f := st; f < st+n; f++ {
+			for i := range vals {
+				vals[i] = in.Values[i*nf+f]
+			}
+			out.Values[f] = op.Reduce(vals)
+		}
+	})
+}
+
+// GlobalMaxPool takes the max over all of the spatial positions of
+// given 4D feature tensor, as in GlobalPool.
+func GlobalMaxPool(in, out *tensor.Float32) {
+	GlobalPool(PoolMax, in, out)
+}
+
+// GlobalAvgPool takes the average over all of the spatial positions of
+// given 4D feature tensor, as in GlobalPool.
+func GlobalAvgPool(in, out *tensor.Float32) {
+	GlobalPool(PoolAvg, in, out)
+}
+
+// FeatPool pools over the Polarity (dim = 2) or Angle (dim = 3)
+// dimension of given 4D feature tensor: [Y, X, Polarity, Angle] with
+// given operation, into out, which has a size of 1 for the pooled
+// dimension, as in MaxReduceFilterY (which is FeatPool with PoolMax
+// over dim 2 for non-negative values).
+// Returns an error if the tensor is not 4D or dim is not 2 or 3.
+func FeatPool(op PoolOps, dim int, in, out *tensor.Float32) error {
+	return featReduce("FeatPool", op.Reduce, dim, in, out)
+}
+
+// FeatReduce reduces over the Polarity (dim = 2) or Angle (dim = 3)
+// dimension of given 4D feature tensor: [Y, X, Polarity, Angle] with
+// given function, as in FeatPool, for arbitrary reductions, e.g.,
+// the range or a percentile of the values.  The function is called
+// in parallel, and must not retain the values.
+// Returns an error if the tensor is not 4D or dim is not 2 or 3.
+func FeatReduce(fun ReduceFunc, dim int, in, out *tensor.Float32) error {
+	return featReduce("FeatReduce", fun, dim, in, out)
+}
+
+// featReduce implements FeatPool and FeatReduce, parallel over Y rows
+func featReduce(fname string, fun ReduceFunc, dim int, in, out *tensor.Float32) error {
+	if in.NumDims() != 4 {
+		return fmt.Errorf("vfilter.%s: input must be 4D [Y, X, Polarity, Angle], has shape: %v", fname, in.ShapeSizes())
+	}
+	if dim != 2 && dim != 3 {
+		return fmt.Errorf("vfilter.%s: dim must be 2 (Polarity) or 3 (Angle), is: %d", fname, dim)
+	}
+	ny, nx := in.DimSize(0), in.DimSize(1)
+	np, na := in.DimSize(2), in.DimSize(3)
+	// n values of each reduction, with stride between them, and
+	// m reductions per position, at stride ms
+	n, stride, m, ms := np, na, na, 1
+	if dim == 3 {
+		n, stride, m, ms = na, 1, np, na
+		out.SetShapeSizes(ny, nx, np, 1)
+	} else {
+		out.SetShapeSizes(ny, nx, 1, na)
+	}
+	nf := np * na
+	nproc.Run(fname, ny, func(st, nr int) {
+		vals := make([]float32, n)
+		for y := st; y < st+nr; y++ {
+			for x := range nx {
+				pos := y*nx + x
+				iv := in.Values[pos*nf:]
+				for j := range m {
+					for i := range vals {
+						vals[i] = iv[j*ms+i*stride]
+					}
+					out.Values[pos*m+j] = fun(vals)
+				}
+			}
+		}
+	})
+	return nil
+}
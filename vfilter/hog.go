@@ -0,0 +1,119 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// HOG specifies histogram of oriented gradients (HOG) style pooling
+// of oriented filter outputs (e.g., V1 simple cells), producing compact
+// region descriptors comparable with the classic computer vision
+// features: the orientation energy is summed over polarities and over
+// the positions within each cell, into a histogram over angles, and the
+// histograms of overlapping blocks of cells are normalized together,
+// using L2 normalization, clipping, and renormalization (L2-Hys).
+type HOG struct {
+
+	// size of each cell, in input positions along each dimension
+	CellSize int `default:"4"`
+
+	// size of each block, in cells along each dimension -- blocks are spaced 1 cell apart, and overlap if > 1
+	BlockSize int `default:"2"`
+
+	// maximum value of the normalized histogram values, after which they are renormalized -- 0 = no clipping (plain L2 normalization)
+	Clip float32 `default:"0.2"`
+
+	// small value added to the norms, to avoid division by zero
+	Eps float32 `default:"0.001"`
+}
+
+func (hg *HOG) Defaults() {
+	hg.CellSize = 4
+	hg.BlockSize = 2
+	hg.Clip = 0.2
+	hg.Eps = 0.001
+}
+
+// Pool computes the cell histograms of given input, with shape
+// [Y, X, Polarity, Angle], into cells, and the normalized block
+// histograms into out, as in Cells and Blocks.
+func (hg *HOG) Pool(in, cells, out *tensor.Float32) {
+	hg.Cells(in, cells)
+	hg.Blocks(cells, out)
+}
+
+// Cells computes the cell histograms of given input, with shape
+// [Y, X, Polarity, Angle], into cells, with shape [CY, CX, 1, Angle],
+// summing over polarities and positions within each cell.
+// Any remaining input positions beyond a whole number of cells
+// are ignored.
+func (hg *HOG) Cells(in, cells *tensor.Float32) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	npol := in.DimSize(2)
+	nang := in.DimSize(3)
+	cy := ny / hg.CellSize
+	cx := nx / hg.CellSize
+	cells.SetShapeSizes(cy, cx, 1, nang)
+	cells.SetZeros()
+	for y := range cy * hg.CellSize {
+		for x := range cx * hg.CellSize {
+			ci := ((y/hg.CellSize)*cx + x/hg.CellSize) * nang
+			for p := range npol {
+				ii := ((y*nx+x)*npol + p) * nang
+				for a := range nang {
+					cells.Values[ci+a] += in.Values[ii+a]
+				}
+			}
+		}
+	}
+}
+
+// Blocks computes the normalized histograms of each block of cells
+// from Cells, into out, with shape [BY, BX, BlockSize * BlockSize, Angle],
+// where BY = CY - BlockSize + 1, and the cells within each block are
+// in row-major order.
+func (hg *HOG) Blocks(cells, out *tensor.Float32) {
+	cy := cells.DimSize(0)
+	cx := cells.DimSize(1)
+	nang := cells.DimSize(3)
+	bs := hg.BlockSize
+	by := max(cy-bs+1, 0)
+	bx := max(cx-bs+1, 0)
+	nb := bs * bs * nang
+	out.SetShapeSizes(by, bx, bs*bs, nang)
+	for y := range by {
+		for x := range bx {
+			bv := out.Values[(y*bx+x)*nb : (y*bx+x+1)*nb]
+			for ry := range bs {
+				for rx := range bs {
+					ci := ((y+ry)*cx + x + rx) * nang
+					copy(bv[(ry*bs+rx)*nang:], cells.Values[ci:ci+nang])
+				}
+			}
+			hg.normalize(bv)
+			if hg.Clip > 0 {
+				for i, v := range bv {
+					bv[i] = min(v, hg.Clip)
+				}
+				hg.normalize(bv)
+			}
+		}
+	}
+}
+
+// normalize normalizes given values by their L2 norm
+func (hg *HOG) normalize(vals []float32) {
+	ss := float32(0)
+	for _, v := range vals {
+		ss += v * v
+	}
+	norm := 1 / math32.Sqrt(ss+hg.Eps*hg.Eps)
+	for i := range vals {
+		vals[i] *= norm
+	}
+}
@@ -13,15 +13,30 @@ import (
 )
 
 // RGBToTensor converts an RGB input image to an RGB tensor
-// with outer dimension as RGB components.
+// with outer dimension as RGB components, ignoring any alpha channel
+// (see RGBToTensorAlpha).
 // padWidth is the amount of padding to add on all sides.
 // topZero retains the Y=0 value at the top of the tensor --
 // otherwise it is flipped with Y=0 at the bottom to be consistent
 // with the emergent / OpenGL standard coordinate system
 func RGBToTensor(img image.Image, tsr *tensor.Float32, padWidth int, topZero bool) {
+	RGBToTensorAlpha(img, tsr, padWidth, topZero, nil, nil)
+}
+
+// RGBDToTensor converts an RGB input image plus a separate depth image
+// (e.g., a 16 bit Kinect-style depth map, of the same size) to an RGBD
+// tensor with outer dimension as R, G, B, Depth components.
+// Depth values are the grey-level of the depth image in 0-1 normalized
+// units, computed at full (16 bit) precision.
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func RGBDToTensor(img, depth image.Image, tsr *tensor.Float32, padWidth int, topZero bool) {
 	bd := img.Bounds()
 	sz := bd.Size()
-	tsr.SetShapeSizes(3, sz.Y+2*padWidth, sz.X+2*padWidth)
+	dbd := depth.Bounds()
+	tsr.SetShapeSizes(4, sz.Y+2*padWidth, sz.X+2*padWidth)
 	for y := 0; y < sz.Y; y++ {
 		for x := 0; x < sz.X; x++ {
 			sy := y
@@ -30,9 +45,42 @@ func RGBToTensor(img image.Image, tsr *tensor.Float32, padWidth int, topZero boo
 			}
 			cv := img.At(bd.Min.X+x, bd.Min.Y+sy)
 			r, g, b, _ := colors.ToFloat32(cv)
+			dv := color.Gray16Model.Convert(depth.At(dbd.Min.X+x, dbd.Min.Y+sy)).(color.Gray16)
 			tsr.Set(r, 0, y+padWidth, x+padWidth)
 			tsr.Set(g, 1, y+padWidth, x+padWidth)
 			tsr.Set(b, 2, y+padWidth, x+padWidth)
+			tsr.Set(float32(dv.Y)/0xffff, 3, y+padWidth, x+padWidth)
+		}
+	}
+}
+
+// BandsToTensor converts a set of single-band images (e.g., the bands
+// of a multispectral or hyperspectral image, of the same size) to a
+// tensor with outer dimension as the bands: [Band, Y, X], as for RGB.
+// Band values are the grey-level of each image in 0-1 normalized units,
+// computed at full (16 bit) precision.
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func BandsToTensor(bands []image.Image, tsr *tensor.Float32, padWidth int, topZero bool) {
+	if len(bands) == 0 {
+		tsr.SetShapeSizes(0, 0, 0)
+		return
+	}
+	sz := bands[0].Bounds().Size()
+	tsr.SetShapeSizes(len(bands), sz.Y+2*padWidth, sz.X+2*padWidth)
+	for b, img := range bands {
+		bd := img.Bounds()
+		for y := 0; y < sz.Y; y++ {
+			for x := 0; x < sz.X; x++ {
+				sy := y
+				if !topZero {
+					sy = (sz.Y - 1) - y
+				}
+				bv := color.Gray16Model.Convert(img.At(bd.Min.X+x, bd.Min.Y+sy)).(color.Gray16)
+				tsr.Set(float32(bv.Y)/0xffff, b, y+padWidth, x+padWidth)
+			}
 		}
 	}
 }
@@ -75,27 +123,14 @@ func RGBTensorToImage(img *image.RGBA, tsr *tensor.Float32, padWidth int, topZer
 }
 
 // RGBToGrey converts an RGB input image to a greyscale tensor
-// in preparation for processing.
+// in preparation for processing, ignoring any alpha channel
+// (see RGBToGreyAlpha).
 // padWidth is the amount of padding to add on all sides.
 // topZero retains the Y=0 value at the top of the tensor --
 // otherwise it is flipped with Y=0 at the bottom to be consistent
 // with the emergent / OpenGL standard coordinate system
 func RGBToGrey(img image.Image, tsr *tensor.Float32, padWidth int, topZero bool) {
-	bd := img.Bounds()
-	sz := bd.Size()
-	tsr.SetShapeSizes(sz.Y+2*padWidth, sz.X+2*padWidth)
-	for y := 0; y < sz.Y; y++ {
-		for x := 0; x < sz.X; x++ {
-			sy := y
-			if !topZero {
-				sy = (sz.Y - 1) - y
-			}
-			cv := img.At(bd.Min.X+x, bd.Min.Y+sy)
-			r, g, b, _ := colors.ToFloat32(cv)
-			gv := (r + g + b) / 3
-			tsr.Set(gv, y+padWidth, x+padWidth)
-		}
-	}
+	RGBToGreyAlpha(img, tsr, padWidth, topZero, nil, nil)
 }
 
 // GreyTensorToImage converts a greyscale tensor to image -- uses
@@ -179,7 +214,7 @@ func WrapPad(tsr *tensor.Float32, padWidth int) {
 func WrapPadRGB(tsr *tensor.Float32, padWidth int) {
 	nc := tsr.DimSize(0)
 	for i := 0; i < nc; i++ {
-		simg := tsr.SubSpace(i).(*tensor.Float32)
+		simg := Channel(tsr, i)
 		WrapPad(simg, padWidth)
 	}
 }
@@ -211,11 +246,15 @@ func EdgeAvg(tsr *tensor.Float32, padWidth int) float32 {
 // FadePad fades given padding width of float32 image around sides
 // gradually fading the edge value toward a mean edge value
 func FadePad(tsr *tensor.Float32, padWidth int) {
+	fadePad(tsr, padWidth, EdgeAvg(tsr, padWidth))
+}
+
+// fadePad implements FadePad, fading toward given mean edge value
+func fadePad(tsr *tensor.Float32, padWidth int, avg float32) {
 	sz := image.Point{tsr.DimSize(1), tsr.DimSize(0)}
 	usz := sz
 	usz.Y -= padWidth
 	usz.X -= padWidth
-	avg := EdgeAvg(tsr, padWidth)
 	for y := 0; y < sz.Y; y++ {
 		var lv, rv float32
 		switch {
@@ -274,7 +313,60 @@ func FadePad(tsr *tensor.Float32, padWidth int) {
 func FadePadRGB(tsr *tensor.Float32, padWidth int) {
 	nc := tsr.DimSize(0)
 	for i := 0; i < nc; i++ {
-		simg := tsr.SubSpace(i).(*tensor.Float32)
+		simg := Channel(tsr, i)
 		FadePad(simg, padWidth)
 	}
 }
+
+// ReflectPad fills given padding width of float32 image around sides
+// by reflecting (mirroring) the image content at each edge, without
+// repeating the edge value, i.e., the padding for the left side of the
+// image is the mirror image of the columns just to the right of the
+// left edge, etc.  Unlike WrapPad, this does not introduce spurious
+// edges at the borders of natural images, where the opposite sides
+// of the image differ.  Padding wider than the image is reflected
+// back and forth.
+func ReflectPad(tsr *tensor.Float32, padWidth int) {
+	sz := image.Point{tsr.DimSize(1), tsr.DimSize(0)}
+	usz := sz.Sub(image.Point{2 * padWidth, 2 * padWidth})
+	if padWidth <= 0 || usz.X <= 0 || usz.Y <= 0 {
+		return
+	}
+	for y := 0; y < sz.Y; y++ {
+		sy := padWidth + ReflectIndex(y-padWidth, usz.Y)
+		inY := y >= padWidth && y < sz.Y-padWidth
+		for x := 0; x < sz.X; x++ {
+			if inY && x >= padWidth && x < sz.X-padWidth {
+				continue
+			}
+			sx := padWidth + ReflectIndex(x-padWidth, usz.X)
+			tsr.Set(tsr.Value(sy, sx), y, x)
+		}
+	}
+}
+
+// ReflectIndex returns the index within [0, n) for given index,
+// reflected at the edges without repeating the edge value,
+// as used in ReflectPad.
+func ReflectIndex(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	per := 2 * (n - 1)
+	i = ((i % per) + per) % per
+	if i >= n {
+		i = per - i
+	}
+	return i
+}
+
+// ReflectPadRGB fills given padding width of float32 image around sides
+// by reflecting (mirroring) the image content at each edge.
+// RGB version iterates over outer-most dimension of components.
+func ReflectPadRGB(tsr *tensor.Float32, padWidth int) {
+	nc := tsr.DimSize(0)
+	for i := 0; i < nc; i++ {
+		simg := Channel(tsr, i)
+		ReflectPad(simg, padWidth)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+	"image/color"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Gray16ToTensor converts a 16 bit greyscale image to a greyscale
+// tensor, at full precision, reading the pixel values directly.
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func Gray16ToTensor(img *image.Gray16, tsr *tensor.Float32, padWidth int, topZero bool) {
+	sz := img.Rect.Size()
+	tsr.SetShapeSizes(sz.Y+2*padWidth, sz.X+2*padWidth)
+	for y := 0; y < sz.Y; y++ {
+		sy := y
+		if !topZero {
+			sy = (sz.Y - 1) - y
+		}
+		pi := sy * img.Stride
+		for x := 0; x < sz.X; x++ {
+			v := uint16(img.Pix[pi+2*x])<<8 | uint16(img.Pix[pi+2*x+1])
+			tsr.Set(float32(v)/0xffff, y+padWidth, x+padWidth)
+		}
+	}
+}
+
+// NRGBA64ToTensor converts a 16 bit per channel non-premultiplied RGBA
+// image to an RGB tensor with outer dimension as RGB components, at
+// full precision, reading the pixel values directly, so that the color
+// values of partially transparent pixels are not quantized by
+// premultiplying.  The alpha channel is ignored (see RGBToTensorAlpha).
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func NRGBA64ToTensor(img *image.NRGBA64, tsr *tensor.Float32, padWidth int, topZero bool) {
+	sz := img.Rect.Size()
+	tsr.SetShapeSizes(3, sz.Y+2*padWidth, sz.X+2*padWidth)
+	for y := 0; y < sz.Y; y++ {
+		sy := y
+		if !topZero {
+			sy = (sz.Y - 1) - y
+		}
+		pi := sy * img.Stride
+		for x := 0; x < sz.X; x++ {
+			for c := 0; c < 3; c++ {
+				i := pi + 8*x + 2*c
+				v := uint16(img.Pix[i])<<8 | uint16(img.Pix[i+1])
+				tsr.Set(float32(v)/0xffff, c, y+padWidth, x+padWidth)
+			}
+		}
+	}
+}
+
+// GreyTensorToImage16 converts a greyscale tensor to a 16 bit image,
+// as in GreyTensorToImage, with values clamped to the 0-1 range.
+// Uses existing img if it is of correct size, otherwise makes a new one.
+// padWidth is the amount of padding to subtract from all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func GreyTensorToImage16(img *image.Gray16, tsr *tensor.Float32, padWidth int, topZero bool) *image.Gray16 {
+	var sz image.Point
+	sz.Y = tsr.DimSize(0) - 2*padWidth
+	sz.X = tsr.DimSize(1) - 2*padWidth
+	if img == nil || img.Bounds().Size() != sz {
+		img = image.NewGray16(image.Rectangle{Max: sz})
+	}
+	for y := 0; y < sz.Y; y++ {
+		for x := 0; x < sz.X; x++ {
+			sy := y
+			if !topZero {
+				sy = (sz.Y - 1) - y
+			}
+			cv := math32.Clamp(tsr.Value(y+padWidth, x+padWidth), 0, 1)
+			img.SetGray16(img.Rect.Min.X+x, img.Rect.Min.Y+sy, color.Gray16{uint16(cv*0xffff + 0.5)})
+		}
+	}
+	return img
+}
+
+// FloatImage is an image with float32 channel values, for high dynamic
+// range (HDR) and scientific images, e.g., as decoded from floating
+// point TIFF or EXR files, which are neither quantized nor clamped,
+// and can be converted to a tensor with FloatImageToTensor.
+// As an image.Image, the values are clamped to the 0-1 range,
+// with 1 channel as grey, and 3 or more as RGB (with alpha if 4).
+type FloatImage struct {
+
+	// channel values, with NChan values per pixel, in row-major order starting at Rect.Min
+	Pix []float32
+
+	// number of values between vertically adjacent pixels in Pix
+	Stride int
+
+	// number of channels per pixel: 1 for grey, 3 for RGB, etc
+	NChan int
+
+	// bounds of the image
+	Rect image.Rectangle
+}
+
+// NewFloatImage returns a new FloatImage with given bounds and
+// number of channels per pixel.
+func NewFloatImage(r image.Rectangle, nchan int) *FloatImage {
+	return &FloatImage{Pix: make([]float32, r.Dx()*r.Dy()*nchan), Stride: r.Dx() * nchan, NChan: nchan, Rect: r}
+}
+
+func (fi *FloatImage) ColorModel() color.Model { return colors.NRGBAF32Model }
+
+func (fi *FloatImage) Bounds() image.Rectangle { return fi.Rect }
+
+func (fi *FloatImage) At(x, y int) color.Color {
+	if !(image.Point{x, y}.In(fi.Rect)) {
+		return colors.NRGBAF32{}
+	}
+	pi := fi.PixOffset(x, y)
+	cl := func(c int) float32 { return math32.Clamp(fi.Pix[pi+c], 0, 1) }
+	if fi.NChan < 3 {
+		v := cl(0)
+		return colors.NRGBAF32{R: v, G: v, B: v, A: 1}
+	}
+	a := float32(1)
+	if fi.NChan > 3 {
+		a = cl(3)
+	}
+	return colors.NRGBAF32{R: cl(0), G: cl(1), B: cl(2), A: a}
+}
+
+// PixOffset returns the index of the first channel value in Pix
+// for the pixel at x, y.
+func (fi *FloatImage) PixOffset(x, y int) int {
+	return (y-fi.Rect.Min.Y)*fi.Stride + (x-fi.Rect.Min.X)*fi.NChan
+}
+
+// FloatImageToTensor converts a FloatImage to a tensor without any
+// quantization or clamping: greyscale [Y, X] for 1 channel, or with
+// an outer dimension of channels otherwise: [Channel, Y, X].
+// padWidth is the amount of padding to add on all sides.
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system
+func FloatImageToTensor(img *FloatImage, tsr *tensor.Float32, padWidth int, topZero bool) {
+	sz := img.Rect.Size()
+	nc := img.NChan
+	psy, psx := sz.Y+2*padWidth, sz.X+2*padWidth
+	if nc == 1 {
+		tsr.SetShapeSizes(psy, psx)
+	} else {
+		tsr.SetShapeSizes(nc, psy, psx)
+	}
+	for y := 0; y < sz.Y; y++ {
+		sy := y
+		if !topZero {
+			sy = (sz.Y - 1) - y
+		}
+		pi := sy * img.Stride
+		for x := 0; x < sz.X; x++ {
+			for c := 0; c < nc; c++ {
+				tsr.Values[(c*psy+y+padWidth)*psx+x+padWidth] = img.Pix[pi+x*nc+c]
+			}
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"cogentcore.org/core/tensor"
+)
+
+// Layout builds a combined V1All-style feature tensor, with shape
+// [Y, X, Row, Angle], from named feature sources, each contributing
+// one or more rows, so that the row offsets do not need to be computed
+// by hand.  Sources are added in order with Add or AddOuter, and Build
+// computes the offsets, validates the shapes of the sources against
+// each other, and aggregates them into the output.  The resulting
+// row layout is recorded in Entries, as metadata describing the output.
+type Layout struct {
+
+	// feature sources, in the order of their rows in the output
+	Sources []LayoutSource
+
+	// layout of the rows in the output, for each source, as computed by the last Build
+	Entries []LayoutEntry `edit:"-"`
+}
+
+// LayoutSource is one feature source of a Layout
+type LayoutSource struct {
+
+	// name of the source, used as the prefix of its row names
+	Name string
+
+	// source tensor: either [Y, X, Row, Angle], or [Row, Y, X] if Outer, in which case each row is replicated across all angles
+	Src *tensor.Float32 `display:"-" json:"-" xml:"-"`
+
+	// rows of the source to copy, in order -- nil = all rows
+	Rows []int
+
+	// names of each of the rows, appended to Name -- defaults to the source row index if not specified
+	RowNames []string
+
+	// whether the source has the rows as the outer-most dimension: [Row, Y, X], replicated across all angles, as for OuterAgg
+	Outer bool
+}
+
+// LayoutEntry records the layout of the rows of one source in
+// the output of a Layout
+type LayoutEntry struct {
+
+	// name of the source
+	Name string
+
+	// starting row of the source in the output
+	Start int
+
+	// number of rows of the source in the output
+	N int
+
+	// full names of each of the rows, as Name + RowNames
+	Rows []string
+}
+
+// Reset removes all of the sources, for building a new layout
+func (ly *Layout) Reset() {
+	ly.Sources = ly.Sources[:0]
+	ly.Entries = ly.Entries[:0]
+}
+
+// Add adds a source with shape [Y, X, Row, Angle], copying the given
+// rows (nil = all), with given row names (appended to the name).
+func (ly *Layout) Add(name string, src *tensor.Float32, rows []int, rowNames ...string) {
+	ly.Sources = append(ly.Sources, LayoutSource{Name: name, Src: src, Rows: rows, RowNames: rowNames})
+}
+
+// AddOuter adds a source with shape [Row, Y, X], copying the given
+// rows (nil = all), each replicated across all angles, with given
+// row names (appended to the name).
+func (ly *Layout) AddOuter(name string, src *tensor.Float32, rows []int, rowNames ...string) {
+	ly.Sources = append(ly.Sources, LayoutSource{Name: name, Src: src, Rows: rows, RowNames: rowNames, Outer: true})
+}
+
+// Source returns the source with given name, or nil if not found
+func (ly *Layout) Source(name string) *LayoutSource {
+	for i := range ly.Sources {
+		if ly.Sources[i].Name == name {
+			return &ly.Sources[i]
+		}
+	}
+	return nil
+}
+
+// Entry returns the layout entry for the source with given name,
+// as computed by the last Build, or nil if not found
+func (ly *Layout) Entry(name string) *LayoutEntry {
+	for i := range ly.Entries {
+		if ly.Entries[i].Name == name {
+			return &ly.Entries[i]
+		}
+	}
+	return nil
+}
+
+// NRows returns the total number of rows of all the sources
+func (ly *Layout) NRows() int {
+	nr := 0
+	for i := range ly.Sources {
+		nr += ly.Sources[i].NRows()
+	}
+	return nr
+}
+
+// RowNames returns the full names of all the rows of all the sources,
+// in order, which can be used before Build
+func (ly *Layout) RowNames() []string {
+	var rows []string
+	for i := range ly.Sources {
+		rows = append(rows, ly.Sources[i].FullRowNames()...)
+	}
+	return rows
+}
+
+// NRows returns the number of rows of the source in the output
+func (ls *LayoutSource) NRows() int {
+	if ls.Rows != nil {
+		return len(ls.Rows)
+	}
+	if ls.Src == nil || ls.Src.NumDims() == 0 {
+		return 0
+	}
+	if ls.Outer {
+		return ls.Src.DimSize(0)
+	}
+	if ls.Src.NumDims() < 3 {
+		return 0
+	}
+	return ls.Src.DimSize(2)
+}
+
+// SrcRows returns the rows of the source to copy
+func (ls *LayoutSource) SrcRows() []int {
+	if ls.Rows != nil {
+		return ls.Rows
+	}
+	rows := make([]int, ls.NRows())
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// FullRowNames returns the full names of the rows of the source in
+// the output, as Name + RowNames
+func (ls *LayoutSource) FullRowNames() []string {
+	nr := ls.NRows()
+	rows := make([]string, nr)
+	for i := range nr {
+		if i < len(ls.RowNames) {
+			rows[i] = ls.Name + ls.RowNames[i]
+		} else if nr == 1 {
+			rows[i] = ls.Name
+		} else {
+			rows[i] = ls.Name + strconv.Itoa(ls.SrcRows()[i])
+		}
+	}
+	return rows
+}
+
+// Validate checks that all of the sources have compatible shapes,
+// returning the output shape sizes [Y, X, Row, Angle], and an error
+// describing the first incompatibility found, if any.
+// The Y, X size is that of the first source, and Angle is that of
+// the first non-Outer source.
+func (ly *Layout) Validate() ([]int, error) {
+	if len(ly.Sources) == 0 {
+		return nil, fmt.Errorf("vfilter.Layout.Validate: no sources")
+	}
+	ny, nx, nang := -1, -1, -1
+	for i := range ly.Sources {
+		ls := &ly.Sources[i]
+		if ls.Src == nil {
+			return nil, fmt.Errorf("vfilter.Layout.Validate: source %q has a nil tensor", ls.Name)
+		}
+		shp := ls.Src.ShapeSizes()
+		var sy, sx, nr int
+		if ls.Outer {
+			if len(shp) != 3 {
+				return nil, fmt.Errorf("vfilter.Layout.Validate: outer source %q must have shape [Row, Y, X], not %v", ls.Name, shp)
+			}
+			nr, sy, sx = shp[0], shp[1], shp[2]
+		} else {
+			if len(shp) != 4 {
+				return nil, fmt.Errorf("vfilter.Layout.Validate: source %q must have shape [Y, X, Row, Angle], not %v", ls.Name, shp)
+			}
+			sy, sx, nr = shp[0], shp[1], shp[2]
+			if nang < 0 {
+				nang = shp[3]
+			} else if shp[3] != nang {
+				return nil, fmt.Errorf("vfilter.Layout.Validate: source %q has %d angles, not %d as in prior sources", ls.Name, shp[3], nang)
+			}
+		}
+		if ny < 0 {
+			ny, nx = sy, sx
+		} else if sy != ny || sx != nx {
+			return nil, fmt.Errorf("vfilter.Layout.Validate: source %q has Y, X size %d, %d, not %d, %d as in prior sources", ls.Name, sy, sx, ny, nx)
+		}
+		for _, r := range ls.Rows {
+			if r < 0 || r >= nr {
+				return nil, fmt.Errorf("vfilter.Layout.Validate: source %q row %d is out of range for %d rows", ls.Name, r, nr)
+			}
+		}
+		if len(ls.RowNames) > ls.NRows() {
+			return nil, fmt.Errorf("vfilter.Layout.Validate: source %q has %d row names for %d rows", ls.Name, len(ls.RowNames), ls.NRows())
+		}
+	}
+	if nang < 0 {
+		nang = 1
+	}
+	return []int{ny, nx, ly.NRows(), nang}, nil
+}
+
+// Build validates the sources, computes the layout Entries, and
+// aggregates all of the sources into given output tensor, which is
+// set to shape [Y, X, Row, Angle].  The output is not changed if
+// there is an error.
+func (ly *Layout) Build(out *tensor.Float32) error {
+	shp, err := ly.Validate()
+	if err != nil {
+		return err
+	}
+	ly.Entries = ly.Entries[:0]
+	out.SetShapeSizes(shp...)
+	st := 0
+	for i := range ly.Sources {
+		ls := &ly.Sources[i]
+		rows := ls.SrcRows()
+		if ls.Outer {
+			layoutOuter(rows, st, ls.Src, out)
+		} else {
+			featAgg(rows, st, ls.Src, out)
+		}
+		ly.Entries = append(ly.Entries, LayoutEntry{Name: ls.Name, Start: st, N: len(rows), Rows: ls.FullRowNames()})
+		st += len(rows)
+	}
+	return nil
+}
+
+// Equal returns whether the given layout entries are the same as the
+// current Entries, e.g., to check that a previously recorded layout
+// matches the current one.
+func (ly *Layout) Equal(entries []LayoutEntry) bool {
+	return slices.EqualFunc(ly.Entries, entries, func(a, b LayoutEntry) bool {
+		return a.Name == b.Name && a.Start == b.Start && a.N == b.N && slices.Equal(a.Rows, b.Rows)
+	})
+}
+
+// layoutOuter copies given rows of the outer-most dimension of src,
+// [Row, Y, X], into out starting at row st, replicated across all angles.
+func layoutOuter(rows []int, st int, src, out *tensor.Float32) {
+	ny := src.DimSize(1)
+	nx := src.DimSize(2)
+	nang := out.DimSize(3)
+	for i, r := range rows {
+		for y := range ny {
+			for x := range nx {
+				v := src.Value(r, y, x)
+				for a := range nang {
+					out.Set(v, y, x, st+i, a)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// LocalContrastNorm is local contrast normalization, which divides each
+// value by the gaussian-weighted standard deviation of the values in its
+// neighborhood, after subtracting the local mean (if SubMean), so that
+// the contrast is equalized across regions of an image with different
+// lighting, as in the retina and LGN.  This is applied separately to
+// each channel of an image, or to each feature map of filter outputs.
+// The neighborhood is truncated at the edges of the tensor, with the
+// weights renormalized, so any padding is treated as image content.
+type LocalContrastNorm struct {
+
+	// standard deviation of the gaussian neighborhood, in pixels (positions) -- the neighborhood extends to 3 * Sigma
+	Sigma float32 `default:"4" min:"0.5"`
+
+	// subtract the local mean before dividing by the local standard deviation -- otherwise only divisive normalization is done
+	SubMean bool `default:"true"`
+
+	// minimum local standard deviation that values are divided by, so that noise in nearly uniform regions is not amplified
+	Floor float32 `default:"0.05"`
+}
+
+func (ln *LocalContrastNorm) Defaults() {
+	ln.Sigma = 4
+	ln.SubMean = true
+	ln.Floor = 0.05
+}
+
+// Normalize normalizes given image tensor into out, which can be the
+// same as in: [Y, X], or with outer components (e.g., RGB [3, Y, X]),
+// each of which is normalized separately.
+func (ln *LocalContrastNorm) Normalize(in, out *tensor.Float32) {
+	nd := in.NumDims()
+	sy, sx := in.DimSize(nd-2), in.DimSize(nd-1)
+	if sy == 0 || sx == 0 {
+		tensor.SetShapeFrom(out, in)
+		return
+	}
+	nm := in.Len() / (sy * sx)
+	ln.normalize(in, out, nm, sy, sx, sy*sx, sx, 1)
+}
+
+// NormalizeFeatures normalizes given 4D filter output tensor into out,
+// which can be the same as in, as from Conv: [Y, X, Polarity, Filter],
+// where the map of each polarity and filter is normalized separately.
+func (ln *LocalContrastNorm) NormalizeFeatures(in, out *tensor.Float32) {
+	sy, sx := in.DimSize(0), in.DimSize(1)
+	nm := in.DimSize(2) * in.DimSize(3)
+	if sy == 0 || sx == 0 || nm == 0 {
+		tensor.SetShapeFrom(out, in)
+		return
+	}
+	ln.normalize(in, out, nm, sy, sx, 1, sx*nm, nm)
+}
+
+// normalize implements normalization of nm maps of size sy, sx, where
+// the map, row and column strides of the values are ms, ys and xs.
+func (ln *LocalContrastNorm) normalize(in, out *tensor.Float32, nm, sy, sx, ms, ys, xs int) {
+	sig := max(ln.Sigma, 0.5)
+	kr := int(math32.Ceil(3 * sig))
+	wts := make([]float32, 2*kr+1)
+	for k := range wts {
+		d := float32(k-kr) / sig
+		wts[k] = math32.Exp(-0.5 * d * d)
+	}
+	mean := Pool.GetLike(in)
+	msq := Pool.GetLike(in)
+	defer Pool.Put(mean, msq)
+	// rows first, then columns of the row sums
+	nproc.Run("LocalContrastNorm", nm*sy, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			m, y := r/sy, r%sy
+			for x := range sx {
+				var sum, ssq, wt float32
+				for k := max(-kr, -x); k <= min(kr, sx-1-x); k++ {
+					w := wts[k+kr]
+					v := in.Values[m*ms+y*ys+(x+k)*xs]
+					sum += w * v
+					ssq += w * v * v
+					wt += w
+				}
+				i := m*ms + y*ys + x*xs
+				mean.Values[i] = sum / wt
+				msq.Values[i] = ssq / wt
+			}
+		}
+	})
+	tensor.SetShapeFrom(out, in)
+	nproc.Run("LocalContrastNorm", nm*sy, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			m, y := r/sy, r%sy
+			for x := range sx {
+				var sum, ssq, wt float32
+				for k := max(-kr, -y); k <= min(kr, sy-1-y); k++ {
+					w := wts[k+kr]
+					j := m*ms + (y+k)*ys + x*xs
+					sum += w * mean.Values[j]
+					ssq += w * msq.Values[j]
+					wt += w
+				}
+				mn := sum / wt
+				std := math32.Sqrt(max(ssq/wt-mn*mn, 0))
+				i := m*ms + y*ys + x*xs
+				v := in.Values[i]
+				if ln.SubMean {
+					v -= mn
+				}
+				out.Values[i] = v / max(std, ln.Floor)
+			}
+		}
+	})
+}
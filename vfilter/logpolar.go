@@ -0,0 +1,208 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// LogPolarGeom specifies the geometry of a log-polar (foveated)
+// sampling of an image around a fixation point, where the radius of
+// the rings increases exponentially with eccentricity, so that the
+// resolution is highest at the fixation point, as in the retinotopic
+// map of V1.  Each ring has the same number of wedges (angles), so that
+// rotation and scaling around the fixation point become translations
+// in the log-polar representation.
+type LogPolarGeom struct {
+
+	// fixation point, as a proportion of the X, Y size of the image (0.5, 0.5 = center)
+	Fix math32.Vector2
+
+	// number of rings (eccentricities), from MinRadius to MaxRadius
+	Rings int `default:"32" min:"2"`
+
+	// number of wedges (angles) around each ring, starting at the positive X axis
+	Wedges int `default:"64" min:"1"`
+
+	// radius of the innermost ring, in pixels -- the fovea within this radius is filled with the innermost ring in LogPolarInverse
+	MinRadius float32 `default:"1" min:"0.01"`
+
+	// radius of the outermost ring, in pixels -- 0 = half the smaller of the X, Y size of the image
+	MaxRadius float32
+
+	// size of the image, set by LogPolar, and used by LogPolarInverse to reconstruct an image of the same size
+	ImgSize image.Point `edit:"-"`
+}
+
+func (lg *LogPolarGeom) Defaults() {
+	lg.Fix.Set(0.5, 0.5)
+	lg.Rings = 32
+	lg.Wedges = 64
+	lg.MinRadius = 1
+	lg.MaxRadius = 0
+}
+
+// maxRadius returns the MaxRadius, or the default for the ImgSize
+func (lg *LogPolarGeom) maxRadius() float32 {
+	if lg.MaxRadius > 0 {
+		return lg.MaxRadius
+	}
+	return 0.5 * float32(min(lg.ImgSize.X, lg.ImgSize.Y))
+}
+
+// fixation returns the fixation point in pixels (x, y)
+func (lg *LogPolarGeom) fixation() (fx, fy float32) {
+	return lg.Fix.X * float32(lg.ImgSize.X-1), lg.Fix.Y * float32(lg.ImgSize.Y-1)
+}
+
+// Radius returns the radius of given ring, in pixels, which must be
+// called after the ImgSize is set (e.g., by LogPolar) if MaxRadius is 0.
+func (lg *LogPolarGeom) Radius(ring int) float32 {
+	mx := lg.maxRadius()
+	return lg.MinRadius * math32.Pow(mx/lg.MinRadius, float32(ring)/float32(lg.Rings-1))
+}
+
+// validate returns an error if the parameters are not usable
+func (lg *LogPolarGeom) validate(fun string) error {
+	if lg.Rings < 2 || lg.Wedges < 1 {
+		return fmt.Errorf("vfilter.%s: must have at least 2 Rings and 1 Wedge: %d, %d", fun, lg.Rings, lg.Wedges)
+	}
+	if lg.MinRadius <= 0 || lg.maxRadius() <= lg.MinRadius {
+		return fmt.Errorf("vfilter.%s: MinRadius %g must be > 0 and < MaxRadius %g", fun, lg.MinRadius, lg.maxRadius())
+	}
+	return nil
+}
+
+// LogPolar resamples given unpadded image ([Y, X], or with outer
+// components, e.g., RGB [3, Y, X]) into out in the log-polar
+// representation specified by lg, with bilinear interpolation:
+// [Ring, Wedge], or with the same outer components as the image.
+// Samples outside of the image are 0.  The outer rings undersample
+// the image, so it should be blurred first (e.g., by a Pyramid level)
+// to avoid aliasing, if that matters.  Sets the ImgSize of lg for
+// LogPolarInverse.  Returns an error for invalid shapes or parameters.
+func LogPolar(lg *LogPolarGeom, img, out *tensor.Float32) error {
+	nd := img.NumDims()
+	if nd < 2 {
+		return fmt.Errorf("vfilter.LogPolar: image must be at least 2D, has shape: %v", img.ShapeSizes())
+	}
+	sy, sx := img.DimSize(nd-2), img.DimSize(nd-1)
+	lg.ImgSize = image.Point{sx, sy}
+	if err := lg.validate("LogPolar"); err != nil {
+		return err
+	}
+	osz := append(img.ShapeSizes()[:nd-2], lg.Rings, lg.Wedges)
+	out.SetShapeSizes(osz...)
+	nc := img.Len() / (sy * sx)
+	fx, fy := lg.fixation()
+	nproc.Run("LogPolar", lg.Rings, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			rad := lg.Radius(r)
+			for w := range lg.Wedges {
+				ang := 2 * math32.Pi * float32(w) / float32(lg.Wedges)
+				x := fx + rad*math32.Cos(ang)
+				y := fy + rad*math32.Sin(ang)
+				for c := range nc {
+					v := bilinearZero(img.Values[c*sy*sx:(c+1)*sy*sx], sy, sx, y, x)
+					out.Values[(c*lg.Rings+r)*lg.Wedges+w] = v
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// LogPolarInverse reconstructs an image of the ImgSize of lg, as set
+// by LogPolar, from given log-polar representation ([Ring, Wedge], or
+// with outer components), with bilinear interpolation between rings
+// and wedges (wrapping around in angle): [Y, X], or with the same outer
+// components.  The fovea within MinRadius gets the innermost ring,
+// and pixels beyond MaxRadius are 0.  Returns an error for invalid
+// shapes or parameters.
+func LogPolarInverse(lg *LogPolarGeom, in, img *tensor.Float32) error {
+	nd := in.NumDims()
+	if nd < 2 || in.DimSize(nd-2) != lg.Rings || in.DimSize(nd-1) != lg.Wedges {
+		return fmt.Errorf("vfilter.LogPolarInverse: input must be [Rings, Wedges] = [%d, %d] in inner dimensions, has shape: %v", lg.Rings, lg.Wedges, in.ShapeSizes())
+	}
+	if lg.ImgSize.X <= 0 || lg.ImgSize.Y <= 0 {
+		return fmt.Errorf("vfilter.LogPolarInverse: ImgSize is not set: %v", lg.ImgSize)
+	}
+	if err := lg.validate("LogPolarInverse"); err != nil {
+		return err
+	}
+	sy, sx := lg.ImgSize.Y, lg.ImgSize.X
+	isz := append(in.ShapeSizes()[:nd-2], sy, sx)
+	img.SetShapeSizes(isz...)
+	nc := in.Len() / (lg.Rings * lg.Wedges)
+	fx, fy := lg.fixation()
+	mx := lg.maxRadius()
+	lmx := math32.Log(mx / lg.MinRadius)
+	nr := float32(lg.Rings - 1)
+	nw := float32(lg.Wedges)
+	nproc.Run("LogPolar", sy, func(st, n int) {
+		for y := st; y < st+n; y++ {
+			for x := range sx {
+				dx := float32(x) - fx
+				dy := float32(y) - fy
+				rad := math32.Hypot(dx, dy)
+				if rad > mx {
+					for c := range nc {
+						img.Values[(c*sy+y)*sx+x] = 0
+					}
+					continue
+				}
+				rf := float32(0)
+				if rad > lg.MinRadius {
+					rf = min(nr*math32.Log(rad/lg.MinRadius)/lmx, nr)
+				}
+				ang := math32.Atan2(dy, dx)
+				if ang < 0 {
+					ang += 2 * math32.Pi
+				}
+				wf := nw * ang / (2 * math32.Pi)
+				r0 := min(int(rf), lg.Rings-2)
+				w0 := int(wf)
+				pr := rf - float32(r0)
+				pw := wf - float32(w0)
+				w0 %= lg.Wedges
+				w1 := (w0 + 1) % lg.Wedges
+				for c := range nc {
+					ri := (c*lg.Rings + r0) * lg.Wedges
+					v00 := in.Values[ri+w0]
+					v01 := in.Values[ri+w1]
+					v10 := in.Values[ri+lg.Wedges+w0]
+					v11 := in.Values[ri+lg.Wedges+w1]
+					img.Values[(c*sy+y)*sx+x] = (1-pr)*((1-pw)*v00+pw*v01) + pr*((1-pw)*v10+pw*v11)
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// bilinearZero returns the bilinearly interpolated value of a row-major
+// 2D image of given size, at floating-point coordinates y, x,
+// returning 0 outside of the image.
+func bilinearZero(vals []float32, sy, sx int, y, x float32) float32 {
+	if y < 0 || x < 0 || y > float32(sy-1) || x > float32(sx-1) {
+		return 0
+	}
+	y0 := min(int(y), max(sy-2, 0))
+	x0 := min(int(x), max(sx-2, 0))
+	y1 := min(y0+1, sy-1)
+	x1 := min(x0+1, sx-1)
+	py := y - float32(y0)
+	px := x - float32(x0)
+	v00 := vals[y0*sx+x0]
+	v01 := vals[y0*sx+x1]
+	v10 := vals[y1*sx+x0]
+	v11 := vals[y1*sx+x1]
+	return (1-py)*((1-px)*v00+px*v01) + py*((1-px)*v10+px*v11)
+}
@@ -5,74 +5,36 @@
 package vfilter
 
 import (
+	"context"
 	"image"
-	"sync"
 
 	"cogentcore.org/core/tensor"
-	"github.com/emer/vision/v2/nproc"
 )
 
-// MaxPool performs max-pooling over given pool size and spacing.
-// size must = spacing or 2 * spacing.
+// MaxPool performs max-pooling over given pool size and spacing,
+// which can be any combination (see PoolOut for the output size).
 // Pooling is sensitive to the feature structure of the input, which
 // must have shape: Y, X, Polarities, Angles.
 func MaxPool(psize, spc image.Point, in, out *tensor.Float32) {
-	ny := in.DimSize(0)
-	nx := in.DimSize(1)
-	pol := in.DimSize(2)
-	nang := in.DimSize(3)
-	oy := ny / int(spc.Y)
-	ox := nx / int(spc.X)
-	if spc.Y != psize.Y {
-		oy--
-	}
-	if spc.X != psize.X {
-		ox--
-	}
+	MaxPoolContext(context.Background(), psize, spc, in, out)
+}
+
+// MaxPoolT is the generic version of MaxPool, for any Float tensor type,
+// e.g., tensor.Float64.
+func MaxPoolT[T Float](psize, spc image.Point, in, out *tensor.Number[T]) {
+	maxPool(context.Background(), psize, spc, in, out)
+}
 
-	out.SetShapeSizes(oy, ox, pol, nang)
-	nf := pol * nang
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nf)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go maxPoolThr(&wg, f, nper, psize, spc, in, out)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go maxPoolThr(&wg, f, rmdr, psize, spc, in, out)
-	}
-	wg.Wait()
+// MaxPoolContext is MaxPool with cancellation and progress reporting
+// via ctx (see nproc.RunContext), returning ctx.Err() if canceled,
+// in which case out is only partially computed.
+func MaxPoolContext(ctx context.Context, psize, spc image.Point, in, out *tensor.Float32) error {
+	return maxPool(ctx, psize, spc, in, out)
 }
 
-// maxPoolThr is per-thread implementation
-func maxPoolThr(wg *sync.WaitGroup, fno, nf int, psize, spc image.Point, in, out *tensor.Float32) {
-	ny := out.DimSize(0)
-	nx := out.DimSize(1)
-	nang := out.DimSize(3)
-	for fi := 0; fi < nf; fi++ {
-		f := fno + fi
-		pol := f / nang
-		ang := f % nang
-		for y := 0; y < ny; y++ {
-			iy := y * spc.Y
-			for x := 0; x < nx; x++ {
-				ix := x * spc.X
-				mx := float32(0)
-				for py := 0; py < psize.Y; py++ {
-					for px := 0; px < psize.X; px++ {
-						iv := in.Value(iy+py, ix+px, pol, ang)
-						if iv > mx {
-							mx = iv
-						}
-					}
-				}
-				out.Set(mx, y, x, pol, ang)
-			}
-		}
-	}
-	wg.Done()
+// maxPool implements MaxPool, with cancellation via ctx
+func maxPool[T Float](ctx context.Context, psize, spc image.Point, in, out *tensor.Number[T]) error {
+	osz := PoolOut(psize, spc, image.Point{in.DimSize(1), in.DimSize(0)})
+	out.SetShapeSizes(osz.Y, osz.X, in.DimSize(2), in.DimSize(3))
+	return pool(ctx, psize, spc, image.Point{}, in, out, PoolMax, false)
 }
@@ -0,0 +1,160 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// checkMetric checks that the image tensors for a metric have the same
+// shape, and returns the number of maps and their unpadded size.
+func checkMetric(fun string, ref, img *tensor.Float32, padWidth int) (nm, sy, sx int, err error) {
+	if !slices.Equal(ref.ShapeSizes(), img.ShapeSizes()) {
+		err = fmt.Errorf("vfilter.%s: images must have the same shape: %v != %v", fun, ref.ShapeSizes(), img.ShapeSizes())
+		return
+	}
+	nd := ref.NumDims()
+	if nd < 2 {
+		err = fmt.Errorf("vfilter.%s: images must be at least 2D, have shape: %v", fun, ref.ShapeSizes())
+		return
+	}
+	sy, sx = ref.DimSize(nd-2)-2*padWidth, ref.DimSize(nd-1)-2*padWidth
+	if sy <= 0 || sx <= 0 {
+		err = fmt.Errorf("vfilter.%s: image shape %v is too small for padding %d", fun, ref.ShapeSizes(), padWidth)
+		return
+	}
+	nm = ref.Len() / (ref.DimSize(nd-2) * ref.DimSize(nd-1))
+	return
+}
+
+// MSE returns the mean squared error between given reference image
+// tensor and img, which must have the same shape: [Y, X], or with
+// outer components (e.g., RGB [3, Y, X]), excluding padWidth
+// of padding on all sides.
+func MSE(ref, img *tensor.Float32, padWidth int) (float32, error) {
+	nm, sy, sx, err := checkMetric("MSE", ref, img, padWidth)
+	if err != nil {
+		return 0, err
+	}
+	psy, psx := sy+2*padWidth, sx+2*padWidth
+	var sum float64
+	for m := range nm {
+		for y := range sy {
+			st := (m*psy+y+padWidth)*psx + padWidth
+			rv := ref.Values[st : st+sx]
+			iv := img.Values[st : st+sx]
+			for x, v := range rv {
+				d := float64(v - iv[x])
+				sum += d * d
+			}
+		}
+	}
+	return float32(sum / float64(nm*sy*sx)), nil
+}
+
+// PSNR returns the peak signal-to-noise ratio in decibels (dB) of img
+// relative to given reference image tensor, with given peak value of
+// the images (e.g., 1 for images in the 0-1 range), as computed from
+// the MSE, excluding padWidth of padding on all sides.
+// Higher values are better, and it is +Inf for identical images.
+func PSNR(ref, img *tensor.Float32, padWidth int, peak float32) (float32, error) {
+	mse, err := MSE(ref, img, padWidth)
+	if err != nil {
+		return 0, err
+	}
+	if mse == 0 {
+		return math32.Inf(1), nil
+	}
+	return 10 * math32.Log10(peak*peak/mse), nil
+}
+
+// SSIM returns the mean structural similarity index of img relative to
+// given reference image tensor (Wang et al, 2004), which must have the
+// same shape: [Y, X], or with outer components (e.g., RGB [3, Y, X]),
+// each of which is compared separately, excluding padWidth of padding
+// on all sides.  The local means, variances and covariance are computed
+// with the standard gaussian window (sigma = 1.5, out to 11 x 11),
+// truncated at the edges of the images, with the weights renormalized.
+// The constants are those of the original paper: (0.01 * peak)^2 and
+// (0.03 * peak)^2, with peak the peak value of the images (e.g., 1 for
+// images in the 0-1 range).  It is 1 for identical images, and is more
+// sensitive to the loss of structure than PSNR.
+func SSIM(ref, img *tensor.Float32, padWidth int, peak float32) (float32, error) {
+	nm, sy, sx, err := checkMetric("SSIM", ref, img, padWidth)
+	if err != nil {
+		return 0, err
+	}
+	const sig, kr = 1.5, 5
+	var wts [2*kr + 1]float32
+	for k := range wts {
+		d := float32(k-kr) / sig
+		wts[k] = math32.Exp(-0.5 * d * d)
+	}
+	c1 := (0.01 * peak) * (0.01 * peak)
+	c2 := (0.03 * peak) * (0.03 * peak)
+	psy, psx := sy+2*padWidth, sx+2*padWidth
+	// row sums of the 5 moments: a, b, a^2, b^2, a*b
+	rows := Pool.Get(5, nm, sy, sx)
+	defer Pool.Put(rows)
+	msz := nm * sy * sx
+	nproc.Run("SSIM", nm*sy, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			m, y := r/sy, r%sy
+			ist := (m*psy+y+padWidth)*psx + padWidth
+			for x := range sx {
+				var s [5]float32
+				var wt float32
+				for k := max(-kr, -x); k <= min(kr, sx-1-x); k++ {
+					w := wts[k+kr]
+					a, b := ref.Values[ist+x+k], img.Values[ist+x+k]
+					s[0] += w * a
+					s[1] += w * b
+					s[2] += w * a * a
+					s[3] += w * b * b
+					s[4] += w * a * b
+					wt += w
+				}
+				for i := range s {
+					rows.Values[i*msz+r*sx+x] = s[i] / wt
+				}
+			}
+		}
+	})
+	ssims := make([]float64, nm*sy)
+	nproc.Run("SSIM", nm*sy, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			m, y := r/sy, r%sy
+			var sum float64
+			for x := range sx {
+				var s [5]float32
+				var wt float32
+				for k := max(-kr, -y); k <= min(kr, sy-1-y); k++ {
+					w := wts[k+kr]
+					j := (m*sy+y+k)*sx + x
+					for i := range s {
+						s[i] += w * rows.Values[i*msz+j]
+					}
+					wt += w
+				}
+				ma, mb := s[0]/wt, s[1]/wt
+				va := s[2]/wt - ma*ma
+				vb := s[3]/wt - mb*mb
+				cv := s[4]/wt - ma*mb
+				sum += float64(((2*ma*mb + c1) * (2*cv + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2)))
+			}
+			ssims[r] = sum
+		}
+	})
+	var sum float64
+	for _, s := range ssims {
+		sum += s
+	}
+	return float32(sum / float64(nm*sy*sx)), nil
+}
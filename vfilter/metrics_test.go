@@ -0,0 +1,48 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"math/rand"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+func TestMetrics(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	ref := randTensor(rnd, 0, 3, 20, 24)
+	img := ref.Clone().(*tensor.Float32)
+	if p, err := PSNR(ref, img, 2, 1); err != nil || !math32.IsInf(p, 1) {
+		t.Errorf("PSNR identical: %g %v", p, err)
+	}
+	if s, err := SSIM(ref, img, 2, 1); err != nil || s != 1 {
+		t.Errorf("SSIM identical: %g %v", s, err)
+	}
+
+	// a constant offset of 0.1 is 20 dB, and only changes the luminance term
+	for i := range img.Values {
+		img.Values[i] += 0.1
+	}
+	if p, _ := PSNR(ref, img, 2, 1); math32.Abs(p-20) > 1.0e-3 {
+		t.Errorf("PSNR offset: %g != 20", p)
+	}
+	offset, _ := SSIM(ref, img, 2, 1)
+	for i := range img.Values {
+		img.Values[i] = ref.Values[i] + 0.3*float32(rnd.NormFloat64())
+	}
+	noise, _ := SSIM(ref, img, 2, 1)
+	if !(noise < offset && offset < 1) {
+		t.Errorf("SSIM noise: %g should be < offset: %g < 1", noise, offset)
+	}
+
+	if _, err := SSIM(ref, tensor.NewFloat32(3, 20, 23), 2, 1); err == nil {
+		t.Error("SSIM did not report shape mismatch")
+	}
+	if _, err := PSNR(ref, img, 10, 1); err == nil {
+		t.Error("PSNR did not report padding larger than image")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// InterpAngles interpolates a 4D [Y, X, Polarity, Angle] tensor of
+// oriented filter outputs (e.g., V1 simple cells with 4 angles) to
+// a finer sampling of nang angles (e.g., 8 or 16), without refiltering,
+// using steerable (trigonometric) interpolation over orientation:
+// outputs are treated as a periodic function of orientation over 180
+// degrees, represented exactly by the Fourier components up to the
+// Nyquist limit of the input angles.  As in gabor, the angles are
+// evenly spaced starting at horizontal, so every nang / NAngles output
+// angle is the same as an input angle, if nang is a multiple.
+// Negative interpolated values are set to 0, consistent with the
+// rectified filter outputs.
+func InterpAngles(in, out *tensor.Float32, nang int) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	npol := in.DimSize(2)
+	nin := in.DimSize(3)
+	out.SetShapeSizes(ny, nx, npol, nang)
+	wts := InterpAnglesWeights(nin, nang)
+	nproc.Run("InterpAngles", ny, func(st, n int) {
+		interpAnglesThr(st, n, wts, in, out)
+	})
+}
+
+// interpAnglesThr is per-thread implementation
+func interpAnglesThr(yst, ny int, wts []float32, in, out *tensor.Float32) {
+	nx := in.DimSize(1)
+	npol := in.DimSize(2)
+	nin := in.DimSize(3)
+	nang := out.DimSize(3)
+	for y := yst; y < yst+ny; y++ {
+		for x := 0; x < nx; x++ {
+			for p := 0; p < npol; p++ {
+				pi := (y*nx+x)*npol + p
+				iv := in.Values[pi*nin : (pi+1)*nin]
+				ov := out.Values[pi*nang : (pi+1)*nang]
+				for a := range ov {
+					wa := wts[a*nin : (a+1)*nin]
+					var sum float32
+					for k, v := range iv {
+						sum += wa[k] * v
+					}
+					ov[a] = math32.Max(sum, 0)
+				}
+			}
+		}
+	}
+}
+
+// InterpAnglesWeights returns the [nang][nin] matrix of weights for
+// computing each of nang output angles from nin input angles, as used
+// in InterpAngles, which is the periodic sinc (Dirichlet) kernel over
+// the doubled angle (as orientation has a period of 180 degrees).
+func InterpAnglesWeights(nin, nang int) []float32 {
+	wts := make([]float32, nang*nin)
+	nyq := nin / 2
+	for a := 0; a < nang; a++ {
+		phi := 2 * math32.Pi * float32(a) / float32(nang)
+		for k := 0; k < nin; k++ {
+			d := phi - 2*math32.Pi*float32(k)/float32(nin)
+			w := float32(1)
+			for m := 1; m <= nyq; m++ {
+				c := math32.Cos(float32(m) * d)
+				if 2*m == nin { // Nyquist term counted once
+					w += c
+				} else {
+					w += 2 * c
+				}
+			}
+			wts[a*nin+k] = w / float32(nin)
+		}
+	}
+	return wts
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"math/rand"
+	"sort"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// EdgeModes are the ways of filling the padding border around an image,
+// for Pad.
+type EdgeModes int32 //enums:enum
+
+const (
+	// Wrap wraps the image around, as in WrapPad:
+	// the padding on the left side is from the right side of the image, etc.
+	Wrap EdgeModes = iota
+
+	// Fade fades the edge values toward the mean edge value, as in FadePad.
+	Fade
+
+	// Reflect mirrors the image at its edges, as in ReflectPad.
+	Reflect
+
+	// Clamp extends the edge values into the padding, as in ClampPad.
+	Clamp
+
+	// Zero fills the padding with zeros, as in ZeroPad.
+	Zero
+
+	// Noise fills the padding with gaussian noise with the mean and
+	// standard deviation of the edge values, as in NoisePad.
+	Noise
+)
+
+// Pad fills given padding width around the sides of given image
+// tensor according to given edge mode.  The tensor can be grey [Y, X]
+// or have outer components, e.g., RGB [3, Y, X], each of which is
+// padded separately.  This allows the padding strategy to be a
+// parameter, instead of calling the specific padding functions.
+func Pad(tsr *tensor.Float32, padWidth int, mode EdgeModes) {
+	if tsr.NumDims() <= 2 {
+		padGrey(tsr, padWidth, mode)
+		return
+	}
+	var view tensor.Float32
+	nc := tsr.DimSize(0)
+	for i := 0; i < nc; i++ {
+		ChannelView(tsr, i, &view)
+		padGrey(&view, padWidth, mode)
+	}
+}
+
+// padGrey pads a grey [Y, X] image according to given mode
+func padGrey(tsr *tensor.Float32, padWidth int, mode EdgeModes) {
+	switch mode {
+	case Wrap:
+		WrapPad(tsr, padWidth)
+	case Fade:
+		FadePad(tsr, padWidth)
+	case Reflect:
+		ReflectPad(tsr, padWidth)
+	case Clamp:
+		ClampPad(tsr, padWidth)
+	case Zero:
+		ZeroPad(tsr, padWidth)
+	case Noise:
+		NoisePad(tsr, padWidth)
+	}
+}
+
+// padRange calls given function for each position in the padding
+// border of given width around given image size, with the position
+// of the nearest edge pixel of the image within the padding.
+func padRange(sy, sx, padWidth int, fun func(y, x, ey, ex int)) {
+	for y := 0; y < sy; y++ {
+		ey := min(max(y, padWidth), sy-padWidth-1)
+		inY := y >= padWidth && y < sy-padWidth
+		for x := 0; x < sx; x++ {
+			if inY && x >= padWidth && x < sx-padWidth {
+				continue
+			}
+			ex := min(max(x, padWidth), sx-padWidth-1)
+			fun(y, x, ey, ex)
+		}
+	}
+}
+
+// ClampPad fills given padding width of float32 image around sides
+// by extending the nearest edge value of the image outward.
+func ClampPad(tsr *tensor.Float32, padWidth int) {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	if padWidth <= 0 || sy <= 2*padWidth || sx <= 2*padWidth {
+		return
+	}
+	padRange(sy, sx, padWidth, func(y, x, ey, ex int) {
+		tsr.Values[y*sx+x] = tsr.Values[ey*sx+ex]
+	})
+}
+
+// ZeroPad fills given padding width of float32 image around sides
+// with zeros.
+func ZeroPad(tsr *tensor.Float32, padWidth int) {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	if padWidth <= 0 {
+		return
+	}
+	padRange(sy, sx, padWidth, func(y, x, ey, ex int) {
+		tsr.Values[y*sx+x] = 0
+	})
+}
+
+// NoiseFill specifies the distribution of the noise that NoisePadRand
+// fills the padding with, which is sampled from the values around the
+// effective edge of the image, so that the padding matches the image
+// statistics without the systematic structure of wrapping or fading,
+// which shows up in pooled filter statistics.
+type NoiseFill struct {
+
+	// sample from the histogram of the edge values, instead of a gaussian with their mean and standard deviation, so that skewed or multimodal edge distributions (e.g., sky and ground) are matched
+	Hist bool
+
+	// number of bins in the histogram of the edge values
+	NBins int `default:"32" min:"1"`
+}
+
+func (nf *NoiseFill) Defaults() {
+	nf.NBins = 32
+}
+
+func (nf *NoiseFill) ShouldDisplay(field string) bool {
+	switch field {
+	case "NBins":
+		return nf.Hist
+	default:
+		return true
+	}
+}
+
+// NoisePad fills given padding width of float32 image around sides
+// with gaussian noise, with the mean and standard deviation of the
+// values around the effective edge of the image, at padWidth in from
+// each side, so that the padding has no systematic structure.
+// See NoisePadRand for histogram sampling and reproducible noise.
+func NoisePad(tsr *tensor.Float32, padWidth int) {
+	NoisePadRand(tsr, padWidth, nil, nil)
+}
+
+// NoisePadRand fills given padding width of float32 image around sides
+// with noise sampled from the distribution of the values around the
+// effective edge of the image, at padWidth in from each side, as
+// specified by nf (nil for gaussian, as in NoisePad), using given
+// random number source, which can be seeded for reproducible
+// padding -- if nil, the global math/rand source is used.
+func NoisePadRand(tsr *tensor.Float32, padWidth int, nf *NoiseFill, rnd *rand.Rand) {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	if padWidth <= 0 || sy <= 2*padWidth || sx <= 2*padWidth {
+		return
+	}
+	rf, rn := rand.Float64, rand.NormFloat64
+	if rnd != nil {
+		rf, rn = rnd.Float64, rnd.NormFloat64
+	}
+	var edge []float32
+	for y := padWidth; y < sy-padWidth; y++ {
+		for x := padWidth; x < sx-padWidth; x++ {
+			if y != padWidth && y != sy-padWidth-1 && x != padWidth && x != sx-padWidth-1 {
+				continue
+			}
+			edge = append(edge, tsr.Values[y*sx+x])
+		}
+	}
+	if nf != nil && nf.Hist {
+		sample := edgeHist(edge, max(nf.NBins, 1))
+		padRange(sy, sx, padWidth, func(y, x, ey, ex int) {
+			tsr.Values[y*sx+x] = sample(rf(), rf())
+		})
+		return
+	}
+	var sum, ssq float32
+	for _, v := range edge {
+		sum += v
+		ssq += v * v
+	}
+	n := float32(len(edge))
+	mean := sum / n
+	std := math32.Sqrt(max(ssq/n-mean*mean, 0))
+	padRange(sy, sx, padWidth, func(y, x, ey, ex int) {
+		tsr.Values[y*sx+x] = mean + std*float32(rn())
+	})
+}
+
+// edgeHist returns a function that samples from the histogram of given
+// values with given number of bins, using two uniform random numbers:
+// one to select the bin, and one for the value within the bin.
+func edgeHist(vals []float32, nBins int) func(ub, uv float64) float32 {
+	mn, mx := vals[0], vals[0]
+	for _, v := range vals {
+		mn = min(mn, v)
+		mx = max(mx, v)
+	}
+	if mx == mn {
+		return func(ub, uv float64) float32 { return mn }
+	}
+	wd := (mx - mn) / float32(nBins)
+	cum := make([]int, nBins)
+	for _, v := range vals {
+		cum[min(int((v-mn)/wd), nBins-1)]++
+	}
+	for i := 1; i < nBins; i++ {
+		cum[i] += cum[i-1]
+	}
+	return func(ub, uv float64) float32 {
+		c := int(ub * float64(len(vals)))
+		b := sort.Search(nBins, func(i int) bool { return cum[i] > c })
+		return mn + (float32(b)+float32(uv))*wd
+	}
+}
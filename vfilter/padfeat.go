@@ -0,0 +1,90 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// PadFeatures fills given padding width around the sides of the outer
+// two spatial dimensions of given 4D feature tensor, as from Conv:
+// [Y, X, Polarity, Angle], according to given edge mode, as in Pad,
+// separately for the map of each polarity and angle.
+// This allows second-stage filters (e.g., V2) to be applied to the
+// feature maps with the same padded, no-bounds-check approach as Conv.
+// See PaddedFeatures to add the padding to the output of Conv,
+// and FeatureMap to get a padded map as the image for Conv.
+func PadFeatures(tsr *tensor.Float32, padWidth int, mode EdgeModes) {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	nm := tsr.DimSize(2) * tsr.DimSize(3)
+	if padWidth <= 0 || sy == 0 || sx == 0 || nm == 0 {
+		return
+	}
+	nproc.Run("PadFeatures", nm, func(st, n int) {
+		fm := Pool.Get(sy, sx)
+		defer Pool.Put(fm)
+		for m := st; m < st+n; m++ {
+			for i := range fm.Values {
+				fm.Values[i] = tsr.Values[i*nm+m]
+			}
+			padGrey(fm, padWidth, mode)
+			for i, v := range fm.Values {
+				tsr.Values[i*nm+m] = v
+			}
+		}
+	})
+}
+
+// WrapPadFeatures wraps given padding width of the outer two spatial
+// dimensions of a 4D feature tensor around the sides, as in WrapPad,
+// for each polarity and angle (see PadFeatures).
+func WrapPadFeatures(tsr *tensor.Float32, padWidth int) {
+	PadFeatures(tsr, padWidth, Wrap)
+}
+
+// FadePadFeatures fades given padding width of the outer two spatial
+// dimensions of a 4D feature tensor toward the mean edge value,
+// as in FadePad, for each polarity and angle (see PadFeatures).
+func FadePadFeatures(tsr *tensor.Float32, padWidth int) {
+	PadFeatures(tsr, padWidth, Fade)
+}
+
+// PaddedFeatures copies given 4D feature tensor, as from Conv:
+// [Y, X, Polarity, Angle], into out, with padWidth of padding added
+// on all sides of the outer two spatial dimensions, filled according
+// to given edge mode, as in PadFeatures.
+// Returns an error if in is not 4D.
+func PaddedFeatures(in, out *tensor.Float32, padWidth int, mode EdgeModes) error {
+	if in.NumDims() != 4 {
+		return fmt.Errorf("vfilter.PaddedFeatures: input must be 4D [Y, X, Polarity, Angle], has shape: %v", in.ShapeSizes())
+	}
+	sy, sx := in.DimSize(0), in.DimSize(1)
+	np, na := in.DimSize(2), in.DimSize(3)
+	psx := sx + 2*padWidth
+	out.SetShapeSizes(sy+2*padWidth, psx, np, na)
+	nm := np * na
+	rsz := sx * nm
+	for y := 0; y < sy; y++ {
+		copy(out.Values[((y+padWidth)*psx+padWidth)*nm:], in.Values[y*rsz:(y+1)*rsz])
+	}
+	PadFeatures(out, padWidth, mode)
+	return nil
+}
+
+// FeatureMap copies the map of given polarity and angle from given
+// 4D feature tensor: [Y, X, Polarity, Angle] into the 2D out: [Y, X],
+// e.g., to use a padded map (from PadFeatures) as the image for Conv.
+func FeatureMap(tsr *tensor.Float32, pol, ang int, out *tensor.Float32) {
+	sy, sx := tsr.DimSize(0), tsr.DimSize(1)
+	nm := tsr.DimSize(2) * tsr.DimSize(3)
+	m := pol*tsr.DimSize(3) + ang
+	out.SetShapeSizes(sy, sx)
+	for i := range out.Values {
+		out.Values[i] = tsr.Values[i*nm+m]
+	}
+}
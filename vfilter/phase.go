@@ -0,0 +1,36 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// PhaseEnergy computes the phase-invariant energy of signed multi-phase
+// responses from ConvPhase, as the square root of the sum of squares
+// over phases, which for a quadrature pair (phases 0, 90) is the local
+// energy (amplitude) of the response, as in complex cells.
+// in shape dims are: Y, X, Phase, Angle
+// out shape dims are: Y, X, 1, Angle
+func PhaseEnergy(in, out *tensor.Float32) {
+	ny := in.DimSize(0)
+	nx := in.DimSize(1)
+	nph := in.DimSize(2)
+	nang := in.DimSize(3)
+	out.SetShapeSizes(ny, nx, 1, nang)
+	for y := range ny {
+		for x := range nx {
+			for ang := range nang {
+				sum := float32(0)
+				for ph := range nph {
+					v := in.Value(y, x, ph, ang)
+					sum += v * v
+				}
+				out.Set(math32.Sqrt(sum), y, x, 0, ang)
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"slices"
+	"sync"
+
+	"cogentcore.org/core/tensor"
+)
+
+// TensorPool is a pool of tensors for the intermediate outputs that
+// are created for each frame of a processing pipeline, which all have
+// the same shapes from one frame to the next.  Tensors are acquired
+// with Get and must be explicitly returned with Put when no longer
+// used, after which Get returns the same memory, so that steady-state
+// processing does no allocation.  Free tensors are kept by number
+// of values, so any shape with the same number of values can be reused.
+// It is safe for concurrent use.  The zero value is ready to use.
+type TensorPool struct {
+
+	// free tensors, by number of values
+	free map[int][]*tensor.Float32
+
+	// number of tensors allocated by the pool
+	nalloc int
+
+	// mutex for concurrent access
+	mu sync.Mutex
+}
+
+// Pool is the default TensorPool used for intermediate tensors
+// within this and other packages.
+var Pool TensorPool
+
+// Get returns a tensor with given shape sizes, reusing a free tensor
+// with the same number of values if available, or allocating a new one.
+// The values are not initialized: use SetZeros if needed.
+func (tp *TensorPool) Get(sizes ...int) *tensor.Float32 {
+	n := 1
+	for _, s := range sizes {
+		n *= s
+	}
+	tp.mu.Lock()
+	var tsr *tensor.Float32
+	if fl := tp.free[n]; len(fl) > 0 {
+		tsr = fl[len(fl)-1]
+		tp.free[n] = fl[:len(fl)-1]
+	} else {
+		tsr = &tensor.Float32{}
+		tp.nalloc++
+	}
+	tp.mu.Unlock()
+	if !slices.Equal(tsr.Shape().Sizes, sizes) { // avoid reallocating the shape
+		tsr.SetShapeSizes(sizes...)
+	}
+	return tsr
+}
+
+// GetLike returns a tensor with the same shape as given tensor,
+// as in Get.
+func (tp *TensorPool) GetLike(like *tensor.Float32) *tensor.Float32 {
+	return tp.Get(like.Shape().Sizes...)
+}
+
+// Put returns given tensors to the pool, for reuse by Get.
+// The tensors must not be used by the caller after this.
+// nil tensors are ignored.
+func (tp *TensorPool) Put(tsrs ...*tensor.Float32) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if tp.free == nil {
+		tp.free = make(map[int][]*tensor.Float32)
+	}
+	for _, tsr := range tsrs {
+		if tsr == nil {
+			continue
+		}
+		n := len(tsr.Values)
+		tp.free[n] = append(tp.free[n], tsr)
+	}
+}
+
+// NAlloc returns the total number of tensors allocated by the pool,
+// which stops increasing once processing reaches a steady state,
+// if all tensors are returned with Put.
+func (tp *TensorPool) NAlloc() int {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.nalloc
+}
+
+// NFree returns the number of free tensors in the pool.
+func (tp *TensorPool) NFree() int {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	nf := 0
+	for _, fl := range tp.free {
+		nf += len(fl)
+	}
+	return nf
+}
+
+// Reset releases all of the free tensors, so their memory can be
+// garbage collected, e.g., when the image size changes.
+func (tp *TensorPool) Reset() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.free = nil
+	tp.nalloc = 0
+}
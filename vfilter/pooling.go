@@ -0,0 +1,125 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"context"
+	"image"
+	"math"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// PoolOps are the pooling operations, for PoolPadded, GlobalPool
+// and FeatPool
+type PoolOps int32 //enums:enum
+
+const (
+	// PoolMax takes the max over the pool, as in MaxPool
+	PoolMax PoolOps = iota
+
+	// PoolAvg takes the average over the pool, as in AvgPool
+	PoolAvg
+
+	// PoolL2 takes the square root of the sum of squares over the pool,
+	// as in L2Pool
+	PoolL2
+)
+
+// PoolOut returns the output size for pooling given input size with
+// given pool size and spacing: the number of complete pools that fit
+// within the input along each axis, for any combination of size and
+// spacing, e.g., 3x3 pools with a spacing of 1 (overlapping), or
+// 2x2 pools with a spacing of 3 (gaps between pools).
+func PoolOut(psize, spc, in image.Point) image.Point {
+	var out image.Point
+	if in.X >= psize.X && spc.X > 0 {
+		out.X = (in.X-psize.X)/spc.X + 1
+	}
+	if in.Y >= psize.Y && spc.Y > 0 {
+		out.Y = (in.Y-psize.Y)/spc.Y + 1
+	}
+	return out
+}
+
+// PoolPadded performs pooling with given operation over an input that
+// has a border of padding, using the same geometry as Conv: the geom
+// Border, Spacing, and FiltSz as the pool size, which is centered on
+// each output position as for a filter, so the output is aligned with
+// Conv outputs of the same geometry.  Pools are clipped to the extent
+// of the input, and averages are over the values within the input.
+// The input must have shape: Y, X, Polarities, Angles,
+// and the output has the same structure.
+func PoolPadded(geom *Geom, op PoolOps, in, out *tensor.Float32) {
+	geom.UpdtFilt()
+	geom.SetSize(image.Point{in.DimSize(1), in.DimSize(0)})
+	out.SetShapeSizes(max(geom.Out.Y, 0), max(geom.Out.X, 0), in.DimSize(2), in.DimSize(3))
+	pool(context.Background(), geom.FiltSz, geom.Spacing, geom.Border.Sub(geom.FiltLt), in, out, op, false)
+}
+
+// pool is the implementation of the pooling functions, with pools of
+// given size starting at given offset plus the spacing times the output
+// position, clipped to the input, summing over the polarities if overPol,
+// into out, which must already have its shape set, with cancellation
+// via ctx.
+func pool[T Float](ctx context.Context, psize, spc, ist image.Point, in, out *tensor.Number[T], op PoolOps, overPol bool) error {
+	nf := out.DimSize(2) * out.DimSize(3)
+	return nproc.RunContext(ctx, [...]string{"MaxPool", "AvgPool", "L2Pool"}[op], nf, func(st, n int) {
+		poolThr(st, n, psize, spc, ist, in, out, op, overPol)
+	})
+}
+
+// poolThr is per-thread implementation
+func poolThr[T Float](fno, nf int, psize, spc, ist image.Point, in, out *tensor.Number[T], op PoolOps, overPol bool) {
+	iny := in.DimSize(0)
+	inx := in.DimSize(1)
+	npol := in.DimSize(2)
+	ny := out.DimSize(0)
+	nx := out.DimSize(1)
+	nang := out.DimSize(3)
+	for fi := 0; fi < nf; fi++ {
+		f := fno + fi
+		pol := f / nang
+		ang := f % nang
+		pst, ped := pol, pol+1
+		if overPol {
+			pst, ped = 0, npol
+		}
+		for y := 0; y < ny; y++ {
+			iy := ist.Y + y*spc.Y
+			ys, ye := max(iy, 0), min(iy+psize.Y, iny)
+			for x := 0; x < nx; x++ {
+				ix := ist.X + x*spc.X
+				xs, xe := max(ix, 0), min(ix+psize.X, inx)
+				sum := T(0)
+				for p := pst; p < ped; p++ {
+					for py := ys; py < ye; py++ {
+						for px := xs; px < xe; px++ {
+							iv := in.Value(py, px, p, ang)
+							switch op {
+							case PoolMax:
+								sum = max(sum, iv)
+							case PoolAvg:
+								sum += iv
+							case PoolL2:
+								sum += iv * iv
+							}
+						}
+					}
+				}
+				switch op {
+				case PoolAvg:
+					if n := (ye - ys) * (xe - xs) * (ped - pst); n > 0 {
+						sum /= T(n)
+					}
+				case PoolL2:
+					sum = T(math.Sqrt(float64(sum)))
+				}
+				out.Set(sum, y, x, pol, ang)
+			}
+		}
+	}
+}
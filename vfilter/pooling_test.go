@@ -0,0 +1,125 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"image"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// refPool returns the max and average of the pool starting at iy, ix,
+// clipped to the input
+func refPool(in *tensor.Float32, iy, ix int, psize image.Point, pol, ang int) (mx, avg float32) {
+	n := 0
+	for py := max(iy, 0); py < min(iy+psize.Y, in.DimSize(0)); py++ {
+		for px := max(ix, 0); px < min(ix+psize.X, in.DimSize(1)); px++ {
+			iv := in.Value(py, px, pol, ang)
+			mx = max(mx, iv)
+			avg += iv
+			n++
+		}
+	}
+	if n > 0 {
+		avg /= float32(n)
+	}
+	return
+}
+
+func TestPoolConfigs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	in := randTensor(rnd, 0, 11, 13, 2, 2)
+	configs := []struct {
+		psize, spc, out image.Point
+	}{
+		{image.Point{3, 3}, image.Point{1, 1}, image.Point{11, 9}},
+		{image.Point{2, 2}, image.Point{3, 3}, image.Point{4, 4}},
+		{image.Point{3, 2}, image.Point{2, 1}, image.Point{6, 10}},
+		{image.Point{4, 4}, image.Point{2, 2}, image.Point{5, 4}},
+		{image.Point{2, 2}, image.Point{2, 2}, image.Point{6, 5}},
+	}
+	var mx, avg, un tensor.Float32
+	for _, cf := range configs {
+		if got := PoolOut(cf.psize, cf.spc, image.Point{13, 11}); got != cf.out {
+			t.Errorf("PoolOut %v %v: %v != %v", cf.psize, cf.spc, got, cf.out)
+		}
+		MaxPool(cf.psize, cf.spc, in, &mx)
+		AvgPool(cf.psize, cf.spc, in, &avg)
+		want := []int{cf.out.Y, cf.out.X, 2, 2}
+		if !slices.Equal(mx.ShapeSizes(), want) || !slices.Equal(avg.ShapeSizes(), want) {
+			t.Fatalf("%v %v shapes: %v %v != %v", cf.psize, cf.spc, mx.ShapeSizes(), avg.ShapeSizes(), want)
+		}
+		for y := 0; y < cf.out.Y; y++ {
+			for x := 0; x < cf.out.X; x++ {
+				rmx, ravg := refPool(in, y*cf.spc.Y, x*cf.spc.X, cf.psize, 0, 1)
+				if got := mx.Value(y, x, 0, 1); got != rmx {
+					t.Errorf("MaxPool %v %v at %d, %d: %g != %g", cf.psize, cf.spc, y, x, got, rmx)
+				}
+				if got := avg.Value(y, x, 0, 1); math32.Abs(got-ravg) > 1.0e-6 {
+					t.Errorf("AvgPool %v %v at %d, %d: %g != %g", cf.psize, cf.spc, y, x, got, ravg)
+				}
+			}
+		}
+
+		// UnPool gives each element the max of the pools containing it
+		tensor.SetShapeFrom(&un, in)
+		UnPool(cf.psize, cf.spc, &un, &mx, false)
+		for iy := 0; iy < 11; iy++ {
+			for ix := 0; ix < 13; ix++ {
+				ref := float32(0)
+				for y := 0; y < cf.out.Y; y++ {
+					for x := 0; x < cf.out.X; x++ {
+						if iy >= y*cf.spc.Y && iy < y*cf.spc.Y+cf.psize.Y && ix >= x*cf.spc.X && ix < x*cf.spc.X+cf.psize.X {
+							ref = max(ref, mx.Value(y, x, 0, 1))
+						}
+					}
+				}
+				if got := un.Value(iy, ix, 0, 1); got != ref {
+					t.Errorf("UnPool %v %v at %d, %d: %g != %g", cf.psize, cf.spc, iy, ix, got, ref)
+				}
+			}
+		}
+	}
+}
+
+func TestPoolPadded(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	in := randTensor(rnd, 0, 10, 12, 2, 3)
+	geoms := []struct {
+		border, spc, psize, out image.Point
+	}{
+		{image.Point{2, 2}, image.Point{1, 1}, image.Point{3, 3}, image.Point{8, 6}},
+		{image.Point{2, 1}, image.Point{2, 1}, image.Point{4, 2}, image.Point{4, 8}},
+		{image.Point{3, 3}, image.Point{1, 2}, image.Point{3, 3}, image.Point{6, 2}},
+	}
+	for _, gm := range geoms {
+		var geom Geom
+		geom.Set(gm.border, gm.spc, gm.psize)
+		ist := geom.Border.Sub(geom.FiltLt)
+		for _, op := range []PoolOps{PoolMax, PoolAvg} {
+			var out tensor.Float32
+			PoolPadded(&geom, op, in, &out)
+			if want := []int{gm.out.Y, gm.out.X, 2, 3}; !slices.Equal(out.ShapeSizes(), want) {
+				t.Fatalf("%v %v shape: %v != %v", op, gm.psize, out.ShapeSizes(), want)
+			}
+			for y := 0; y < gm.out.Y; y++ {
+				for x := 0; x < gm.out.X; x++ {
+					rmx, ravg := refPool(in, ist.Y+y*gm.spc.Y, ist.X+x*gm.spc.X, gm.psize, 1, 2)
+					ref := rmx
+					if op == PoolAvg {
+						ref = ravg
+					}
+					if got := out.Value(y, x, 1, 2); math32.Abs(got-ref) > 1.0e-6 {
+						t.Errorf("%v %v at %d, %d: %g != %g", op, gm.psize, y, x, got, ref)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/tensor"
+)
+
+// PyramidModes are the ways of reducing each level of a Pyramid
+// to half the size of the previous level
+type PyramidModes int32 //enums:enum
+
+const (
+	// PyrGauss blurs with a 5 tap binomial (gaussian) kernel before
+	// taking every other pixel, as in the classic Burt & Adelson
+	// gaussian pyramid, which minimizes aliasing.
+	PyrGauss PyramidModes = iota
+
+	// PyrBox averages each 2x2 block of pixels, which is faster,
+	// but has more aliasing of high spatial frequencies.
+	PyrBox
+)
+
+// Pyramid computes the outputs of a filter bank at multiple spatial
+// scales, by filtering each level of an image pyramid, where each level
+// is half the size of the previous one, with the same filters and
+// geometry, so that each level responds to half the spatial frequency
+// of the previous one (i.e., an octave lower).  The outputs of each
+// level are in Outs, and aggregated into All at the resolution
+// of the first level.
+type Pyramid struct {
+
+	// number of levels, including the original image as the first level
+	Levels int `default:"3" min:"1"`
+
+	// how each level of the image pyramid is reduced from the previous one
+	Mode PyramidModes
+
+	// how the border of each level is padded for filtering (see ConvSafe)
+	Pad EdgeModes `default:"Reflect"`
+
+	// gain applied to the filter outputs at each level
+	Gain float32 `default:"1"`
+
+	// geometry for filtering each level: the Border and Spacing are used for each level, and the rest is computed
+	Geom Geom `display:"inline"`
+
+	// images for each level of the pyramid: [Y, X], with the first being a copy of the input image
+	Images []tensor.Float32 `display:"-"`
+
+	// filtering geometry for each level, as computed by ConvSafe
+	Geoms []Geom `display:"-"`
+
+	// filter outputs for each level, as from Conv: [Y, X, Polarity (2), Filter]
+	Outs []tensor.Float32 `display:"-"`
+
+	// filter outputs of all levels at the resolution of the first level, with each coarser level replicated over the corresponding positions, and the polarities of each level as the rows: [Y, X, Level * 2 + Polarity, Filter], as in the V1All feature tensors
+	All tensor.Float32 `display:"no-inline"`
+}
+
+func (pr *Pyramid) Defaults() {
+	pr.Levels = 3
+	pr.Mode = PyrGauss
+	pr.Pad = Reflect
+	pr.Gain = 1
+}
+
+// Build builds the image pyramid in Images from given grey-scale
+// image without any padding: [Y, X].  Returns an error if the image
+// is not 2D, or is too small for the number of Levels.
+func (pr *Pyramid) Build(img *tensor.Float32) error {
+	if img.NumDims() != 2 {
+		return fmt.Errorf("vfilter.Pyramid.Build: image must be 2D, has shape: %v", img.ShapeSizes())
+	}
+	nlev := max(pr.Levels, 1)
+	sy, sx := img.DimSize(0), img.DimSize(1)
+	if min(sy, sx)>>(nlev-1) < 1 {
+		return fmt.Errorf("vfilter.Pyramid.Build: image size %d x %d is too small for %d levels", sx, sy, nlev)
+	}
+	if len(pr.Images) != nlev {
+		pr.Images = make([]tensor.Float32, nlev)
+	}
+	tensor.SetShapeFrom(&pr.Images[0], img)
+	copy(pr.Images[0].Values, img.Values)
+	for l := 1; l < nlev; l++ {
+		Downsample(&pr.Images[l-1], &pr.Images[l], pr.Mode)
+	}
+	return nil
+}
+
+// Filter builds the image pyramid from given grey-scale image without
+// any padding ([Y, X]), and filters each level with given filters
+// ([Filter, Y, X]) using ConvSafe, with the Outs for each level
+// aggregated into All.  Returns an error if the image is not 2D,
+// is too small for the number of Levels, or cannot be filtered.
+func (pr *Pyramid) Filter(img, flt *tensor.Float32) error {
+	if err := pr.Build(img); err != nil {
+		return err
+	}
+	nlev := len(pr.Images)
+	if len(pr.Outs) != nlev {
+		pr.Outs = make([]tensor.Float32, nlev)
+		pr.Geoms = make([]Geom, nlev)
+	}
+	for l := range nlev {
+		pr.Geoms[l] = pr.Geom
+		err := ConvSafe(&pr.Geoms[l], flt, &pr.Images[l], &pr.Outs[l], pr.Gain, pr.Pad)
+		if err != nil {
+			return fmt.Errorf("vfilter.Pyramid.Filter: level %d: %w", l, err)
+		}
+	}
+	pr.Aggregate()
+	return nil
+}
+
+// Aggregate aggregates the Outs of each level into All, at the resolution
+// of the first level, where each output position takes the value of the
+// nearest output position of each coarser level, at the same location
+// in the image.
+func (pr *Pyramid) Aggregate() {
+	nlev := len(pr.Outs)
+	if nlev == 0 {
+		return
+	}
+	o0 := &pr.Outs[0]
+	oy, ox, nf := o0.DimSize(0), o0.DimSize(1), o0.DimSize(3)
+	pr.All.SetShapeSizes(oy, ox, 2*nlev, nf)
+	for l := range nlev {
+		out := &pr.Outs[l]
+		ly, lx := out.DimSize(0), out.DimSize(1)
+		if ly == 0 || lx == 0 {
+			continue
+		}
+		spc := pr.Geoms[l].Spacing
+		for y := range oy {
+			// image position of level 0 output, at this level
+			yl := min((y*pr.Geoms[0].Spacing.Y>>l)/spc.Y, ly-1)
+			for x := range ox {
+				xl := min((x*pr.Geoms[0].Spacing.X>>l)/spc.X, lx-1)
+				for p := range 2 {
+					for f := range nf {
+						pr.All.Set(out.Value(yl, xl, p, f), y, x, 2*l+p, f)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Downsample reduces given grey-scale image without any padding ([Y, X])
+// to half its size in out, using given mode, where any odd last row or
+// column is dropped.  Edges are reflected for PyrGauss.
+func Downsample(in, out *tensor.Float32, mode PyramidModes) {
+	sy, sx := in.DimSize(0), in.DimSize(1)
+	oy, ox := sy/2, sx/2
+	out.SetShapeSizes(oy, ox)
+	if mode == PyrBox {
+		for y := range oy {
+			for x := range ox {
+				sum := in.Value(2*y, 2*x) + in.Value(2*y, 2*x+1) + in.Value(2*y+1, 2*x) + in.Value(2*y+1, 2*x+1)
+				out.Set(0.25*sum, y, x)
+			}
+		}
+		return
+	}
+	wts := [5]float32{1.0 / 16, 4.0 / 16, 6.0 / 16, 4.0 / 16, 1.0 / 16}
+	// rows first, at the output X positions
+	tmp := Pool.Get(sy, ox)
+	defer Pool.Put(tmp)
+	for y := range sy {
+		for x := range ox {
+			var sum float32
+			for k, w := range wts {
+				sum += w * in.Value(y, ReflectIndex(2*x+k-2, sx))
+			}
+			tmp.Set(sum, y, x)
+		}
+	}
+	for y := range oy {
+		for x := range ox {
+			var sum float32
+			for k, w := range wts {
+				sum += w * tmp.Value(ReflectIndex(2*y+k-2, sy), x)
+			}
+			out.Set(sum, y, x)
+		}
+	}
+}
@@ -5,8 +5,6 @@
 package vfilter
 
 import (
-	"sync"
-
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
 )
@@ -19,24 +17,13 @@ func MaxReduceFilterY(in, out *tensor.Float32) {
 	nx := in.DimSize(1)
 	nang := in.DimSize(3)
 	out.SetShapeSizes(ny, nx, 1, nang)
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nang)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go maxReduceFilterYThr(&wg, f, nper, in, out)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go maxReduceFilterYThr(&wg, f, rmdr, in, out)
-	}
-	wg.Wait()
+	nproc.Run("MaxReduceFilterY", nang, func(st, n int) {
+		maxReduceFilterYThr(st, n, in, out)
+	})
 }
 
 // maxReduceFilterYThr is per-thread implementation
-func maxReduceFilterYThr(wg *sync.WaitGroup, fno, nf int, in, out *tensor.Float32) {
+func maxReduceFilterYThr(fno, nf int, in, out *tensor.Float32) {
 	ny := in.DimSize(0)
 	nx := in.DimSize(1)
 	np := in.DimSize(2)
@@ -55,5 +42,4 @@ func maxReduceFilterYThr(wg *sync.WaitGroup, fno, nf int, in, out *tensor.Float3
 			}
 		}
 	}
-	wg.Done()
 }
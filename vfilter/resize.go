@@ -0,0 +1,166 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/vision/v2/nproc"
+)
+
+// ResizeModes are the interpolation kernels for ResizeTensor
+type ResizeModes int32 //enums:enum
+
+const (
+	// Bilinear interpolates linearly between the 2 nearest pixels
+	// in each direction, which is fast, but somewhat blurry.
+	Bilinear ResizeModes = iota
+
+	// Bicubic uses the Catmull-Rom cubic kernel over the 4 nearest
+	// pixels in each direction, which is sharper than Bilinear.
+	Bicubic
+
+	// Lanczos uses the Lanczos windowed sinc kernel over the 6 nearest
+	// pixels in each direction, which is the sharpest, and best preserves
+	// the spatial frequencies that drive the filters.
+	Lanczos
+)
+
+// kernel returns the kernel function and its support radius
+func (rm ResizeModes) kernel() (func(x float32) float32, float32) {
+	switch rm {
+	case Bicubic:
+		return func(x float32) float32 {
+			x = math32.Abs(x)
+			switch {
+			case x < 1:
+				return (1.5*x-2.5)*x*x + 1
+			case x < 2:
+				return ((-0.5*x+2.5)*x-4)*x + 2
+			}
+			return 0
+		}, 2
+	case Lanczos:
+		sinc := func(x float32) float32 {
+			if x == 0 {
+				return 1
+			}
+			x *= math32.Pi
+			return math32.Sin(x) / x
+		}
+		return func(x float32) float32 {
+			if x <= -3 || x >= 3 {
+				return 0
+			}
+			return sinc(x) * sinc(x/3)
+		}, 3
+	default:
+		return func(x float32) float32 {
+			return max(1-math32.Abs(x), 0)
+		}, 1
+	}
+}
+
+// resizeWeights are the input indexes and weights for each output
+// position along one axis, with n taps per output.
+type resizeWeights struct {
+	n   int
+	idx []int
+	wts []float32
+}
+
+// newResizeWeights returns the resizeWeights for resizing an axis of
+// given input size to given output size, with the kernel stretched
+// by the downsampling factor for anti-aliasing, and the input
+// indexes clamped to the edges of the input.
+func newResizeWeights(in, out int, mode ResizeModes) *resizeWeights {
+	fun, sup := mode.kernel()
+	scale := float32(out) / float32(in)
+	fs := max(1/scale, 1)
+	rad := sup * fs
+	nr := int(math32.Ceil(rad))
+	rw := &resizeWeights{n: 2*nr + 1}
+	rw.idx = make([]int, out*rw.n)
+	rw.wts = make([]float32, out*rw.n)
+	for o := range out {
+		c := (float32(o)+0.5)/scale - 0.5
+		st := int(math32.Floor(c)) - nr + 1
+		var sum float32
+		for k := range rw.n {
+			i := st + k
+			w := fun((float32(i) - c) / fs)
+			rw.idx[o*rw.n+k] = min(max(i, 0), in-1)
+			rw.wts[o*rw.n+k] = w
+			sum += w
+		}
+		if sum != 0 {
+			for k := range rw.n {
+				rw.wts[o*rw.n+k] /= sum
+			}
+		}
+	}
+	return rw
+}
+
+// ResizeTensor resizes given unpadded image tensor ([Y, X], or with
+// outer components, e.g., RGB [3, Y, X]) to given size in out, using
+// given interpolation mode, with anti-aliasing when downsampling
+// (the kernel is stretched to cover all of the input pixels), in two
+// separable passes.  out has the same outer dimensions as in, with
+// padWidth of padding added on all sides (as in RGBToTensor), which is
+// not filled (see Pad).  Bicubic and Lanczos can overshoot the range of
+// the input values at sharp edges.  Returns an error for invalid shapes.
+func ResizeTensor(in, out *tensor.Float32, size image.Point, padWidth int, mode ResizeModes) error {
+	nd := in.NumDims()
+	if nd < 2 {
+		return fmt.Errorf("vfilter.ResizeTensor: image must be at least 2D, has shape: %v", in.ShapeSizes())
+	}
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("vfilter.ResizeTensor: size must be positive: %v", size)
+	}
+	iy, ix := in.DimSize(nd-2), in.DimSize(nd-1)
+	psy, psx := size.Y+2*padWidth, size.X+2*padWidth
+	osz := append(in.ShapeSizes()[:nd-2], psy, psx)
+	out.SetShapeSizes(osz...)
+	if iy == 0 || ix == 0 {
+		return nil
+	}
+	nc := in.Len() / (iy * ix)
+	xw := newResizeWeights(ix, size.X, mode)
+	yw := newResizeWeights(iy, size.Y, mode)
+	rows := Pool.Get(nc, iy, size.X)
+	defer Pool.Put(rows)
+	nproc.Run("Resize", nc*iy, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			iv := in.Values[r*ix : (r+1)*ix]
+			rv := rows.Values[r*size.X : (r+1)*size.X]
+			for x := range size.X {
+				var sum float32
+				for k := range xw.n {
+					sum += xw.wts[x*xw.n+k] * iv[xw.idx[x*xw.n+k]]
+				}
+				rv[x] = sum
+			}
+		}
+	})
+	nproc.Run("Resize", nc*size.Y, func(st, n int) {
+		for r := st; r < st+n; r++ {
+			c, y := r/size.Y, r%size.Y
+			cv := rows.Values[c*iy*size.X : (c+1)*iy*size.X]
+			ov := out.Values[(c*psy+y+padWidth)*psx+padWidth:]
+			for x := range size.X {
+				var sum float32
+				for k := range yw.n {
+					sum += yw.wts[y*yw.n+k] * cv[yw.idx[y*yw.n+k]*size.X+x]
+				}
+				ov[x] = sum
+			}
+		}
+	})
+	return nil
+}
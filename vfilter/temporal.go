@@ -0,0 +1,133 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"slices"
+
+	"cogentcore.org/core/tensor"
+)
+
+// ExpInteg does exponential temporal integration (low-pass filtering)
+// over a stream of same-shaped tensors, e.g., successive outputs of
+// any filtering stage over the frames of a video, maintaining the
+// integrated State across calls.
+type ExpInteg struct {
+
+	// time constant in frames for integration -- 1 = no integration
+	Tau float32 `default:"4"`
+
+	// integrated state, same shape as inputs
+	State tensor.Float32 `display:"no-inline"`
+
+	// number of inputs integrated since Init
+	N int `edit:"-"`
+
+	// rate = 1 / tau
+	Dt float32 `display:"-" json:"-" xml:"-"`
+}
+
+func (ei *ExpInteg) Defaults() {
+	ei.Tau = 4
+	ei.Update()
+}
+
+// Update must be called after any changes to parameters
+func (ei *ExpInteg) Update() {
+	ei.Dt = 1 / ei.Tau
+}
+
+// Init resets the integration state, so the next input is
+// used directly as the initial state.
+func (ei *ExpInteg) Init() {
+	ei.N = 0
+}
+
+// Integ integrates given input into State, and copies the resulting
+// State into out if it is non-nil.  The first input after Init,
+// or any change in shape, initializes the State to the input.
+func (ei *ExpInteg) Integ(in, out *tensor.Float32) {
+	if ei.Dt == 0 {
+		ei.Update()
+	}
+	if ei.N == 0 || slices.Compare(in.Shape().Sizes, ei.State.Shape().Sizes) != 0 {
+		tensor.SetShapeFrom(&ei.State, in)
+		copy(ei.State.Values, in.Values)
+		ei.N = 0
+	} else {
+		for i, v := range in.Values {
+			ei.State.Values[i] += ei.Dt * (v - ei.State.Values[i])
+		}
+	}
+	ei.N++
+	if out != nil {
+		tensor.SetShapeFrom(out, in)
+		copy(out.Values, ei.State.Values)
+	}
+}
+
+// Boxcar does boxcar (moving window average) temporal integration
+// over a stream of same-shaped tensors, e.g., successive outputs of
+// any filtering stage over the frames of a video, maintaining a
+// buffer of the most recent Window inputs across calls.
+type Boxcar struct {
+
+	// number of most recent inputs to average over -- values less than 1 are treated as 1
+	Window int `default:"4"`
+
+	// buffer of recent inputs, with outer dimension as Window
+	Buf tensor.Float32 `display:"no-inline"`
+
+	// running sum over the inputs in Buf
+	Sum tensor.Float32 `display:"no-inline"`
+
+	// number of inputs in the buffer, up to Window
+	N int `edit:"-"`
+
+	// index of the next buffer row to write into
+	Idx int `edit:"-"`
+}
+
+func (bc *Boxcar) Defaults() {
+	bc.Window = 4
+}
+
+// Init resets the integration state, clearing the buffer
+func (bc *Boxcar) Init() {
+	bc.N = 0
+	bc.Idx = 0
+	bc.Sum.SetZeros()
+}
+
+// Integ adds given input into the buffer, replacing the oldest one
+// if the buffer is full, and computes the average over the buffer
+// into out.  Any change in shape re-initializes the buffer.
+func (bc *Boxcar) Integ(in, out *tensor.Float32) {
+	sz := in.Len()
+	win := max(bc.Window, 1)
+	if slices.Compare(in.Shape().Sizes, bc.Sum.Shape().Sizes) != 0 || bc.Buf.DimSize(0) != win {
+		tensor.SetShapeFrom(&bc.Sum, in)
+		bc.Buf.SetShapeSizes(append([]int{win}, in.Shape().Sizes...)...)
+		bc.Init()
+	}
+	row := bc.Buf.Values[bc.Idx*sz : (bc.Idx+1)*sz]
+	full := bc.N == win
+	for i, v := range in.Values {
+		if full {
+			bc.Sum.Values[i] -= row[i]
+		}
+		bc.Sum.Values[i] += v
+		row[i] = v
+	}
+	if !full {
+		bc.N++
+	}
+	bc.Idx = (bc.Idx + 1) % win
+	tensor.SetShapeFrom(out, in)
+	norm := 1 / float32(bc.N)
+	for i, v := range bc.Sum.Values {
+		out.Values[i] = v * norm
+	}
+}
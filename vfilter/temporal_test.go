@@ -0,0 +1,43 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"testing"
+
+	"cogentcore.org/core/tensor"
+)
+
+func TestBoxcar(t *testing.T) {
+	in := tensor.NewFloat32(2, 3)
+	var out tensor.Float32
+	var bc Boxcar
+	bc.Defaults()
+	bc.Window = 2
+	for i, want := range []float32{1, 1.5, 2.5, 3.5} {
+		for j := range in.Values {
+			in.Values[j] = float32(i + 1)
+		}
+		bc.Integ(in, &out)
+		if out.Values[0] != want {
+			t.Errorf("step %d: %g != %g", i, out.Values[0], want)
+		}
+	}
+
+	// zero value, without Defaults: Window is treated as 1
+	var zbc Boxcar
+	for i := range 3 {
+		for j := range in.Values {
+			in.Values[j] = float32(i + 1)
+		}
+		zbc.Integ(in, &out)
+		if out.Values[0] != float32(i+1) {
+			t.Errorf("zero Window step %d: %g != %d", i, out.Values[0], i+1)
+		}
+	}
+	if zbc.Buf.DimSize(0) != 1 {
+		t.Errorf("zero Window buffer shape: %v", zbc.Buf.ShapeSizes())
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import "github.com/emer/vision/v2/nproc"
+
+// SetNumThreads sets the total number of threads used for all of the
+// parallel filtering operations, which run on a shared worker pool:
+// 1 runs everything in the calling goroutine, with no extra goroutines,
+// and a value <= 0 resets to the default number of CPUs.
+// See nproc.SetNumThreads.
+func SetNumThreads(n int) {
+	nproc.SetNumThreads(n)
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"fmt"
+	"image"
+
+	"cogentcore.org/core/tensor"
+)
+
+// ImageSource provides the values of a large grey-scale image for
+// ConvTiled, one region at a time, so that the whole image does not
+// need to be in memory at once (e.g., reading from a tiled image file).
+type ImageSource interface {
+
+	// ImageSize returns the size of the image.
+	ImageSize() image.Point
+
+	// ImageRegion sets the values of given tile, which has the size
+	// of given region ([Y, X]), from that region of the image,
+	// which is always within the bounds of the image.
+	ImageRegion(r image.Rectangle, tile *tensor.Float32)
+}
+
+// TensorSource is an ImageSource for a grey-scale image tensor
+// without any padding: [Y, X].
+type TensorSource struct {
+	Tensor *tensor.Float32
+}
+
+func (ts *TensorSource) ImageSize() image.Point {
+	return image.Point{ts.Tensor.DimSize(1), ts.Tensor.DimSize(0)}
+}
+
+func (ts *TensorSource) ImageRegion(r image.Rectangle, tile *tensor.Float32) {
+	sx := ts.Tensor.DimSize(1)
+	w := r.Dx()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		copy(tile.Values[(y-r.Min.Y)*w:(y-r.Min.Y+1)*w], ts.Tensor.Values[y*sx+r.Min.X:])
+	}
+}
+
+// TileFunc is called by ConvTiled with the output of each tile,
+// where r is the region of output positions of the tile, and tile
+// has the outputs for that region: [Y, X, Polarity (2), Filter].
+// The tile tensor is reused, and is only valid during the call.
+type TileFunc func(r image.Rectangle, tile *tensor.Float32)
+
+// ConvTiled performs convolution of filter over a large grey-scale
+// image without any padding, provided by src, in tiles of given size
+// (in output positions), with the same results as ConvSafe, but
+// without a padded copy of the full image: only the padded region of
+// each tile (including the halo of image values around it needed by
+// the filters) is in memory at a time.  The padding at the image edges
+// is filled according to given mode, which must be Wrap, Reflect,
+// Clamp, or Zero, as Fade and Noise depend on the entire image edge.
+// The output of each tile is written into out, if non-nil, which is
+// the full output as from Conv: [Y, X, Polarity (2), Filter], and is
+// passed to fun, if non-nil, which allows the output to be streamed
+// (e.g., pooled or written to a file) without materializing it.
+// The geom is updated as in ConvSafe, for the full output.
+// Returns an error for invalid shapes or parameters.
+func ConvTiled(geom *Geom, flt *tensor.Float32, src ImageSource, out *tensor.Float32, gain float32, mode EdgeModes, tileSize image.Point, fun TileFunc) error {
+	if flt.NumDims() != 3 {
+		return fmt.Errorf("vfilter.ConvTiled: filter must be 3D [Filter, Y, X], has shape: %v", flt.ShapeSizes())
+	}
+	if geom.Spacing.X <= 0 || geom.Spacing.Y <= 0 {
+		return fmt.Errorf("vfilter.ConvTiled: Spacing must be positive: %v", geom.Spacing)
+	}
+	if tileSize.X <= 0 || tileSize.Y <= 0 {
+		return fmt.Errorf("vfilter.ConvTiled: tile size must be positive: %v", tileSize)
+	}
+	if mode == Fade || mode == Noise {
+		return fmt.Errorf("vfilter.ConvTiled: edge mode %v is not supported, as it depends on the entire image edge", mode)
+	}
+	isz := src.ImageSize()
+	if isz.X <= 0 || isz.Y <= 0 {
+		return fmt.Errorf("vfilter.ConvTiled: image size must be positive: %v", isz)
+	}
+	nf := flt.DimSize(0)
+	geom.FiltSz.X = flt.DimSize(2)
+	geom.FiltSz.Y = flt.DimSize(1)
+	geom.UpdtFilt()
+	pw := max(geom.Border.X, geom.Border.Y)
+	geom.Border.X = pw
+	geom.Border.Y = pw
+	geom.Pad = mode
+	geom.SetSize(isz.Add(image.Point{2 * pw, 2 * pw}))
+	if out != nil {
+		out.SetShapeSizes(geom.Out.Y, geom.Out.X, 2, nf)
+	}
+	spc := geom.Spacing
+	tgeom := *geom
+	var tout tensor.Float32
+	for ty := 0; ty < geom.Out.Y; ty += tileSize.Y {
+		ny := min(tileSize.Y, geom.Out.Y-ty)
+		// image rows of the padded tile, relative to the image
+		iy := tileIndexes(ty*spc.Y-pw, ny*spc.Y+2*pw, isz.Y, mode)
+		for tx := 0; tx < geom.Out.X; tx += tileSize.X {
+			nx := min(tileSize.X, geom.Out.X-tx)
+			ix := tileIndexes(tx*spc.X-pw, nx*spc.X+2*pw, isz.X, mode)
+			pimg := Pool.Get(len(iy), len(ix))
+			tileImage(src, iy, ix, pimg)
+			Conv(&tgeom, flt, pimg, &tout, gain)
+			Pool.Put(pimg)
+			r := image.Rect(tx, ty, tx+nx, ty+ny)
+			if out != nil {
+				rw := nx * 2 * nf
+				for y := range ny {
+					oi := ((ty+y)*geom.Out.X + tx) * 2 * nf
+					copy(out.Values[oi:oi+rw], tout.Values[y*rw:(y+1)*rw])
+				}
+			}
+			if fun != nil {
+				fun(r, &tout)
+			}
+		}
+	}
+	return nil
+}
+
+// tileIndexes returns the image indexes for n padded positions starting
+// at given position (which can be outside the image) along an image
+// axis of size sz, according to given edge mode, with -1 for positions
+// that are zero.
+func tileIndexes(st, n, sz int, mode EdgeModes) []int {
+	idx := make([]int, n)
+	for i := range n {
+		p := st + i
+		if p >= 0 && p < sz {
+			idx[i] = p
+			continue
+		}
+		switch mode {
+		case Wrap:
+			idx[i] = ((p % sz) + sz) % sz
+		case Reflect:
+			idx[i] = ReflectIndex(p, sz)
+		case Clamp:
+			idx[i] = min(max(p, 0), sz-1)
+		default:
+			idx[i] = -1
+		}
+	}
+	return idx
+}
+
+// indexRun is a run of contiguous image indexes, for tileImage
+type indexRun struct {
+
+	// starting position and number of positions in the run
+	st, n int
+
+	// lowest and highest image index in the run
+	lo, hi int
+
+	// direction of the image indexes: +1 or -1, or 0 if not yet known
+	dir int
+}
+
+// indexRuns returns the runs of contiguous image indexes in idx,
+// which are increasing or decreasing by at most 1 at each position
+// (including repeated edge indexes for Clamp), skipping -1 (zero) indexes.
+func indexRuns(idx []int) []indexRun {
+	var runs []indexRun
+	for i, v := range idx {
+		if v < 0 {
+			continue
+		}
+		if len(runs) > 0 {
+			rn := &runs[len(runs)-1]
+			d := v - idx[i-1]
+			if rn.st+rn.n == i && d >= -1 && d <= 1 && (d == 0 || rn.dir == 0 || d == rn.dir) {
+				if d != 0 {
+					rn.dir = d
+				}
+				rn.n++
+				rn.lo = min(rn.lo, v)
+				rn.hi = max(rn.hi, v)
+				continue
+			}
+		}
+		runs = append(runs, indexRun{st: i, n: 1, lo: v, hi: v})
+	}
+	return runs
+}
+
+// tileImage fills the padded tile image pimg with the values of src
+// at given image row and column indexes, getting each contiguous
+// region of the image from src in one call.
+func tileImage(src ImageSource, iy, ix []int, pimg *tensor.Float32) {
+	pimg.SetZeros()
+	psx := len(ix)
+	yruns := indexRuns(iy)
+	xruns := indexRuns(ix)
+	for _, yr := range yruns {
+		for _, xr := range xruns {
+			bsx := xr.hi - xr.lo + 1
+			buf := Pool.Get(yr.hi-yr.lo+1, bsx)
+			src.ImageRegion(image.Rect(xr.lo, yr.lo, xr.hi+1, yr.hi+1), buf)
+			for y := yr.st; y < yr.st+yr.n; y++ {
+				bi := (iy[y] - yr.lo) * bsx
+				for x := xr.st; x < xr.st+xr.n; x++ {
+					pimg.Values[y*psx+x] = buf.Values[bi+ix[x]-xr.lo]
+				}
+			}
+			Pool.Put(buf)
+		}
+	}
+}
@@ -6,4 +6,68 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Geom", IDName: "geom", Doc: "Geom contains the filtering geometry info for a given filter pass.", Fields: []types.Field{{Name: "In", Doc: "size of input -- computed from image or set"}, {Name: "Out", Doc: "size of output -- computed"}, {Name: "Border", Doc: "starting border into image -- must be >= FiltRt"}, {Name: "Spacing", Doc: "spacing -- number of pixels to skip in each direction"}, {Name: "FiltSz", Doc: "full size of filter"}, {Name: "FiltLt", Doc: "computed size of left/top size of filter"}, {Name: "FiltRt", Doc: "computed size of right/bottom size of filter (FiltSz - FiltLeft)"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Alpha", IDName: "alpha", Doc: "Alpha specifies how the alpha (transparency) channel of an image\nis handled in converting it to a tensor, by RGBToTensorAlpha and\nRGBToGreyAlpha.  Without compositing, the color values are used\nregardless of alpha, so that transparent pixels typically have\ntheir stored color of black, which produces spurious filter\nresponses at the edges of sprites with transparency.", Fields: []types.Field{{Name: "Composite", Doc: "composite the image over the Background color, so that transparent pixels have the background color, and partially transparent ones are blended with it"}, {Name: "Background", Doc: "background color to composite the image over"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.AngPools", IDName: "ang-pools", Doc: "AngPools are the ways of pooling over neighboring angles in AngPool"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.AngPool", IDName: "ang-pool", Doc: "AngPool performs pooling over neighboring angles (orientations)\nat each position, complementing the spatial pooling of MaxPool,\nproducing outputs with a broader orientation bandwidth, like complex\ncells, without spatial downsampling.  Angles are circular over 180\ndegrees, so the neighbors of the last angle include the first angle:\nas the edge direction is reversed across this wrap-around, the\nopposite polarity is used for inputs with 2 polarities.", Fields: []types.Field{{Name: "Pool", Doc: "type of pooling over angles"}, {Name: "Width", Doc: "number of neighboring angles on either side of each angle to pool over -- limited to less than half the number of angles, so each angle is included only once"}, {Name: "Sigma", Doc: "for AngAvg, standard deviation of the gaussian weighting, in angle steps"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Float", IDName: "float", Doc: "Float is the constraint for the tensor value types supported by the\ngeneric versions of the filtering functions (e.g., ConvT, MaxPoolT),\nfor use with tensor.Float64 as well as tensor.Float32, which use\nthe same filtering code, with no conversion."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ConvStatTypes", IDName: "conv-stat-types", Doc: "ConvStatTypes are the per-filter statistics accumulated by ConvStats"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ConvStats", IDName: "conv-stats", Doc: "ConvStats accumulates per-filter response statistics over Conv\npasses, computed during the pass itself, so that the gain balance\nacross filters and scales can be monitored over a dataset without\na second sweep over the outputs.  Use the ConvStats Conv method in\nplace of Conv to accumulate.  It is safe for concurrent use.", Fields: []types.Field{{Name: "N", Doc: "number of Conv passes accumulated since Init"}, {Name: "Stats", Doc: "per-filter statistics over all output positions of all passes since Init: [Filter, Polarity (on, off), ConvStatTypes]"}, {Name: "sum", Doc: "accumulated sums per filter and polarity"}, {Name: "max", Doc: "accumulated max per filter and polarity"}, {Name: "nonZero", Doc: "accumulated count of non-zero responses per filter and polarity"}, {Name: "count", Doc: "accumulated count of output positions per filter"}, {Name: "mu", Doc: "mutex for merging per-thread accumulators"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.convAcc", IDName: "conv-acc", Doc: "convAcc is a per-thread accumulator of ConvStats,\nindexed by filter and polarity.", Fields: []types.Field{{Name: "sum"}, {Name: "max"}, {Name: "nonZero"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.OutputLayouts", IDName: "output-layouts", Doc: "OutputLayouts are the layouts of the on / off polarity outputs\nof Conv1 and ConvDiff."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Geom", IDName: "geom", Doc: "Geom contains the filtering geometry info for a given filter pass.", Fields: []types.Field{{Name: "In", Doc: "size of input -- computed from image or set"}, {Name: "Out", Doc: "size of output -- computed"}, {Name: "Border", Doc: "starting border into image -- must be >= FiltRt"}, {Name: "Spacing", Doc: "spacing -- number of pixels to skip in each direction"}, {Name: "FiltSz", Doc: "full size of filter"}, {Name: "Dilation", Doc: "dilation (atrous) factor: spacing between the filter taps in the input, so that the filter covers (FiltSz-1)*Dilation+1 pixels, for larger receptive fields without larger filters -- 0 or 1 is no dilation"}, {Name: "FiltLt", Doc: "computed size of left/top size of the (dilated) filter extent"}, {Name: "FiltRt", Doc: "computed size of right/bottom size of the (dilated) filter extent (FiltExt - FiltLeft)"}, {Name: "Pad", Doc: "how the border of the input was padded (see Pad) -- set by the caller for reference, and not used by Conv"}, {Name: "OutLayout", Doc: "layout of the on / off polarities in the output of Conv1 and ConvDiff: OnOffOuter [2, Y, X] or PolarityInner [Y, X, 1, 2], which has the 4D pool structure of kwta.KWTAPool"}, {Name: "clamped", Doc: "whether UpdtFilt has increased a non-zero Border to FiltRt since the last Set, reported by Validate"}, {Name: "preClamp", Doc: "the Border prior to being increased by UpdtFilt"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.GeomStage", IDName: "geom-stage", Doc: "GeomStage is one filtering or pooling stage of a GeomSet", Fields: []types.Field{{Name: "Name", Doc: "name of the stage, e.g., V1s"}, {Name: "Geom", Doc: "geometry of a filtering stage (if not Pool), where the input is the output of the prior stage (or the input image for the first stage), padded by Border on all sides"}, {Name: "Pool", Doc: "whether this is a pooling stage, as in MaxPool, with PoolSize and PoolSpacing"}, {Name: "PoolSize", Doc: "size of the pools, for a pooling stage"}, {Name: "PoolSpacing", Doc: "spacing of the pools, for a pooling stage"}, {Name: "Out", Doc: "size of the output of this stage, computed by SetSize"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.GeomSet", IDName: "geom-set", Doc: "GeomSet chains the geometry of a sequence of filtering and pooling\nstages, e.g., V1s filtering, followed by pooling, followed by V1c\nfiltering, and computes the cumulative mapping between the units of\neach stage and the pixels of the input image, e.g., for visualizing\nreceptive fields, and routing attention.  Each filtering stage is\nassumed to pad its input (the output of the prior stage, or the\ninput image) by its Border on all sides, as in ConvSafe and Pad,\nso that pixel coordinates are those of the unpadded input image.\nReceptive fields are the bounding rectangles of all the input pixels\nthat feed a unit, including any gaps from Dilation, and can extend\nbeyond the input image into the padding.", Fields: []types.Field{{Name: "Stages", Doc: "stages, in order from the input image"}, {Name: "In", Doc: "size of the (unpadded) input image, set by SetSize"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ReduceFunc", IDName: "reduce-func", Doc: "ReduceFunc reduces the given values to a single value, for FeatReduce."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.HOG", IDName: "hog", Doc: "HOG specifies histogram of oriented gradients (HOG) style pooling\nof oriented filter outputs (e.g., V1 simple cells), producing compact\nregion descriptors comparable with the classic computer vision\nfeatures: the orientation energy is summed over polarities and over\nthe positions within each cell, into a histogram over angles, and the\nhistograms of overlapping blocks of cells are normalized together,\nusing L2 normalization, clipping, and renormalization (L2-Hys).", Fields: []types.Field{{Name: "CellSize", Doc: "size of each cell, in input positions along each dimension"}, {Name: "BlockSize", Doc: "size of each block, in cells along each dimension -- blocks are spaced 1 cell apart, and overlap if > 1"}, {Name: "Clip", Doc: "maximum value of the normalized histogram values, after which they are renormalized -- 0 = no clipping (plain L2 normalization)"}, {Name: "Eps", Doc: "small value added to the norms, to avoid division by zero"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.FloatImage", IDName: "float-image", Doc: "FloatImage is an image with float32 channel values, for high dynamic\nrange (HDR) and scientific images, e.g., as decoded from floating\npoint TIFF or EXR files, which are neither quantized nor clamped,\nand can be converted to a tensor with FloatImageToTensor.\nAs an image.Image, the values are clamped to the 0-1 range,\nwith 1 channel as grey, and 3 or more as RGB (with alpha if 4).", Fields: []types.Field{{Name: "Pix", Doc: "channel values, with NChan values per pixel, in row-major order starting at Rect.Min"}, {Name: "Stride", Doc: "number of values between vertically adjacent pixels in Pix"}, {Name: "NChan", Doc: "number of channels per pixel: 1 for grey, 3 for RGB, etc"}, {Name: "Rect", Doc: "bounds of the image"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Layout", IDName: "layout", Doc: "Layout builds a combined V1All-style feature tensor, with shape\n[Y, X, Row, Angle], from named feature sources, each contributing\none or more rows, so that the row offsets do not need to be computed\nby hand.  Sources are added in order with Add or AddOuter, and Build\ncomputes the offsets, validates the shapes of the sources against\neach other, and aggregates them into the output.  The resulting\nrow layout is recorded in Entries, as metadata describing the output.", Fields: []types.Field{{Name: "Sources", Doc: "feature sources, in the order of their rows in the output"}, {Name: "Entries", Doc: "layout of the rows in the output, for each source, as computed by the last Build"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.LayoutSource", IDName: "layout-source", Doc: "LayoutSource is one feature source of a Layout", Fields: []types.Field{{Name: "Name", Doc: "name of the source, used as the prefix of its row names"}, {Name: "Src", Doc: "source tensor: either [Y, X, Row, Angle], or [Row, Y, X] if Outer, in which case each row is replicated across all angles"}, {Name: "Rows", Doc: "rows of the source to copy, in order -- nil = all rows"}, {Name: "RowNames", Doc: "names of each of the rows, appended to Name -- defaults to the source row index if not specified"}, {Name: "Outer", Doc: "whether the source has the rows as the outer-most dimension: [Row, Y, X], replicated across all angles, as for OuterAgg"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.LayoutEntry", IDName: "layout-entry", Doc: "LayoutEntry records the layout of the rows of one source in\nthe output of a Layout", Fields: []types.Field{{Name: "Name", Doc: "name of the source"}, {Name: "Start", Doc: "starting row of the source in the output"}, {Name: "N", Doc: "number of rows of the source in the output"}, {Name: "Rows", Doc: "full names of each of the rows, as Name + RowNames"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.LocalContrastNorm", IDName: "local-contrast-norm", Doc: "LocalContrastNorm is local contrast normalization, which divides each\nvalue by the gaussian-weighted standard deviation of the values in its\nneighborhood, after subtracting the local mean (if SubMean), so that\nthe contrast is equalized across regions of an image with different\nlighting, as in the retina and LGN.  This is applied separately to\neach channel of an image, or to each feature map of filter outputs.\nThe neighborhood is truncated at the edges of the tensor, with the\nweights renormalized, so any padding is treated as image content.", Fields: []types.Field{{Name: "Sigma", Doc: "standard deviation of the gaussian neighborhood, in pixels (positions) -- the neighborhood extends to 3 * Sigma"}, {Name: "SubMean", Doc: "subtract the local mean before dividing by the local standard deviation -- otherwise only divisive normalization is done"}, {Name: "Floor", Doc: "minimum local standard deviation that values are divided by, so that noise in nearly uniform regions is not amplified"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.LogPolarGeom", IDName: "log-polar-geom", Doc: "LogPolarGeom specifies the geometry of a log-polar (foveated)\nsampling of an image around a fixation point, where the radius of\nthe rings increases exponentially with eccentricity, so that the\nresolution is highest at the fixation point, as in the retinotopic\nmap of V1.  Each ring has the same number of wedges (angles), so that\nrotation and scaling around the fixation point become translations\nin the log-polar representation.", Fields: []types.Field{{Name: "Fix", Doc: "fixation point, as a proportion of the X, Y size of the image (0.5, 0.5 = center)"}, {Name: "Rings", Doc: "number of rings (eccentricities), from MinRadius to MaxRadius"}, {Name: "Wedges", Doc: "number of wedges (angles) around each ring, starting at the positive X axis"}, {Name: "MinRadius", Doc: "radius of the innermost ring, in pixels -- the fovea within this radius is filled with the innermost ring in LogPolarInverse"}, {Name: "MaxRadius", Doc: "radius of the outermost ring, in pixels -- 0 = half the smaller of the X, Y size of the image"}, {Name: "ImgSize", Doc: "size of the image, set by LogPolar, and used by LogPolarInverse to reconstruct an image of the same size"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.EdgeModes", IDName: "edge-modes", Doc: "EdgeModes are the ways of filling the padding border around an image,\nfor Pad."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.NoiseFill", IDName: "noise-fill", Doc: "NoiseFill specifies the distribution of the noise that NoisePadRand\nfills the padding with, which is sampled from the values around the\neffective edge of the image, so that the padding matches the image\nstatistics without the systematic structure of wrapping or fading,\nwhich shows up in pooled filter statistics.", Fields: []types.Field{{Name: "Hist", Doc: "sample from the histogram of the edge values, instead of a gaussian with their mean and standard deviation, so that skewed or multimodal edge distributions (e.g., sky and ground) are matched"}, {Name: "NBins", Doc: "number of bins in the histogram of the edge values"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.TensorPool", IDName: "tensor-pool", Doc: "TensorPool is a pool of tensors for the intermediate outputs that\nare created for each frame of a processing pipeline, which all have\nthe same shapes from one frame to the next.  Tensors are acquired\nwith Get and must be explicitly returned with Put when no longer\nused, after which Get returns the same memory, so that steady-state\nprocessing does no allocation.  Free tensors are kept by number\nof values, so any shape with the same number of values can be reused.\nIt is safe for concurrent use.  The zero value is ready to use.", Fields: []types.Field{{Name: "free", Doc: "free tensors, by number of values"}, {Name: "nalloc", Doc: "number of tensors allocated by the pool"}, {Name: "mu", Doc: "mutex for concurrent access"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.PoolOps", IDName: "pool-ops", Doc: "PoolOps are the pooling operations, for PoolPadded, GlobalPool\nand FeatPool"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.PyramidModes", IDName: "pyramid-modes", Doc: "PyramidModes are the ways of reducing each level of a Pyramid\nto half the size of the previous level"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Pyramid", IDName: "pyramid", Doc: "Pyramid computes the outputs of a filter bank at multiple spatial\nscales, by filtering each level of an image pyramid, where each level\nis half the size of the previous one, with the same filters and\ngeometry, so that each level responds to half the spatial frequency\nof the previous one (i.e., an octave lower).  The outputs of each\nlevel are in Outs, and aggregated into All at the resolution\nof the first level.", Fields: []types.Field{{Name: "Levels", Doc: "number of levels, including the original image as the first level"}, {Name: "Mode", Doc: "how each level of the image pyramid is reduced from the previous one"}, {Name: "Pad", Doc: "how the border of each level is padded for filtering (see ConvSafe)"}, {Name: "Gain", Doc: "gain applied to the filter outputs at each level"}, {Name: "Geom", Doc: "geometry for filtering each level: the Border and Spacing are used for each level, and the rest is computed"}, {Name: "Images", Doc: "images for each level of the pyramid: [Y, X], with the first being a copy of the input image"}, {Name: "Geoms", Doc: "filtering geometry for each level, as computed by ConvSafe"}, {Name: "Outs", Doc: "filter outputs for each level, as from Conv: [Y, X, Polarity (2), Filter]"}, {Name: "All", Doc: "filter outputs of all levels at the resolution of the first level, with each coarser level replicated over the corresponding positions, and the polarities of each level as the rows: [Y, X, Level * 2 + Polarity, Filter], as in the V1All feature tensors"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ResizeModes", IDName: "resize-modes", Doc: "ResizeModes are the interpolation kernels for ResizeTensor"})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.resizeWeights", IDName: "resize-weights", Doc: "resizeWeights are the input indexes and weights for each output\nposition along one axis, with n taps per output.", Fields: []types.Field{{Name: "n"}, {Name: "idx"}, {Name: "wts"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ExpInteg", IDName: "exp-integ", Doc: "ExpInteg does exponential temporal integration (low-pass filtering)\nover a stream of same-shaped tensors, e.g., successive outputs of\nany filtering stage over the frames of a video, maintaining the\nintegrated State across calls.", Fields: []types.Field{{Name: "Tau", Doc: "time constant in frames for integration -- 1 = no integration"}, {Name: "State", Doc: "integrated state, same shape as inputs"}, {Name: "N", Doc: "number of inputs integrated since Init"}, {Name: "Dt", Doc: "rate = 1 / tau"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.Boxcar", IDName: "boxcar", Doc: "Boxcar does boxcar (moving window average) temporal integration\nover a stream of same-shaped tensors, e.g., successive outputs of\nany filtering stage over the frames of a video, maintaining a\nbuffer of the most recent Window inputs across calls.", Fields: []types.Field{{Name: "Window", Doc: "number of most recent inputs to average over -- values less than 1 are treated as 1"}, {Name: "Buf", Doc: "buffer of recent inputs, with outer dimension as Window"}, {Name: "Sum", Doc: "running sum over the inputs in Buf"}, {Name: "N", Doc: "number of inputs in the buffer, up to Window"}, {Name: "Idx", Doc: "index of the next buffer row to write into"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.ImageSource", IDName: "image-source", Doc: "ImageSource provides the values of a large grey-scale image for\nConvTiled, one region at a time, so that the whole image does not\nneed to be in memory at once (e.g., reading from a tiled image file).", Methods: []types.Method{{Name: "ImageSize", Doc: "ImageSize returns the size of the image.", Returns: []string{"Point"}}, {Name: "ImageRegion", Doc: "ImageRegion sets the values of given tile, which has the size\nof given region ([Y, X]), from that region of the image,\nwhich is always within the bounds of the image.", Args: []string{"r", "tile"}}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.TensorSource", IDName: "tensor-source", Doc: "TensorSource is an ImageSource for a grey-scale image tensor\nwithout any padding: [Y, X].", Fields: []types.Field{{Name: "Tensor"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.TileFunc", IDName: "tile-func", Doc: "TileFunc is called by ConvTiled with the output of each tile,\nwhere r is the region of output positions of the tile, and tile\nhas the outputs for that region: [Y, X, Polarity (2), Filter].\nThe tile tensor is reused, and is only valid during the call."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vfilter.indexRun", IDName: "index-run", Doc: "indexRun is a run of contiguous image indexes, for tileImage", Fields: []types.Field{{Name: "st", Doc: "starting position and number of positions in the run"}, {Name: "n", Doc: "starting position and number of positions in the run"}, {Name: "lo", Doc: "lowest and highest image index in the run"}, {Name: "hi", Doc: "lowest and highest image index in the run"}, {Name: "dir", Doc: "direction of the image indexes: +1 or -1, or 0 if not yet known"}}})
@@ -7,7 +7,6 @@ package vfilter
 import (
 	"image"
 	"math/rand"
-	"sync"
 
 	"cogentcore.org/core/tensor"
 	"github.com/emer/vision/v2/nproc"
@@ -18,43 +17,25 @@ import (
 // just copies the max pooled value over all of the
 // individual elements that were pooled.  A smarter solution would require
 // maintaining the index of the max item, but that requires more infrastructure
-// size must = spacing or 2 * spacing.
+// Any combination of pool size and spacing can be used (see PoolOut):
+// where pools overlap, the max of the pooled values is used, and
+// elements not in any pool are set to 0.
 // Pooling is sensitive to the feature structure of the input, which
 // must have shape: Y, X, Polarities, Angles.
 func UnPool(psize, spc image.Point, in, out *tensor.Float32, rnd bool) {
-	ny := in.DimSize(0)
-	nx := in.DimSize(1)
 	pol := in.DimSize(2)
 	nang := in.DimSize(3)
-	oy := ny / int(spc.Y)
-	ox := nx / int(spc.X)
-	if spc.Y != psize.Y {
-		oy--
-	}
-	if spc.X != psize.X {
-		ox--
-	}
-
-	out.SetShapeSizes(oy, ox, pol, nang)
+	osz := PoolOut(psize, spc, image.Point{in.DimSize(1), in.DimSize(0)})
+	out.SetShapeSizes(osz.Y, osz.X, pol, nang)
+	in.SetZeros()
 	nf := pol * nang
-	ncpu := nproc.NumCPU()
-	nthrs, nper, rmdr := nproc.ThreadNs(ncpu, nf)
-	var wg sync.WaitGroup
-	for th := 0; th < nthrs; th++ {
-		wg.Add(1)
-		f := th * nper
-		go unPoolThr(&wg, f, nper, psize, spc, in, out, rnd)
-	}
-	if rmdr > 0 {
-		wg.Add(1)
-		f := nthrs * nper
-		go unPoolThr(&wg, f, rmdr, psize, spc, in, out, rnd)
-	}
-	wg.Wait()
+	nproc.Run("UnPool", nf, func(st, n int) {
+		unPoolThr(st, n, psize, spc, in, out, rnd)
+	})
 }
 
 // unPoolThr is per-thread implementation
-func unPoolThr(wg *sync.WaitGroup, fno, nf int, psize, spc image.Point, in, out *tensor.Float32, rnd bool) {
+func unPoolThr(fno, nf int, psize, spc image.Point, in, out *tensor.Float32, rnd bool) {
 	ny := out.DimSize(0)
 	nx := out.DimSize(1)
 	nang := out.DimSize(3)
@@ -70,26 +51,16 @@ func unPoolThr(wg *sync.WaitGroup, fno, nf int, psize, spc image.Point, in, out
 				mx := out.Value(y, x, pol, ang)
 				if rnd {
 					ptrg := rand.Intn(psz)
-					pdx := 0
-					for py := 0; py < psize.Y; py++ {
-						for px := 0; px < psize.X; px++ {
-							if pdx == ptrg {
-								in.Set(mx, iy+py, ix+px, pol, ang)
-							} else {
-								in.Set(0, iy+py, ix+px, pol, ang)
-							}
-							pdx++
-						}
-					}
+					py, px := iy+ptrg/psize.X, ix+ptrg%psize.X
+					in.Set(max(mx, in.Value(py, px, pol, ang)), py, px, pol, ang)
 				} else {
 					for py := 0; py < psize.Y; py++ {
 						for px := 0; px < psize.X; px++ {
-							in.Set(mx, iy+py, ix+px, pol, ang)
+							in.Set(max(mx, in.Value(iy+py, ix+px, pol, ang)), iy+py, ix+px, pol, ang)
 						}
 					}
 				}
 			}
 		}
 	}
-	wg.Done()
 }
@@ -0,0 +1,52 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"slices"
+
+	"cogentcore.org/core/tensor"
+)
+
+// Channel returns a view of given channel (index along the outer-most
+// dimension) of given tensor, e.g., one color component of an
+// [RGB, Y, X] image, with the remaining inner dimensions as its shape.
+// The view directly shares the values of the source tensor, with no
+// copying, so writes to either are visible in both.  The view is only
+// valid as long as the source tensor is not reshaped.
+func Channel(tsr *tensor.Float32, ch int) *tensor.Float32 {
+	view := &tensor.Float32{}
+	ChannelView(tsr, ch, view)
+	return view
+}
+
+// ChannelView sets given view tensor to be a view of given channel
+// (index along the outer-most dimension) of given tensor, as in Channel.
+// Any existing values of the view are not used, and the view shape is
+// only updated if it differs, so the view can be stored and reused
+// with no allocation on each frame.
+func ChannelView(tsr *tensor.Float32, ch int, view *tensor.Float32) {
+	sizes := tsr.Shape().Sizes[1:]
+	n := 1
+	for _, s := range sizes {
+		n *= s
+	}
+	st := ch * n
+	view.Values = tsr.Values[st : st+n : st+n]
+	if !slices.Equal(view.Shape().Sizes, sizes) {
+		view.SetShapeSizes(sizes...)
+	}
+}
+
+// Channels returns views of each of the channels (outer-most
+// dimension) of given tensor, as in Channel.
+func Channels(tsr *tensor.Float32) []*tensor.Float32 {
+	nc := tsr.DimSize(0)
+	views := make([]*tensor.Float32, nc)
+	for i := range views {
+		views[i] = Channel(tsr, i)
+	}
+	return views
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vfilter
+
+import (
+	"testing"
+
+	"cogentcore.org/core/tensor"
+)
+
+func TestChannelView(t *testing.T) {
+	tsr := &tensor.Float32{}
+	tsr.SetShapeSizes(3, 4, 5)
+	for i := range tsr.Values {
+		tsr.Values[i] = float32(i)
+	}
+	for ch, view := range Channels(tsr) {
+		if view.DimSize(0) != 4 || view.DimSize(1) != 5 {
+			t.Errorf("channel %d shape: %v", ch, view.Shape().Sizes)
+		}
+		if &view.Values[0] != &tsr.Values[ch*20] {
+			t.Errorf("channel %d is not a view of the source values", ch)
+		}
+		if v := view.Value(2, 3); v != tsr.Value(ch, 2, 3) {
+			t.Errorf("channel %d value: %g != %g", ch, v, tsr.Value(ch, 2, 3))
+		}
+		view.Set(-1, 1, 1)
+		if tsr.Value(ch, 1, 1) != -1 {
+			t.Errorf("channel %d write not visible in source", ch)
+		}
+	}
+
+	// reused view does not allocate, and cannot append past its channel
+	var view tensor.Float32
+	ChannelView(tsr, 1, &view)
+	allocs := testing.AllocsPerRun(10, func() {
+		ChannelView(tsr, 2, &view)
+	})
+	if allocs > 0 {
+		t.Errorf("ChannelView allocated %g times per run", allocs)
+	}
+	if cap(view.Values) != 20 {
+		t.Errorf("view capacity: %d != 20", cap(view.Values))
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vxform
+
+import (
+	"fmt"
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+)
+
+// ColorXForm represents current and previous color transformation values
+// and can apply current values to an RGB tensor, as produced by
+// vfilter.RGBToTensor, with shape [3, Y, X] and values in 0..1.
+// Transformations are performed as: hue rotation, saturation scaling,
+// then per-channel gain, with results clipped to 0..1.
+// Hue rotation and saturation scaling are computed relative to the
+// grey (R = G = B) axis, so they preserve the mean of the channels
+// (prior to clipping), and black (e.g., padding) is left unchanged.
+type ColorXForm struct {
+
+	// current, prv hue rotation value, in degrees, as a rotation of RGB values around the grey axis
+	Hue env.CurPrev[float32]
+
+	// current, prv saturation scale value: 0 = greyscale, 1 = no change, > 1 = more saturated -- uses 1 if Sat.Cur is < 0
+	Sat env.CurPrev[float32]
+
+	// current, prv per-channel gain values, for R, G, B channels respectively
+	Gain [3]env.CurPrev[float32]
+}
+
+// Defaults sets values that leave colors unchanged
+func (cx *ColorXForm) Defaults() {
+	cx.Set(0, 1, 1, 1, 1)
+}
+
+// Set updates current values
+func (cx *ColorXForm) Set(hue, sat, gainR, gainG, gainB float32) {
+	cx.Hue.Set(hue)
+	cx.Sat.Set(sat)
+	cx.Gain[0].Set(gainR)
+	cx.Gain[1].Set(gainG)
+	cx.Gain[2].Set(gainB)
+}
+
+// Matrix returns the 3x3 matrix applied to RGB values for the
+// current hue rotation, saturation scaling, and per-channel gains,
+// as rows of the output R, G, B channels.
+func (cx *ColorXForm) Matrix() [3][3]float32 {
+	sat := cx.Sat.Cur
+	if sat < 0 {
+		sat = 1
+	}
+	// rotation around the unit grey axis (1,1,1)/sqrt(3), by Rodrigues' formula
+	th := math32.DegToRad(cx.Hue.Cur)
+	cs := math32.Cos(th)
+	sn := math32.Sin(th) / math32.Sqrt(3)
+	gr := (1 - cs) / 3
+	rot := [3][3]float32{
+		{cs + gr, gr - sn, gr + sn},
+		{gr + sn, cs + gr, gr - sn},
+		{gr - sn, gr + sn, cs + gr},
+	}
+	// saturation: lerp between grey (mean) and the rotated color
+	var mx [3][3]float32
+	for i := range 3 {
+		for j := range 3 {
+			var mean float32
+			for k := range 3 {
+				mean += rot[k][j]
+			}
+			mean /= 3
+			mx[i][j] = cx.Gain[i].Cur * (mean + sat*(rot[i][j]-mean))
+		}
+	}
+	return mx
+}
+
+// Tensor applies the current color transformation to given RGB tensor
+// [3, Y, X], in place.
+func (cx *ColorXForm) Tensor(rgb *tensor.Float32) {
+	mx := cx.Matrix()
+	n := rgb.DimSize(1) * rgb.DimSize(2)
+	rs := rgb.Values[:n]
+	gs := rgb.Values[n : 2*n]
+	bs := rgb.Values[2*n : 3*n]
+	for i := range n {
+		r, g, b := rs[i], gs[i], bs[i]
+		rs[i] = math32.Clamp(mx[0][0]*r+mx[0][1]*g+mx[0][2]*b, 0, 1)
+		gs[i] = math32.Clamp(mx[1][0]*r+mx[1][1]*g+mx[1][2]*b, 0, 1)
+		bs[i] = math32.Clamp(mx[2][0]*r+mx[2][1]*g+mx[2][2]*b, 0, 1)
+	}
+}
+
+func (cx *ColorXForm) String() string {
+	return fmt.Sprintf("Hue: %.4f, Sat: %.4f, Gain: %.4f, %.4f, %.4f", cx.Hue.Cur, cx.Sat.Cur, cx.Gain[0].Cur, cx.Gain[1].Cur, cx.Gain[2].Cur)
+}
+
+// ColorRand specifies random color transforms
+type ColorRand struct {
+
+	// min -- max range of hue rotations to generate (in degrees)
+	Hue minmax.F32
+
+	// min -- max range of saturation scales to generate, sampled uniformly in log space -- if Max is 0, no saturation change is applied (sat = 1) -- Min must be > 0 otherwise
+	Sat minmax.F32
+
+	// min -- max range of per-channel gains to generate, sampled independently for each channel, uniformly in log space -- if Max is 0, no gain change is applied (gain = 1) -- Min must be > 0 otherwise
+	Gain minmax.F32
+}
+
+// Gen generates new random color transform values, using given
+// random number source, which can be seeded for reproducible
+// augmentations -- if nil, the global math/rand source is used.
+func (cr *ColorRand) Gen(cx *ColorXForm, rnd *rand.Rand) {
+	rf := rand.Float32
+	if rnd != nil {
+		rf = rnd.Float32
+	}
+	hue := cr.Hue.ProjValue(rf())
+	sat := float32(1)
+	if cr.Sat.Max > 0 {
+		sat = LogUniform(cr.Sat, rf())
+	}
+	var gains [3]float32
+	for i := range gains {
+		gains[i] = 1
+		if cr.Gain.Max > 0 {
+			gains[i] = LogUniform(cr.Gain, rf())
+		}
+	}
+	cx.Set(hue, sat, gains[0], gains[1], gains[2])
+}
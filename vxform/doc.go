@@ -6,5 +6,17 @@
 Package vxform supports visual (image) transformations: translation, scaling, rotation.
 
 Includes parameters for specifying random range to generate.
+
+ColorXForm supports color augmentations of RGB tensors: hue rotation,
+saturation scaling, and per-channel gain, with ColorRand generating
+random values from a seedable source, for training and testing the
+color invariance of the color opponent pipeline.
+
+LightXForm multiplies image tensors by a directional luminance gradient
+and a radial vignette, with LightRand generating random values, for
+testing the invariance of the front end to illumination gradients.
+
+Shadow darkens a soft-edged polygonal region of image tensors, as a cast
+shadow, with ShadowRand generating random shadows.
 */
 package vxform
@@ -0,0 +1,94 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vxform
+
+import (
+	"image"
+	"math"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+// Matrix returns the affine transformation matrix that maps points in
+// an original image of size sz (pixel coordinates, Y=0 at the top) to
+// the corresponding points in the image produced by XFormImage with
+// the same parameters: rotation about the center, scale, then translation.
+// When scaling to a larger size, XFormImage returns an image that is
+// larger than the original, anchored at the upper-left.
+func Matrix(sz image.Point, trX, trY, sc, rot float32) math32.Matrix2 {
+	ctr := math32.Vec2(float32(sz.X/2), float32(sz.Y/2))
+	m := math32.Translate2D(ctr.X, ctr.Y).Mul(math32.Rotate2D(math32.DegToRad(rot))).Mul(math32.Translate2D(-ctr.X, -ctr.Y))
+	csz := sz
+	if sc != 1 && sc > 0 {
+		nsz := sz
+		nsz.X = int(math.Round(float64(nsz.X) * float64(sc)))
+		nsz.Y = int(math.Round(float64(nsz.Y) * float64(sc)))
+		scm := math32.Scale2D(float32(nsz.X)/float32(sz.X), float32(nsz.Y)/float32(sz.Y))
+		if sc < 1 {
+			psz := sz.Sub(nsz).Div(2)
+			scm = math32.Translate2D(float32(psz.X), float32(psz.Y)).Mul(scm)
+		} else {
+			csz = nsz
+		}
+		m = scm.Mul(m)
+	}
+	if trX != 0 || trY != 0 {
+		offX := int(math.Round(0.5 * float64(csz.X) * float64(trX)))
+		offY := int(math.Round(0.5 * float64(csz.Y) * float64(trY)))
+		m = math32.Translate2D(float32(offX), -float32(offY)).Mul(m)
+	}
+	return m
+}
+
+// Matrix returns the affine transformation matrix for the current
+// transform values, for an image of given size.  See Matrix function.
+func (xf *XForm) Matrix(sz image.Point) math32.Matrix2 {
+	return Matrix(sz, xf.TransX.Cur, xf.TransY.Cur, xf.Scale.Cur, xf.Rot.Cur)
+}
+
+// PrevMatrix returns the affine transformation matrix for the previous
+// transform values, for an image of given size.  See Matrix function.
+func (xf *XForm) PrevMatrix(sz image.Point) math32.Matrix2 {
+	return Matrix(sz, xf.TransX.Prev, xf.TransY.Prev, xf.Scale.Prev, xf.Rot.Prev)
+}
+
+// Flow computes the exact per-pixel optic flow field implied by going
+// from the prv to the cur transformation of an image of size sz
+// (as returned by Matrix): for each pixel in the prv transformed image,
+// the flow is the displacement in pixels to where that same image
+// content is located in the cur transformed image.
+// Output is a 2-channel tensor with outer dimension as X, Y displacement
+// components: [2, Y, X].
+// topZero retains the Y=0 value at the top of the tensor --
+// otherwise it is flipped with Y=0 at the bottom to be consistent
+// with the emergent / OpenGL standard coordinate system, and the
+// Y displacement is correspondingly positive for upward motion.
+func Flow(sz image.Point, prv, cur math32.Matrix2, flow *tensor.Float32, topZero bool) {
+	flow.SetShapeSizes(2, sz.Y, sz.X)
+	m := cur.Mul(prv.Inverse())
+	for y := 0; y < sz.Y; y++ {
+		ty := y
+		if !topZero {
+			ty = (sz.Y - 1) - y
+		}
+		for x := 0; x < sz.X; x++ {
+			p := math32.Vec2(float32(x), float32(y))
+			d := m.MulVector2AsPoint(p).Sub(p)
+			if !topZero {
+				d.Y = -d.Y
+			}
+			flow.Set(d.X, 0, ty, x)
+			flow.Set(d.Y, 1, ty, x)
+		}
+	}
+}
+
+// Flow computes the exact per-pixel optic flow field implied by going
+// from the previous to the current transform values, for an image of
+// given size.  See Flow function for details.
+func (xf *XForm) Flow(sz image.Point, flow *tensor.Float32, topZero bool) {
+	Flow(sz, xf.PrevMatrix(sz), xf.Matrix(sz), flow, topZero)
+}
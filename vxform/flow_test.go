@@ -0,0 +1,58 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vxform
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+// dotCentroid returns the centroid of bright pixels in image
+func dotCentroid(img *image.RGBA) math32.Vector2 {
+	var sum math32.Vector2
+	var n float32
+	bd := img.Bounds()
+	for y := bd.Min.Y; y < bd.Max.Y; y++ {
+		for x := bd.Min.X; x < bd.Max.X; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			if r > 0x8000 {
+				sum = sum.Add(math32.Vec2(float32(x), float32(y)))
+				n++
+			}
+		}
+	}
+	return sum.DivScalar(n)
+}
+
+func TestMatrix(t *testing.T) {
+	sz := image.Point{64, 64}
+	img := image.NewRGBA(image.Rectangle{Max: sz})
+	for y := 18; y < 22; y++ {
+		for x := 38; x < 42; x++ {
+			img.Set(x, y, color.RGBA{255, 255, 255, 255})
+		}
+	}
+	orig := dotCentroid(img)
+	params := [][4]float32{
+		{0.2, 0, 1, 0},
+		{0, 0.25, 1, 0},
+		{0, 0, 0.75, 0},
+		{0, 0, 1.25, 0},
+		{0, 0, 1, 30},
+		{-0.1, 0.1, 0.8, -20},
+	}
+	for _, pr := range params {
+		ximg := XFormImage(img, pr[0], pr[1], pr[2], pr[3])
+		got := dotCentroid(ximg)
+		m := Matrix(sz, pr[0], pr[1], pr[2], pr[3])
+		exp := m.MulVector2AsPoint(orig)
+		if got.Sub(exp).Length() > 1.5 {
+			t.Errorf("params: %v  dot at: %v  matrix predicts: %v\n", pr, got, exp)
+		}
+	}
+}
@@ -6,8 +6,10 @@ package vxform
 
 import (
 	"image"
+	"image/draw"
 	"math"
 
+	"cogentcore.org/core/math32"
 	"github.com/anthonynsimon/bild/clone"
 	"github.com/anthonynsimon/bild/transform"
 )
@@ -30,6 +32,27 @@ func XFormImage(img image.Image, trX, trY, sc, rot float32) *image.RGBA {
 	return cimg.(*image.RGBA)
 }
 
+// XFormImageAspect transforms given image according to given parameters,
+// as in XFormImage, with separate X and Y scales determined by given
+// aspect ratio (X scale / Y scale), with the overall area scale = sc,
+// using ScaleImageXY.
+func XFormImageAspect(img image.Image, trX, trY, sc, asp, rot float32) *image.RGBA {
+	cimg := img
+	if rot != 0 {
+		cimg = RotImage(cimg, rot)
+	}
+	sqa := math32.Sqrt(asp)
+	scX := sc * sqa
+	scY := sc / sqa
+	if scX > 0 && scY > 0 && (scX != 1 || scY != 1) {
+		cimg = ScaleImageXY(cimg, scX, scY)
+	}
+	if trX != 0 || trY != 0 {
+		cimg = TransImage(cimg, trX, trY)
+	}
+	return cimg.(*image.RGBA)
+}
+
 // RotImage rotates image by given number of degrees
 func RotImage(img image.Image, rot float32) *image.RGBA {
 	return transform.Rotate(img, float64(rot), nil) // default options: center, crop
@@ -55,6 +78,30 @@ func ScaleImage(img image.Image, sc float32) *image.RGBA {
 	return simg
 }
 
+// ScaleImageXY scales image by given separate X and Y scale factors,
+// retaining the current image size, by filling the border with the
+// current border along axes scaled to a smaller size, and cropping
+// around the center along axes scaled to a larger size.
+func ScaleImageXY(img image.Image, scX, scY float32) *image.RGBA {
+	sz := img.Bounds().Size()
+	nsz := sz
+	nsz.X = int(math.Round(float64(nsz.X) * float64(scX)))
+	nsz.Y = int(math.Round(float64(nsz.Y) * float64(scY)))
+	simg := transform.Resize(img, nsz.X, nsz.Y, transform.Linear)
+	psz := image.Point{(max(sz.X-nsz.X, 0) + 1) / 2, (max(sz.Y-nsz.Y, 0) + 1) / 2}
+	if psz.X > 0 || psz.Y > 0 {
+		simg = clone.Pad(simg, psz.X, psz.Y, clone.EdgeExtend)
+	}
+	bd := simg.Bounds()
+	if bd.Size() == sz && bd.Min == (image.Point{}) {
+		return simg
+	}
+	off := bd.Min.Add(bd.Size().Sub(sz).Div(2))
+	rimg := image.NewRGBA(image.Rectangle{Max: sz})
+	draw.Draw(rimg, rimg.Bounds(), simg, off, draw.Src)
+	return rimg
+}
+
 // TransImage translates image in each axis by given proportion of image half-size
 // i.e., 1 = move from center to edge
 func TransImage(img image.Image, trX, trY float32) *image.RGBA {
@@ -0,0 +1,105 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vxform
+
+import (
+	"fmt"
+	"math/rand"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+)
+
+// LightXForm represents current and previous lighting transformation
+// values, and can apply current values to an image tensor, multiplying
+// it by a smooth luminance gradient along a given direction, simulating
+// a directional light source, and a radial vignette darkening toward
+// the corners, with results clipped to 0..1.
+type LightXForm struct {
+
+	// current, prv direction of the luminance gradient, in degrees: 0 = brighter toward the right (+X), 90 = brighter toward the top
+	Dir env.CurPrev[float32]
+
+	// current, prv strength of the luminance gradient: the multiplier goes from 1 - Grad to 1 + Grad across the image along Dir
+	Grad env.CurPrev[float32]
+
+	// current, prv strength of the vignette: the multiplier is 1 - Vignette * r^2, where r is the distance from the center, normalized to 1 at the corners
+	Vignette env.CurPrev[float32]
+}
+
+// Set updates current values
+func (lx *LightXForm) Set(dir, grad, vignette float32) {
+	lx.Dir.Set(dir)
+	lx.Grad.Set(grad)
+	lx.Vignette.Set(vignette)
+}
+
+// Gain returns the luminance multiplier at given normalized position,
+// with x, y in -1..1 from the center, y increasing downward.
+func (lx *LightXForm) Gain(x, y float32) float32 {
+	th := math32.DegToRad(lx.Dir.Cur)
+	// projection onto the gradient direction, normalized to -1..1 over the image
+	pr := (x*math32.Cos(th) - y*math32.Sin(th)) / (math32.Abs(math32.Cos(th)) + math32.Abs(math32.Sin(th)))
+	r2 := 0.5 * (x*x + y*y)
+	return (1 + lx.Grad.Cur*pr) * (1 - lx.Vignette.Cur*r2)
+}
+
+// Tensor applies the current lighting transformation to given image
+// tensor, in place, which can be grey [Y, X] or have any number of outer
+// components (e.g., RGB [3, Y, X]), excluding padWidth of padding on the
+// two inner-most dimensions from the image extent used for the gradient
+// and vignette, which are extrapolated into the padding.
+func (lx *LightXForm) Tensor(img *tensor.Float32, padWidth int) {
+	nd := img.NumDims()
+	sy := img.DimSize(nd - 2)
+	sx := img.DimSize(nd - 1)
+	nc := img.Len() / (sy * sx)
+	hy := 0.5 * float32(sy-2*padWidth-1)
+	hx := 0.5 * float32(sx-2*padWidth-1)
+	for y := range sy {
+		ny := (float32(y-padWidth) - hy) / max(hy, 1)
+		for x := range sx {
+			nx := (float32(x-padWidth) - hx) / max(hx, 1)
+			gn := lx.Gain(nx, ny)
+			for c := range nc {
+				i := c*sy*sx + y*sx + x
+				img.Values[i] = math32.Clamp(img.Values[i]*gn, 0, 1)
+			}
+		}
+	}
+}
+
+func (lx *LightXForm) String() string {
+	return fmt.Sprintf("Dir: %.4f, Grad: %.4f, Vignette: %.4f", lx.Dir.Cur, lx.Grad.Cur, lx.Vignette.Cur)
+}
+
+// LightRand specifies random lighting transforms
+type LightRand struct {
+
+	// min -- max range of gradient directions to generate (in degrees)
+	Dir minmax.F32
+
+	// min -- max range of gradient strengths to generate
+	Grad minmax.F32
+
+	// min -- max range of vignette strengths to generate
+	Vignette minmax.F32
+}
+
+// Gen generates new random lighting transform values, using given
+// random number source, which can be seeded for reproducible
+// augmentations -- if nil, the global math/rand source is used.
+func (lr *LightRand) Gen(lx *LightXForm, rnd *rand.Rand) {
+	rf := rand.Float32
+	if rnd != nil {
+		rf = rnd.Float32
+	}
+	dir := lr.Dir.ProjValue(rf())
+	grad := lr.Grad.ProjValue(rf())
+	vig := lr.Vignette.ProjValue(rf())
+	lx.Set(dir, grad, vig)
+}
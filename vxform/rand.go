@@ -7,6 +7,7 @@ package vxform
 import (
 	"math/rand"
 
+	"cogentcore.org/core/math32"
 	"cogentcore.org/core/math32/minmax"
 )
 
@@ -22,6 +23,12 @@ type Rand struct {
 	// min -- max range of scales to generate
 	Scale minmax.F32
 
+	// sample scales uniformly in log space within the Scale range, so that, e.g., halving and doubling are equally likely -- otherwise uniform -- Scale.Min must be > 0
+	LogScale bool
+
+	// min -- max range of aspect ratios (X scale / Y scale) to generate, sampled uniformly in log space, with the X and Y scales set so that the overall area scale is Scale -- if Max is 0, no aspect jitter is applied (aspect = 1) -- Min must be > 0 otherwise
+	Aspect minmax.F32
+
 	// min -- max range of rotations to generate (in degrees)
 	Rot minmax.F32
 }
@@ -30,7 +37,27 @@ type Rand struct {
 func (rx *Rand) Gen(xf *XForm) {
 	trX := rx.TransX.ProjValue(rand.Float32())
 	trY := rx.TransY.ProjValue(rand.Float32())
-	sc := rx.Scale.ProjValue(rand.Float32())
+	var sc float32
+	if rx.LogScale {
+		sc = LogUniform(rx.Scale, rand.Float32())
+	} else {
+		sc = rx.Scale.ProjValue(rand.Float32())
+	}
 	rt := rx.Rot.ProjValue(rand.Float32())
 	xf.Set(trX, trY, sc, rt)
+	asp := float32(1)
+	if rx.Aspect.Max > 0 {
+		asp = LogUniform(rx.Aspect, rand.Float32())
+	}
+	xf.Aspect.Set(asp)
+}
+
+// LogUniform returns the value within given range for given
+// uniform random number in [0..1), projected uniformly in log space,
+// so that, e.g., 0.5 and 2 are equally likely in the range 0.25 - 4.
+// Range values must be > 0.
+func LogUniform(rng minmax.F32, rnd float32) float32 {
+	lmin := math32.Log(rng.Min)
+	lmax := math32.Log(rng.Max)
+	return math32.Exp(lmin + rnd*(lmax-lmin))
 }
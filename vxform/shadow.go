@@ -0,0 +1,158 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vxform
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+)
+
+// Shadow is a cast shadow overlay, which darkens a polygonal region
+// of an image tensor with a soft edge, as cast shadows are a known
+// failure mode for edge-based recognition.
+type Shadow struct {
+
+	// polygon vertices of the shadow region, in normalized image coordinates (0..1 over the image, Y increasing downward)
+	Poly []math32.Vector2
+
+	// opacity of the shadow: the image is multiplied by 1 - Opacity within the region
+	Opacity float32
+
+	// width of the soft edge of the shadow, as a proportion of the image size, over which the opacity ramps smoothly from 0 outside to Opacity inside -- 0 = hard edge
+	Blur float32
+}
+
+// Mask returns the shadow opacity at given normalized image position,
+// ramping smoothly across the polygon edge over the Blur width,
+// centered on the edge.
+func (sh *Shadow) Mask(p math32.Vector2) float32 {
+	if len(sh.Poly) < 3 {
+		return 0
+	}
+	d := sh.edgeDist(p)
+	if !sh.inside(p) {
+		d = -d
+	}
+	if sh.Blur <= 0 {
+		if d >= 0 {
+			return sh.Opacity
+		}
+		return 0
+	}
+	t := math32.Clamp(0.5+d/sh.Blur, 0, 1)
+	return sh.Opacity * t * t * (3 - 2*t) // smoothstep
+}
+
+// Tensor applies the shadow to given image tensor, in place, which can
+// be grey [Y, X] or have any number of outer components (e.g., RGB
+// [3, Y, X]), excluding padWidth of padding on the two inner-most
+// dimensions from the image extent used for the normalized coordinates.
+func (sh *Shadow) Tensor(img *tensor.Float32, padWidth int) {
+	if len(sh.Poly) < 3 || sh.Opacity == 0 {
+		return
+	}
+	nd := img.NumDims()
+	sy := img.DimSize(nd - 2)
+	sx := img.DimSize(nd - 1)
+	nc := img.Len() / (sy * sx)
+	iy := float32(max(sy-2*padWidth, 1))
+	ix := float32(max(sx-2*padWidth, 1))
+	for y := range sy {
+		for x := range sx {
+			p := math32.Vec2((float32(x-padWidth)+0.5)/ix, (float32(y-padWidth)+0.5)/iy)
+			gn := 1 - sh.Mask(p)
+			if gn == 1 {
+				continue
+			}
+			for c := range nc {
+				img.Values[c*sy*sx+y*sx+x] *= gn
+			}
+		}
+	}
+}
+
+// inside returns whether given point is inside the polygon,
+// using the even-odd rule.
+func (sh *Shadow) inside(p math32.Vector2) bool {
+	in := false
+	n := len(sh.Poly)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := sh.Poly[i], sh.Poly[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// edgeDist returns the distance from given point to the nearest
+// polygon edge.
+func (sh *Shadow) edgeDist(p math32.Vector2) float32 {
+	md := float32(math.MaxFloat32)
+	n := len(sh.Poly)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := sh.Poly[j], sh.Poly[i]
+		ab := b.Sub(a)
+		t := float32(0)
+		if l2 := ab.LengthSquared(); l2 > 0 {
+			t = math32.Clamp(p.Sub(a).Dot(ab)/l2, 0, 1)
+		}
+		md = min(md, p.Sub(a.Add(ab.MulScalar(t))).Length())
+	}
+	return md
+}
+
+func (sh *Shadow) String() string {
+	return fmt.Sprintf("NVerts: %d, Opacity: %.4f, Blur: %.4f", len(sh.Poly), sh.Opacity, sh.Blur)
+}
+
+// ShadowRand specifies random cast shadows: star-shaped polygons
+// with a random center within the image, and vertices at random
+// angles and radii around it.
+type ShadowRand struct {
+
+	// min -- max range of number of polygon vertices to generate
+	NVerts minmax.Int
+
+	// min -- max range of radii of the polygon vertices from its center, as a proportion of the image size
+	Radius minmax.F32
+
+	// min -- max range of shadow opacities to generate
+	Opacity minmax.F32
+
+	// min -- max range of soft edge widths to generate, as a proportion of the image size
+	Blur minmax.F32
+}
+
+// Gen generates a new random shadow, using given random number source,
+// which can be seeded for reproducible augmentations -- if nil,
+// the global math/rand source is used.
+func (sr *ShadowRand) Gen(sh *Shadow, rnd *rand.Rand) {
+	rf := rand.Float32
+	if rnd != nil {
+		rf = rnd.Float32
+	}
+	nv := max(sr.NVerts.Min+int(rf()*float32(sr.NVerts.Range()+1)), 3)
+	nv = min(nv, max(sr.NVerts.Max, 3))
+	ctr := math32.Vec2(rf(), rf())
+	angs := make([]float32, nv)
+	for i := range angs {
+		angs[i] = 2 * math.Pi * rf()
+	}
+	slices.Sort(angs)
+	sh.Poly = sh.Poly[:0]
+	for _, a := range angs {
+		r := sr.Radius.ProjValue(rf())
+		sh.Poly = append(sh.Poly, ctr.Add(math32.Vec2(math32.Cos(a), math32.Sin(a)).MulScalar(r)))
+	}
+	sh.Opacity = sr.Opacity.ProjValue(rf())
+	sh.Blur = sr.Blur.ProjValue(rf())
+}
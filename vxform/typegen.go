@@ -6,6 +6,18 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.Rand", IDName: "rand", Doc: "Rand specifies random transforms", Fields: []types.Field{{Name: "TransX", Doc: "min -- max range of X-axis (horizontal) translations to generate (as proportion of image size)"}, {Name: "TransY", Doc: "min -- max range of Y-axis (vertical) translations to generate (as proportion of image size)"}, {Name: "Scale", Doc: "min -- max range of scales to generate"}, {Name: "Rot", Doc: "min -- max range of rotations to generate (in degrees)"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.ColorXForm", IDName: "color-x-form", Doc: "ColorXForm represents current and previous color transformation values\nand can apply current values to an RGB tensor, as produced by\nvfilter.RGBToTensor, with shape [3, Y, X] and values in 0..1.\nTransformations are performed as: hue rotation, saturation scaling,\nthen per-channel gain, with results clipped to 0..1.\nHue rotation and saturation scaling are computed relative to the\ngrey (R = G = B) axis, so they preserve the mean of the channels\n(prior to clipping), and black (e.g., padding) is left unchanged.", Fields: []types.Field{{Name: "Hue", Doc: "current, prv hue rotation value, in degrees, as a rotation of RGB values around the grey axis"}, {Name: "Sat", Doc: "current, prv saturation scale value: 0 = greyscale, 1 = no change, > 1 = more saturated -- uses 1 if Sat.Cur is < 0"}, {Name: "Gain", Doc: "current, prv per-channel gain values, for R, G, B channels respectively"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.XForm", IDName: "x-form", Doc: "XForm represents current and previous visual transformation values\nand can apply current values to transform an image.\nTransformations are performed as: rotation, scale, then translation.\nScaling crops to retain the current image size.", Fields: []types.Field{{Name: "TransX", Doc: "current, prv X-axis (horizontal) translation value, as proportion of image half-size (i.e., 1 = move from center to edge)"}, {Name: "TransY", Doc: "current, prv Y-axis (horizontal) translation value, as proportion of image half-size (i.e., 1 = move from center to edge)"}, {Name: "Scale", Doc: "current, prv scale value"}, {Name: "Rot", Doc: "current, prv rotation value, in degrees"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.ColorRand", IDName: "color-rand", Doc: "ColorRand specifies random color transforms", Fields: []types.Field{{Name: "Hue", Doc: "min -- max range of hue rotations to generate (in degrees)"}, {Name: "Sat", Doc: "min -- max range of saturation scales to generate, sampled uniformly in log space -- if Max is 0, no saturation change is applied (sat = 1) -- Min must be > 0 otherwise"}, {Name: "Gain", Doc: "min -- max range of per-channel gains to generate, sampled independently for each channel, uniformly in log space -- if Max is 0, no gain change is applied (gain = 1) -- Min must be > 0 otherwise"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.LightXForm", IDName: "light-x-form", Doc: "LightXForm represents current and previous lighting transformation\nvalues, and can apply current values to an image tensor, multiplying\nit by a smooth luminance gradient along a given direction, simulating\na directional light source, and a radial vignette darkening toward\nthe corners, with results clipped to 0..1.", Fields: []types.Field{{Name: "Dir", Doc: "current, prv direction of the luminance gradient, in degrees: 0 = brighter toward the right (+X), 90 = brighter toward the top"}, {Name: "Grad", Doc: "current, prv strength of the luminance gradient: the multiplier goes from 1 - Grad to 1 + Grad across the image along Dir"}, {Name: "Vignette", Doc: "current, prv strength of the vignette: the multiplier is 1 - Vignette * r^2, where r is the distance from the center, normalized to 1 at the corners"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.LightRand", IDName: "light-rand", Doc: "LightRand specifies random lighting transforms", Fields: []types.Field{{Name: "Dir", Doc: "min -- max range of gradient directions to generate (in degrees)"}, {Name: "Grad", Doc: "min -- max range of gradient strengths to generate"}, {Name: "Vignette", Doc: "min -- max range of vignette strengths to generate"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.Rand", IDName: "rand", Doc: "Rand specifies random transforms", Fields: []types.Field{{Name: "TransX", Doc: "min -- max range of X-axis (horizontal) translations to generate (as proportion of image size)"}, {Name: "TransY", Doc: "min -- max range of Y-axis (vertical) translations to generate (as proportion of image size)"}, {Name: "Scale", Doc: "min -- max range of scales to generate"}, {Name: "LogScale", Doc: "sample scales uniformly in log space within the Scale range, so that, e.g., halving and doubling are equally likely -- otherwise uniform -- Scale.Min must be > 0"}, {Name: "Aspect", Doc: "min -- max range of aspect ratios (X scale / Y scale) to generate, sampled uniformly in log space, with the X and Y scales set so that the overall area scale is Scale -- if Max is 0, no aspect jitter is applied (aspect = 1) -- Min must be > 0 otherwise"}, {Name: "Rot", Doc: "min -- max range of rotations to generate (in degrees)"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.Shadow", IDName: "shadow", Doc: "Shadow is a cast shadow overlay, which darkens a polygonal region\nof an image tensor with a soft edge, as cast shadows are a known\nfailure mode for edge-based recognition.", Fields: []types.Field{{Name: "Poly", Doc: "polygon vertices of the shadow region, in normalized image coordinates (0..1 over the image, Y increasing downward)"}, {Name: "Opacity", Doc: "opacity of the shadow: the image is multiplied by 1 - Opacity within the region"}, {Name: "Blur", Doc: "width of the soft edge of the shadow, as a proportion of the image size, over which the opacity ramps smoothly from 0 outside to Opacity inside -- 0 = hard edge"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.ShadowRand", IDName: "shadow-rand", Doc: "ShadowRand specifies random cast shadows: star-shaped polygons\nwith a random center within the image, and vertices at random\nangles and radii around it.", Fields: []types.Field{{Name: "NVerts", Doc: "min -- max range of number of polygon vertices to generate"}, {Name: "Radius", Doc: "min -- max range of radii of the polygon vertices from its center, as a proportion of the image size"}, {Name: "Opacity", Doc: "min -- max range of shadow opacities to generate"}, {Name: "Blur", Doc: "min -- max range of soft edge widths to generate, as a proportion of the image size"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/vision/v2/vxform.XForm", IDName: "x-form", Doc: "XForm represents current and previous visual transformation values\nand can apply current values to transform an image.\nTransformations are performed as: rotation, scale, then translation.\nScaling crops to retain the current image size.", Fields: []types.Field{{Name: "TransX", Doc: "current, prv X-axis (horizontal) translation value, as proportion of image half-size (i.e., 1 = move from center to edge)"}, {Name: "TransY", Doc: "current, prv Y-axis (horizontal) translation value, as proportion of image half-size (i.e., 1 = move from center to edge)"}, {Name: "Scale", Doc: "current, prv scale value"}, {Name: "Rot", Doc: "current, prv rotation value, in degrees"}, {Name: "Aspect", Doc: "current, prv aspect ratio value: X scale / Y scale, with the overall area scale given by Scale -- 0 is equivalent to 1 (no aspect change)"}}})
@@ -30,6 +30,9 @@ type XForm struct {
 
 	// current, prv rotation value, in degrees
 	Rot env.CurPrev[float32]
+
+	// current, prv aspect ratio value: X scale / Y scale, with the overall area scale given by Scale -- 0 is equivalent to 1 (no aspect change)
+	Aspect env.CurPrev[float32]
 }
 
 // Set updates current values
@@ -42,9 +45,15 @@ func (xf *XForm) Set(trX, trY, sc, rot float32) {
 
 // Image transforms given image according to current parameters
 func (xf *XForm) Image(img image.Image) *image.RGBA {
+	if asp := xf.Aspect.Cur; asp > 0 && asp != 1 {
+		return XFormImageAspect(img, xf.TransX.Cur, xf.TransY.Cur, xf.Scale.Cur, asp, xf.Rot.Cur)
+	}
 	return XFormImage(img, xf.TransX.Cur, xf.TransY.Cur, xf.Scale.Cur, xf.Rot.Cur)
 }
 
 func (xf *XForm) String() string {
+	if asp := xf.Aspect.Cur; asp > 0 && asp != 1 {
+		return fmt.Sprintf("tX: %.4f, tY: %.4f, Sc: %.4f, Asp: %.4f, Rt: %.4f", xf.TransX.Cur, xf.TransY.Cur, xf.Scale.Cur, asp, xf.Rot.Cur)
+	}
 	return fmt.Sprintf("tX: %.4f, tY: %.4f, Sc: %.4f, Rt: %.4f", xf.TransX.Cur, xf.TransY.Cur, xf.Scale.Cur, xf.Rot.Cur)
 }